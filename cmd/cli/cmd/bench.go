@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Bench-specific flags
+	benchRequests    int
+	benchConcurrency int
+	// Inherit common flags from other commands
+	benchTLSEnabled    bool
+	benchSkipTLSVerify bool
+	benchLogLevel      string
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark the response times of the free@home system access point",
+	Long:  `Repeatedly request the device list from the free@home system access point and report the minimum, average, 95th percentile and maximum latency as well as the error rate.`,
+	Args:  cobra.NoArgs,
+	RunE:  runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	// Add bench-specific flags
+	benchCmd.Flags().IntVar(&benchRequests, "requests", 20, "Number of requests to send")
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", 1, "Number of requests to send concurrently")
+
+	// Add TLS configuration flags
+	benchCmd.Flags().BoolVar(&benchTLSEnabled, "tls", true, "Enable TLS for connection")
+	benchCmd.Flags().BoolVar(&benchSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	benchCmd.Flags().StringVar(&benchLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	return cli.Bench(cli.BenchCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    benchTLSEnabled,
+			SkipTLSVerify: benchSkipTLSVerify,
+			LogLevel:      benchLogLevel,
+		},
+		Requests:    benchRequests,
+		Concurrency: benchConcurrency,
+	})
+}
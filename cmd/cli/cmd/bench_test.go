@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchCmd(t *testing.T) {
+	// Test that bench command exists
+	assert.NotNil(t, benchCmd)
+	assert.Equal(t, "bench", benchCmd.Use)
+	assert.Equal(t, "Benchmark the response times of the free@home system access point", benchCmd.Short)
+}
+
+func TestBenchCmdFlags(t *testing.T) {
+	// Test that bench command has the expected flags
+	flags := benchCmd.Flags()
+
+	requestsFlag := flags.Lookup("requests")
+	assert.NotNil(t, requestsFlag)
+	assert.Equal(t, "20", requestsFlag.DefValue)
+
+	concurrencyFlag := flags.Lookup("concurrency")
+	assert.NotNil(t, concurrencyFlag)
+	assert.Equal(t, "1", concurrencyFlag.DefValue)
+
+	// Check TLS flags
+	tlsFlag := flags.Lookup("tls")
+	assert.NotNil(t, tlsFlag)
+	assert.Equal(t, "true", tlsFlag.DefValue)
+
+	skipTLSFlag := flags.Lookup("skip-tls-verify")
+	assert.NotNil(t, skipTLSFlag)
+	assert.Equal(t, "false", skipTLSFlag.DefValue)
+
+	// Check log level flag
+	logLevelFlag := flags.Lookup("log-level")
+	assert.NotNil(t, logLevelFlag)
+	assert.Equal(t, "info", logLevelFlag.DefValue)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the shell completion script",
+	Long: `Generate the shell completion script for the specified shell.
+
+Examples:
+  source <(free@home completion bash)
+  free@home completion zsh > "${fpath[1]}/_free@home"
+  free@home completion fish > ~/.config/fish/completions/free@home.fish
+  free@home completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	return generateCompletion(cmd.OutOrStdout(), args[0])
+}
+
+// generateCompletion writes the completion script for the specified shell to the writer
+func generateCompletion(out io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(out, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(out)
+	case "fish":
+		return rootCmd.GenFishCompletion(out, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(out)
+	default:
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
+
+// completionTimeout is the maximum time a request for completions may take, so that an offline system access point does
+// not block the shell
+const completionTimeout = 2 * time.Second
+
+// completionCommandConfig returns the command configuration used to query the system access point for completions.
+// The log output is discarded, so that failures do not clutter the shell, which then offers no completions.
+func completionCommandConfig() cli.CommandConfig {
+	return cli.CommandConfig{
+		Viper:          viper.GetViper(),
+		TLSEnabled:     tlsEnabled,
+		SkipTLSVerify:  skipTLSVerify,
+		LogLevel:       "error",
+		RequestTimeout: completionTimeout,
+		DiscardLogs:    true,
+	}
+}
+
+// completeDeviceArgs completes the serial argument of the device commands
+func completeDeviceArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cli.CompleteDeviceSerials(completionCommandConfig(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDatapointArgs completes the serial, channel and datapoint arguments of the datapoint commands
+func completeDatapointArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config := completionCommandConfig()
+	switch len(args) {
+	case 0:
+		return cli.CompleteDeviceSerials(config, toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		// Only the serial is required when all datapoints are read
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return cli.CompleteChannels(config, args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 2:
+		return cli.CompleteDatapoints(config, args[0], args[1], toComplete), cobra.ShellCompDirectiveNoFileComp
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestCompletionCommand tests that the completion command has the expected properties.
+func TestCompletionCommand(t *testing.T) {
+	if completionCmd.Name() != "completion" {
+		t.Errorf("Expected completion command name to be 'completion', got '%s'", completionCmd.Name())
+	}
+
+	if completionCmd.Short == "" {
+		t.Error("Expected completion command to have a Short description")
+	}
+
+	if completionCmd.Long == "" {
+		t.Error("Expected completion command to have a Long description")
+	}
+}
+
+// TestCompletionCommandIsChildOfRoot tests that the completion command is properly added to the root command.
+func TestCompletionCommandIsChildOfRoot(t *testing.T) {
+	found := slices.ContainsFunc(rootCmd.Commands(), func(cmd *cobra.Command) bool {
+		return cmd.Name() == "completion"
+	})
+	if !found {
+		t.Error("Expected completion command to be a child of root command")
+	}
+}
+
+// TestGenerateCompletion tests that a completion script is generated for each supported shell.
+func TestGenerateCompletion(t *testing.T) {
+	for _, shell := range completionCmd.ValidArgs {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := generateCompletion(&buf, shell); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if buf.Len() == 0 {
+				t.Errorf("Expected non-empty completion script for shell '%s'", shell)
+			}
+		})
+	}
+}
+
+// TestGenerateCompletionUnknownShell tests that an unknown shell returns an error.
+func TestGenerateCompletionUnknownShell(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCompletion(&buf, "tcsh")
+	if err == nil {
+		t.Fatal("Expected error for unknown shell, got nil")
+	}
+	if err.Error() != "unsupported shell: tcsh" {
+		t.Errorf("Expected error 'unsupported shell: tcsh', got '%s'", err.Error())
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for unknown shell, got: %s", buf.String())
+	}
+}
+
+// TestCompletionCommandRun tests that running the completion command writes the script to the command output.
+func TestCompletionCommandRun(t *testing.T) {
+	var buf bytes.Buffer
+	completionCmd.SetOut(&buf)
+	defer completionCmd.SetOut(nil)
+
+	if err := completionCmd.RunE(completionCmd, []string{"bash"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Expected non-empty completion script")
+	}
+}
+
+// TestCompletionArgsFunctions tests that the argument completion functions are registered and stop completing after the last argument.
+func TestCompletionArgsFunctions(t *testing.T) {
+	for _, cmd := range []*cobra.Command{deviceCmd, datapointCmd, datapointSetCmd} {
+		if cmd.ValidArgsFunction == nil {
+			t.Errorf("Expected command '%s' to have a ValidArgsFunction", cmd.CommandPath())
+		}
+	}
+
+	completions, directive := completeDeviceArgs(deviceCmd, []string{"ABB7F595EC47"}, "")
+	if completions != nil || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no completions after the serial, got %v (%v)", completions, directive)
+	}
+
+	completions, directive = completeDatapointArgs(datapointCmd, []string{"ABB7F595EC47", "ch0000", "odp0000"}, "")
+	if completions != nil || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no completions after the datapoint, got %v (%v)", completions, directive)
+	}
+}
+
+// TestCompletionCommandConfig tests that completions use a short request timeout and discard the log output.
+func TestCompletionCommandConfig(t *testing.T) {
+	config := completionCommandConfig()
+	if config.RequestTimeout != completionTimeout || config.RequestTimeout <= 0 {
+		t.Errorf("Expected request timeout %v, got %v", completionTimeout, config.RequestTimeout)
+	}
+	if !config.DiscardLogs {
+		t.Error("Expected the log output to be discarded")
+	}
+}
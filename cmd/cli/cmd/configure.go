@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/pgerke/freeathome/v2/internal/cli"
@@ -43,6 +44,14 @@ Examples:
 		Long:  `Display the current configuration settings for the free@home system access point.`,
 		RunE:  runShow,
 	}
+
+	effectiveCmd = &cobra.Command{
+		Use:   "effective",
+		Short: "Show the effective configuration settings and their sources",
+		Long: `Display the settings in effect after merging flags, environment variables, the config file and the defaults.
+Each value is shown with its source (flag, env, file or default), the password is masked.`,
+		RunE: runEffective,
+	}
 )
 
 func init() {
@@ -50,14 +59,21 @@ func init() {
 
 	// Add subcommands
 	configureCmd.AddCommand(showCmd)
+	configureCmd.AddCommand(effectiveCmd)
 
 	// Add flags
-	configureCmd.Flags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.freeathome/config.yaml)")
+	configureCmd.Flags().StringVar(&cfgFile, "config", "", "config file (default is $FREEATHOME_CONFIG or $HOME/.freeathome/config.yaml)")
 	configureCmd.Flags().StringVar(&hostname, "hostname", "", "free@home system hostname or IP address")
 	configureCmd.Flags().StringVar(&username, "username", "", "username for authentication")
 	configureCmd.Flags().StringVar(&password, "password", "", "password for authentication")
 	configureCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "fail instead of prompting for missing configuration values")
 
+	// Add effective configuration flags, which are reported with the settings
+	effectiveCmd.Flags().StringVar(&cfgFile, "config", "", "config file (default is $FREEATHOME_CONFIG or $HOME/.freeathome/config.yaml)")
+	effectiveCmd.Flags().BoolVar(&tlsEnabled, "tls", true, "Enable TLS for connection")
+	effectiveCmd.Flags().BoolVar(&skipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+	effectiveCmd.Flags().StringVar(&logLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+
 	// Bind flags to viper
 	_ = viper.BindPFlag("hostname", configureCmd.Flags().Lookup("hostname"))
 	_ = viper.BindPFlag("username", configureCmd.Flags().Lookup("username"))
@@ -71,3 +87,21 @@ func runConfigure(cmd *cobra.Command, args []string) error {
 func runShow(cmd *cobra.Command, args []string) error {
 	return cli.ShowConfiguration(viper.GetViper(), cfgFile)
 }
+
+func runEffective(cmd *cobra.Command, args []string) error {
+	var changedFlags []string
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		changedFlags = append(changedFlags, flag.Name)
+	})
+
+	return cli.ShowEffectiveConfiguration(cli.EffectiveCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    tlsEnabled,
+			SkipTLSVerify: skipTLSVerify,
+			LogLevel:      logLevel,
+		},
+		ConfigFile:   cfgFile,
+		ChangedFlags: changedFlags,
+	})
+}
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"io"
+	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // TestConfigureCommand tests that the configure command has the expected properties.
@@ -116,3 +121,78 @@ func TestConfigureCommandFlagBindings(t *testing.T) {
 		t.Error("Expected password flag to exist")
 	}
 }
+
+// TestEffectiveCommand tests that the effective command has the expected properties and flags.
+func TestEffectiveCommand(t *testing.T) {
+	if effectiveCmd.Use != "effective" {
+		t.Errorf("Expected effective command Use to be 'effective', got '%s'", effectiveCmd.Use)
+	}
+
+	found := slices.ContainsFunc(configureCmd.Commands(), func(cmd *cobra.Command) bool {
+		return cmd.Name() == "effective"
+	})
+	if !found {
+		t.Error("Expected effective command to be a child of configure command")
+	}
+
+	flags := map[string]string{"config": "", "tls": "true", "skip-tls-verify": "false", "log-level": "info"}
+	for name, defValue := range flags {
+		flag := effectiveCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Errorf("Expected effective command to have a %s flag", name)
+			continue
+		}
+		if flag.DefValue != defValue {
+			t.Errorf("Expected %s flag default to be '%s', got '%s'", name, defValue, flag.DefValue)
+		}
+	}
+}
+
+// runConfigureWithRootFlags runs the configure command with the given root flags against an empty config file and returns
+// the written config file
+func runConfigureWithRootFlags(t *testing.T, rootFlags ...string) string {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, nil, 0600); err != nil {
+		t.Fatalf("Failed to create config file: %v", err)
+	}
+	t.Cleanup(func() {
+		// The flags keep their values between executions of the root command
+		reset := func(flag *pflag.Flag) {
+			_ = flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+		}
+		rootCmd.PersistentFlags().VisitAll(reset)
+		configureCmd.Flags().VisitAll(reset)
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+	})
+
+	args := append(rootFlags, "configure", "--config", configFile, "--non-interactive", "--hostname", "test-host", "--username", "test-user", "--password", "test-pass")
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(io.Discard)
+	if err := Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	return string(data)
+}
+
+// TestConfigureDoesNotSaveRootFlags tests that the root flags of the invocation are not saved to the config file.
+func TestConfigureDoesNotSaveRootFlags(t *testing.T) {
+	written := runConfigureWithRootFlags(t, "--sysap-uuid", "1a2b3c4d-0000-0000-0000-000000000000", "--read-only", "--wrap-log-lines", "--api-version", "v9")
+
+	for _, key := range []string{"sysap-uuid", "read-only", "wrap-log-lines", "api-version"} {
+		if strings.Contains(written, key) {
+			t.Errorf("Expected %s not to be saved, got:\n%s", key, written)
+		}
+	}
+	if !strings.Contains(written, "hostname: test-host") {
+		t.Errorf("Expected the hostname to be saved, got:\n%s", written)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Dashboard-specific flags
+	dashboardWatchFile string
+	dashboardInterval  int
+	// Inherit common flags from other commands
+	dashboardTLSEnabled    bool
+	dashboardSkipTLSVerify bool
+	dashboardLogLevel      string
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show a live dashboard of watched datapoints",
+	Long:  `Read the datapoints listed in a YAML or JSON watch file and update their values live via WebSocket. On a terminal the dashboard is redrawn in place and recent changes are highlighted, otherwise a snapshot is printed periodically.`,
+	Args:  cobra.NoArgs,
+	RunE:  runDashboard,
+}
+
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+
+	// Add dashboard-specific flags
+	dashboardCmd.Flags().StringVar(&dashboardWatchFile, "watch-file", "", "YAML or JSON file containing a list of serial/channel/datapoint addresses to watch")
+	_ = dashboardCmd.MarkFlagRequired("watch-file")
+	dashboardCmd.Flags().IntVar(&dashboardInterval, "interval", 10, "Interval between two snapshots in seconds when the output is not a terminal")
+
+	// Add TLS configuration flags
+	dashboardCmd.Flags().BoolVar(&dashboardTLSEnabled, "tls", true, "Enable TLS for connection")
+	dashboardCmd.Flags().BoolVar(&dashboardSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	dashboardCmd.Flags().StringVar(&dashboardLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	return cli.Dashboard(cli.DashboardCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    dashboardTLSEnabled,
+			SkipTLSVerify: dashboardSkipTLSVerify,
+			LogLevel:      dashboardLogLevel,
+		},
+		WatchFile:        dashboardWatchFile,
+		SnapshotInterval: dashboardInterval,
+	})
+}
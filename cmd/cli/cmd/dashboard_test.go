@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardCmd(t *testing.T) {
+	// Test that dashboard command exists
+	assert.NotNil(t, dashboardCmd)
+	assert.Equal(t, "dashboard", dashboardCmd.Use)
+	assert.Equal(t, "Show a live dashboard of watched datapoints", dashboardCmd.Short)
+}
+
+func TestDashboardCmdFlags(t *testing.T) {
+	// Test that dashboard command has the expected flags
+	flags := dashboardCmd.Flags()
+
+	// Check watch file flag
+	watchFileFlag := flags.Lookup("watch-file")
+	assert.NotNil(t, watchFileFlag)
+	assert.Equal(t, "", watchFileFlag.DefValue)
+
+	// Check interval flag
+	intervalFlag := flags.Lookup("interval")
+	assert.NotNil(t, intervalFlag)
+	assert.Equal(t, "10", intervalFlag.DefValue)
+
+	// Check TLS flags
+	tlsFlag := flags.Lookup("tls")
+	assert.NotNil(t, tlsFlag)
+	assert.Equal(t, "true", tlsFlag.DefValue)
+
+	skipTLSFlag := flags.Lookup("skip-tls-verify")
+	assert.NotNil(t, skipTLSFlag)
+	assert.Equal(t, "false", skipTLSFlag.DefValue)
+
+	// Check log level flag
+	logLevelFlag := flags.Lookup("log-level")
+	assert.NotNil(t, logLevelFlag)
+	assert.Equal(t, "info", logLevelFlag.DefValue)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Config files of the compared system access points
+	diffProfiles []string
+	// Inherit common flags from other commands
+	diffTLSEnabled    bool
+	diffSkipTLSVerify bool
+	diffLogLevel      string
+)
+
+var diffSysApsCmd = &cobra.Command{
+	Use:   "diff-sysaps",
+	Short: "Compare the devices of two free@home system access points",
+	Long:  `Fetch the configurations of two free@home system access points, each configured in its own config file, and report the devices present on only one of them and the device attributes that differ.`,
+	Args:  cobra.NoArgs,
+	RunE:  runDiffSysAps,
+}
+
+func init() {
+	rootCmd.AddCommand(diffSysApsCmd)
+
+	// Add profile flag
+	diffSysApsCmd.Flags().StringArrayVar(&diffProfiles, "profile", nil, "Config file of a system access point to compare, required exactly twice")
+	_ = diffSysApsCmd.MarkFlagRequired("profile")
+
+	// Add TLS configuration flags
+	diffSysApsCmd.Flags().BoolVar(&diffTLSEnabled, "tls", true, "Enable TLS for connection")
+	diffSysApsCmd.Flags().BoolVar(&diffSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	diffSysApsCmd.Flags().StringVar(&diffLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+}
+
+func runDiffSysAps(cmd *cobra.Command, args []string) error {
+	return cli.DiffSysAps(cli.CommandConfig{
+		Viper:         viper.GetViper(),
+		TLSEnabled:    diffTLSEnabled,
+		SkipTLSVerify: diffSkipTLSVerify,
+		LogLevel:      diffLogLevel,
+	}, diffProfiles)
+}
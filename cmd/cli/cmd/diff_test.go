@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSysApsCmd(t *testing.T) {
+	// Test that diff-sysaps command exists
+	assert.NotNil(t, diffSysApsCmd)
+	assert.Equal(t, "diff-sysaps", diffSysApsCmd.Use)
+	assert.Equal(t, "Compare the devices of two free@home system access points", diffSysApsCmd.Short)
+}
+
+func TestDiffSysApsCmdFlags(t *testing.T) {
+	// Test that diff-sysaps command has the expected flags
+	flags := diffSysApsCmd.Flags()
+
+	// Check profile flag
+	profileFlag := flags.Lookup("profile")
+	assert.NotNil(t, profileFlag)
+	assert.Equal(t, "[]", profileFlag.DefValue)
+
+	// Check TLS flags
+	tlsFlag := flags.Lookup("tls")
+	assert.NotNil(t, tlsFlag)
+	assert.Equal(t, "true", tlsFlag.DefValue)
+
+	skipTLSFlag := flags.Lookup("skip-tls-verify")
+	assert.NotNil(t, skipTLSFlag)
+	assert.Equal(t, "false", skipTLSFlag.DefValue)
+
+	// Check log level flag
+	logLevelFlag := flags.Lookup("log-level")
+	assert.NotNil(t, logLevelFlag)
+	assert.Equal(t, "info", logLevelFlag.DefValue)
+}
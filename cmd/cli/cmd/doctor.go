@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Doctor-specific flags
+	maxClockSkew time.Duration
+	// Inherit common flags from other commands
+	doctorTLSEnabled    bool
+	doctorSkipTLSVerify bool
+	doctorLogLevel      string
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the free@home system access point for common problems",
+	Long:  `Check the free@home system access point for conditions that confuse time-sensitive features, e.g. a clock that differs from the local clock.`,
+	Args:  cobra.NoArgs,
+	RunE:  runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	// Add doctor-specific flags
+	doctorCmd.Flags().DurationVar(&maxClockSkew, "max-clock-skew", 30*time.Second, "Warn if the clock of the system access point differs from the local clock by more than this duration")
+
+	// Add TLS configuration flags
+	doctorCmd.Flags().BoolVar(&doctorTLSEnabled, "tls", true, "Enable TLS for connection")
+	doctorCmd.Flags().BoolVar(&doctorSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	doctorCmd.Flags().StringVar(&doctorLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	return cli.Doctor(cli.DoctorCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    doctorTLSEnabled,
+			SkipTLSVerify: doctorSkipTLSVerify,
+			LogLevel:      doctorLogLevel,
+		},
+		MaxClockSkew: maxClockSkew,
+	})
+}
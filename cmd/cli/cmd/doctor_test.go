@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctorCmd(t *testing.T) {
+	// Test that doctor command exists
+	assert.NotNil(t, doctorCmd)
+	assert.Equal(t, "doctor", doctorCmd.Use)
+	assert.Equal(t, "Check the free@home system access point for common problems", doctorCmd.Short)
+}
+
+func TestDoctorCmdFlags(t *testing.T) {
+	// Test that doctor command has the expected flags
+	flags := doctorCmd.Flags()
+
+	// Check max clock skew flag
+	maxClockSkewFlag := flags.Lookup("max-clock-skew")
+	assert.NotNil(t, maxClockSkewFlag)
+	assert.Equal(t, "30s", maxClockSkewFlag.DefValue)
+
+	// Check TLS flags
+	tlsFlag := flags.Lookup("tls")
+	assert.NotNil(t, tlsFlag)
+	assert.Equal(t, "true", tlsFlag.DefValue)
+
+	skipTLSFlag := flags.Lookup("skip-tls-verify")
+	assert.NotNil(t, skipTLSFlag)
+	assert.Equal(t, "false", skipTLSFlag.DefValue)
+
+	// Check log level flag
+	logLevelFlag := flags.Lookup("log-level")
+	assert.NotNil(t, logLevelFlag)
+	assert.Equal(t, "info", logLevelFlag.DefValue)
+}
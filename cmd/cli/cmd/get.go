@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/pgerke/freeathome/v2/internal/cli"
+	"github.com/pgerke/freeathome/v2/pkg/models"
 )
 
 var (
@@ -17,6 +20,17 @@ var (
 	outputFormat string
 	// JSON output configuration
 	prettify bool
+	envelope bool
+	// Datapoint configuration
+	allDatapoints bool
+	withUnits     bool
+	scaled        bool
+	// Configuration format
+	configurationFormat string
+	// Batch file with the datapoint addresses
+	batchFile string
+	// Device list configuration
+	withStatus bool
 
 	getCmd = &cobra.Command{
 		Use:   "get",
@@ -36,26 +50,72 @@ var (
 		Use:     "configuration",
 		Aliases: []string{"config", "cfg"},
 		Short:   "Get the configuration from the system access point",
-		Long:    `Retrieve and display the configuration from the free@home system access point.`,
+		Long:    `Retrieve and display the configuration from the free@home system access point. With --format dot, the floors, rooms and devices are rendered as a Graphviz DOT graph.`,
 		RunE:    runGetConfiguration,
 	}
 
 	deviceCmd = &cobra.Command{
-		Use:     "device [serial]",
-		Aliases: []string{"dev"},
-		Short:   "Get a specific device from the system access point",
-		Long:    `Retrieve and display information about a specific device by its serial number.`,
-		Args:    cobra.ExactArgs(1),
-		RunE:    runGetDevice,
+		Use:               "device [serial]",
+		Aliases:           []string{"dev"},
+		Short:             "Get a specific device from the system access point",
+		Long:              `Retrieve and display information about a specific device by its serial number.`,
+		Args:              cobra.MatchAll(cobra.ExactArgs(1), serialArg),
+		RunE:              runGetDevice,
+		ValidArgsFunction: completeDeviceArgs,
 	}
 
 	datapointCmd = &cobra.Command{
-		Use:     "datapoint [serial] [channel] [datapoint]",
-		Aliases: []string{"dp"},
-		Short:   "Get a specific datapoint from the system access point",
-		Long:    `Retrieve and display information about a specific datapoint by its serial number, channel, and datapoint identifier.`,
-		Args:    cobra.ExactArgs(3),
-		RunE:    runGetDatapoint,
+		Use:               "datapoint [serial] [channel] [datapoint]",
+		Aliases:           []string{"dp"},
+		Short:             "Get a specific datapoint from the system access point",
+		Long:              `Retrieve and display information about a specific datapoint by its serial number, channel, and datapoint identifier. With --all, all datapoints of the device are read and displayed as a tree.`,
+		Args:              cobra.MatchAll(datapointArgs, serialArg),
+		RunE:              runGetDatapoint,
+		ValidArgsFunction: completeDatapointArgs,
+	}
+
+	deviceStateCmd = &cobra.Command{
+		Use:               "device-state [serial]",
+		Aliases:           []string{"ds"},
+		Short:             "Get the current values of all datapoints of a device",
+		Long:              `Read and display the current values of all datapoints across all channels of a device. Datapoints that cannot be read are reported without aborting.`,
+		Args:              cobra.MatchAll(cobra.ExactArgs(1), serialArg),
+		RunE:              runGetDeviceState,
+		ValidArgsFunction: completeDeviceArgs,
+	}
+
+	channelsCmd = &cobra.Command{
+		Use:               "channels [serial]",
+		Aliases:           []string{"ch"},
+		Short:             "Get the channels of a device with their datapoints",
+		Long:              `Retrieve and display the channels of a device with their function and input and output datapoint identifiers, without reading the datapoint values.`,
+		Args:              cobra.MatchAll(cobra.ExactArgs(1), serialArg),
+		RunE:              runGetChannels,
+		ValidArgsFunction: completeDeviceArgs,
+	}
+
+	scenesCmd = &cobra.Command{
+		Use:   "scenes",
+		Short: "Get the scenes defined on the system access point",
+		Long:  `Retrieve and display the scenes of the free@home system access point with the serials and channels of their scene actuators, which are used to activate the scenes.`,
+		Args:  cobra.NoArgs,
+		RunE:  runGetScenes,
+	}
+
+	interfacesCmd = &cobra.Command{
+		Use:   "interfaces",
+		Short: "Get the number of devices on each interface",
+		Long:  `Retrieve the configuration of the free@home system access point and display how many devices are on each interface, e.g. TP for the wired bus. This helps to diagnose bus and interface issues.`,
+		Args:  cobra.NoArgs,
+		RunE:  runGetInterfaces,
+	}
+
+	batchCmd = &cobra.Command{
+		Use:   "batch",
+		Short: "Get the values of the datapoints listed in a file",
+		Long:  `Read the datapoints listed in a YAML or JSON file and display their values. The file contains a list of serial/channel/datapoint addresses. Datapoints that cannot be read are reported without aborting.`,
+		Args:  cobra.NoArgs,
+		RunE:  runGetBatch,
 	}
 )
 
@@ -67,6 +127,26 @@ func init() {
 	getCmd.AddCommand(configurationCmd)
 	getCmd.AddCommand(deviceCmd)
 	getCmd.AddCommand(datapointCmd)
+	getCmd.AddCommand(deviceStateCmd)
+	getCmd.AddCommand(channelsCmd)
+	getCmd.AddCommand(batchCmd)
+	getCmd.AddCommand(scenesCmd)
+	getCmd.AddCommand(interfacesCmd)
+
+	// Add device list flags
+	devicelistCmd.Flags().BoolVar(&withStatus, "with-status", false, "Annotate each device with its name and whether it is online or offline, which requires fetching the configuration")
+
+	// Add configuration flags
+	configurationCmd.Flags().StringVar(&configurationFormat, "format", "", "Render the configuration in an alternative format (dot)")
+
+	// Add batch flags
+	batchCmd.Flags().StringVar(&batchFile, "file", "", "YAML or JSON file with the list of serial/channel/datapoint addresses to read")
+	_ = batchCmd.MarkFlagRequired("file")
+
+	// Add datapoint flags
+	datapointCmd.Flags().BoolVar(&allDatapoints, "all", false, "Read all datapoints of the device, only the serial is required")
+	datapointCmd.Flags().BoolVar(&withUnits, "with-units", false, "Append the unit and show the description of the datapoint from the configuration. Only used for text output.")
+	datapointCmd.Flags().BoolVar(&scaled, "scaled", false, "Show the numeric value with the scale from the config file applied, followed by its unit")
 
 	// Add TLS configuration flags
 	getCmd.PersistentFlags().BoolVar(&tlsEnabled, "tls", true, "Enable TLS for connection")
@@ -80,10 +160,13 @@ func init() {
 
 	// Add prettify flag
 	getCmd.PersistentFlags().BoolVar(&prettify, "prettify", false, "Prettify JSON output with indentation. Only used for JSON output.")
+
+	// Add envelope flag
+	getCmd.PersistentFlags().BoolVar(&envelope, "envelope", false, "Wrap JSON output in an envelope with metadata. Only used for JSON output.")
 }
 
 func runGetDeviceList(cmd *cobra.Command, args []string) error {
-	return cli.GetDeviceList(cli.GetCommandConfig{
+	config := cli.GetCommandConfig{
 		CommandConfig: cli.CommandConfig{
 			Viper:         viper.GetViper(),
 			TLSEnabled:    tlsEnabled,
@@ -92,10 +175,25 @@ func runGetDeviceList(cmd *cobra.Command, args []string) error {
 		},
 		OutputFormat: outputFormat,
 		Prettify:     prettify,
-	})
+		Envelope:     envelope,
+	}
+	if withStatus {
+		return cli.GetDeviceListWithStatus(config)
+	}
+	return cli.GetDeviceList(config)
 }
 
 func runGetConfiguration(cmd *cobra.Command, args []string) error {
+	// The format replaces the output format, as it renders the configuration differently
+	format := outputFormat
+	switch configurationFormat {
+	case "":
+	case "dot":
+		format = configurationFormat
+	default:
+		return fmt.Errorf("unsupported configuration format: %s", configurationFormat)
+	}
+
 	return cli.GetConfiguration(cli.GetCommandConfig{
 		CommandConfig: cli.CommandConfig{
 			Viper:         viper.GetViper(),
@@ -103,8 +201,9 @@ func runGetConfiguration(cmd *cobra.Command, args []string) error {
 			SkipTLSVerify: skipTLSVerify,
 			LogLevel:      logLevel,
 		},
-		OutputFormat: outputFormat,
+		OutputFormat: format,
 		Prettify:     prettify,
+		Envelope:     envelope,
 	})
 }
 
@@ -118,11 +217,81 @@ func runGetDevice(cmd *cobra.Command, args []string) error {
 		},
 		OutputFormat: outputFormat,
 		Prettify:     prettify,
+		Envelope:     envelope,
 	}, args[0])
 }
 
+// serialArg validates that the first argument is a well-formed serial, so that obviously malformed serials are rejected
+// locally instead of causing a confusing error of the system access point.
+func serialArg(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && !models.IsValidSerial(args[0]) {
+		return fmt.Errorf("invalid serial %q: expected 12 alphanumeric characters, e.g. ABB7F595EC47", args[0])
+	}
+	return nil
+}
+
+// datapointArgs validates the arguments of the datapoint command, which requires only the serial when all datapoints are read.
+func datapointArgs(cmd *cobra.Command, args []string) error {
+	if allDatapoints {
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+	return cobra.ExactArgs(3)(cmd, args)
+}
+
 func runGetDatapoint(cmd *cobra.Command, args []string) error {
-	return cli.GetDatapoint(cli.GetCommandConfig{
+	config := cli.GetCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    tlsEnabled,
+			SkipTLSVerify: skipTLSVerify,
+			LogLevel:      logLevel,
+		},
+		OutputFormat: outputFormat,
+		Prettify:     prettify,
+		Envelope:     envelope,
+	}
+	if allDatapoints {
+		return cli.GetAllDatapoints(config, args[0])
+	}
+	if scaled {
+		return cli.GetDatapointScaled(config, args[0], args[1], args[2])
+	}
+	if withUnits {
+		return cli.GetDatapointWithUnits(config, args[0], args[1], args[2])
+	}
+	return cli.GetDatapoint(config, args[0], args[1], args[2])
+}
+
+func runGetDeviceState(cmd *cobra.Command, args []string) error {
+	return cli.GetDeviceState(cli.GetCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    tlsEnabled,
+			SkipTLSVerify: skipTLSVerify,
+			LogLevel:      logLevel,
+		},
+		OutputFormat: outputFormat,
+		Prettify:     prettify,
+		Envelope:     envelope,
+	}, args[0])
+}
+
+func runGetChannels(cmd *cobra.Command, args []string) error {
+	return cli.GetChannels(cli.GetCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    tlsEnabled,
+			SkipTLSVerify: skipTLSVerify,
+			LogLevel:      logLevel,
+		},
+		OutputFormat: outputFormat,
+		Prettify:     prettify,
+		Envelope:     envelope,
+	}, args[0])
+}
+
+func runGetBatch(cmd *cobra.Command, args []string) error {
+	return cli.GetBatch(cli.GetCommandConfig{
 		CommandConfig: cli.CommandConfig{
 			Viper:         viper.GetViper(),
 			TLSEnabled:    tlsEnabled,
@@ -131,5 +300,34 @@ func runGetDatapoint(cmd *cobra.Command, args []string) error {
 		},
 		OutputFormat: outputFormat,
 		Prettify:     prettify,
-	}, args[0], args[1], args[2])
+		Envelope:     envelope,
+	}, batchFile)
+}
+
+func runGetScenes(cmd *cobra.Command, args []string) error {
+	return cli.GetScenes(cli.GetCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    tlsEnabled,
+			SkipTLSVerify: skipTLSVerify,
+			LogLevel:      logLevel,
+		},
+		OutputFormat: outputFormat,
+		Prettify:     prettify,
+		Envelope:     envelope,
+	})
+}
+
+func runGetInterfaces(cmd *cobra.Command, args []string) error {
+	return cli.GetInterfaces(cli.GetCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    tlsEnabled,
+			SkipTLSVerify: skipTLSVerify,
+			LogLevel:      logLevel,
+		},
+		OutputFormat: outputFormat,
+		Prettify:     prettify,
+		Envelope:     envelope,
+	})
 }
@@ -32,6 +32,95 @@ func TestMonitorCmdFlags(t *testing.T) {
 	assert.NotNil(t, exponentialBackoffFlag)
 	assert.Equal(t, "true", exponentialBackoffFlag.DefValue)
 
+	// Check heartbeat flag
+	heartbeatFlag := flags.Lookup("heartbeat")
+	assert.NotNil(t, heartbeatFlag)
+	assert.Equal(t, "0s", heartbeatFlag.DefValue)
+
+	// Check capture flag
+	captureFlag := flags.Lookup("capture")
+	assert.NotNil(t, captureFlag)
+	assert.Equal(t, "", captureFlag.DefValue)
+
+	// Check Home Assistant flags
+	homeAssistantFlag := flags.Lookup("homeassistant")
+	assert.NotNil(t, homeAssistantFlag)
+	assert.Equal(t, "", homeAssistantFlag.DefValue)
+	homeAssistantPrefixFlag := flags.Lookup("homeassistant-prefix")
+	assert.NotNil(t, homeAssistantPrefixFlag)
+	assert.Equal(t, "homeassistant", homeAssistantPrefixFlag.DefValue)
+
+	// Check threshold flag
+	thresholdFlag := flags.Lookup("threshold")
+	assert.NotNil(t, thresholdFlag)
+	assert.Equal(t, "stringArray", thresholdFlag.Value.Type())
+
+	// Check mask serials flag
+	maskSerialsFlag := flags.Lookup("mask-serials")
+	assert.NotNil(t, maskSerialsFlag)
+	assert.Equal(t, "false", maskSerialsFlag.DefValue)
+
+	// Check schema flag
+	schemaFlag := flags.Lookup("schema")
+	assert.NotNil(t, schemaFlag)
+	assert.Equal(t, "false", schemaFlag.DefValue)
+
+	// Check unix socket flag
+	unixSocketFlag := flags.Lookup("unix-socket")
+	assert.NotNil(t, unixSocketFlag)
+	assert.Equal(t, "", unixSocketFlag.DefValue)
+
+	// Check server-sent events address flag
+	sseAddrFlag := flags.Lookup("sse-addr")
+	assert.NotNil(t, sseAddrFlag)
+	assert.Equal(t, "", sseAddrFlag.DefValue)
+
+	// Check resolve names flag
+	resolveNamesFlag := flags.Lookup("resolve-names")
+	assert.NotNil(t, resolveNamesFlag)
+	assert.Equal(t, "false", resolveNamesFlag.DefValue)
+
+	// Check sqlite flag
+	sqliteFlag := flags.Lookup("sqlite")
+	assert.NotNil(t, sqliteFlag)
+	assert.Equal(t, "", sqliteFlag.DefValue)
+
+	// Check timing flag
+	timingFlag := flags.Lookup("timing")
+	assert.NotNil(t, timingFlag)
+	assert.Equal(t, "false", timingFlag.DefValue)
+
+	// Check uptime flag
+	uptimeFlag := flags.Lookup("uptime")
+	assert.NotNil(t, uptimeFlag)
+	assert.Equal(t, "false", uptimeFlag.DefValue)
+
+	// Check update hook flags
+	onUpdateFlag := flags.Lookup("on-update")
+	assert.NotNil(t, onUpdateFlag)
+	assert.Equal(t, "", onUpdateFlag.DefValue)
+
+	onUpdateFilterFlag := flags.Lookup("on-update-filter")
+	assert.NotNil(t, onUpdateFilterFlag)
+	assert.Equal(t, "", onUpdateFilterFlag.DefValue)
+
+	onUpdateConcurrencyFlag := flags.Lookup("on-update-concurrency")
+	assert.NotNil(t, onUpdateConcurrencyFlag)
+	assert.Equal(t, "4", onUpdateConcurrencyFlag.DefValue)
+
+	// Check aggregate flags
+	aggregateFlag := flags.Lookup("aggregate")
+	assert.NotNil(t, aggregateFlag)
+	assert.Equal(t, "[]", aggregateFlag.DefValue)
+
+	aggregatePowerFlag := flags.Lookup("aggregate-power")
+	assert.NotNil(t, aggregatePowerFlag)
+	assert.Equal(t, "[]", aggregatePowerFlag.DefValue)
+
+	aggregateIntervalFlag := flags.Lookup("aggregate-interval")
+	assert.NotNil(t, aggregateIntervalFlag)
+	assert.Equal(t, "60", aggregateIntervalFlag.DefValue)
+
 	// Check TLS flags
 	tlsFlag := flags.Lookup("tls")
 	assert.NotNil(t, tlsFlag)
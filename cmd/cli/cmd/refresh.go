@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Inherit common flags from other commands
+	refreshTLSEnabled    bool
+	refreshSkipTLSVerify bool
+	refreshLogLevel      string
+)
+
+var refreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Refresh the configuration of the free@home system access point",
+	Long:  `Retrieve the configuration of the free@home system access point again and summarize the devices of every system access point. The system access point itself is not modified.`,
+	Args:  cobra.NoArgs,
+	RunE:  runRefresh,
+}
+
+func init() {
+	rootCmd.AddCommand(refreshCmd)
+
+	// Add TLS configuration flags
+	refreshCmd.Flags().BoolVar(&refreshTLSEnabled, "tls", true, "Enable TLS for connection")
+	refreshCmd.Flags().BoolVar(&refreshSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	refreshCmd.Flags().StringVar(&refreshLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+}
+
+func runRefresh(cmd *cobra.Command, args []string) error {
+	return cli.Refresh(cli.CommandConfig{
+		Viper:         viper.GetViper(),
+		TLSEnabled:    refreshTLSEnabled,
+		SkipTLSVerify: refreshSkipTLSVerify,
+		LogLevel:      refreshLogLevel,
+	})
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshCmd(t *testing.T) {
+	// Test that refresh command exists
+	assert.NotNil(t, refreshCmd)
+	assert.Equal(t, "refresh", refreshCmd.Use)
+	assert.Equal(t, "Refresh the configuration of the free@home system access point", refreshCmd.Short)
+}
+
+func TestRefreshCmdFlags(t *testing.T) {
+	// Test that refresh command has the expected flags
+	flags := refreshCmd.Flags()
+
+	// Check TLS flags
+	tlsFlag := flags.Lookup("tls")
+	assert.NotNil(t, tlsFlag)
+	assert.Equal(t, "true", tlsFlag.DefValue)
+
+	skipTLSFlag := flags.Lookup("skip-tls-verify")
+	assert.NotNil(t, skipTLSFlag)
+	assert.Equal(t, "false", skipTLSFlag.DefValue)
+
+	// Check log level flag
+	logLevelFlag := flags.Lookup("log-level")
+	assert.NotNil(t, logLevelFlag)
+	assert.Equal(t, "info", logLevelFlag.DefValue)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Logging configuration flag, parsing problems of the replayed frames are logged as warnings
+	replayLogLevel string
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [capture file]",
+	Short: "Replay web socket frames captured by the monitor",
+	Long:  `Feed the raw web socket frames recorded with 'monitor --capture' through the message processing and print the parsed datapoint updates. No connection to the system access point is needed, which makes captures reproducible inputs for bug reports.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	// Add logging configuration flag
+	replayCmd.Flags().StringVar(&replayLogLevel, "log-level", "warn", "Set the log level (debug, info, warn, error)")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	return cli.Replay(cli.ReplayCommandConfig{
+		File:     args[0],
+		LogLevel: replayLogLevel,
+	})
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayCmd(t *testing.T) {
+	// Test that replay command exists
+	assert.NotNil(t, replayCmd)
+	assert.Equal(t, "replay [capture file]", replayCmd.Use)
+	assert.Equal(t, "Replay web socket frames captured by the monitor", replayCmd.Short)
+
+	// Test that exactly one capture file is required
+	assert.Error(t, replayCmd.Args(replayCmd, []string{}))
+	assert.NoError(t, replayCmd.Args(replayCmd, []string{"capture.ndjson"}))
+}
+
+func TestReplayCmdFlags(t *testing.T) {
+	// Check log level flag
+	logLevelFlag := replayCmd.Flags().Lookup("log-level")
+	assert.NotNil(t, logLevelFlag)
+	assert.Equal(t, "warn", logLevelFlag.DefValue)
+}
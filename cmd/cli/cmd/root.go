@@ -3,12 +3,35 @@ package cmd
 import (
 	"github.com/pgerke/freeathome/v2/internal/cli"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   cli.MustExecutableName(),
 	Short: "Interact with ABB free@home devices using the local API",
 	Long:  `A CLI tool to interact with ABB free@home devices using the local API.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Apply FREEATHOME_TLS_ENABLED and FREEATHOME_SKIP_TLS_VERIFY to the flags not set on the command line
+		return cli.ApplyFlagEnvVars(cmd.Flags())
+	},
+}
+
+func init() {
+	// Select the system access point if the configuration of the gateway contains several
+	rootCmd.PersistentFlags().String("sysap-uuid", "", "UUID of the system access point to use if the configuration contains several")
+	_ = viper.BindPFlag("sysap-uuid", rootCmd.PersistentFlags().Lookup("sysap-uuid"))
+
+	// Guarantee that no device state is changed, e.g. in shared monitoring deployments
+	rootCmd.PersistentFlags().Bool("read-only", false, "Reject all write operations, so that no device state can be changed")
+	_ = viper.BindPFlag("read-only", rootCmd.PersistentFlags().Lookup("read-only"))
+
+	// Target a newer local API of future firmware without a new release
+	rootCmd.PersistentFlags().String("api-version", "v1", "Version segment of the local API paths, v followed by digits, e.g. v1 in /fhapi/v1")
+	_ = viper.BindPFlag("api-version", rootCmd.PersistentFlags().Lookup("api-version"))
+
+	// Keep long log lines readable in narrow terminals
+	rootCmd.PersistentFlags().Bool("wrap-log-lines", false, "Wrap the log lines to the width of the terminal")
+	_ = viper.BindPFlag("wrap-log-lines", rootCmd.PersistentFlags().Lookup("wrap-log-lines"))
 }
 
 func Execute() error {
@@ -52,3 +52,19 @@ func TestExecute(t *testing.T) {
 	// This will likely fail since we're not providing proper args, but we're testing it doesn't panic
 	_ = Execute()
 }
+
+// TestRootCommandPersistentFlags tests that the root command has the flags shared by all commands.
+func TestRootCommandPersistentFlags(t *testing.T) {
+	flags := map[string]string{"sysap-uuid": "", "read-only": "false", "api-version": "v1", "wrap-log-lines": "false"}
+
+	for name, defValue := range flags {
+		flag := rootCmd.PersistentFlags().Lookup(name)
+		if flag == nil {
+			t.Errorf("Expected root command to have a '%s' flag", name)
+			continue
+		}
+		if flag.DefValue != defValue {
+			t.Errorf("Expected '%s' flag default to be '%s', got '%s'", name, defValue, flag.DefValue)
+		}
+	}
+}
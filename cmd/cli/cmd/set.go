@@ -8,6 +8,14 @@ import (
 )
 
 var (
+	// Value format of the datapoint value
+	valueFormat string
+
+	// Configuration of the set all command
+	allFunction  string
+	allValue     string
+	allConfirmed bool
+
 	setCmd = &cobra.Command{
 		Use:   "set",
 		Short: "Set data on the free@home system access point",
@@ -15,12 +23,23 @@ var (
 	}
 
 	datapointSetCmd = &cobra.Command{
-		Use:     "datapoint [serial] [channel] [datapoint] [value]",
-		Aliases: []string{"dp"},
-		Short:   "Set a specific datapoint value on the system access point",
-		Long:    `Set the value of a specific datapoint by its serial number, channel, datapoint identifier, and value.`,
-		Args:    cobra.ExactArgs(4),
-		RunE:    runSetDatapoint,
+		Use:               "datapoint [serial] [channel] [datapoint] [value]",
+		Aliases:           []string{"dp"},
+		Short:             "Set a specific datapoint value on the system access point",
+		Long:              `Set the value of a specific datapoint by its serial number, channel, datapoint identifier, and value.`,
+		Args:              cobra.MatchAll(cobra.ExactArgs(4), serialArg),
+		RunE:              runSetDatapoint,
+		ValidArgsFunction: completeDatapointArgs,
+	}
+
+	allSetCmd = &cobra.Command{
+		Use:   "all",
+		Short: "Set the switch datapoint of all devices of a function type",
+		Long: `Set the value of the switch datapoint of every channel of the given function type, e.g. to turn off all lights.
+The matching channels are determined from the configuration. As this affects the entire installation, the command
+must be confirmed with --yes.`,
+		Args: cobra.NoArgs,
+		RunE: runSetAll,
 	}
 )
 
@@ -29,6 +48,17 @@ func init() {
 
 	// Add subcommands
 	setCmd.AddCommand(datapointSetCmd)
+	setCmd.AddCommand(allSetCmd)
+
+	// Add datapoint flags
+	datapointSetCmd.Flags().StringVar(&valueFormat, "value-format", "auto", "Set the format of the value (auto, decimal, hex). In auto mode, values with a 0x prefix are parsed as hex.")
+
+	// Add set all flags
+	allSetCmd.Flags().StringVar(&allFunction, "function", "", "Set the function type of the devices to set (dimmer, switch)")
+	allSetCmd.Flags().StringVar(&allValue, "value", "", "Set the value to write to the switch datapoint of each device")
+	allSetCmd.Flags().BoolVar(&allConfirmed, "yes", false, "Confirm setting the value on all matching devices")
+	_ = allSetCmd.MarkFlagRequired("function")
+	_ = allSetCmd.MarkFlagRequired("value")
 
 	// Add TLS configuration flags
 	setCmd.PersistentFlags().BoolVar(&tlsEnabled, "tls", true, "Enable TLS for connection")
@@ -42,9 +72,17 @@ func init() {
 
 	// Add prettify flag
 	setCmd.PersistentFlags().BoolVar(&prettify, "prettify", false, "Prettify JSON output with indentation. Only used for JSON output.")
+
+	// Add envelope flag
+	setCmd.PersistentFlags().BoolVar(&envelope, "envelope", false, "Wrap JSON output in an envelope with metadata. Only used for JSON output.")
 }
 
 func runSetDatapoint(cmd *cobra.Command, args []string) error {
+	value, err := cli.ParseDatapointValue(args[3], valueFormat)
+	if err != nil {
+		return err
+	}
+
 	return cli.SetDatapoint(cli.SetCommandConfig{
 		CommandConfig: cli.CommandConfig{
 			Viper:         viper.GetViper(),
@@ -54,5 +92,25 @@ func runSetDatapoint(cmd *cobra.Command, args []string) error {
 		},
 		OutputFormat: outputFormat,
 		Prettify:     prettify,
-	}, args[0], args[1], args[2], args[3])
+		Envelope:     envelope,
+	}, args[0], args[1], args[2], value)
+}
+
+func runSetAll(cmd *cobra.Command, args []string) error {
+	return cli.SetAll(cli.SetAllCommandConfig{
+		SetCommandConfig: cli.SetCommandConfig{
+			CommandConfig: cli.CommandConfig{
+				Viper:         viper.GetViper(),
+				TLSEnabled:    tlsEnabled,
+				SkipTLSVerify: skipTLSVerify,
+				LogLevel:      logLevel,
+			},
+			OutputFormat: outputFormat,
+			Prettify:     prettify,
+			Envelope:     envelope,
+		},
+		Function:  allFunction,
+		Value:     allValue,
+		Confirmed: allConfirmed,
+	})
 }
@@ -24,7 +24,7 @@ func TestSetCommand(t *testing.T) {
 
 // TestSetCommandFlags tests that the set command has the expected persistent flags.
 func TestSetCommandFlags(t *testing.T) {
-	expectedFlags := []string{"tls", "skip-tls-verify", "log-level", "output"}
+	expectedFlags := []string{"tls", "skip-tls-verify", "log-level", "output", "prettify", "envelope"}
 
 	for _, expected := range expectedFlags {
 		flag := setCmd.PersistentFlags().Lookup(expected)
@@ -120,7 +120,7 @@ func TestSetCommandFlagDefaults(t *testing.T) {
 
 // TestSetCommandSubcommands tests that the set command has the expected subcommands.
 func TestSetCommandSubcommands(t *testing.T) {
-	expectedSubcommands := []string{"datapoint"}
+	expectedSubcommands := []string{"datapoint", "all"}
 
 	for _, expected := range expectedSubcommands {
 		found := slices.ContainsFunc(setCmd.Commands(), func(cmd *cobra.Command) bool {
@@ -131,3 +131,51 @@ func TestSetCommandSubcommands(t *testing.T) {
 		}
 	}
 }
+
+// TestDatapointSetCommandValueFormatFlag tests the value format flag of the datapoint set command.
+func TestDatapointSetCommandValueFormatFlag(t *testing.T) {
+	flag := datapointSetCmd.Flags().Lookup("value-format")
+	if flag == nil {
+		t.Fatal("Expected datapoint set command to have flag 'value-format'")
+	}
+	if flag.DefValue != "auto" {
+		t.Errorf("Expected value-format default 'auto', got '%s'", flag.DefValue)
+	}
+
+	// Invalid values are rejected before connecting to the system access point
+	valueFormat = "hex"
+	defer func() { valueFormat = "auto" }()
+	err := runSetDatapoint(nil, []string{"serial", "channel", "datapoint", "0xZZ"})
+	if err == nil || err.Error() != "invalid hex value: 0xZZ" {
+		t.Errorf("Expected invalid hex value error, got %v", err)
+	}
+}
+
+// TestAllSetCommandFlags tests the flags of the set all command.
+func TestAllSetCommandFlags(t *testing.T) {
+	for _, name := range []string{"function", "value"} {
+		flag := allSetCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Fatalf("Expected set all command to have flag '%s'", name)
+		}
+		if required := flag.Annotations[cobra.BashCompOneRequiredFlag]; len(required) == 0 || required[0] != "true" {
+			t.Errorf("Expected flag '%s' to be required", name)
+		}
+	}
+
+	if flag := allSetCmd.Flags().Lookup("yes"); flag == nil || flag.DefValue != "false" {
+		t.Error("Expected set all command to have a 'yes' flag defaulting to false")
+	}
+}
+
+// TestRunSetAllRequiresConfirmation tests that the set all command is rejected without --yes.
+func TestRunSetAllRequiresConfirmation(t *testing.T) {
+	allFunction = "switch"
+	allValue = "0"
+	defer func() { allFunction, allValue = "", "" }()
+
+	err := runSetAll(nil, nil)
+	if err == nil || err.Error() != "setting the value of all switch devices requires confirmation with --yes" {
+		t.Errorf("Expected confirmation error, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Inherit common flags from other commands
+	shellTLSEnabled    bool
+	shellSkipTLSVerify bool
+	shellLogLevel      string
+	shellOutputFormat  string
+	shellPrettify      bool
+	shellEnvelope      bool
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run get and set commands interactively using a single connection",
+	Long:  `Start an interactive shell that keeps a single connection to the free@home system access point and runs get and set commands read from the standard input without reconnecting.`,
+	Args:  cobra.NoArgs,
+	RunE:  runShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+
+	// Add TLS configuration flags
+	shellCmd.Flags().BoolVar(&shellTLSEnabled, "tls", true, "Enable TLS for connection")
+	shellCmd.Flags().BoolVar(&shellSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	shellCmd.Flags().StringVar(&shellLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+
+	// Add output configuration flags
+	shellCmd.Flags().StringVar(&shellOutputFormat, "output", "json", "Set the output format (json, text)")
+	shellCmd.Flags().BoolVar(&shellPrettify, "prettify", false, "Prettify JSON output with indentation. Only used for JSON output.")
+	shellCmd.Flags().BoolVar(&shellEnvelope, "envelope", false, "Wrap JSON output in an envelope with metadata. Only used for JSON output.")
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	return cli.Shell(cli.ShellCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    shellTLSEnabled,
+			SkipTLSVerify: shellSkipTLSVerify,
+			LogLevel:      shellLogLevel,
+		},
+		OutputFormat: shellOutputFormat,
+		Prettify:     shellPrettify,
+		Envelope:     shellEnvelope,
+	}, os.Stdin)
+}
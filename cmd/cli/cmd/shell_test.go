@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShellCmd(t *testing.T) {
+	// Test that shell command exists
+	assert.NotNil(t, shellCmd)
+	assert.Equal(t, "shell", shellCmd.Use)
+	assert.Equal(t, "Run get and set commands interactively using a single connection", shellCmd.Short)
+}
+
+func TestShellCmdFlags(t *testing.T) {
+	// Test that shell command has the expected flags
+	flags := shellCmd.Flags()
+
+	for name, expected := range map[string]string{
+		"tls":             "true",
+		"skip-tls-verify": "false",
+		"log-level":       "info",
+		"output":          "json",
+		"prettify":        "false",
+		"envelope":        "false",
+	} {
+		flag := flags.Lookup(name)
+		if assert.NotNil(t, flag, "Expected flag '%s'", name) {
+			assert.Equal(t, expected, flag.DefValue)
+		}
+	}
+}
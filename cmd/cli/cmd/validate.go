@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Inherit common flags from other commands
+	validateTLSEnabled    bool
+	validateSkipTLSVerify bool
+	validateLogLevel      string
+	validateBatchFile     string
+)
+
+var (
+	validateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Validate input files against the free@home system access point",
+		Long:  `Validate input files against the configuration of the free@home system access point without changing any device.`,
+	}
+
+	validateBatchCmd = &cobra.Command{
+		Use:   "batch",
+		Short: "Check that the datapoint addresses of a batch file exist",
+		Long: `Check that every serial/channel/datapoint address listed in a YAML or JSON file exists in the configuration of the system access point.
+The configuration is fetched once and no datapoint is read or written. Unknown addresses are reported with the part that does not exist.`,
+		Args: cobra.NoArgs,
+		RunE: runValidateBatch,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.AddCommand(validateBatchCmd)
+
+	// Add TLS configuration flags
+	validateCmd.PersistentFlags().BoolVar(&validateTLSEnabled, "tls", true, "Enable TLS for connection")
+	validateCmd.PersistentFlags().BoolVar(&validateSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	validateCmd.PersistentFlags().StringVar(&validateLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+
+	// Add batch flags
+	validateBatchCmd.Flags().StringVar(&validateBatchFile, "file", "", "YAML or JSON file with the list of serial/channel/datapoint addresses to validate")
+	_ = validateBatchCmd.MarkFlagRequired("file")
+}
+
+func runValidateBatch(cmd *cobra.Command, args []string) error {
+	return cli.ValidateBatch(cli.CommandConfig{
+		Viper:         viper.GetViper(),
+		TLSEnabled:    validateTLSEnabled,
+		SkipTLSVerify: validateSkipTLSVerify,
+		LogLevel:      validateLogLevel,
+	}, validateBatchFile)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCmd(t *testing.T) {
+	assert.Equal(t, "validate", validateCmd.Use)
+	assert.Contains(t, validateCmd.Commands(), validateBatchCmd)
+	assert.Equal(t, "batch", validateBatchCmd.Use)
+}
+
+func TestValidateCmdFlags(t *testing.T) {
+	flags := validateCmd.PersistentFlags()
+	assert.Equal(t, "true", flags.Lookup("tls").DefValue)
+	assert.Equal(t, "false", flags.Lookup("skip-tls-verify").DefValue)
+	assert.Equal(t, "info", flags.Lookup("log-level").DefValue)
+
+	fileFlag := validateBatchCmd.Flags().Lookup("file")
+	assert.NotNil(t, fileFlag)
+	assert.Equal(t, "", fileFlag.DefValue)
+}
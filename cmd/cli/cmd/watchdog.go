@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/internal/cli"
+)
+
+var (
+	// Watchdog-specific flags
+	watchdogEnforce bool
+	// Inherit common flags from other commands
+	watchdogTLSEnabled    bool
+	watchdogSkipTLSVerify bool
+	watchdogLogLevel      string
+)
+
+var watchdogCmd = &cobra.Command{
+	Use:   "watchdog serial.channel.datapoint=expected...",
+	Short: "Exit with an error if a datapoint leaves its expected value",
+	Long:  `Watch the given datapoints via WebSocket and exit with an error as soon as one of them leaves its expected value, e.g. to trigger an alert. With --enforce a deviating datapoint is set back to its expected value instead, the watchdog only exits with an error if that fails.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runWatchdog,
+}
+
+func init() {
+	rootCmd.AddCommand(watchdogCmd)
+
+	// Add watchdog-specific flags
+	watchdogCmd.Flags().BoolVar(&watchdogEnforce, "enforce", false, "Set a deviating datapoint back to its expected value instead of exiting")
+
+	// Add TLS configuration flags
+	watchdogCmd.Flags().BoolVar(&watchdogTLSEnabled, "tls", true, "Enable TLS for connection")
+	watchdogCmd.Flags().BoolVar(&watchdogSkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate verification")
+
+	// Add logging configuration flag
+	watchdogCmd.Flags().StringVar(&watchdogLogLevel, "log-level", "info", "Set the log level (debug, info, warn, error)")
+}
+
+func runWatchdog(cmd *cobra.Command, args []string) error {
+	return cli.Watchdog(cli.WatchdogCommandConfig{
+		CommandConfig: cli.CommandConfig{
+			Viper:         viper.GetViper(),
+			TLSEnabled:    watchdogTLSEnabled,
+			SkipTLSVerify: watchdogSkipTLSVerify,
+			LogLevel:      watchdogLogLevel,
+		},
+		Enforce: watchdogEnforce,
+	}, args)
+}
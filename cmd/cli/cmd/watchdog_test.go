@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdogCmd(t *testing.T) {
+	// Test that watchdog command exists
+	assert.NotNil(t, watchdogCmd)
+	assert.Equal(t, "watchdog serial.channel.datapoint=expected...", watchdogCmd.Use)
+	assert.Equal(t, "Exit with an error if a datapoint leaves its expected value", watchdogCmd.Short)
+	assert.Error(t, watchdogCmd.Args(watchdogCmd, []string{}))
+}
+
+func TestWatchdogCmdFlags(t *testing.T) {
+	// Test that watchdog command has the expected flags
+	flags := watchdogCmd.Flags()
+
+	// Check enforce flag
+	enforceFlag := flags.Lookup("enforce")
+	assert.NotNil(t, enforceFlag)
+	assert.Equal(t, "false", enforceFlag.DefValue)
+
+	// Check TLS flags
+	tlsFlag := flags.Lookup("tls")
+	assert.NotNil(t, tlsFlag)
+	assert.Equal(t, "true", tlsFlag.DefValue)
+
+	skipTLSFlag := flags.Lookup("skip-tls-verify")
+	assert.NotNil(t, skipTLSFlag)
+	assert.Equal(t, "false", skipTLSFlag.DefValue)
+
+	// Check log level flag
+	logLevelFlag := flags.Lookup("log-level")
+	assert.NotNil(t, logLevelFlag)
+	assert.Equal(t, "info", logLevelFlag.DefValue)
+}
@@ -3,11 +3,13 @@
 package integration
 
 import (
+	"bufio"
 	"io"
 	"net"
 	"net/http"
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 	"time"
 
@@ -186,6 +188,74 @@ func TestMonitorSuccessfulRunInterrupt(t *testing.T) {
 	}
 }
 
+// TestMonitorSuccessfulRunInterruptStdinOpen tests that an interrupt shuts the monitor down while stdin is open and no key was pressed.
+func TestMonitorSuccessfulRunInterruptStdinOpen(t *testing.T) {
+	// Set up the test server
+	addr, shutdown := startTestWebSocketServer(t)
+	defer shutdown()
+
+	// Run the monitor
+	run := exec.Command(
+		bin,
+		"monitor",
+		"--log-level=debug",
+		"--tls=false",
+	)
+
+	run.Env = append(os.Environ(),
+		"GOCOVERDIR="+coverageDirectory,
+		"FREEATHOME_HOSTNAME="+addr,
+		"FREEATHOME_USERNAME=admin",
+		"FREEATHOME_PASSWORD=password",
+	)
+
+	// Set up pipes for output, stdin stays open without input until the process exits
+	var stdout, stderr io.ReadCloser
+	var err error
+	if _, err = run.StdinPipe(); err != nil {
+		t.Fatalf("could not get stdin pipe: %v", err)
+	}
+	stdout, err = run.StdoutPipe()
+	if err != nil {
+		t.Fatalf("could not get stdout pipe: %v", err)
+	}
+	stderr, err = run.StderrPipe()
+	if err != nil {
+		t.Fatalf("could not get stderr pipe: %v", err)
+	}
+	go io.Copy(t.Output(), stdout)
+	go io.Copy(t.Output(), stderr)
+
+	// Start the monitor
+	if err := run.Start(); err != nil {
+		t.Fatalf("could not start monitor: %v", err)
+	}
+
+	t.Logf("Waiting for monitor to connect...")
+	time.Sleep(500 * time.Millisecond) // Allow some time for the monitor to connect
+
+	// Send an interrupt signal to the monitor process
+	if err := run.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("could not send interrupt signal: %v", err)
+	}
+
+	// Wait for the monitor to finish, a reader blocked on stdin must not hold up the shutdown
+	exited := make(chan error, 1)
+	go func() {
+		exited <- run.Wait()
+	}()
+	select {
+	case err = <-exited:
+	case <-time.After(2 * time.Second):
+		_ = run.Process.Kill()
+		t.Fatal("monitor did not shut down while stdin was open")
+	}
+
+	if err != nil {
+		t.Errorf("expected exit code 0, got: %v", err)
+	}
+}
+
 // TestMonitorSuccessfulRunForcedExit tests that the monitor runs successfully when the user presses the 'q' key.
 func TestMonitorSuccessfulRunForcedExit(t *testing.T) {
 	// Set up the test server
@@ -259,8 +329,103 @@ func TestMonitorSuccessfulRunForcedExit(t *testing.T) {
 	}
 }
 
+// TestMonitorRetriesUnreachableSysAp tests that the monitor reports the failed connection attempts with a friendly
+// message and connects once the system access point becomes reachable.
+func TestMonitorRetriesUnreachableSysAp(t *testing.T) {
+	// Reserve an address, the server is only started after the first connection attempt failed
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	// Run the monitor
+	run := exec.Command(
+		bin,
+		"monitor",
+		"--tls=false",
+	)
+
+	run.Env = append(os.Environ(),
+		"GOCOVERDIR="+coverageDirectory,
+		"FREEATHOME_HOSTNAME="+addr,
+		"FREEATHOME_USERNAME=admin",
+		"FREEATHOME_PASSWORD=password",
+	)
+
+	stdin, err := run.StdinPipe()
+	if err != nil {
+		t.Fatalf("could not get stdin pipe: %v", err)
+	}
+	stdout, err := run.StdoutPipe()
+	if err != nil {
+		t.Fatalf("could not get stdout pipe: %v", err)
+	}
+	run.Stderr = t.Output()
+
+	// Collect the output lines
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			t.Log(scanner.Text())
+			lines <- scanner.Text()
+		}
+	}()
+	waitForLine := func(prefix string) {
+		t.Helper()
+		timeout := time.After(3 * time.Second)
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatalf("monitor exited before printing %q", prefix)
+				}
+				if strings.HasPrefix(line, prefix) {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for %q", prefix)
+			}
+		}
+	}
+
+	// Start the monitor
+	if err := run.Start(); err != nil {
+		t.Fatalf("could not start monitor: %v", err)
+	}
+
+	waitForLine("Connecting to " + addr + ", will retry")
+	waitForLine("Could not connect to " + addr + " (attempt 1 of 3), retrying...")
+
+	// Make the system access point reachable during the backoff
+	_, shutdown := startTestWebSocketServerOn(t, addr)
+	defer shutdown()
+	waitForLine("Connected to " + addr)
+
+	// Send 'q' keypress to trigger graceful shutdown
+	if _, err := stdin.Write([]byte("q\n")); err != nil {
+		t.Errorf("could not send 'q' keypress: %v", err)
+	}
+	stdin.Close()
+	for range lines {
+	}
+
+	if err := run.Wait(); err != nil {
+		t.Errorf("expected exit code 0, got %v", err)
+	}
+}
+
 // startTestWebSocketServer starts a test WebSocket server that sends an empty message every second.
 func startTestWebSocketServer(t *testing.T) (addr string, shutdown func()) {
+	t.Helper()
+	return startTestWebSocketServerOn(t, "localhost:0")
+}
+
+// startTestWebSocketServerOn starts the test WebSocket server on the given address.
+func startTestWebSocketServerOn(t *testing.T, listenAddr string) (addr string, shutdown func()) {
 	t.Helper()
 	upgrader := websocket.Upgrader{}
 
@@ -292,8 +457,8 @@ func startTestWebSocketServer(t *testing.T) (addr string, shutdown func()) {
 		}
 	})
 
-	// Listen on a random port
-	ln, err := net.Listen("tcp", "localhost:0")
+	// Listen on the given address
+	ln, err := net.Listen("tcp", listenAddr)
 	if err != nil {
 		t.Fatalf("Failed to listen: %v", err)
 	}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// runningStats maintains the running statistics of the numeric values of a datapoint
+type runningStats struct {
+	// Count is the number of values
+	Count int
+	// Min is the smallest value
+	Min float64
+	// Max is the largest value
+	Max float64
+	// Sum is the sum of all values
+	Sum float64
+	// Energy is the time integral of the values in value hours, e.g. Wh for a power in W. Each value is held until the next value arrives.
+	Energy float64
+	// last is the most recent value
+	last float64
+	// lastTimestamp is the timestamp of the most recent value
+	lastTimestamp time.Time
+}
+
+// add adds a timestamped value to the statistics. Values older than the most recent value are not integrated.
+func (s *runningStats) add(timestamp time.Time, value float64) {
+	if s.Count == 0 {
+		s.Min, s.Max = value, value
+	} else {
+		s.Min = min(s.Min, value)
+		s.Max = max(s.Max, value)
+		if elapsed := timestamp.Sub(s.lastTimestamp); elapsed > 0 {
+			s.Energy += s.last * elapsed.Hours()
+		}
+	}
+
+	s.Count++
+	s.Sum += value
+	if s.Count == 1 || !timestamp.Before(s.lastTimestamp) {
+		s.last = value
+		s.lastTimestamp = timestamp
+	}
+}
+
+// Average returns the average of all values, zero if there are none
+func (s *runningStats) Average() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// datapointAggregator maintains the running statistics of the configured datapoints, the energy is only reported for power datapoints
+type datapointAggregator struct {
+	keys  []models.DatapointKey
+	power map[models.DatapointKey]bool
+	stats map[models.DatapointKey]*runningStats
+	mutex sync.Mutex
+}
+
+// newDatapointAggregator creates an aggregator for the datapoints and the power datapoints, given as serial/channel/datapoint keys.
+// The keys are normalized, so that they match the keys of received updates regardless of their case.
+func newDatapointAggregator(datapoints, power []string) (*datapointAggregator, error) {
+	aggregator := &datapointAggregator{
+		power: map[models.DatapointKey]bool{},
+		stats: map[models.DatapointKey]*runningStats{},
+	}
+
+	for i, value := range append(append([]string{}, datapoints...), power...) {
+		key, err := models.ParseDatapointKey(value)
+		if err != nil {
+			return nil, err
+		}
+		key = key.Normalize()
+		if i >= len(datapoints) {
+			aggregator.power[key] = true
+		}
+		if _, exists := aggregator.stats[key]; !exists {
+			aggregator.keys = append(aggregator.keys, key)
+			aggregator.stats[key] = &runningStats{}
+		}
+	}
+
+	return aggregator, nil
+}
+
+// handle adds the value of the update to the statistics of its datapoint. Updates of other datapoints and non-numeric values are ignored.
+func (a *datapointAggregator) handle(update models.DatapointUpdate) {
+	key := models.DatapointKey{Serial: update.Serial, Channel: update.Channel, Datapoint: update.Datapoint}
+	value, err := strconv.ParseFloat(strings.TrimSpace(update.Value), 64)
+	if err != nil {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if stats, exists := a.stats[key]; exists {
+		stats.add(update.Timestamp, value)
+	}
+}
+
+// summary formats the statistics with one line per datapoint in the configured order
+func (a *datapointAggregator) summary() string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	lines := make([]string, 0, len(a.keys))
+	for _, key := range a.keys {
+		stats := a.stats[key]
+		if stats.Count == 0 {
+			lines = append(lines, fmt.Sprintf("%s: no values", key))
+			continue
+		}
+
+		line := fmt.Sprintf("%s: count=%d min=%g max=%g avg=%.2f", key, stats.Count, stats.Min, stats.Max, stats.Average())
+		if a.power[key] {
+			line += fmt.Sprintf(" energy=%.2fWh", stats.Energy)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
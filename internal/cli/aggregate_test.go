@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestRunningStatsAdd tests the running min, max and average over a sequence of values
+func TestRunningStatsAdd(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	stats := runningStats{}
+
+	for i, value := range []float64{20, 5, 35, 20} {
+		stats.add(start.Add(time.Duration(i)*time.Minute), value)
+	}
+
+	if stats.Count != 4 {
+		t.Errorf("Expected count 4, got %d", stats.Count)
+	}
+	if stats.Min != 5 || stats.Max != 35 {
+		t.Errorf("Expected min 5 and max 35, got %g and %g", stats.Min, stats.Max)
+	}
+	if stats.Average() != 20 {
+		t.Errorf("Expected average 20, got %g", stats.Average())
+	}
+}
+
+// TestRunningStatsAverageEmpty tests that the average of no values is zero
+func TestRunningStatsAverageEmpty(t *testing.T) {
+	stats := runningStats{}
+	if stats.Average() != 0 {
+		t.Errorf("Expected average 0, got %g", stats.Average())
+	}
+}
+
+// TestRunningStatsEnergy tests the time integration, each value is held until the next value arrives
+func TestRunningStatsEnergy(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		offset   time.Duration
+		value    float64
+		expected float64
+	}{
+		{offset: 0, value: 100, expected: 0},
+		{offset: 30 * time.Minute, value: 200, expected: 50},
+		{offset: 45 * time.Minute, value: 0, expected: 100},
+		{offset: 2 * time.Hour, value: 60, expected: 100},
+		// Values older than the most recent value are counted but not integrated
+		{offset: time.Hour, value: 1000, expected: 100},
+		{offset: 3 * time.Hour, value: 60, expected: 160},
+	}
+
+	stats := runningStats{}
+	for i, tt := range tests {
+		stats.add(start.Add(tt.offset), tt.value)
+		if math.Abs(stats.Energy-tt.expected) > 1e-9 {
+			t.Errorf("Value %d: expected energy %g, got %g", i, tt.expected, stats.Energy)
+		}
+	}
+}
+
+// TestDatapointAggregatorSummary tests that only configured numeric datapoints are aggregated and the energy is only shown for power datapoints
+func TestDatapointAggregatorSummary(t *testing.T) {
+	aggregator, err := newDatapointAggregator([]string{"ABB7F595EC47/ch0000/odp0001", "ABB7F595EC47/ch0000/odp0002"}, []string{"ABB7F595EC47/ch0001/odp0004"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	update := func(channel, datapoint, value string, offset time.Duration) models.DatapointUpdate {
+		return models.DatapointUpdate{Timestamp: start.Add(offset), Serial: "ABB7F595EC47", Channel: channel, Datapoint: datapoint, Value: value}
+	}
+	aggregator.handle(update("ch0000", "odp0001", "21.5", 0))
+	aggregator.handle(update("ch0000", "odp0001", "22.5", time.Minute))
+	aggregator.handle(update("ch0000", "odp0001", "on", 2*time.Minute))
+	aggregator.handle(update("ch0000", "odp0003", "100", 2*time.Minute))
+	aggregator.handle(update("ch0001", "odp0004", "1000", 0))
+	aggregator.handle(update("ch0001", "odp0004", "500", 30*time.Minute))
+
+	expected := "ABB7F595EC47/ch0000/odp0001: count=2 min=21.5 max=22.5 avg=22.00\n" +
+		"ABB7F595EC47/ch0000/odp0002: no values\n" +
+		"ABB7F595EC47/ch0001/odp0004: count=2 min=500 max=1000 avg=750.00 energy=500.00Wh"
+	if actual := aggregator.summary(); actual != expected {
+		t.Errorf("Expected summary:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+// TestDatapointAggregatorNormalizesKeys tests that datapoint and power keys given in any case match the received updates
+func TestDatapointAggregatorNormalizesKeys(t *testing.T) {
+	aggregator, err := newDatapointAggregator([]string{"abb7f595ec47/CH0000/ODP0001"}, []string{"abb7f595ec47/CH0001/ODP0004", "ABB7F595EC47/ch0001/odp0004"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	aggregator.handle(models.DatapointUpdate{Timestamp: start, Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001", Value: "21.5"})
+	aggregator.handle(models.DatapointUpdate{Timestamp: start, Serial: "ABB7F595EC47", Channel: "ch0001", Datapoint: "odp0004", Value: "1000"})
+	aggregator.handle(models.DatapointUpdate{Timestamp: start.Add(30 * time.Minute), Serial: "ABB7F595EC47", Channel: "ch0001", Datapoint: "odp0004", Value: "500"})
+
+	expected := "ABB7F595EC47/ch0000/odp0001: count=1 min=21.5 max=21.5 avg=21.50\n" +
+		"ABB7F595EC47/ch0001/odp0004: count=2 min=500 max=1000 avg=750.00 energy=500.00Wh"
+	if actual := aggregator.summary(); actual != expected {
+		t.Errorf("Expected summary:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+// TestNewDatapointAggregatorInvalidKey tests that invalid datapoint keys are rejected
+func TestNewDatapointAggregatorInvalidKey(t *testing.T) {
+	if _, err := newDatapointAggregator([]string{"ABB7F595EC47/ch0000"}, nil); err == nil {
+		t.Error("Expected error for invalid datapoint key, got nil")
+	}
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+	"go.yaml.in/yaml/v3"
+)
+
+// batchConcurrency is the maximum number of datapoints that are read concurrently by the batch command
+const batchConcurrency = 4
+
+// batchReadResult contains the values of a datapoint read by the batch command or the error that occurred while reading it
+type batchReadResult struct {
+	Address string   `json:"address"`
+	Values  []string `json:"values,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// GetBatch reads the datapoints listed in a YAML or JSON file and displays their values.
+// It returns an error after the output if any datapoint could not be read.
+func GetBatch(config GetCommandConfig, file string) error {
+	addresses, err := loadBatchAddresses(file)
+	if err != nil {
+		return err
+	}
+
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	results := readBatch(sysAp, addresses)
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		if err := outputCommandJSON(results, "batch results", config.Prettify, config.Envelope, sysAp.GetHostName(), "get batch"); err != nil {
+			return err
+		}
+	} else {
+		printBatchResults(results)
+	}
+
+	// Report failed datapoints after the output, so that the successfully read values are not lost
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to read %d of %d datapoints", failed, len(results))
+	}
+
+	return nil
+}
+
+// loadBatchAddresses loads the list of datapoint addresses in the format "serial/channel/datapoint" from a YAML or JSON file
+func loadBatchAddresses(file string) ([]string, error) {
+	return loadAddressFile(file, "batch file")
+}
+
+// loadAddressFile loads a list of datapoint addresses from a YAML or JSON file, the name describes the file in errors
+func loadAddressFile(file string, name string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	// JSON is a subset of YAML, so both formats are parsed by the YAML parser
+	var addresses []string
+	if err := yaml.Unmarshal(data, &addresses); err != nil {
+		return nil, fmt.Errorf("failed to parse %s, expected a list of serial/channel/datapoint addresses: %w", name, err)
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s contains no datapoint addresses", name)
+	}
+
+	return addresses, nil
+}
+
+// readBatch reads the datapoints with bounded concurrency. Invalid addresses and read errors are recorded per address
+// and do not abort reading the remaining datapoints. The results are in the order of the addresses.
+func readBatch(sysAp *freeathome.SystemAccessPoint, addresses []string) []batchReadResult {
+	results := make([]batchReadResult, len(addresses))
+
+	// Read the datapoints, each worker writes only to its own index
+	var waitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, batchConcurrency)
+	for i, address := range addresses {
+		results[i].Address = address
+		key, err := models.ParseDatapointKey(address)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+		go func(result *batchReadResult) {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+
+			response, err := sysAp.GetDatapoint(key.Serial, key.Channel, key.Datapoint)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			if response != nil {
+				datapoint, _ := response.DataPoint()
+				result.Values = datapoint.Values
+			}
+		}(&results[i])
+	}
+	waitGroup.Wait()
+
+	return results
+}
+
+// batchWriteResult contains the datapoint written by the batch mechanism or the error that occurred while writing it
+type batchWriteResult struct {
+	Address string `json:"address"`
+	Error   string `json:"error,omitempty"`
+}
+
+// writeBatch writes the value to the datapoints with bounded concurrency. Invalid addresses and write errors are recorded
+// per address and do not abort writing the remaining datapoints. The results are in the order of the addresses.
+func writeBatch(sysAp *freeathome.SystemAccessPoint, addresses []string, value string) []batchWriteResult {
+	results := make([]batchWriteResult, len(addresses))
+
+	// Write the datapoints, each worker writes only to its own index
+	var waitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, batchConcurrency)
+	for i, address := range addresses {
+		results[i].Address = address
+		key, err := models.ParseDatapointKey(address)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+		go func(result *batchWriteResult) {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+
+			if _, err := sysAp.SetDatapoint(key.Serial, key.Channel, key.Datapoint, value); err != nil {
+				result.Error = err.Error()
+			}
+		}(&results[i])
+	}
+	waitGroup.Wait()
+
+	return results
+}
+
+// printBatchResults prints the batch results as a table of addresses and values
+func printBatchResults(results []batchReadResult) {
+	width := len("ADDRESS")
+	for _, result := range results {
+		width = max(width, len(result.Address))
+	}
+
+	fmt.Printf("%-*s  %s\n", width, "ADDRESS", "VALUE")
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Printf("%-*s  error: %s\n", width, result.Address, result.Error)
+		case len(result.Values) > 0:
+			fmt.Printf("%-*s  %v\n", width, result.Address, result.Values)
+		default:
+			fmt.Printf("%-*s  (empty)\n", width, result.Address)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// batchFixture is the path of the batch fixture
+var batchFixture = filepath.Join("..", "..", "testdata", "batch_reads.yaml")
+
+// mockBatchSetup mocks the system access point, the datapoints of the device ABB7013B85DE cannot be read
+func mockBatchSetup(t *testing.T) *pathRoundTripper {
+	t.Helper()
+
+	transport := &pathRoundTripper{responses: []pathResponse{
+		{"GET /ABB7F595EC47.ch0000.odp0000", `{"00000000-0000-0000-0000-000000000000":{"values":["1"]}}`},
+		{"GET /ABB7F595EC47.ch0000.idp0000", `{"00000000-0000-0000-0000-000000000000":{"values":["0"]}}`},
+	}}
+	setupPathMock(t, transport)
+	return transport
+}
+
+// TestGetBatchText tests that all addresses of the fixture are read and errors are reported without aborting
+func TestGetBatchText(t *testing.T) {
+	transport := mockBatchSetup(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetBatch(GetCommandConfig{OutputFormat: "text"}, batchFixture)
+	})
+	if err == nil || err.Error() != "failed to read 2 of 4 datapoints" {
+		t.Errorf("Expected error for 2 failed datapoints, got %v", err)
+	}
+
+	// The invalid address is not requested
+	if len(transport.requests) != 3 {
+		t.Errorf("Expected 3 requests, got %v", transport.requests)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected a header and 4 results, got:\n%s", output)
+	}
+	expectedPrefixes := []string{
+		"ADDRESS                      VALUE",
+		"ABB7F595EC47/ch0000/odp0000  [1]",
+		"ABB7F595EC47/ch0000/idp0000  [0]",
+		"ABB7013B85DE/ch0001/odp0001  error: failed to get datapoint",
+		"invalid-address              error: invalid datapoint key",
+	}
+	for i, expected := range expectedPrefixes {
+		if !strings.HasPrefix(lines[i], expected) {
+			t.Errorf("Expected line %d to start with '%s', got '%s'", i, expected, lines[i])
+		}
+	}
+}
+
+// TestGetBatchJSON tests that the results are printed as JSON in the order of the addresses
+func TestGetBatchJSON(t *testing.T) {
+	mockBatchSetup(t)
+
+	// JSON input is supported as well
+	file := filepath.Join(t.TempDir(), "reads.json")
+	if err := os.WriteFile(file, []byte(`["ABB7F595EC47/ch0000/odp0000", "ABB7013B85DE/ch0001/odp0001"]`), 0644); err != nil {
+		t.Fatalf("Failed to write batch file: %v", err)
+	}
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetBatch(GetCommandConfig{OutputFormat: "json"}, file)
+	})
+	if err == nil {
+		t.Error("Expected error for the failed datapoint")
+	}
+
+	var results []batchReadResult
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Address != "ABB7F595EC47/ch0000/odp0000" || len(results[0].Values) != 1 || results[0].Values[0] != "1" || results[0].Error != "" {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if results[1].Address != "ABB7013B85DE/ch0001/odp0001" || results[1].Error == "" {
+		t.Errorf("Expected an error for the second result, got %+v", results[1])
+	}
+}
+
+// TestGetBatchInvalidFile tests that missing, malformed and empty batch files are rejected before connecting
+func TestGetBatchInvalidFile(t *testing.T) {
+	setupCalls := setupPathMock(t, &pathRoundTripper{})
+	dir := t.TempDir()
+
+	tests := map[string]string{
+		"missing":   "",
+		"malformed": "serial: ABB7F595EC47",
+		"empty":     "[]",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(dir, name+".yaml")
+			if content != "" {
+				if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write batch file: %v", err)
+				}
+			}
+
+			if err := GetBatch(GetCommandConfig{}, file); err == nil {
+				t.Error("Expected error for invalid batch file")
+			}
+		})
+	}
+
+	if *setupCalls != 0 {
+		t.Errorf("Expected no setup calls, got %d", *setupCalls)
+	}
+}
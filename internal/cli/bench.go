@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BenchCommandConfig is a struct that contains the configuration for the bench command
+type BenchCommandConfig struct {
+	CommandConfig
+	Requests    int
+	Concurrency int
+}
+
+// latencyStats contains the aggregated latencies and the error rate of a benchmark
+type latencyStats struct {
+	Requests  int
+	Errors    int
+	ErrorRate float64
+	Min       time.Duration
+	Avg       time.Duration
+	P95       time.Duration
+	Max       time.Duration
+}
+
+// Bench repeatedly requests the device list from the system access point and reports the response times
+func Bench(config BenchCommandConfig) error {
+	if config.Requests < 1 {
+		return fmt.Errorf("number of requests must be at least 1, got %d", config.Requests)
+	}
+	if config.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1, got %d", config.Concurrency)
+	}
+
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Benchmarking %s with %d requests (concurrency %d)\n", sysAp.GetHostName(), config.Requests, config.Concurrency)
+
+	// Send the requests, each worker writes only to its own index
+	durations := make([]time.Duration, config.Requests)
+	failed := make([]bool, config.Requests)
+	var waitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, config.Concurrency)
+	for i := range config.Requests {
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+		go func(index int) {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+
+			start := time.Now()
+			_, err := sysAp.GetDeviceList()
+			durations[index] = time.Since(start)
+			failed[index] = err != nil
+		}(i)
+	}
+	waitGroup.Wait()
+
+	// Only successful requests are considered for the latencies
+	var successful []time.Duration
+	for i, duration := range durations {
+		if !failed[i] {
+			successful = append(successful, duration)
+		}
+	}
+
+	printLatencyStats(computeLatencyStats(successful, config.Requests-len(successful)))
+	return nil
+}
+
+// computeLatencyStats aggregates the durations of successful requests and the number of failed requests.
+// The 95th percentile is determined using the nearest-rank method.
+func computeLatencyStats(durations []time.Duration, errors int) latencyStats {
+	stats := latencyStats{
+		Requests: len(durations) + errors,
+		Errors:   errors,
+	}
+	if stats.Requests > 0 {
+		stats.ErrorRate = float64(errors) / float64(stats.Requests)
+	}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	sorted := slices.Sorted(slices.Values(durations))
+	var total time.Duration
+	for _, duration := range sorted {
+		total += duration
+	}
+
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Avg = total / time.Duration(len(sorted))
+	stats.P95 = sorted[(len(sorted)*95+99)/100-1]
+	return stats
+}
+
+// printLatencyStats prints the benchmark statistics as plain text
+func printLatencyStats(stats latencyStats) {
+	fmt.Printf("Requests: %d\n", stats.Requests)
+	fmt.Printf("Errors: %d (%.1f%%)\n", stats.Errors, stats.ErrorRate*100)
+	if stats.Errors == stats.Requests {
+		fmt.Println("Latency: (no successful requests)")
+		return
+	}
+	fmt.Printf("Latency: min %s, avg %s, p95 %s, max %s\n", stats.Min, stats.Avg, stats.P95, stats.Max)
+}
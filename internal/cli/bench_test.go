@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// TestComputeLatencyStats tests the statistics computed from a synthetic set of durations
+func TestComputeLatencyStats(t *testing.T) {
+	durations := make([]time.Duration, 0, 20)
+	for i := 20; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := computeLatencyStats(durations, 5)
+
+	if stats.Requests != 25 {
+		t.Errorf("Expected 25 requests, got %d", stats.Requests)
+	}
+	if stats.Errors != 5 {
+		t.Errorf("Expected 5 errors, got %d", stats.Errors)
+	}
+	if stats.ErrorRate != 0.2 {
+		t.Errorf("Expected error rate 0.2, got %f", stats.ErrorRate)
+	}
+	if stats.Min != time.Millisecond {
+		t.Errorf("Expected min 1ms, got %s", stats.Min)
+	}
+	if stats.Max != 20*time.Millisecond {
+		t.Errorf("Expected max 20ms, got %s", stats.Max)
+	}
+	if stats.Avg != 10500*time.Microsecond {
+		t.Errorf("Expected avg 10.5ms, got %s", stats.Avg)
+	}
+	if stats.P95 != 19*time.Millisecond {
+		t.Errorf("Expected p95 19ms, got %s", stats.P95)
+	}
+}
+
+// TestComputeLatencyStatsSingleDuration tests that a single duration is used for all latencies
+func TestComputeLatencyStatsSingleDuration(t *testing.T) {
+	stats := computeLatencyStats([]time.Duration{42 * time.Millisecond}, 0)
+
+	for name, value := range map[string]time.Duration{"min": stats.Min, "avg": stats.Avg, "p95": stats.P95, "max": stats.Max} {
+		if value != 42*time.Millisecond {
+			t.Errorf("Expected %s 42ms, got %s", name, value)
+		}
+	}
+	if stats.ErrorRate != 0 {
+		t.Errorf("Expected error rate 0, got %f", stats.ErrorRate)
+	}
+}
+
+// TestComputeLatencyStatsOnlyErrors tests the statistics when no request succeeded
+func TestComputeLatencyStatsOnlyErrors(t *testing.T) {
+	stats := computeLatencyStats(nil, 3)
+
+	if stats.Requests != 3 || stats.Errors != 3 || stats.ErrorRate != 1 {
+		t.Errorf("Expected 3 failed requests with error rate 1, got %+v", stats)
+	}
+	if stats.Min != 0 || stats.Avg != 0 || stats.P95 != 0 || stats.Max != 0 {
+		t.Errorf("Expected zero latencies, got %+v", stats)
+	}
+}
+
+// TestComputeLatencyStatsEmpty tests the statistics without any requests
+func TestComputeLatencyStatsEmpty(t *testing.T) {
+	stats := computeLatencyStats(nil, 0)
+
+	if stats != (latencyStats{}) {
+		t.Errorf("Expected empty statistics, got %+v", stats)
+	}
+}
+
+// TestBench tests that the benchmark sends the requested number of requests and reports the statistics
+func TestBench(t *testing.T) {
+	transport := &pathRoundTripper{responses: []pathResponse{
+		{"GET /devicelist", `{"00000000-0000-0000-0000-000000000000":["ABB7F595EC47"]}`},
+	}}
+	setupPathMock(t, transport)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = Bench(BenchCommandConfig{Requests: 10, Concurrency: 3})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(transport.requests) != 10 {
+		t.Errorf("Expected 10 requests, got %d", len(transport.requests))
+	}
+	for _, expected := range []string{"Requests: 10", "Errors: 0 (0.0%)", "Latency: min "} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got '%s'", expected, output)
+		}
+	}
+}
+
+// TestBenchErrors tests that failed requests are reported in the error rate
+func TestBenchErrors(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = Bench(BenchCommandConfig{Requests: 2, Concurrency: 1})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"Errors: 2 (100.0%)", "Latency: (no successful requests)"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got '%s'", expected, output)
+		}
+	}
+}
+
+// TestBenchInvalidConfig tests that invalid request counts and concurrency are rejected
+func TestBenchInvalidConfig(t *testing.T) {
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		t.Fatal("Expected no setup for an invalid configuration")
+		return nil, nil
+	}
+	t.Cleanup(func() {
+		setupFunc = setup
+	})
+
+	if err := Bench(BenchCommandConfig{Requests: 0, Concurrency: 1}); err == nil {
+		t.Error("Expected error for zero requests")
+	}
+	if err := Bench(BenchCommandConfig{Requests: 1, Concurrency: 0}); err == nil {
+		t.Error("Expected error for zero concurrency")
+	}
+}
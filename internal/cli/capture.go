@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// captureRecord is a raw web socket frame recorded by the monitor, written as a single line of newline delimited JSON
+type captureRecord struct {
+	// Timestamp is the time the frame was received
+	Timestamp time.Time `json:"timestamp"`
+	// Frame is the raw frame as received, kept as a string so that malformed frames are captured as well
+	Frame string `json:"frame"`
+}
+
+// frameCapture records the raw web socket frames received by the monitor to a file, so that they can be replayed
+type frameCapture struct {
+	file    *os.File
+	encoder *json.Encoder
+	mutex   sync.Mutex
+}
+
+// newFrameCapture creates the capture file, an existing file is truncated
+func newFrameCapture(path string) (*frameCapture, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create capture file %s: %w", path, err)
+	}
+
+	return &frameCapture{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// record appends the frame to the capture file
+func (c *frameCapture) record(frame []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.encoder.Encode(captureRecord{Timestamp: time.Now().UTC(), Frame: string(frame)}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to capture frame: %v\n", err)
+	}
+}
+
+// Close closes the capture file
+func (c *frameCapture) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.file.Close()
+}
+
+// readCapture reads the records of a capture file in the order they were recorded
+func readCapture(r io.Reader) ([]captureRecord, error) {
+	var records []captureRecord
+	decoder := json.NewDecoder(r)
+	for {
+		var record captureRecord
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse capture record %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
+
+// ReplayCommandConfig is a struct that contains the configuration for the replay command
+type ReplayCommandConfig struct {
+	File     string
+	LogLevel string
+}
+
+// Replay feeds the frames of a capture file through the web socket message processing and prints the parsed
+// datapoint updates with the time the frames were captured. No connection to a system access point is needed.
+func Replay(config ReplayCommandConfig) error {
+	file, err := os.Open(config.File)
+	if err != nil {
+		return fmt.Errorf("failed to open capture file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	records, err := readCapture(file)
+	if err != nil {
+		return err
+	}
+
+	updates, err := replayCapture(records, config.LogLevel)
+	if err != nil {
+		return err
+	}
+	for _, update := range updates {
+		fmt.Printf("%s %s\n", update.Timestamp.Format(time.RFC3339), formatUpdate(update))
+	}
+	fmt.Printf("Replayed %d frames, %d datapoint updates\n", len(records), len(updates))
+	return nil
+}
+
+// replayCapture processes the captured frames offline and returns the parsed datapoint updates in order,
+// timestamped with the time their frame was captured
+func replayCapture(records []captureRecord, logLevel string) ([]models.DatapointUpdate, error) {
+	logger := freeathome.NewDefaultLogger(freeathome.NewColorHandler(os.Stderr, &slog.HandlerOptions{
+		Level: parseLogLevel(logLevel),
+	}))
+	sysApConfig := freeathome.NewConfig("replay", "", "")
+	sysApConfig.Logger = logger
+	sysAp, err := freeathome.NewSystemAccessPoint(sysApConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// The datapoint handler is called synchronously, so the timestamp of the frame being processed can be applied
+	var updates []models.DatapointUpdate
+	var captured time.Time
+	sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		update.Timestamp = captured
+		updates = append(updates, update)
+	})
+	for _, record := range records {
+		captured = record.Timestamp
+		sysAp.ProcessMessage([]byte(record.Frame))
+	}
+	return updates, nil
+}
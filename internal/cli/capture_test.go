@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestCaptureReplayRoundTrip tests that frames captured by the monitor replay to the same datapoint updates
+func TestCaptureReplayRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.ndjson")
+	capture, err := newFrameCapture(path)
+	if err != nil {
+		t.Fatalf("Failed to create capture: %v", err)
+	}
+
+	frames := []string{
+		`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"1"}}}`,
+		// Malformed frames are captured and skipped on replay
+		"not json\nat all",
+		`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"0"}},` +
+			`"11111111-1111-1111-1111-111111111111":{"datapoints":{"ABB7013B85DE/ch0001/odp0010":"21.5"}}}`,
+	}
+	for _, frame := range frames {
+		capture.record([]byte(frame))
+	}
+	if err := capture.Close(); err != nil {
+		t.Fatalf("Failed to close capture: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open capture: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	records, err := readCapture(file)
+	if err != nil {
+		t.Fatalf("Failed to read capture: %v", err)
+	}
+	if len(records) != len(frames) {
+		t.Fatalf("Expected %d records, got %d", len(frames), len(records))
+	}
+	for i, record := range records {
+		if record.Frame != frames[i] {
+			t.Errorf("Expected frame %q, got %q", frames[i], record.Frame)
+		}
+		if record.Timestamp.IsZero() {
+			t.Errorf("Expected frame %d to be timestamped", i)
+		}
+	}
+
+	updates, err := replayCapture(records, "error")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []models.DatapointUpdate{
+		{Timestamp: records[0].Timestamp, SysApID: models.EmptyUUID, Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"},
+		{Timestamp: records[2].Timestamp, SysApID: models.EmptyUUID, Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "0"},
+		{Timestamp: records[2].Timestamp, SysApID: "11111111-1111-1111-1111-111111111111", Serial: "ABB7013B85DE", Channel: "ch0001", Datapoint: "odp0010", Value: "21.5"},
+	}
+	if len(updates) != len(expected) {
+		t.Fatalf("Expected %d updates, got %d: %+v", len(expected), len(updates), updates)
+	}
+	for i, update := range updates {
+		if update != expected[i] {
+			t.Errorf("Expected update %+v, got %+v", expected[i], update)
+		}
+	}
+}
+
+// TestReplay tests that the replay command prints the updates of a capture file with their capture time
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.ndjson")
+	content := `{"timestamp":"2025-01-01T12:00:00Z","frame":"{\"00000000-0000-0000-0000-000000000000\":{\"datapoints\":{\"ABB7F595EC47/ch0000/odp0000\":\"1\"}}}"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write capture file: %v", err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := Replay(ReplayCommandConfig{File: path, LogLevel: "error"}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	expected := "2025-01-01T12:00:00Z ABB7F595EC47 ch0000.odp0000 = 1\nReplayed 1 frames, 1 datapoint updates\n"
+	if output != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// TestReplayInvalidFile tests that missing and malformed capture files are reported
+func TestReplayInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := Replay(ReplayCommandConfig{File: filepath.Join(dir, "missing.ndjson")}); err == nil || !strings.Contains(err.Error(), "failed to open capture file") {
+		t.Errorf("Expected open error, got %v", err)
+	}
+
+	path := filepath.Join(dir, "invalid.ndjson")
+	content := `{"timestamp":"2025-01-01T12:00:00Z","frame":"{}"}` + "\n" + "not json\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write capture file: %v", err)
+	}
+	if err := Replay(ReplayCommandConfig{File: path}); err == nil || !strings.Contains(err.Error(), "failed to parse capture record 2") {
+		t.Errorf("Expected parse error, got %v", err)
+	}
+}
+
+// TestNewFrameCaptureInvalidPath tests that a capture file that cannot be created is reported
+func TestNewFrameCaptureInvalidPath(t *testing.T) {
+	_, err := newFrameCapture(filepath.Join(t.TempDir(), "missing", "capture.ndjson"))
+	if err == nil || !strings.Contains(err.Error(), "failed to create capture file") {
+		t.Errorf("Expected create error, got %v", err)
+	}
+}
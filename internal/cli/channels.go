@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// channelInfo describes a channel of a device with its datapoint identifiers
+type channelInfo struct {
+	Channel  string   `json:"channel"`
+	Function string   `json:"function,omitempty"`
+	Inputs   []string `json:"inputs"`
+	Outputs  []string `json:"outputs"`
+}
+
+// GetChannels retrieves a device and displays its channels with their input and output datapoint identifiers
+func GetChannels(config GetCommandConfig, serial string) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Get device
+	deviceResponse, err := sysAp.GetDevice(serial)
+	if err != nil {
+		return handleSysApError(err, "get device", config.TLSEnabled, config.SkipTLSVerify)
+	}
+
+	// Check if the specific device exists
+	device, err := findDevice(deviceResponse, serial)
+	if err != nil {
+		return err
+	}
+
+	channels := listChannels(device)
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		return outputCommandJSON(channels, "channels", config.Prettify, config.Envelope, sysAp.GetHostName(), "get channels")
+	}
+
+	// Output as plain text
+	fmt.Printf("Device Serial: %s\n", serial)
+	if len(channels) == 0 {
+		fmt.Println("  Channels: (none)")
+		return nil
+	}
+	for _, channel := range channels {
+		fmt.Printf("  Channel: %s\n", channel.Channel)
+		if channel.Function != "" {
+			fmt.Printf("    Function: %s\n", channel.Function)
+		}
+		fmt.Printf("    Inputs: %s\n", formatDatapointIDs(channel.Inputs))
+		fmt.Printf("    Outputs: %s\n", formatDatapointIDs(channel.Outputs))
+	}
+
+	return nil
+}
+
+// findDevice returns the device with the given serial from a device response
+func findDevice(deviceResponse *models.DeviceResponse, serial string) (*models.Device, error) {
+	if deviceResponse != nil {
+		if device, exists := deviceResponse.Device(serial); exists {
+			return device, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no device found with serial: %s", serial)
+}
+
+// listChannels returns the channels of a device with their sorted datapoint identifiers, sorted by channel identifier
+func listChannels(device *models.Device) []channelInfo {
+	channels := []channelInfo{}
+	if device.Channels == nil {
+		return channels
+	}
+
+	for channelID, channel := range *device.Channels {
+		info := channelInfo{Channel: channelID, Inputs: []string{}, Outputs: []string{}}
+		if channel != nil {
+			if channel.FunctionID != nil {
+				info.Function = *channel.FunctionID
+			}
+			if channel.Inputs != nil {
+				info.Inputs = slices.Sorted(maps.Keys(*channel.Inputs))
+			}
+			if channel.Outputs != nil {
+				info.Outputs = slices.Sorted(maps.Keys(*channel.Outputs))
+			}
+		}
+		channels = append(channels, info)
+	}
+	slices.SortFunc(channels, func(a, b channelInfo) int {
+		return strings.Compare(a.Channel, b.Channel)
+	})
+
+	return channels
+}
+
+// formatDatapointIDs formats a list of datapoint identifiers for text output
+func formatDatapointIDs(ids []string) string {
+	if len(ids) == 0 {
+		return "(none)"
+	}
+	return strings.Join(ids, ", ")
+}
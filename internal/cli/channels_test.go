@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// loadDeviceFixture loads the device fixture from the testdata directory
+func loadDeviceFixture(t *testing.T) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "device.json"))
+	if err != nil {
+		t.Fatalf("Failed to read device fixture: %v", err)
+	}
+	return string(data)
+}
+
+// mockChannelsSetup overrides the setupFunc with a mock SystemAccessPoint returning the given response
+func mockChannelsSetup(t *testing.T, responseBody string) {
+	t.Helper()
+
+	v := setupViper(t)
+	sysAp, _, _ := setupMock(t, v, http.StatusOK, responseBody)
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		return sysAp, nil
+	}
+	t.Cleanup(func() {
+		setupFunc = setup
+	})
+}
+
+// TestGetChannelsJSON tests that the channels of the device fixture are listed with their datapoint identifiers
+func TestGetChannelsJSON(t *testing.T) {
+	mockChannelsSetup(t, loadDeviceFixture(t))
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetChannels(GetCommandConfig{OutputFormat: "json"}, "600028E1ED13")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var channels []channelInfo
+	if err := json.Unmarshal([]byte(output), &channels); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(channels) == 0 {
+		t.Fatal("Expected at least one channel")
+	}
+
+	first := channels[0]
+	if first.Channel != "ch0000" {
+		t.Errorf("Expected first channel 'ch0000', got '%s'", first.Channel)
+	}
+	if first.Function != "0007" {
+		t.Errorf("Expected function '0007', got '%s'", first.Function)
+	}
+	expectedInputs := []string{"idp0000", "idp0001", "idp0002", "idp0003", "idp0004", "idp0005", "idp0006", "idp0007", "idp0008"}
+	if !reflect.DeepEqual(first.Inputs, expectedInputs) {
+		t.Errorf("Expected inputs %v, got %v", expectedInputs, first.Inputs)
+	}
+	if len(first.Outputs) == 0 || first.Outputs[0] != "odp0000" {
+		t.Errorf("Expected outputs starting with 'odp0000', got %v", first.Outputs)
+	}
+
+	// Channels are sorted by identifier
+	for i := 1; i < len(channels); i++ {
+		if channels[i-1].Channel >= channels[i].Channel {
+			t.Errorf("Expected channels to be sorted, got '%s' before '%s'", channels[i-1].Channel, channels[i].Channel)
+		}
+	}
+}
+
+// TestGetChannelsText tests the plain text output of the channels
+func TestGetChannelsText(t *testing.T) {
+	mockChannelsSetup(t, `{"00000000-0000-0000-0000-000000000000":{"devices":{"ABB7F595EC47":{"channels":{
+		"ch0001":{"outputs":{"odp0000":{"value":"1"}}},
+		"ch0000":{"functionId":"0007","inputs":{"idp0001":{},"idp0000":{}},"outputs":{"odp0000":{}}}
+	}}}}}`)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetChannels(GetCommandConfig{OutputFormat: "text"}, "ABB7F595EC47")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `Device Serial: ABB7F595EC47
+  Channel: ch0000
+    Function: 0007
+    Inputs: idp0000, idp0001
+    Outputs: odp0000
+  Channel: ch0001
+    Inputs: (none)
+    Outputs: odp0000
+`
+	if output != expected {
+		t.Errorf("Expected output '%s', got '%s'", expected, output)
+	}
+}
+
+// TestGetChannelsUnknownDevice tests that an unknown device returns an error
+func TestGetChannelsUnknownDevice(t *testing.T) {
+	mockChannelsSetup(t, loadDeviceFixture(t))
+
+	err := GetChannels(GetCommandConfig{OutputFormat: "text"}, "UNKNOWN")
+	if err == nil || !strings.Contains(err.Error(), "no device found with serial: UNKNOWN") {
+		t.Errorf("Expected error to contain 'no device found with serial: UNKNOWN', got '%v'", err)
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// CompleteDeviceSerials returns the serials of the devices of the system access point that start with the given prefix.
+// It returns nil if the device list cannot be retrieved, e.g. because the system access point is offline.
+func CompleteDeviceSerials(config CommandConfig, toComplete string) []string {
+	// Setup system access point
+	sysAp, err := setupFunc(config, "")
+	if err != nil {
+		return nil
+	}
+
+	// Get device list
+	deviceList, err := sysAp.GetDeviceList()
+	if err != nil || deviceList == nil {
+		return nil
+	}
+
+	devices, _ := deviceList.Serials()
+	return filterCompletions(devices, toComplete)
+}
+
+// CompleteChannels returns the channel identifiers of a device that start with the given prefix.
+// It returns nil if the device cannot be retrieved.
+func CompleteChannels(config CommandConfig, serial string, toComplete string) []string {
+	device, ok := completionDevice(config, serial)
+	if !ok || device.Channels == nil {
+		return nil
+	}
+
+	channels := make([]string, 0, len(*device.Channels))
+	for channelID := range *device.Channels {
+		channels = append(channels, channelID)
+	}
+
+	return filterCompletions(channels, toComplete)
+}
+
+// CompleteDatapoints returns the input and output datapoint identifiers of a device channel that start with the given prefix.
+// It returns nil if the device cannot be retrieved or the channel does not exist.
+func CompleteDatapoints(config CommandConfig, serial string, channel string, toComplete string) []string {
+	device, ok := completionDevice(config, serial)
+	if !ok || device.Channels == nil {
+		return nil
+	}
+
+	channelData, exists := (*device.Channels)[channel]
+	if !exists || channelData == nil {
+		return nil
+	}
+
+	var datapoints []string
+	if channelData.Inputs != nil {
+		for datapointID := range *channelData.Inputs {
+			datapoints = append(datapoints, datapointID)
+		}
+	}
+	if channelData.Outputs != nil {
+		for datapointID := range *channelData.Outputs {
+			datapoints = append(datapoints, datapointID)
+		}
+	}
+
+	return filterCompletions(datapoints, toComplete)
+}
+
+// completionDevice retrieves a device for completion purposes
+func completionDevice(config CommandConfig, serial string) (*models.Device, bool) {
+	// Setup system access point
+	sysAp, err := setupFunc(config, "")
+	if err != nil {
+		return nil, false
+	}
+
+	// Get device
+	deviceResponse, err := sysAp.GetDevice(serial)
+	if err != nil || deviceResponse == nil {
+		return nil, false
+	}
+
+	device, err := findDevice(deviceResponse, serial)
+	return device, err == nil
+}
+
+// filterCompletions returns the sorted candidates that start with the given prefix
+func filterCompletions(candidates []string, toComplete string) []string {
+	var completions []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(toComplete)) {
+			completions = append(completions, candidate)
+		}
+	}
+
+	slices.Sort(completions)
+	return completions
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+const completionDeviceResponse = `{
+  "00000000-0000-0000-0000-000000000000": {
+    "devices": {
+      "ABB7F595EC47": {
+        "displayName": "Test Device",
+        "channels": {
+          "ch0001": {
+            "inputs": {"idp0000": {"value": "0"}},
+            "outputs": {"odp0001": {"value": "1"}, "odp0000": {"value": "0"}}
+          },
+          "ch0000": {}
+        }
+      }
+    }
+  }
+}`
+
+// mockCompletionSetup overrides the setupFunc with a mock SystemAccessPoint returning the given response
+func mockCompletionSetup(t *testing.T, responseCode int, responseBody string) {
+	t.Helper()
+
+	v := setupViper(t)
+	sysAp, _, _ := setupMock(t, v, responseCode, responseBody)
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		return sysAp, nil
+	}
+	t.Cleanup(func() {
+		setupFunc = setup
+	})
+}
+
+// TestCompleteDeviceSerials tests that the device serials are completed from the device list
+func TestCompleteDeviceSerials(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		expected   []string
+	}{
+		{name: "Empty prefix", toComplete: "", expected: []string{"ABB7013B85DE", "ABB7F5947E20", "ABB7F595EC47"}},
+		{name: "Matching prefix", toComplete: "abb7f5", expected: []string{"ABB7F5947E20", "ABB7F595EC47"}},
+		{name: "No match", toComplete: "XYZ", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCompletionSetup(t, http.StatusOK, `{"00000000-0000-0000-0000-000000000000":["ABB7F595EC47","ABB7013B85DE","ABB7F5947E20"]}`)
+
+			completions := CompleteDeviceSerials(CommandConfig{}, tt.toComplete)
+			if !reflect.DeepEqual(completions, tt.expected) {
+				t.Errorf("Expected completions %v, got %v", tt.expected, completions)
+			}
+		})
+	}
+}
+
+// TestCompleteChannels tests that the channels are completed from the device
+func TestCompleteChannels(t *testing.T) {
+	mockCompletionSetup(t, http.StatusOK, completionDeviceResponse)
+
+	completions := CompleteChannels(CommandConfig{}, "ABB7F595EC47", "ch")
+	expected := []string{"ch0000", "ch0001"}
+	if !reflect.DeepEqual(completions, expected) {
+		t.Errorf("Expected completions %v, got %v", expected, completions)
+	}
+}
+
+// TestCompleteDatapoints tests that the datapoints are completed from the device channel
+func TestCompleteDatapoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		channel    string
+		toComplete string
+		expected   []string
+	}{
+		{name: "All datapoints", channel: "ch0001", toComplete: "", expected: []string{"idp0000", "odp0000", "odp0001"}},
+		{name: "Output datapoints", channel: "ch0001", toComplete: "o", expected: []string{"odp0000", "odp0001"}},
+		{name: "Channel without datapoints", channel: "ch0000", toComplete: "", expected: nil},
+		{name: "Unknown channel", channel: "ch0002", toComplete: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCompletionSetup(t, http.StatusOK, completionDeviceResponse)
+
+			completions := CompleteDatapoints(CommandConfig{}, "ABB7F595EC47", tt.channel, tt.toComplete)
+			if !reflect.DeepEqual(completions, tt.expected) {
+				t.Errorf("Expected completions %v, got %v", tt.expected, completions)
+			}
+		})
+	}
+}
+
+// TestCompletionErrors tests that no completions are returned if the system access point cannot be queried
+func TestCompletionErrors(t *testing.T) {
+	t.Run("HTTP error response", func(t *testing.T) {
+		mockCompletionSetup(t, http.StatusUnauthorized, `{"error": "Unauthorized"}`)
+		if completions := CompleteDeviceSerials(CommandConfig{}, ""); completions != nil {
+			t.Errorf("Expected no completions, got %v", completions)
+		}
+	})
+
+	t.Run("Unknown device", func(t *testing.T) {
+		mockCompletionSetup(t, http.StatusOK, completionDeviceResponse)
+		if completions := CompleteChannels(CommandConfig{}, "ABB700000000", ""); completions != nil {
+			t.Errorf("Expected no completions, got %v", completions)
+		}
+	})
+
+	t.Run("Setup error", func(t *testing.T) {
+		setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+			return nil, errors.New("offline")
+		}
+		defer func() {
+			setupFunc = setup
+		}()
+
+		if completions := CompleteDeviceSerials(CommandConfig{}, ""); completions != nil {
+			t.Errorf("Expected no completions, got %v", completions)
+		}
+		if completions := CompleteChannels(CommandConfig{}, "ABB7F595EC47", ""); completions != nil {
+			t.Errorf("Expected no completions, got %v", completions)
+		}
+		if completions := CompleteDatapoints(CommandConfig{}, "ABB7F595EC47", "ch0000", ""); completions != nil {
+			t.Errorf("Expected no completions, got %v", completions)
+		}
+	})
+}
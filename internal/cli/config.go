@@ -1,15 +1,28 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
 )
 
 var configFileDir, _ = os.UserHomeDir()
 
+// EnvConfigFile is the environment variable containing the path of the config file if the --config flag is not set
+const EnvConfigFile = "FREEATHOME_CONFIG"
+
 // GetExecutableName returns the name of the executable
 func GetExecutableName() (string, error) {
 	executablePath, err := os.Executable()
@@ -36,6 +49,16 @@ type Config struct {
 	Hostname   string `mapstructure:"hostname" yaml:"hostname"`
 	Username   string `mapstructure:"username" yaml:"username"`
 	Password   string `mapstructure:"password" yaml:"password"`
+	// SysApUUID selects the system access point if the configuration contains several, empty uses the local one
+	SysApUUID string `mapstructure:"sysap-uuid" yaml:"sysap-uuid,omitempty"`
+	// ReadOnly rejects all write operations, so that the CLI cannot change the state of any device
+	ReadOnly bool `mapstructure:"read-only" yaml:"read-only,omitempty"`
+	// APIVersion is the version segment of the API paths, e.g. v1 in /fhapi/v1, empty uses v1
+	APIVersion string `mapstructure:"api-version" yaml:"api-version,omitempty"`
+	// Scales converts the raw values of datapoints read scaled, each in the format serial/channel/datapoint=factor unit
+	Scales []string `mapstructure:"scales" yaml:"scales,omitempty"`
+	// WrapLogLines wraps the log lines to the width of the terminal if the log output is a terminal
+	WrapLogLines bool `mapstructure:"wrap-log-lines" yaml:"wrap-log-lines,omitempty"`
 }
 
 // CommandConfig represents the basic configuration for a command
@@ -44,6 +67,14 @@ type CommandConfig struct {
 	TLSEnabled    bool
 	SkipTLSVerify bool
 	LogLevel      string
+	// HeartbeatInterval is the interval at which the web socket logs that it is still running, zero disables the heartbeat
+	HeartbeatInterval time.Duration
+	// SerialMasker masks the device serials in the log output if set
+	SerialMasker *freeathome.SerialMasker
+	// RequestTimeout is the maximum time a REST request may take, zero disables the timeout
+	RequestTimeout time.Duration
+	// DiscardLogs discards the log output of the client, e.g. while completing shell arguments
+	DiscardLogs bool
 }
 
 // load loads the configuration from file and environment variables
@@ -60,16 +91,29 @@ func load(v *viper.Viper, configFile string) (*Config, error) {
 	// Initialize viper configuration
 	initConfig(v)
 
-	// Override config file if specified
+	// Override config file if specified, the flag takes precedence over the environment variable
+	source := "--config"
+	if configFile == "" {
+		configFile = os.Getenv(EnvConfigFile)
+		source = EnvConfigFile
+	}
 	if configFile != "" {
 		v.SetConfigFile(configFile)
 		if err := v.ReadInConfig(); err != nil {
-			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-				return nil, fmt.Errorf("error reading config file: %w", err)
+			// An explicitly given config file must exist, rather than silently continuing with an empty configuration
+			var notFound viper.ConfigFileNotFoundError
+			if errors.Is(err, fs.ErrNotExist) || errors.As(err, &notFound) {
+				return nil, fmt.Errorf("config file %s given by %s does not exist", configFile, source)
 			}
+			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
 	}
 
+	// Reject unknown keys, as a misspelled key would silently leave its setting empty
+	if unknown := unknownConfigKeys(v); len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown keys in config file %s: %s, supported keys are: %s", v.ConfigFileUsed(), strings.Join(unknown, ", "), strings.Join(configKeys(), ", "))
+	}
+
 	// Create config struct and unmarshal
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
@@ -80,19 +124,56 @@ func load(v *viper.Viper, configFile string) (*Config, error) {
 	return &cfg, nil
 }
 
-// save saves the configuration to file
+// configKeys returns the keys supported in the config file, derived from the mapstructure tags of the Config struct
+func configKeys() []string {
+	var keys []string
+	configType := reflect.TypeFor[Config]()
+	for i := range configType.NumField() {
+		if key := configType.Field(i).Tag.Get("mapstructure"); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// unknownConfigKeys returns the sorted keys of the configuration that are not supported
+func unknownConfigKeys(v *viper.Viper) []string {
+	known := configKeys()
+	var unknown []string
+	for _, key := range v.AllKeys() {
+		if !slices.Contains(known, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// save saves the hostname, username and password to the config file used by the viper instance. The other keys of the
+// config file are preserved. The settings of the viper instance itself are not written, as they include the flags and
+// environment variables of the current invocation, e.g. --read-only or --sysap-uuid.
 func (c *Config) save(v *viper.Viper) error {
-	configDir := filepath.Dir(v.ConfigFileUsed())
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	configFile := v.ConfigFileUsed()
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
 		return fmt.Errorf("error creating config directory: %w", err)
 	}
 
-	// Set values in viper
-	v.Set("hostname", c.Hostname)
-	v.Set("username", c.Username)
-	v.Set("password", c.Password)
+	// Read the keys of the config file only
+	file := viper.New()
+	file.SetConfigType("yaml")
+	file.SetConfigFile(configFile)
+	if err := file.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.Is(err, fs.ErrNotExist) && !errors.As(err, &notFound) {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+	}
 
-	if err := v.WriteConfig(); err != nil {
+	file.Set("hostname", c.Hostname)
+	file.Set("username", c.Username)
+	file.Set("password", c.Password)
+
+	if err := file.WriteConfig(); err != nil {
 		return fmt.Errorf("error writing config file: %w", err)
 	}
 	return nil
@@ -121,6 +202,21 @@ func (c *Config) printSummary(v *viper.Viper) {
 	} else {
 		fmt.Printf("  Password: %s\n", "(not set)")
 	}
+	if c.SysApUUID != "" {
+		fmt.Printf("  SysAP UUID: %s\n", c.SysApUUID)
+	}
+	if c.ReadOnly {
+		fmt.Println("  Read-only: true")
+	}
+	if c.APIVersion != "" {
+		fmt.Printf("  API version: %s\n", c.APIVersion)
+	}
+	if len(c.Scales) > 0 {
+		fmt.Printf("  Scales: %d\n", len(c.Scales))
+	}
+	if c.WrapLogLines {
+		fmt.Println("  Wrap log lines: true")
+	}
 
 	if v.ConfigFileUsed() != "" {
 		fmt.Printf("Config file: %s\n", v.ConfigFileUsed())
@@ -129,6 +225,42 @@ func (c *Config) printSummary(v *viper.Viper) {
 	}
 }
 
+// configEnvVars maps the config keys that can be set by environment variables to the variables
+var configEnvVars = map[string]string{
+	"hostname": freeathome.EnvHostname,
+	"username": freeathome.EnvUsername,
+	"password": freeathome.EnvPassword,
+}
+
+// flagEnvVars maps the command flags that can be set by environment variables to the variables, they are not part of the
+// config file
+var flagEnvVars = map[string]string{
+	"tls":             freeathome.EnvTLSEnabled,
+	"skip-tls-verify": freeathome.EnvSkipTLSVerify,
+}
+
+// ApplyFlagEnvVars sets the flags that are not set on the command line to the values of their environment variables, using
+// the same variables and parsing as the library
+func ApplyFlagEnvVars(flags *pflag.FlagSet) error {
+	for name, variable := range flagEnvVars {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		value, set, err := freeathome.LookupEnvBool(variable)
+		if err != nil {
+			return err
+		}
+		if !set {
+			continue
+		}
+		if err := flag.Value.Set(strconv.FormatBool(value)); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", variable, err)
+		}
+	}
+	return nil
+}
+
 // initConfig initializes viper configuration
 func initConfig(v *viper.Viper) {
 	// Set config file name and type
@@ -143,14 +275,14 @@ func initConfig(v *viper.Viper) {
 	// Set environment variable prefix
 	v.SetEnvPrefix("FREEATHOME")
 
-	// Map environment variables to config keys
-	_ = v.BindEnv("hostname")
-	_ = v.BindEnv("username")
-	_ = v.BindEnv("password")
+	// Map environment variables to config keys, using the same variables as the library
+	for key, name := range configEnvVars {
+		_ = v.BindEnv(key, name)
+	}
 
-	// Read config file if it exists
+	// Read config file if it exists, the settings fall back to the environment variables otherwise
 	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok && !errors.Is(err, fs.ErrNotExist) {
 			fmt.Printf("Error reading config file: %v\n", err)
 		}
 	}
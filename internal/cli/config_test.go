@@ -3,9 +3,13 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
 )
 
 // TestGetExecutableName tests the GetExecutableName function
@@ -169,6 +173,47 @@ func TestLoadWithNonExistentFile(t *testing.T) {
 	}
 }
 
+// TestLoadWithMissingExplicitFile tests that an explicitly given config file that does not exist is reported clearly
+func TestLoadWithMissingExplicitFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "missing.yaml")
+
+	_, err := load(viper.New(), configFile)
+	expected := "config file " + configFile + " given by --config does not exist"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+
+	// The environment variable is an explicit choice as well
+	t.Setenv(EnvConfigFile, configFile)
+	_, err = load(viper.New(), "")
+	expected = "config file " + configFile + " given by " + EnvConfigFile + " does not exist"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+}
+
+// TestLoadWithoutDefaultFile tests that a missing config file at the default location falls back to the environment variables
+func TestLoadWithoutDefaultFile(t *testing.T) {
+	configFileDir = t.TempDir()
+	t.Setenv(EnvConfigFile, "")
+	t.Setenv(freeathome.EnvHostname, "env-host")
+
+	var cfg *Config
+	var err error
+	output := captureStdout(t, func() {
+		cfg, err = load(viper.New(), "")
+	})
+	if err != nil {
+		t.Fatalf("Expected no error without a config file at the default location, got: %v", err)
+	}
+	if cfg.Hostname != "env-host" {
+		t.Errorf("Expected hostname from environment variable, got '%s'", cfg.Hostname)
+	}
+	if output != "" {
+		t.Errorf("Expected no output, got: %s", output)
+	}
+}
+
 // TestLoadWithEmptyString tests loading configuration with empty file path
 func TestLoadWithEmptyString(t *testing.T) {
 	// Create a fresh viper instance for testing
@@ -276,6 +321,47 @@ password: file-pass`
 	}
 }
 
+// writeTestConfigFile writes a config file with the given host name to a temporary directory and returns its path
+func writeTestConfigFile(t *testing.T, hostname string) string {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := "hostname: " + hostname + "\nusername: file-user\npassword: file-pass"
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	return configFile
+}
+
+// TestLoadWithConfigFileEnvironmentVariable tests that the config file is read from the path in the environment variable
+func TestLoadWithConfigFileEnvironmentVariable(t *testing.T) {
+	t.Setenv(EnvConfigFile, writeTestConfigFile(t, "env-file-host"))
+
+	cfg, err := load(viper.New(), "")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	assertConfigValues(t, cfg, "env-file-host", "file-user", "file-pass")
+}
+
+// TestLoadWithConfigFileFlagOverridesEnvironmentVariable tests that the config file flag takes precedence over the environment variable
+func TestLoadWithConfigFileFlagOverridesEnvironmentVariable(t *testing.T) {
+	t.Setenv(EnvConfigFile, writeTestConfigFile(t, "env-file-host"))
+	flagFile := writeTestConfigFile(t, "flag-file-host")
+
+	v := viper.New()
+	cfg, err := load(v, flagFile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	assertConfigValues(t, cfg, "flag-file-host", "file-user", "file-pass")
+	if v.ConfigFileUsed() != flagFile {
+		t.Errorf("Expected config file '%s' to be used, got '%s'", flagFile, v.ConfigFileUsed())
+	}
+}
+
 // TestLoadWithInvalidYAML tests loading configuration with invalid YAML
 func TestLoadWithInvalidYAML(t *testing.T) {
 	// Create a temporary config file with invalid YAML
@@ -329,6 +415,31 @@ password: test-pass`
 	}
 }
 
+// TestLoadWithUnknownKey tests that a misspelled key in the config file is rejected and identified
+func TestLoadWithUnknownKey(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "config.yaml")
+
+	configContent := `hostnam: test-host
+username: test-user
+password: test-pass`
+
+	err := os.WriteFile(configFile, []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	// Load configuration should fail
+	cfg, err := load(viper.New(), configFile)
+	if err == nil {
+		t.Fatalf("Expected error when loading a config file with an unknown key, got config: %v", cfg)
+	}
+	expected := "unknown keys in config file " + configFile + ": hostnam, supported keys are: hostname, username, password, sysap-uuid, read-only, api-version, scales, wrap-log-lines"
+	if err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err.Error())
+	}
+}
+
 // TestLoadWithPartialFile tests loading configuration with partial values in file
 func TestLoadWithPartialFile(t *testing.T) {
 	// Create a temporary config file with partial values
@@ -506,3 +617,39 @@ func TestInitConfig(t *testing.T) {
 		t.Error("Expected viper env prefix to be set to FREEATHOME but got ", v.GetEnvPrefix())
 	}
 }
+
+// TestApplyFlagEnvVars tests that the environment variables set the flags not set on the command line
+func TestApplyFlagEnvVars(t *testing.T) {
+	t.Setenv(freeathome.EnvTLSEnabled, "false")
+	t.Setenv(freeathome.EnvSkipTLSVerify, "true")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	tlsEnabled := flags.Bool("tls", true, "")
+	skipTLSVerify := flags.Bool("skip-tls-verify", false, "")
+	if err := flags.Parse([]string{"--skip-tls-verify=false"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if err := ApplyFlagEnvVars(flags); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *tlsEnabled {
+		t.Error("Expected TLS to be disabled by the environment variable")
+	}
+	if *skipTLSVerify {
+		t.Error("Expected the flag set on the command line to take precedence over the environment variable")
+	}
+}
+
+// TestApplyFlagEnvVarsInvalid tests that invalid values of the environment variables are rejected
+func TestApplyFlagEnvVarsInvalid(t *testing.T) {
+	t.Setenv(freeathome.EnvTLSEnabled, "maybe")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("tls", true, "")
+
+	err := ApplyFlagEnvVars(flags)
+	if err == nil || !strings.Contains(err.Error(), "invalid value for FREEATHOME_TLS_ENABLED") {
+		t.Errorf("Expected invalid value error, got %v", err)
+	}
+}
@@ -3,10 +3,14 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
 // TestConfigure tests the Configure function with various scenarios
@@ -122,6 +126,68 @@ password: old-pass`,
 	}
 }
 
+// TestConfigureSavesConnectionSettingsOnly tests that the flags of the invocation bound to viper are not saved
+func TestConfigureSavesConnectionSettingsOnly(t *testing.T) {
+	configFileDir = t.TempDir()
+	v := viper.New()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("read-only", false, "")
+	flags.String("sysap-uuid", "", "")
+	flags.String("api-version", "v1", "")
+	flags.Bool("wrap-log-lines", false, "")
+	for _, name := range []string{"read-only", "sysap-uuid", "api-version", "wrap-log-lines"} {
+		if err := v.BindPFlag(name, flags.Lookup(name)); err != nil {
+			t.Fatalf("Failed to bind flag: %v", err)
+		}
+	}
+	if err := flags.Parse([]string{"--read-only", "--sysap-uuid", "sysap-b", "--api-version", "v9", "--wrap-log-lines"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if err := Configure(v, "", "test-host", "test-user", "test-pass", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	written := readConfigFile(t, filepath.Join(configFileDir, ".freeathome", "config.yaml"))
+	expected := map[string]any{"hostname": "test-host", "username": "test-user", "password": "test-pass"}
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected config file %v, got %v", expected, written)
+	}
+}
+
+// TestConfigurePreservesFileKeys tests that the keys of the config file other than the connection settings are preserved
+func TestConfigurePreservesFileKeys(t *testing.T) {
+	configFile := createTestConfigFile(t, `hostname: old-host
+username: old-user
+password: old-pass
+read-only: true`)
+
+	if err := Configure(viper.New(), configFile, "new-host", "", "", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	written := readConfigFile(t, configFile)
+	expected := map[string]any{"hostname": "new-host", "username": "old-user", "password": "old-pass", "read-only": true}
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected config file %v, got %v", expected, written)
+	}
+}
+
+// readConfigFile returns the keys and values written to the config file
+func readConfigFile(t *testing.T, configFile string) map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	var written map[string]any
+	if err := yaml.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Failed to parse config file: %v", err)
+	}
+	return written
+}
+
 // TestConfigureWithInvalidConfigFile tests Configure with an invalid config file
 func TestConfigureWithInvalidConfigFile(t *testing.T) {
 	invalidYAML := `hostname: test-host
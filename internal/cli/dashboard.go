@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+const (
+	// dashboardRecentChange is the time a changed value stays highlighted
+	dashboardRecentChange = 5 * time.Second
+	// dashboardRedrawInterval is the interval the dashboard is redrawn at on a terminal, so that highlights fade out
+	dashboardRedrawInterval = time.Second
+	// dashboardKeepaliveInterval is the keepalive interval of the web socket connection of the dashboard
+	dashboardKeepaliveInterval = 30 * time.Second
+	// dashboardMaxReconnectionAttempts is the maximum number of reconnection attempts of the dashboard
+	dashboardMaxReconnectionAttempts = 3
+)
+
+// DashboardCommandConfig is a struct that contains the configuration for the dashboard command
+type DashboardCommandConfig struct {
+	CommandConfig
+	WatchFile        string
+	SnapshotInterval int
+}
+
+// dashboardEntry is the state of a watched datapoint
+type dashboardEntry struct {
+	Key models.DatapointKey
+	// Value is the current value, empty if no value is known
+	Value string
+	// Known indicates whether a value was read or received
+	Known bool
+	// Changed is the time of the last change of the value, zero if the value did not change since the dashboard started
+	Changed time.Time
+	// Changes is the number of changes of the value since the dashboard started
+	Changes int
+}
+
+// dashboardState is the state of the watched datapoints in the order of the watch file
+type dashboardState struct {
+	entries []dashboardEntry
+	index   map[models.DatapointKey]int
+}
+
+// newDashboardState creates the state for the watched datapoints, duplicates are only watched once
+func newDashboardState(keys []models.DatapointKey) *dashboardState {
+	state := &dashboardState{index: map[models.DatapointKey]int{}}
+	for _, key := range keys {
+		if _, exists := state.index[key]; exists {
+			continue
+		}
+		state.index[key] = len(state.entries)
+		state.entries = append(state.entries, dashboardEntry{Key: key})
+	}
+	return state
+}
+
+// initialize sets the initial value of a watched datapoint without counting it as a change
+func (s *dashboardState) initialize(key models.DatapointKey, value string) {
+	if i, exists := s.index[key]; exists {
+		s.entries[i].Value = value
+		s.entries[i].Known = true
+	}
+}
+
+// apply applies an update and reports whether the value of a watched datapoint changed.
+// Updates of other datapoints and updates repeating the current value are ignored.
+func (s *dashboardState) apply(update models.DatapointUpdate) bool {
+	i, exists := s.index[models.DatapointKey{Serial: update.Serial, Channel: update.Channel, Datapoint: update.Datapoint}]
+	if !exists {
+		return false
+	}
+
+	entry := &s.entries[i]
+	if entry.Known && entry.Value == update.Value {
+		return false
+	}
+	// The first value of a datapoint that could not be read is not a change
+	if entry.Known {
+		entry.Changed = update.Timestamp
+		entry.Changes++
+	}
+	entry.Value = update.Value
+	entry.Known = true
+	return true
+}
+
+// recentlyChanged reports whether the value of the entry changed within the highlight window before now
+func (e dashboardEntry) recentlyChanged(now time.Time) bool {
+	return !e.Changed.IsZero() && now.Sub(e.Changed) < dashboardRecentChange
+}
+
+// render formats the state as a table, recently changed values are highlighted if colors are enabled
+func (s *dashboardState) render(now time.Time) string {
+	width := len("DATAPOINT")
+	for _, entry := range s.entries {
+		width = max(width, len(entry.Key.String()))
+	}
+	highlight := color.New(color.FgYellow, color.Bold)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%-*s  %s\n", width, "DATAPOINT", "VALUE")
+	for _, entry := range s.entries {
+		value := "(unknown)"
+		if entry.Known {
+			value = entry.Value
+		}
+		if entry.recentlyChanged(now) {
+			value = highlight.Sprint(value)
+		}
+		fmt.Fprintf(&builder, "%-*s  %s\n", width, entry.Key.String(), value)
+	}
+	return builder.String()
+}
+
+// parseWatchKeys parses the datapoint addresses of the watch file into normalized keys, so that they match the keys of
+// received updates regardless of the case used in the watch file
+func parseWatchKeys(addresses []string) ([]models.DatapointKey, error) {
+	keys := make([]models.DatapointKey, 0, len(addresses))
+	for _, address := range addresses {
+		key, err := models.ParseDatapointKey(address)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.Normalize())
+	}
+	return keys, nil
+}
+
+// Dashboard shows the current values of the datapoints listed in the watch file and updates them live from the web socket.
+// On a terminal the dashboard is redrawn in place, otherwise a snapshot is printed periodically.
+func Dashboard(config DashboardCommandConfig) error {
+	addresses, err := loadAddressFile(config.WatchFile, "watch file")
+	if err != nil {
+		return err
+	}
+	keys, err := parseWatchKeys(addresses)
+	if err != nil {
+		return err
+	}
+	if config.SnapshotInterval < 1 {
+		return fmt.Errorf("snapshot interval must be at least 1 second, got %d", config.SnapshotInterval)
+	}
+
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Read the initial values, datapoints that cannot be read are shown as unknown until an update arrives
+	state := newDashboardState(keys)
+	for _, result := range readBatch(sysAp, addresses) {
+		if result.Error == "" && len(result.Values) > 0 {
+			key, _ := models.ParseDatapointKey(result.Address)
+			state.initialize(key.Normalize(), result.Values[0])
+		}
+	}
+
+	var mutex sync.Mutex
+	interactive := isTerminal(os.Stdout)
+	draw := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if interactive {
+			// Move the cursor home and clear the screen before redrawing
+			fmt.Print("\033[H\033[2J")
+			fmt.Print(state.render(time.Now()))
+			fmt.Println("\nPress Ctrl+C to exit")
+			return
+		}
+		fmt.Printf("%s\n%s\n", time.Now().Format(time.RFC3339), state.render(time.Now()))
+	}
+
+	// Redraw on every change on a terminal, snapshots are only printed periodically
+	sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		mutex.Lock()
+		changed := state.apply(update)
+		mutex.Unlock()
+		if changed && interactive {
+			draw()
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	interval := time.Duration(config.SnapshotInterval) * time.Second
+	if interactive {
+		interval = dashboardRedrawInterval
+	}
+	draw()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				draw()
+			}
+		}
+	}()
+
+	err = sysAp.ConnectWebSocket(ctx, dashboardMaxReconnectionAttempts, true, dashboardKeepaliveInterval)
+	if err != nil && err != context.Canceled {
+		return err
+	}
+	return nil
+}
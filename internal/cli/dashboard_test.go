@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestDashboardStateApply tests the state of the watched datapoints given a sequence of updates
+func TestDashboardStateApply(t *testing.T) {
+	light := models.DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}
+	temperature := models.DatapointKey{Serial: "ABB7013B85DE", Channel: "ch0001", Datapoint: "odp0010"}
+	state := newDashboardState([]models.DatapointKey{light, temperature, light})
+	state.initialize(light, "0")
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	update := func(key models.DatapointKey, value string, offset time.Duration) models.DatapointUpdate {
+		return models.DatapointUpdate{Timestamp: start.Add(offset), Serial: key.Serial, Channel: key.Channel, Datapoint: key.Datapoint, Value: value}
+	}
+	tests := []struct {
+		name    string
+		update  models.DatapointUpdate
+		changed bool
+	}{
+		{name: "Changed value", update: update(light, "1", 0), changed: true},
+		{name: "Repeated value", update: update(light, "1", time.Second), changed: false},
+		{name: "First value of an unknown datapoint", update: update(temperature, "21.5", 2*time.Second), changed: true},
+		{name: "Unwatched datapoint", update: update(models.DatapointKey{Serial: "ABB7F5947E20", Channel: "ch0000", Datapoint: "odp0000"}, "1", 3*time.Second), changed: false},
+		{name: "Changed again", update: update(light, "0", 4*time.Second), changed: true},
+	}
+	for _, tt := range tests {
+		if changed := state.apply(tt.update); changed != tt.changed {
+			t.Errorf("%s: expected changed %t, got %t", tt.name, tt.changed, changed)
+		}
+	}
+
+	expected := []dashboardEntry{
+		{Key: light, Value: "0", Known: true, Changed: start.Add(4 * time.Second), Changes: 2},
+		{Key: temperature, Value: "21.5", Known: true},
+	}
+	if len(state.entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(state.entries))
+	}
+	for i, entry := range expected {
+		if state.entries[i] != entry {
+			t.Errorf("Expected entry %+v, got %+v", entry, state.entries[i])
+		}
+	}
+}
+
+// TestParseWatchKeys tests that the addresses of the watch file are normalized to match the keys of received updates
+func TestParseWatchKeys(t *testing.T) {
+	keys, err := parseWatchKeys([]string{"abb7f595ec47/CH0000/ODP0000", "ABB7013B85DE/ch0001/odp0010"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []models.DatapointKey{
+		{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"},
+		{Serial: "ABB7013B85DE", Channel: "ch0001", Datapoint: "odp0010"},
+	}
+	if !slices.Equal(keys, expected) {
+		t.Errorf("Expected keys %v, got %v", expected, keys)
+	}
+
+	state := newDashboardState(keys)
+	if !state.apply(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"}) {
+		t.Error("Expected the update to match the watched datapoint")
+	}
+}
+
+// TestDashboardStateRender tests that the values are rendered in the order of the watch file and recent changes are highlighted
+func TestDashboardStateRender(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	light := models.DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}
+	temperature := models.DatapointKey{Serial: "ABB7013B85DE", Channel: "ch0001", Datapoint: "odp0010"}
+	state := newDashboardState([]models.DatapointKey{light, temperature})
+	state.initialize(light, "0")
+	changed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	state.apply(models.DatapointUpdate{Timestamp: changed, Serial: light.Serial, Channel: light.Channel, Datapoint: light.Datapoint, Value: "1"})
+
+	highlighted := color.New(color.FgYellow, color.Bold).Sprint("1")
+	expected := "DATAPOINT                    VALUE\n" +
+		"ABB7F595EC47/ch0000/odp0000  " + highlighted + "\n" +
+		"ABB7013B85DE/ch0001/odp0010  (unknown)\n"
+	if actual := state.render(changed.Add(time.Second)); actual != expected {
+		t.Errorf("Expected output:\n%q\ngot:\n%q", expected, actual)
+	}
+
+	// The highlight fades out
+	expected = strings.Replace(expected, highlighted, "1", 1)
+	if actual := state.render(changed.Add(dashboardRecentChange)); actual != expected {
+		t.Errorf("Expected output:\n%q\ngot:\n%q", expected, actual)
+	}
+}
+
+// TestDashboardInvalidWatchFile tests that missing, empty and invalid watch files are rejected before connecting
+func TestDashboardInvalidWatchFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{name: "Empty", content: "[]", errorContains: "watch file contains no datapoint addresses"},
+		{name: "Invalid address", content: "- ABB7F595EC47/ch0000", errorContains: "invalid datapoint key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, tt.name+".yaml")
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write watch file: %v", err)
+			}
+
+			err := Dashboard(DashboardCommandConfig{WatchFile: file, SnapshotInterval: 10})
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error containing '%s', got %v", tt.errorContains, err)
+			}
+		})
+	}
+
+	if err := Dashboard(DashboardCommandConfig{WatchFile: filepath.Join(dir, "missing.yaml"), SnapshotInterval: 10}); err == nil || !strings.Contains(err.Error(), "failed to read watch file") {
+		t.Errorf("Expected missing watch file error, got %v", err)
+	}
+}
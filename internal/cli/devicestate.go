@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// deviceStateConcurrency is the maximum number of datapoints that are read concurrently
+const deviceStateConcurrency = 4
+
+// datapointState contains the current values of a datapoint or the error that occurred while reading it
+type datapointState struct {
+	Channel   string   `json:"channel"`
+	Datapoint string   `json:"datapoint"`
+	Values    []string `json:"values,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// GetDeviceState reads and displays the current values of all datapoints across all channels of a device
+func GetDeviceState(config GetCommandConfig, serial string) error {
+	return outputDeviceDatapoints(config, serial, "get device-state", printDeviceState)
+}
+
+// GetAllDatapoints reads all datapoints of a device and displays them as a tree of channels and datapoints
+func GetAllDatapoints(config GetCommandConfig, serial string) error {
+	return outputDeviceDatapoints(config, serial, "get datapoint --all", printDatapointTree)
+}
+
+// outputDeviceDatapoints reads all datapoints of a device and outputs them as JSON or using the given text printer.
+// It returns an error after the output if any datapoint could not be read.
+func outputDeviceDatapoints(config GetCommandConfig, serial string, command string, printText func(serial string, states []datapointState)) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Get device
+	deviceResponse, err := sysAp.GetDevice(serial)
+	if err != nil {
+		return handleSysApError(err, "get device", config.TLSEnabled, config.SkipTLSVerify)
+	}
+
+	// Check if the specific device exists
+	device, err := findDevice(deviceResponse, serial)
+	if err != nil {
+		return err
+	}
+
+	states := readDeviceState(sysAp, serial, device)
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		if err := outputCommandJSON(states, "device state", config.Prettify, config.Envelope, sysAp.GetHostName(), command); err != nil {
+			return err
+		}
+	} else {
+		printText(serial, states)
+	}
+
+	// Report failed datapoints after the output, so that the successfully read values are not lost
+	failed := 0
+	for _, state := range states {
+		if state.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to read %d of %d datapoints", failed, len(states))
+	}
+
+	return nil
+}
+
+// readDeviceState reads all input and output datapoints of a device with bounded concurrency.
+// Errors are recorded per datapoint and do not abort reading the remaining datapoints.
+// The result is sorted by channel and datapoint.
+func readDeviceState(sysAp *freeathome.SystemAccessPoint, serial string, device *models.Device) []datapointState {
+	addresses := device.DatapointAddresses()
+	states := make([]datapointState, len(addresses))
+	for i, address := range addresses {
+		states[i] = datapointState{Channel: address.Channel, Datapoint: address.Datapoint}
+	}
+
+	// Read the datapoints, each worker writes only to its own index
+	var waitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, deviceStateConcurrency)
+	for i := range states {
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+		go func(state *datapointState) {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+
+			response, err := sysAp.GetDatapoint(serial, state.Channel, state.Datapoint)
+			if err != nil {
+				state.Error = err.Error()
+				return
+			}
+			if response != nil {
+				datapoint, _ := response.DataPoint()
+				state.Values = datapoint.Values
+			}
+		}(&states[i])
+	}
+	waitGroup.Wait()
+
+	return states
+}
+
+// printDeviceState prints the datapoint states as plain text grouped by channel
+func printDeviceState(serial string, states []datapointState) {
+	fmt.Printf("Device Serial: %s\n", serial)
+	if len(states) == 0 {
+		fmt.Println("  Datapoints: (none)")
+		return
+	}
+
+	channel := ""
+	for _, state := range states {
+		if state.Channel != channel {
+			channel = state.Channel
+			fmt.Printf("  Channel: %s\n", channel)
+		}
+		if state.Error != "" {
+			fmt.Printf("    %s: error: %s\n", state.Datapoint, state.Error)
+		} else if len(state.Values) > 0 {
+			fmt.Printf("    %s: %v\n", state.Datapoint, state.Values)
+		} else {
+			fmt.Printf("    %s: (empty)\n", state.Datapoint)
+		}
+	}
+}
+
+// printDatapointTree prints the datapoint states as a tree of channels and datapoints
+func printDatapointTree(serial string, states []datapointState) {
+	fmt.Println(serial)
+
+	// Group the sorted states by channel
+	var channels [][]datapointState
+	for _, state := range states {
+		if len(channels) == 0 || channels[len(channels)-1][0].Channel != state.Channel {
+			channels = append(channels, nil)
+		}
+		channels[len(channels)-1] = append(channels[len(channels)-1], state)
+	}
+
+	for i, channel := range channels {
+		lastChannel := i == len(channels)-1
+		branch, indent := "├── ", "│   "
+		if lastChannel {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Printf("%s%s\n", branch, channel[0].Channel)
+
+		for j, state := range channel {
+			leaf := "├── "
+			if j == len(channel)-1 {
+				leaf = "└── "
+			}
+			switch {
+			case state.Error != "":
+				fmt.Printf("%s%s%s: error: %s\n", indent, leaf, state.Datapoint, state.Error)
+			case len(state.Values) > 0:
+				fmt.Printf("%s%s%s: %v\n", indent, leaf, state.Datapoint, state.Values)
+			default:
+				fmt.Printf("%s%s%s: (empty)\n", indent, leaf, state.Datapoint)
+			}
+		}
+	}
+}
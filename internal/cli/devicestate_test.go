@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// deviceStateResponseBody is a device fixture with two channels
+const deviceStateResponseBody = `{
+  "00000000-0000-0000-0000-000000000000": {
+    "devices": {
+      "ABB7F595EC47": {
+        "channels": {
+          "ch0000": {
+            "inputs": {"idp0000": {"value": "0"}},
+            "outputs": {"odp0000": {"value": "1"}, "odp0001": {"value": "0"}}
+          },
+          "ch0001": {
+            "outputs": {"odp0000": {"value": "42"}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+// routingRoundTripper returns responses depending on the request path and counts the datapoint requests
+type routingRoundTripper struct {
+	mutex      sync.Mutex
+	datapoints []string
+	failing    string
+}
+
+// RoundTrip executes a single HTTP transaction and returns the response.
+func (m *routingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	if strings.Contains(path, "/device/") {
+		return newTestResponse(http.StatusOK, deviceStateResponseBody), nil
+	}
+
+	// Datapoint request, the path ends with serial.channel.datapoint
+	address := path[strings.LastIndex(path, "/")+1:]
+	m.mutex.Lock()
+	m.datapoints = append(m.datapoints, address)
+	m.mutex.Unlock()
+
+	if address == m.failing {
+		return newTestResponse(http.StatusInternalServerError, "Internal Server Error"), nil
+	}
+	return newTestResponse(http.StatusOK, `{"00000000-0000-0000-0000-000000000000":{"values":["`+address+`"]}}`), nil
+}
+
+// newTestResponse creates an HTTP response with the given status code and body
+func newTestResponse(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+// setupDeviceStateMock overrides the setupFunc with a SystemAccessPoint using the routing round tripper
+func setupDeviceStateMock(t *testing.T, transport *routingRoundTripper) {
+	t.Helper()
+
+	config := freeathome.NewConfig("test-host", "test-user", "test-pass")
+	config.Logger = freeathome.NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+	config.Client = resty.New().SetTransport(transport)
+	sysAp := freeathome.MustNewSystemAccessPoint(config)
+
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		return sysAp, nil
+	}
+	t.Cleanup(func() {
+		setupFunc = setup
+	})
+}
+
+// captureStdout runs the function and returns everything it writes to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	fn()
+
+	_ = w.Close()
+	output, _ := io.ReadAll(r)
+	return string(output)
+}
+
+// TestGetDeviceStateJSON tests that all datapoints of the device are read and output as JSON
+func TestGetDeviceStateJSON(t *testing.T) {
+	transport := &routingRoundTripper{}
+	setupDeviceStateMock(t, transport)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetDeviceState(GetCommandConfig{OutputFormat: "json"}, "ABB7F595EC47")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var states []datapointState
+	if err := json.Unmarshal([]byte(output), &states); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	expected := []string{
+		"ABB7F595EC47.ch0000.idp0000",
+		"ABB7F595EC47.ch0000.odp0000",
+		"ABB7F595EC47.ch0000.odp0001",
+		"ABB7F595EC47.ch0001.odp0000",
+	}
+	if len(states) != len(expected) {
+		t.Fatalf("Expected %d datapoint states, got %d", len(expected), len(states))
+	}
+	for i, state := range states {
+		address := "ABB7F595EC47." + state.Channel + "." + state.Datapoint
+		if address != expected[i] {
+			t.Errorf("Expected datapoint '%s' at index %d, got '%s'", expected[i], i, address)
+		}
+		if len(state.Values) != 1 || state.Values[0] != address {
+			t.Errorf("Expected values [%s], got %v", address, state.Values)
+		}
+		if state.Error != "" {
+			t.Errorf("Expected no error for '%s', got '%s'", address, state.Error)
+		}
+	}
+	if len(transport.datapoints) != len(expected) {
+		t.Errorf("Expected %d datapoint requests, got %d", len(expected), len(transport.datapoints))
+	}
+}
+
+// TestGetDeviceStateWithError tests that a failing datapoint is reported without aborting the other reads
+func TestGetDeviceStateWithError(t *testing.T) {
+	transport := &routingRoundTripper{failing: "ABB7F595EC47.ch0000.odp0001"}
+	setupDeviceStateMock(t, transport)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetDeviceState(GetCommandConfig{OutputFormat: "text"}, "ABB7F595EC47")
+	})
+
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if !strings.Contains(err.Error(), "failed to read 1 of 4 datapoints") {
+		t.Errorf("Expected error to contain 'failed to read 1 of 4 datapoints', got '%s'", err.Error())
+	}
+	if len(transport.datapoints) != 4 {
+		t.Errorf("Expected 4 datapoint requests, got %d", len(transport.datapoints))
+	}
+
+	expected := `Device Serial: ABB7F595EC47
+  Channel: ch0000
+    idp0000: [ABB7F595EC47.ch0000.idp0000]
+    odp0000: [ABB7F595EC47.ch0000.odp0000]
+    odp0001: error: failed to get datapoint: Internal Server Error
+  Channel: ch0001
+    odp0000: [ABB7F595EC47.ch0001.odp0000]
+`
+	if output != expected {
+		t.Errorf("Expected output '%s', got '%s'", expected, output)
+	}
+}
+
+// TestGetDeviceStateUnknownDevice tests that an unknown device returns an error
+func TestGetDeviceStateUnknownDevice(t *testing.T) {
+	setupDeviceStateMock(t, &routingRoundTripper{})
+
+	err := GetDeviceState(GetCommandConfig{OutputFormat: "text"}, "UNKNOWN")
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if !strings.Contains(err.Error(), "no device found with serial: UNKNOWN") {
+		t.Errorf("Expected error to contain 'no device found with serial: UNKNOWN', got '%s'", err.Error())
+	}
+}
+
+// TestGetAllDatapoints tests that all datapoints of a multi-channel device are read and printed as a tree
+func TestGetAllDatapoints(t *testing.T) {
+	transport := &routingRoundTripper{}
+	setupDeviceStateMock(t, transport)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetAllDatapoints(GetCommandConfig{OutputFormat: "text"}, "ABB7F595EC47")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(transport.datapoints) != 4 {
+		t.Errorf("Expected 4 datapoint requests, got %d", len(transport.datapoints))
+	}
+
+	expected := `ABB7F595EC47
+├── ch0000
+│   ├── idp0000: [ABB7F595EC47.ch0000.idp0000]
+│   ├── odp0000: [ABB7F595EC47.ch0000.odp0000]
+│   └── odp0001: [ABB7F595EC47.ch0000.odp0001]
+└── ch0001
+    └── odp0000: [ABB7F595EC47.ch0001.odp0000]
+`
+	if output != expected {
+		t.Errorf("Expected output '%s', got '%s'", expected, output)
+	}
+}
+
+// TestGetAllDatapointsWithError tests that a failing datapoint is shown in the tree and reported as an error
+func TestGetAllDatapointsWithError(t *testing.T) {
+	transport := &routingRoundTripper{failing: "ABB7F595EC47.ch0001.odp0000"}
+	setupDeviceStateMock(t, transport)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetAllDatapoints(GetCommandConfig{OutputFormat: "text"}, "ABB7F595EC47")
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to read 1 of 4 datapoints") {
+		t.Errorf("Expected error to contain 'failed to read 1 of 4 datapoints', got '%v'", err)
+	}
+	if !strings.Contains(output, "    └── odp0000: error: failed to get datapoint: Internal Server Error\n") {
+		t.Errorf("Expected output to contain the datapoint error, got '%s'", output)
+	}
+}
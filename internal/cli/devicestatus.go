@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+const (
+	// deviceOnline is the status of a device that is reachable according to the configuration
+	deviceOnline = "online"
+	// deviceOffline is the status of a device that the configuration flags as unresponsive or defect
+	deviceOffline = "offline"
+	// deviceStatusUnknown is the status of a device that is not part of the configuration
+	deviceStatusUnknown = "unknown"
+)
+
+// deviceStatus is a device of the device list annotated with its reachability
+type deviceStatus struct {
+	Serial string `json:"serial"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status"`
+}
+
+// GetDeviceListWithStatus retrieves the device list like GetDeviceList and annotates each device with its name and whether it
+// is online or offline, derived from the reachability flags of the configuration. The text output is rendered as a table.
+func GetDeviceListWithStatus(config GetCommandConfig) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Get device list and configuration
+	deviceList, err := sysAp.GetDeviceList()
+	if err != nil {
+		return handleSysApError(err, "get device list", config.TLSEnabled, config.SkipTLSVerify)
+	}
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
+	}
+	sysApConfig, err := sysApConfiguration(*configuration, sysAp.GetUUID())
+	if err != nil {
+		return err
+	}
+
+	var serials []string
+	if deviceList != nil {
+		serials, _ = deviceList.Serials()
+	}
+	devices := deviceStatuses(serials, sysApConfig)
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		return outputCommandJSON(devices, "device list", config.Prettify, config.Envelope, sysAp.GetHostName(), "get devicelist")
+	}
+
+	if len(devices) == 0 {
+		fmt.Println("No devices found")
+		return nil
+	}
+
+	// Output as a table (one device per line)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "SERIAL\tNAME\tSTATUS")
+	for _, device := range devices {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", device.Serial, device.Name, device.Status)
+	}
+	return writer.Flush()
+}
+
+// deviceStatuses annotates the serials of the device list in their order with the name and reachability from the configuration
+func deviceStatuses(serials []string, sysApConfig models.SysAP) []deviceStatus {
+	devices := make([]deviceStatus, 0, len(serials))
+	for _, serial := range serials {
+		device, exists := sysApConfig.Devices[serial]
+		if !exists {
+			devices = append(devices, deviceStatus{Serial: serial, Status: deviceStatusUnknown})
+			continue
+		}
+
+		status := deviceOffline
+		if device.IsReachable() {
+			status = deviceOnline
+		}
+		name := ""
+		if device.DisplayName != nil {
+			name = *device.DisplayName
+		}
+		devices = append(devices, deviceStatus{Serial: serial, Name: name, Status: status})
+	}
+	return devices
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// setupDeviceStatusMock serves a device list and a configuration with reachable, unresponsive, defect and unknown devices
+func setupDeviceStatusMock(t *testing.T) {
+	t.Helper()
+
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{
+		{"GET /devicelist", `{"00000000-0000-0000-0000-000000000000":["ABB700000001","ABB700000002","ABB700000003","ABB700000004"]}`},
+		{"GET /configuration", `{"00000000-0000-0000-0000-000000000000":{"devices":{
+			"ABB700000001":{"displayName":"Kitchen light","unresponsive":false,"defect":false},
+			"ABB700000002":{"displayName":"Garage door","unresponsive":true},
+			"ABB700000003":{"displayName":"Hallway sensor","defect":true}
+		}}}`},
+	}})
+}
+
+// TestGetDeviceListWithStatusText tests that the status column reflects the reachability flags of the configuration
+func TestGetDeviceListWithStatusText(t *testing.T) {
+	setupDeviceStatusMock(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetDeviceListWithStatus(GetCommandConfig{OutputFormat: "text"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `SERIAL        NAME            STATUS
+ABB700000001  Kitchen light   online
+ABB700000002  Garage door     offline
+ABB700000003  Hallway sensor  offline
+ABB700000004                  unknown
+`
+	if output != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// TestGetDeviceListWithStatusJSON tests that the annotated devices are printed as JSON
+func TestGetDeviceListWithStatusJSON(t *testing.T) {
+	setupDeviceStatusMock(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetDeviceListWithStatus(GetCommandConfig{OutputFormat: "json"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var devices []deviceStatus
+	if err := json.Unmarshal([]byte(output), &devices); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	expected := []deviceStatus{
+		{Serial: "ABB700000001", Name: "Kitchen light", Status: deviceOnline},
+		{Serial: "ABB700000002", Name: "Garage door", Status: deviceOffline},
+		{Serial: "ABB700000003", Name: "Hallway sensor", Status: deviceOffline},
+		{Serial: "ABB700000004", Status: deviceStatusUnknown},
+	}
+	if len(devices) != len(expected) {
+		t.Fatalf("Expected %d devices, got %d", len(expected), len(devices))
+	}
+	for i, device := range devices {
+		if device != expected[i] {
+			t.Errorf("Expected device %+v, got %+v", expected[i], device)
+		}
+	}
+}
+
+// TestGetDeviceListWithStatusConfigurationError tests that a failing configuration request is reported
+func TestGetDeviceListWithStatusConfigurationError(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{
+		{"GET /devicelist", `{"00000000-0000-0000-0000-000000000000":["ABB700000001"]}`},
+	}})
+
+	err := GetDeviceListWithStatus(GetCommandConfig{OutputFormat: "text"})
+	if err == nil || !strings.Contains(err.Error(), "get configuration") {
+		t.Errorf("Expected configuration error, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// attributeDifference describes an attribute of a device that differs between two configurations
+type attributeDifference struct {
+	Serial    string
+	Attribute string
+	A         string
+	B         string
+}
+
+// configurationDiff describes the differences between the devices of two configurations
+type configurationDiff struct {
+	// OnlyInA contains the sorted serials of the devices only present in the first configuration
+	OnlyInA []string
+	// OnlyInB contains the sorted serials of the devices only present in the second configuration
+	OnlyInB []string
+	// Changed contains the differing attributes of the devices present in both configurations, sorted by serial
+	Changed []attributeDifference
+}
+
+// diffConfigurations compares the devices of two configurations by their serials and attributes
+func diffConfigurations(a, b models.Configuration) configurationDiff {
+	devicesA, devicesB := configurationDevices(a), configurationDevices(b)
+	diff := configurationDiff{OnlyInA: []string{}, OnlyInB: []string{}, Changed: []attributeDifference{}}
+
+	for _, serial := range slices.Sorted(maps.Keys(devicesA)) {
+		deviceB, exists := devicesB[serial]
+		if !exists {
+			diff.OnlyInA = append(diff.OnlyInA, serial)
+			continue
+		}
+
+		attributesA, attributesB := deviceAttributes(devicesA[serial]), deviceAttributes(deviceB)
+		for _, attribute := range deviceAttributeNames {
+			if attributesA[attribute] != attributesB[attribute] {
+				diff.Changed = append(diff.Changed, attributeDifference{Serial: serial, Attribute: attribute, A: attributesA[attribute], B: attributesB[attribute]})
+			}
+		}
+	}
+	for _, serial := range slices.Sorted(maps.Keys(devicesB)) {
+		if _, exists := devicesA[serial]; !exists {
+			diff.OnlyInB = append(diff.OnlyInB, serial)
+		}
+	}
+
+	return diff
+}
+
+// configurationDevices returns the devices of all system access points of the configuration by their serial
+func configurationDevices(configuration models.Configuration) map[string]models.Device {
+	devices := map[string]models.Device{}
+	for _, sysAp := range configuration {
+		maps.Copy(devices, sysAp.Devices)
+	}
+	return devices
+}
+
+// deviceAttributeNames are the compared device attributes in the order they are reported
+var deviceAttributeNames = []string{"displayName", "floor", "room", "interface", "nativeId", "channels"}
+
+// deviceAttributes returns the compared attributes of the device, the channels are listed as sorted identifiers
+func deviceAttributes(device models.Device) map[string]string {
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	channels := ""
+	if device.Channels != nil {
+		channels = strings.Join(slices.Sorted(maps.Keys(*device.Channels)), ",")
+	}
+
+	return map[string]string{
+		"displayName": value(device.DisplayName),
+		"floor":       value(device.Floor),
+		"room":        value(device.Room),
+		"interface":   value(device.Interface),
+		"nativeId":    value(device.NativeID),
+		"channels":    channels,
+	}
+}
+
+// DiffSysAps fetches the configurations of the system access points configured in the two profiles, given as config
+// file paths, and prints the devices present on only one of them and the attributes that differ between them.
+func DiffSysAps(config CommandConfig, profiles []string) error {
+	if len(profiles) != 2 {
+		return fmt.Errorf("exactly two profiles are required, got %d", len(profiles))
+	}
+
+	configurations := make([]models.Configuration, len(profiles))
+	for i, profile := range profiles {
+		// Every profile gets its own viper instance, so the values of one config file cannot leak into the other
+		profileConfig := config
+		profileConfig.Viper = viper.New()
+		sysAp, err := setupFunc(profileConfig, profile)
+		if err != nil {
+			return fmt.Errorf("failed to set up profile %s: %w", profile, err)
+		}
+
+		configuration, err := sysAp.GetConfiguration()
+		if err != nil {
+			return handleSysApError(err, fmt.Sprintf("get configuration of profile %s", profile), config.TLSEnabled, config.SkipTLSVerify)
+		}
+		configurations[i] = *configuration
+	}
+
+	diff := diffConfigurations(configurations[0], configurations[1])
+	if len(diff.OnlyInA) == 0 && len(diff.OnlyInB) == 0 && len(diff.Changed) == 0 {
+		fmt.Println("No differences found")
+		return nil
+	}
+
+	for _, serial := range diff.OnlyInA {
+		fmt.Printf("Only in %s: %s\n", profiles[0], serial)
+	}
+	for _, serial := range diff.OnlyInB {
+		fmt.Printf("Only in %s: %s\n", profiles[1], serial)
+	}
+	for _, change := range diff.Changed {
+		fmt.Printf("%s %s: %q != %q\n", change.Serial, change.Attribute, change.A, change.B)
+	}
+	return nil
+}
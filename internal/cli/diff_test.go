@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// diffConfigurationA and diffConfigurationB are the configurations of the two mock system access points
+const diffConfigurationA = `{"00000000-0000-0000-0000-000000000000":{"devices":{
+	"ABB700000001":{"displayName":"Light","floor":"01","room":"01","interface":"TP","channels":{"ch0000":{}}},
+	"ABB700000002":{"displayName":"Blind","floor":"01","room":"02","interface":"TP"},
+	"ABB700000003":{"displayName":"Sensor","interface":"TP"}
+},"floorplan":{"floors":{}}}}`
+
+const diffConfigurationB = `{"00000000-0000-0000-0000-000000000000":{"devices":{
+	"ABB700000001":{"displayName":"Light","floor":"01","room":"01","interface":"TP","channels":{"ch0000":{}}},
+	"ABB700000002":{"displayName":"Blind kitchen","floor":"01","room":"03","interface":"TP"},
+	"ABB700000004":{"displayName":"Switch","interface":"wireless"}
+},"floorplan":{"floors":{}}}}`
+
+// setupDiffMock overrides the setupFunc with one mock system access point per profile
+func setupDiffMock(t *testing.T, configurations map[string]string) {
+	t.Helper()
+
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		sysApConfig := freeathome.NewConfig(configFile, "test-user", "test-pass")
+		sysApConfig.Logger = freeathome.NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+		sysApConfig.Client = resty.New().SetTransport(&pathRoundTripper{responses: []pathResponse{
+			{"GET /configuration", configurations[configFile]},
+		}})
+		return freeathome.MustNewSystemAccessPoint(sysApConfig), nil
+	}
+	t.Cleanup(func() {
+		setupFunc = setup
+	})
+}
+
+// TestDiffConfigurations tests that missing devices and differing attributes are reported in a stable order
+func TestDiffConfigurations(t *testing.T) {
+	var a, b models.Configuration
+	if err := json.Unmarshal([]byte(diffConfigurationA), &a); err != nil {
+		t.Fatalf("failed to unmarshal configuration: %v", err)
+	}
+	if err := json.Unmarshal([]byte(diffConfigurationB), &b); err != nil {
+		t.Fatalf("failed to unmarshal configuration: %v", err)
+	}
+
+	diff := diffConfigurations(a, b)
+	if !slices.Equal(diff.OnlyInA, []string{"ABB700000003"}) {
+		t.Errorf("Expected only ABB700000003 in the first configuration, got %v", diff.OnlyInA)
+	}
+	if !slices.Equal(diff.OnlyInB, []string{"ABB700000004"}) {
+		t.Errorf("Expected only ABB700000004 in the second configuration, got %v", diff.OnlyInB)
+	}
+	expected := []attributeDifference{
+		{Serial: "ABB700000002", Attribute: "displayName", A: "Blind", B: "Blind kitchen"},
+		{Serial: "ABB700000002", Attribute: "room", A: "02", B: "03"},
+	}
+	if !slices.Equal(diff.Changed, expected) {
+		t.Errorf("Expected changes %+v, got %+v", expected, diff.Changed)
+	}
+}
+
+// TestDiffConfigurationsEqual tests that identical configurations have no differences
+func TestDiffConfigurationsEqual(t *testing.T) {
+	configuration := loadConfigurationFixture(t)
+
+	diff := diffConfigurations(configuration, configuration)
+	if len(diff.OnlyInA) != 0 || len(diff.OnlyInB) != 0 || len(diff.Changed) != 0 {
+		t.Errorf("Expected no differences, got %+v", diff)
+	}
+}
+
+// TestDiffSysAps tests that the differences between two mock system access points are printed
+func TestDiffSysAps(t *testing.T) {
+	setupDiffMock(t, map[string]string{"a.yaml": diffConfigurationA, "b.yaml": diffConfigurationB})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = DiffSysAps(CommandConfig{}, []string{"a.yaml", "b.yaml"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "Only in a.yaml: ABB700000003\n" +
+		"Only in b.yaml: ABB700000004\n" +
+		"ABB700000002 displayName: \"Blind\" != \"Blind kitchen\"\n" +
+		"ABB700000002 room: \"02\" != \"03\"\n"
+	if output != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// TestDiffSysApsNoDifferences tests the output for two system access points with the same devices
+func TestDiffSysApsNoDifferences(t *testing.T) {
+	setupDiffMock(t, map[string]string{"a.yaml": diffConfigurationA, "b.yaml": diffConfigurationA})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = DiffSysAps(CommandConfig{}, []string{"a.yaml", "b.yaml"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "No differences found\n" {
+		t.Errorf("Expected no differences, got %q", output)
+	}
+}
+
+// TestDiffSysApsConfigurationError tests that a failure to fetch a configuration names the profile
+func TestDiffSysApsConfigurationError(t *testing.T) {
+	setupDiffMock(t, map[string]string{"a.yaml": diffConfigurationA})
+
+	var err error
+	captureStdout(t, func() {
+		err = DiffSysAps(CommandConfig{}, []string{"a.yaml", "b.yaml"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "get configuration of profile b.yaml") {
+		t.Errorf("Expected configuration error for profile b.yaml, got %v", err)
+	}
+}
+
+// TestDiffSysApsProfileCount tests that exactly two profiles are required
+func TestDiffSysApsProfileCount(t *testing.T) {
+	err := DiffSysAps(CommandConfig{}, []string{"a.yaml"})
+	if err == nil || err.Error() != "exactly two profiles are required, got 1" {
+		t.Errorf("Expected profile count error, got %v", err)
+	}
+}
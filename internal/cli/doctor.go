@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// DoctorCommandConfig is a struct that contains the configuration for the doctor command
+type DoctorCommandConfig struct {
+	CommandConfig
+	MaxClockSkew time.Duration
+}
+
+// Doctor checks the system access point for conditions that confuse time-sensitive features and prints a line per check.
+// Checks exceeding their threshold are reported as warnings, only checks that cannot be performed return an error.
+func Doctor(config DoctorCommandConfig) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Compare the clock of the system access point to the local clock
+	skew, err := sysAp.GetClockSkew()
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatClockSkewCheck(skew, config.MaxClockSkew))
+	return nil
+}
+
+// formatClockSkewCheck formats the result of the clock skew check, warning if the skew exceeds the threshold
+func formatClockSkewCheck(skew freeathome.ClockSkew, threshold time.Duration) string {
+	// The time of the system access point has a resolution of one second
+	rounded := skew.Skew.Round(time.Second)
+	var description string
+	switch {
+	case rounded > 0:
+		description = fmt.Sprintf("system access point clock is %s ahead of the local clock", rounded)
+	case rounded < 0:
+		description = fmt.Sprintf("system access point clock is %s behind the local clock", -rounded)
+	default:
+		description = "system access point clock is in sync with the local clock"
+	}
+
+	if skew.Exceeds(threshold) {
+		return fmt.Sprintf("WARNING clock skew: %s, exceeding %s. Timestamps and history may be misleading, check the time settings of the system access point", description, threshold)
+	}
+	return fmt.Sprintf("OK      clock skew: %s", description)
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// TestFormatClockSkewCheck tests that the clock skew is described and a warning is printed above the threshold
+func TestFormatClockSkewCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		skew     time.Duration
+		expected string
+	}{
+		{name: "In sync", skew: 300 * time.Millisecond, expected: "OK      clock skew: system access point clock is in sync with the local clock"},
+		{name: "Ahead within threshold", skew: 12 * time.Second, expected: "OK      clock skew: system access point clock is 12s ahead of the local clock"},
+		{name: "Behind at threshold", skew: -30 * time.Second, expected: "OK      clock skew: system access point clock is 30s behind the local clock"},
+		{name: "Ahead above threshold", skew: 2*time.Minute + 5*time.Second, expected: "WARNING clock skew: system access point clock is 2m5s ahead of the local clock, exceeding 30s. Timestamps and history may be misleading, check the time settings of the system access point"},
+		{name: "Behind above threshold", skew: -31 * time.Second, expected: "WARNING clock skew: system access point clock is 31s behind the local clock, exceeding 30s."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := formatClockSkewCheck(freeathome.ClockSkew{Skew: tt.skew}, 30*time.Second)
+			if !strings.HasPrefix(actual, tt.expected) {
+				t.Errorf("Expected check starting with %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// TestDoctorClockSkewUnavailable tests that a missing Date header is reported as an error
+func TestDoctorClockSkewUnavailable(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{{"GET /devicelist", `{}`}}})
+
+	err := Doctor(DoctorCommandConfig{MaxClockSkew: 30 * time.Second})
+	if err == nil || !strings.Contains(err.Error(), "response contains no Date header") {
+		t.Errorf("Expected missing Date header error, got %v", err)
+	}
+}
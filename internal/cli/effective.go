@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Sources of the effective settings in the order of their precedence
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+// EffectiveCommandConfig is a struct that contains the configuration for the configure effective command
+type EffectiveCommandConfig struct {
+	CommandConfig
+	ConfigFile string
+	// ChangedFlags contains the names of the flags set on the command line
+	ChangedFlags []string
+}
+
+// effectiveSetting is a setting in effect with the mechanism it was set by
+type effectiveSetting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+// ShowEffectiveConfiguration prints the settings in effect after merging flags, environment variables, the config file and
+// the defaults, together with the source of each value. The password is masked.
+func ShowEffectiveConfiguration(config EffectiveCommandConfig) error {
+	cfg, err := load(config.Viper, config.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "SETTING\tVALUE\tSOURCE")
+	for _, setting := range effectiveSettings(config, cfg) {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", setting.Name, setting.Value, setting.Source)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if config.Viper.ConfigFileUsed() != "" {
+		fmt.Printf("Config file: %s\n", config.Viper.ConfigFileUsed())
+	} else {
+		fmt.Println("Config file: (not found)")
+	}
+	return nil
+}
+
+// effectiveSettings returns the effective settings of the loaded configuration and the command flags with their sources
+func effectiveSettings(config EffectiveCommandConfig, cfg *Config) []effectiveSetting {
+	password := "(not set)"
+	if cfg.Password != "" {
+		password = "***"
+	}
+
+	// Command flags are not part of the config file, so they are set on the command line, by an environment variable or default
+	flagSource := func(name string) string {
+		if slices.Contains(config.ChangedFlags, name) {
+			return sourceFlag
+		}
+		if variable, exists := flagEnvVars[name]; exists && os.Getenv(variable) != "" {
+			return sourceEnv
+		}
+		return sourceDefault
+	}
+
+	return []effectiveSetting{
+		{Name: "hostname", Value: cfg.Hostname, Source: config.configSource("hostname")},
+		{Name: "username", Value: cfg.Username, Source: config.configSource("username")},
+		{Name: "password", Value: password, Source: config.configSource("password")},
+		{Name: "sysap-uuid", Value: cfg.SysApUUID, Source: config.configSource("sysap-uuid")},
+		{Name: "read-only", Value: strconv.FormatBool(cfg.ReadOnly), Source: config.configSource("read-only")},
+		{Name: "api-version", Value: cfg.APIVersion, Source: config.configSource("api-version")},
+		{Name: "scales", Value: strings.Join(cfg.Scales, "; "), Source: config.configSource("scales")},
+		{Name: "wrap-log-lines", Value: strconv.FormatBool(cfg.WrapLogLines), Source: config.configSource("wrap-log-lines")},
+		{Name: "tls", Value: strconv.FormatBool(config.TLSEnabled), Source: flagSource("tls")},
+		{Name: "skip-tls-verify", Value: strconv.FormatBool(config.SkipTLSVerify), Source: flagSource("skip-tls-verify")},
+		{Name: "log-level", Value: config.LogLevel, Source: flagSource("log-level")},
+	}
+}
+
+// configSource returns the source of a config key following the precedence of viper: flag, environment variable, config
+// file and default
+func (config EffectiveCommandConfig) configSource(key string) string {
+	if slices.Contains(config.ChangedFlags, key) {
+		return sourceFlag
+	}
+	if name, exists := configEnvVars[key]; exists && os.Getenv(name) != "" {
+		return sourceEnv
+	}
+	if config.Viper.InConfig(key) {
+		return sourceFile
+	}
+	return sourceDefault
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// TestShowEffectiveConfiguration tests that the effective settings are printed with their sources
+func TestShowEffectiveConfiguration(t *testing.T) {
+	t.Setenv(freeathome.EnvHostname, "env-host")
+	t.Setenv(freeathome.EnvUsername, "")
+	t.Setenv(freeathome.EnvPassword, "")
+	t.Setenv(EnvConfigFile, "")
+	configFile := createTestConfigFile(t, "hostname: file-host\nusername: file-user\npassword: secret\n")
+
+	v := viper.New()
+	v.Set("read-only", true)
+	config := EffectiveCommandConfig{
+		CommandConfig: CommandConfig{
+			Viper:      v,
+			TLSEnabled: true,
+			LogLevel:   "debug",
+		},
+		ConfigFile:   configFile,
+		ChangedFlags: []string{"log-level", "read-only"},
+	}
+
+	var err error
+	output := captureStdout(t, func() {
+		err = ShowEffectiveConfiguration(config)
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string][]string{
+		"hostname":        {"env-host", sourceEnv},
+		"username":        {"file-user", sourceFile},
+		"password":        {"***", sourceFile},
+		"read-only":       {"true", sourceFlag},
+		"tls":             {"true", sourceDefault},
+		"skip-tls-verify": {"false", sourceDefault},
+		"log-level":       {"debug", sourceFlag},
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		if want, exists := expected[fields[0]]; exists {
+			if fields[1] != want[0] || fields[2] != want[1] {
+				t.Errorf("Expected %s to be %s from %s, got %s from %s", fields[0], want[0], want[1], fields[1], fields[2])
+			}
+			delete(expected, fields[0])
+		}
+	}
+	for name := range expected {
+		t.Errorf("Expected setting %s in output:\n%s", name, output)
+	}
+	if strings.Contains(output, "secret") {
+		t.Errorf("Expected the password to be masked, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Config file: "+configFile) {
+		t.Errorf("Expected the config file in output, got:\n%s", output)
+	}
+}
+
+// TestEffectiveSettingsPasswordNotSet tests that a missing password is reported as not set from the default
+func TestEffectiveSettingsPasswordNotSet(t *testing.T) {
+	t.Setenv(freeathome.EnvPassword, "")
+	config := EffectiveCommandConfig{CommandConfig: CommandConfig{Viper: viper.New()}}
+
+	for _, setting := range effectiveSettings(config, &Config{}) {
+		if setting.Name == "password" {
+			if setting.Value != "(not set)" || setting.Source != sourceDefault {
+				t.Errorf("Expected password to be (not set) from default, got %s from %s", setting.Value, setting.Source)
+			}
+			return
+		}
+	}
+	t.Error("Expected password setting")
+}
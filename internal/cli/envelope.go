@@ -0,0 +1,37 @@
+package cli
+
+import "time"
+
+// envelope wraps command output with metadata for scripting
+type envelope struct {
+	Meta envelopeMeta `json:"meta"`
+	Data any          `json:"data"`
+}
+
+// envelopeMeta contains the metadata of an envelope
+type envelopeMeta struct {
+	Hostname  string    `json:"hostname"`
+	Timestamp time.Time `json:"timestamp"`
+	Command   string    `json:"command"`
+}
+
+// newEnvelope creates an envelope for the data returned by a command. The timestamp is converted to UTC.
+func newEnvelope(hostname string, command string, timestamp time.Time, data any) envelope {
+	return envelope{
+		Meta: envelopeMeta{
+			Hostname:  hostname,
+			Timestamp: timestamp.UTC(),
+			Command:   command,
+		},
+		Data: data,
+	}
+}
+
+// outputCommandJSON provides JSON output for a command, wrapping the data in an envelope if requested
+func outputCommandJSON(data any, dataType string, prettify bool, wrap bool, hostname string, command string) error {
+	if wrap {
+		data = newEnvelope(hostname, command, time.Now(), data)
+	}
+
+	return outputJSON(data, dataType, prettify)
+}
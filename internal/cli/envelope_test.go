@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// TestNewEnvelope tests that the envelope contains the metadata and the data
+func TestNewEnvelope(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	data := map[string]string{"key": "value"}
+
+	result := newEnvelope("test-host", "get devicelist", timestamp, data)
+
+	if result.Meta.Hostname != "test-host" {
+		t.Errorf("Expected hostname 'test-host', got '%s'", result.Meta.Hostname)
+	}
+	if result.Meta.Command != "get devicelist" {
+		t.Errorf("Expected command 'get devicelist', got '%s'", result.Meta.Command)
+	}
+	if result.Meta.Timestamp.Location() != time.UTC {
+		t.Errorf("Expected timestamp in UTC, got %v", result.Meta.Timestamp.Location())
+	}
+	if !result.Meta.Timestamp.Equal(timestamp) {
+		t.Errorf("Expected timestamp %v, got %v", timestamp, result.Meta.Timestamp)
+	}
+	if !reflect.DeepEqual(result.Data, data) {
+		t.Errorf("Expected data %v, got %v", data, result.Data)
+	}
+}
+
+// TestGetDeviceListWithEnvelope tests that the JSON output is wrapped in an envelope if requested
+func TestGetDeviceListWithEnvelope(t *testing.T) {
+	responseBody := `{"00000000-0000-0000-0000-000000000000":["ABB7F595EC47","ABB7013B85DE"]}`
+
+	v := setupViper(t)
+	v.Set("hostname", "test-host")
+	sysAp, _, _ := setupMock(t, v, http.StatusOK, responseBody)
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		return sysAp, nil
+	}
+	defer func() {
+		setupFunc = setup
+	}()
+
+	// Capture stdout for output testing
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	before := time.Now().UTC().Truncate(time.Second)
+	err := GetDeviceList(GetCommandConfig{
+		CommandConfig: CommandConfig{
+			Viper:    v,
+			LogLevel: "info",
+		},
+		OutputFormat: "json",
+		Envelope:     true,
+	})
+
+	// Close pipe and read output
+	_ = w.Close()
+	output, _ := io.ReadAll(r)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result struct {
+		Meta struct {
+			Hostname  string `json:"hostname"`
+			Timestamp string `json:"timestamp"`
+			Command   string `json:"command"`
+		} `json:"meta"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if result.Meta.Hostname != "test-host" {
+		t.Errorf("Expected hostname 'test-host', got '%s'", result.Meta.Hostname)
+	}
+	if result.Meta.Command != "get devicelist" {
+		t.Errorf("Expected command 'get devicelist', got '%s'", result.Meta.Command)
+	}
+	timestamp, err := time.Parse(time.RFC3339, result.Meta.Timestamp)
+	if err != nil {
+		t.Fatalf("Failed to parse timestamp '%s': %v", result.Meta.Timestamp, err)
+	}
+	if _, offset := timestamp.Zone(); offset != 0 {
+		t.Errorf("Expected timestamp in UTC, got '%s'", result.Meta.Timestamp)
+	}
+	if timestamp.Before(before) {
+		t.Errorf("Expected timestamp not before %v, got %v", before, timestamp)
+	}
+	if string(result.Data) != responseBody {
+		t.Errorf("Expected data '%s', got '%s'", responseBody, string(result.Data))
+	}
+}
@@ -3,14 +3,17 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/go-resty/resty/v2"
 	"github.com/pgerke/freeathome/v2/pkg/freeathome"
 	"github.com/pgerke/freeathome/v2/pkg/models"
-	"golang.org/x/term"
 )
 
 var setupFunc = setup
@@ -20,6 +23,7 @@ type GetCommandConfig struct {
 	CommandConfig
 	OutputFormat string
 	Prettify     bool
+	Envelope     bool
 }
 
 // parseLogLevel converts a string log level to slog.Level
@@ -70,6 +74,19 @@ func outputJSON(data any, dataType string, prettify bool) error {
 	return nil
 }
 
+// encodeJSON encodes the data directly to the writer without buffering the encoded output.
+// The output is identical to the output of outputJSON.
+func encodeJSON(w io.Writer, data any, dataType string, prettify bool) error {
+	encoder := json.NewEncoder(w)
+	if prettify {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to marshal %s to JSON: %w", dataType, err)
+	}
+	return nil
+}
+
 func setup(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
 	// Load configuration
 	cfg, err := load(config.Viper, configFile)
@@ -89,23 +106,72 @@ func setup(config CommandConfig, configFile string) (*freeathome.SystemAccessPoi
 	}
 
 	// Create a new logger with the specified options
-	// Use a colorized handler if the terminal supports colors
-	if !term.IsTerminal(int(os.Stderr.Fd())) {
-		color.NoColor = true
-	}
+	// Use a colorized handler if the terminal supports colors and wrap the log lines to the terminal width if requested
 	handler := freeathome.NewColorHandler(os.Stderr, &slog.HandlerOptions{
 		Level: parseLogLevel(config.LogLevel),
 	})
-	logger := freeathome.NewDefaultLogger(handler)
+	if !isTerminal(os.Stderr) {
+		color.NoColor = true
+	} else if cfg.WrapLogLines {
+		handler = handler.WithLineWrapping(int(os.Stderr.Fd()))
+	}
+	// Redact the password in case it ends up in a log message and mask the device serials if requested
+	var logHandler slog.Handler = freeathome.NewRedactHandler(handler, cfg.Password)
+	if config.SerialMasker != nil {
+		logHandler = freeathome.NewSerialMaskHandler(logHandler, config.SerialMasker)
+	}
+	if config.DiscardLogs {
+		logHandler = slog.DiscardHandler
+	}
+	logger := freeathome.NewDefaultLogger(logHandler)
 
 	// Create system access point client
 	sysApConfig := freeathome.NewConfig(cfg.Hostname, cfg.Username, cfg.Password)
 	sysApConfig.TLSEnabled = config.TLSEnabled
 	sysApConfig.SkipTLSVerify = config.SkipTLSVerify
+	sysApConfig.ReadOnly = cfg.ReadOnly
+	if err := freeathome.ValidateAPIVersion(cfg.APIVersion); err != nil {
+		return nil, fmt.Errorf("invalid api-version: %w", err)
+	}
+	if cfg.APIVersion != "" {
+		sysApConfig.APIVersion = cfg.APIVersion
+	}
+	if sysApConfig.DatapointScales, err = parseScales(cfg.Scales); err != nil {
+		return nil, err
+	}
+	sysApConfig.HeartbeatInterval = config.HeartbeatInterval
+	// Log verbose errors if requested by the same environment variable as the library
+	verboseErrors, set, err := freeathome.LookupEnvBool(freeathome.EnvVerboseErrors)
+	if err != nil {
+		return nil, err
+	}
+	if set {
+		sysApConfig.VerboseErrors = verboseErrors
+	}
 	sysApConfig.Logger = logger
-	return freeathome.NewSystemAccessPoint(sysApConfig)
+	sysApConfig.Client = resty.New().SetTimeout(config.RequestTimeout)
+	if config.DiscardLogs {
+		sysApConfig.Client.SetLogger(discardLogger{})
+	}
+	sysAp, err := freeathome.NewSystemAccessPoint(sysApConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Select the system access point if the configuration contains several
+	if cfg.SysApUUID != "" {
+		sysAp.SetUUID(cfg.SysApUUID)
+	}
+	return sysAp, nil
 }
 
+// discardLogger is a resty logger that discards all messages
+type discardLogger struct{}
+
+func (discardLogger) Errorf(string, ...any) {}
+func (discardLogger) Warnf(string, ...any)  {}
+func (discardLogger) Debugf(string, ...any) {}
+
 // GetDeviceList retrieves and displays the device list
 func GetDeviceList(config GetCommandConfig) error {
 	// Setup system access point
@@ -122,7 +188,7 @@ func GetDeviceList(config GetCommandConfig) error {
 
 	// Output depending on output format
 	if config.OutputFormat == "json" {
-		return outputJSON(deviceList, "device list", config.Prettify)
+		return outputCommandJSON(deviceList, "device list", config.Prettify, config.Envelope, sysAp.GetHostName(), "get devicelist")
 	}
 
 	// Check if device list is empty
@@ -131,8 +197,8 @@ func GetDeviceList(config GetCommandConfig) error {
 		return nil
 	}
 
-	// Get devices for the system access point (using EmptyUUID as key)
-	devices, exists := (*deviceList)[models.EmptyUUID]
+	// Get devices for the system access point
+	devices, exists := deviceList.Serials()
 	if !exists {
 		fmt.Println("No devices found for system access point")
 		return nil
@@ -159,15 +225,24 @@ func GetConfiguration(config GetCommandConfig) error {
 		return err
 	}
 
+	// Stream the JSON output, as the configuration of large installations can be huge
+	if config.OutputFormat == "json" {
+		return streamConfigurationJSON(os.Stdout, sysAp, config)
+	}
+
 	// Get configuration
 	configuration, err := sysAp.GetConfiguration()
 	if err != nil {
 		return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
 	}
 
-	// Output depending on output format
-	if config.OutputFormat == "json" {
-		return outputJSON(configuration, "configuration", config.Prettify)
+	// Render the topology as a Graphviz graph if requested
+	if config.OutputFormat == "dot" {
+		if configuration == nil {
+			configuration = &models.Configuration{}
+		}
+		fmt.Print(renderTopologyDOT(*configuration))
+		return nil
 	}
 
 	// Check if configuration is empty
@@ -189,6 +264,28 @@ func GetConfiguration(config GetCommandConfig) error {
 	return nil
 }
 
+// streamConfigurationJSON decodes the configuration directly from the response body and encodes it to the writer, so that
+// the response body is not read into a separate buffer first. The decoded configuration and its encoded JSON are still held
+// in memory as a whole, the output matches the buffered output of the configuration model.
+func streamConfigurationJSON(w io.Writer, sysAp *freeathome.SystemAccessPoint, config GetCommandConfig) error {
+	body, err := sysAp.GetRawConfiguration()
+	if err != nil {
+		return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
+	}
+	defer func() { _ = body.Close() }()
+
+	var configuration *models.Configuration
+	if err := json.NewDecoder(body).Decode(&configuration); err != nil {
+		return fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	var data any = configuration
+	if config.Envelope {
+		data = newEnvelope(sysAp.GetHostName(), "get configuration", time.Now(), data)
+	}
+	return encodeJSON(w, data, "configuration", config.Prettify)
+}
+
 // GetDevice retrieves and displays a specific device by serial number
 func GetDevice(config GetCommandConfig, serial string) error {
 	// Setup system access point
@@ -205,27 +302,13 @@ func GetDevice(config GetCommandConfig, serial string) error {
 
 	// Output depending on output format
 	if config.OutputFormat == "json" {
-		return outputJSON(device, "device", config.Prettify)
-	}
-
-	// Check if device is empty
-	var deviceNotFound = fmt.Sprintf("No device found with serial: %s", serial)
-	if device == nil || len(*device) == 0 {
-		fmt.Println(deviceNotFound)
-		return nil
-	}
-
-	// Get devices for the system access point (using EmptyUUID as key)
-	devices, exists := (*device)[models.EmptyUUID]
-	if !exists {
-		fmt.Println(deviceNotFound)
-		return nil
+		return outputCommandJSON(device, "device", config.Prettify, config.Envelope, sysAp.GetHostName(), "get device")
 	}
 
 	// Check if the specific device exists
-	deviceData, deviceExists := devices.Devices[serial]
-	if !deviceExists {
-		fmt.Println(deviceNotFound)
+	deviceData, err := findDevice(device, serial)
+	if err != nil {
+		fmt.Printf("No device found with serial: %s\n", serial)
 		return nil
 	}
 
@@ -252,12 +335,63 @@ func GetDevice(config GetCommandConfig, serial string) error {
 	if deviceData.Parameters != nil {
 		fmt.Printf("  Parameters: %d\n", len(*deviceData.Parameters))
 	}
+	if deviceData.Unresponsive != nil || deviceData.Defect != nil {
+		fmt.Printf("  Reachable: %t\n", deviceData.IsReachable())
+	}
 
 	return nil
 }
 
 // GetDatapoint retrieves and displays a specific datapoint
 func GetDatapoint(config GetCommandConfig, serial string, channel string, datapoint string) error {
+	return getDatapoint(config, serial, channel, datapoint, false)
+}
+
+// GetDatapointWithUnits retrieves and displays a specific datapoint like GetDatapoint. The text output appends the unit
+// to the values and shows the description of the datapoint, if the configuration provides them.
+func GetDatapointWithUnits(config GetCommandConfig, serial string, channel string, datapoint string) error {
+	return getDatapoint(config, serial, channel, datapoint, true)
+}
+
+// scaledDatapoint is the JSON output of a scaled datapoint
+type scaledDatapoint struct {
+	Datapoint string  `json:"datapoint"`
+	Value     float64 `json:"value"`
+	Unit      string  `json:"unit,omitempty"`
+}
+
+// GetDatapointScaled retrieves a specific datapoint and displays its numeric value with the scale from the config file
+// applied, followed by its unit from the scale or the configuration of the system access point.
+func GetDatapointScaled(config GetCommandConfig, serial string, channel string, datapoint string) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the configuration, so that the unit of the datapoint can be looked up
+	if _, err := sysAp.GetConfiguration(); err != nil {
+		return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
+	}
+
+	key := models.DatapointKey{Serial: serial, Channel: channel, Datapoint: datapoint}
+	value, unit, err := sysAp.ScaledValue(key)
+	if err != nil {
+		return handleSysApError(err, "get scaled datapoint", config.TLSEnabled, config.SkipTLSVerify)
+	}
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		return outputCommandJSON(scaledDatapoint{Datapoint: key.String(), Value: value, Unit: unit}, "datapoint", config.Prettify, config.Envelope, sysAp.GetHostName(), "get datapoint --scaled")
+	}
+
+	fmt.Printf("Datapoint: %s.%s.%s\n", serial, channel, datapoint)
+	fmt.Printf("  Value: %s\n", formatValueWithUnit(strconv.FormatFloat(value, 'f', -1, 64), unit))
+	return nil
+}
+
+// getDatapoint retrieves and displays a specific datapoint, optionally with the unit and description from the configuration
+func getDatapoint(config GetCommandConfig, serial string, channel string, datapoint string, withUnits bool) error {
 	// Setup system access point
 	sysAp, err := setupFunc(config.CommandConfig, "")
 	if err != nil {
@@ -272,7 +406,7 @@ func GetDatapoint(config GetCommandConfig, serial string, channel string, datapo
 
 	// Output depending on output format
 	if config.OutputFormat == "json" {
-		return outputJSON(datapointResponse, "datapoint", config.Prettify)
+		return outputCommandJSON(datapointResponse, "datapoint", config.Prettify, config.Envelope, sysAp.GetHostName(), "get datapoint")
 	}
 
 	// Check if datapoint response is empty
@@ -281,20 +415,46 @@ func GetDatapoint(config GetCommandConfig, serial string, channel string, datapo
 		return nil
 	}
 
-	// Get datapoint for the system access point (using EmptyUUID as key)
-	datapointData, exists := (*datapointResponse)[models.EmptyUUID]
+	// Get datapoint for the system access point
+	datapointData, exists := datapointResponse.DataPoint()
 	if !exists {
 		fmt.Printf("No datapoint found: %s.%s.%s\n", serial, channel, datapoint)
 		return nil
 	}
 
+	// Look up the unit and description of the datapoint in the configuration if requested
+	values := []string(datapointData.Values)
+	metadata := models.DatapointMetadata{}
+	if withUnits {
+		configuration, err := sysAp.GetConfiguration()
+		if err != nil {
+			return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
+		}
+		metadata, _ = configuration.DatapointMetadata(models.DatapointKey{Serial: serial, Channel: channel, Datapoint: datapoint})
+		values = make([]string, len(datapointData.Values))
+		for i, value := range datapointData.Values {
+			values[i] = formatValueWithUnit(value, metadata.Unit)
+		}
+	}
+
 	// Output as plain text
 	fmt.Printf("Datapoint: %s.%s.%s\n", serial, channel, datapoint)
-	if len(datapointData.Values) > 0 {
-		fmt.Printf("  Values: %v\n", datapointData.Values)
+	if metadata.Description != "" {
+		fmt.Printf("  Description: %s\n", metadata.Description)
+	}
+	if len(values) > 0 {
+		fmt.Printf("  Values: %v\n", values)
 	} else {
 		fmt.Printf("  Values: (empty)\n")
 	}
 
 	return nil
 }
+
+// formatValueWithUnit appends the unit to the value, empty values and values without a unit are returned unchanged
+func formatValueWithUnit(value string, unit string) string {
+	if value == "" || unit == "" {
+		return value
+	}
+	return value + " " + unit
+}
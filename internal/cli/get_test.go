@@ -1,16 +1,23 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
 	"github.com/spf13/viper"
 )
 
@@ -205,6 +212,165 @@ password: test-pass`,
 	}
 }
 
+// TestSetupSysApUUID tests that the configured system access point UUID is used in requests
+func TestSetupSysApUUID(t *testing.T) {
+	configFileDir = t.TempDir()
+	configDir := filepath.Join(configFileDir, ".freeathome")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configData := `hostname: test-host
+username: test-user
+password: test-pass
+sysap-uuid: sysap-b`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	sysAp, err := setup(CommandConfig{Viper: viper.New(), TLSEnabled: true, LogLevel: "info"}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if uuid := sysAp.GetUUID(); uuid != "sysap-b" {
+		t.Errorf("Expected UUID 'sysap-b', got '%s'", uuid)
+	}
+}
+
+// TestSetupReadOnly tests that the read-only mode rejects writes without sending a request
+func TestSetupReadOnly(t *testing.T) {
+	configFileDir = t.TempDir()
+	configDir := filepath.Join(configFileDir, ".freeathome")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configData := `hostname: 127.0.0.1:1
+username: test-user
+password: test-pass
+read-only: true`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	sysAp, err := setup(CommandConfig{Viper: viper.New(), LogLevel: "error"}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := sysAp.SetDatapoint("ABB7F595EC47", "ch0000", "idp0000", "1"); !errors.Is(err, freeathome.ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+}
+
+// TestSetupRequestTimeout tests that requests are aborted after the request timeout without log output
+func TestSetupRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	configFileDir = t.TempDir()
+	configDir := filepath.Join(configFileDir, ".freeathome")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configData := fmt.Sprintf(`hostname: %s
+username: test-user
+password: test-pass`, strings.TrimPrefix(server.URL, "http://"))
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	// The log handlers are bound to stderr when the system access point is set up
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() { os.Stderr = oldStderr }()
+
+	sysAp, err := setup(CommandConfig{Viper: viper.New(), LogLevel: "debug", RequestTimeout: 50 * time.Millisecond, DiscardLogs: true}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	start := time.Now()
+	if _, err := sysAp.GetDeviceList(); err == nil {
+		t.Error("Expected the request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the request to be aborted after the timeout, took %v", elapsed)
+	}
+
+	_ = w.Close()
+	if stderr, _ := io.ReadAll(r); len(stderr) != 0 {
+		t.Errorf("Expected no log output, got: %s", stderr)
+	}
+}
+
+// TestSetupAPIVersion tests that the API version of the config file is used in the API paths
+func TestSetupAPIVersion(t *testing.T) {
+	configFileDir = t.TempDir()
+	configDir := filepath.Join(configFileDir, ".freeathome")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configData := `hostname: test-host
+username: test-user
+password: test-pass
+api-version: v2`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	sysAp, err := setup(CommandConfig{Viper: viper.New(), TLSEnabled: true, LogLevel: "error"}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if url, expected := sysAp.GetUrl("devicelist"), "https://test-host/fhapi/v2/api/rest/devicelist"; url != expected {
+		t.Errorf("Expected URL %s, got %s", expected, url)
+	}
+}
+
+// TestSetupInvalidAPIVersion tests that an API version that would build broken API paths is rejected
+func TestSetupInvalidAPIVersion(t *testing.T) {
+	configFileDir = t.TempDir()
+	configDir := filepath.Join(configFileDir, ".freeathome")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configData := `hostname: test-host
+username: test-user
+password: test-pass
+api-version: v1/../x`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	_, err := setup(CommandConfig{Viper: viper.New(), TLSEnabled: true, LogLevel: "error"}, "")
+	if err == nil || !strings.Contains(err.Error(), `invalid api-version: invalid API version "v1/../x"`) {
+		t.Errorf("Expected invalid API version error, got %v", err)
+	}
+}
+
+// TestSetupInvalidVerboseErrors tests that setup rejects an invalid value of the verbose errors environment variable
+func TestSetupInvalidVerboseErrors(t *testing.T) {
+	configFileDir = t.TempDir()
+	configDir := filepath.Join(configFileDir, ".freeathome")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configData := `hostname: test-host
+username: test-user
+password: test-pass`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	t.Setenv(freeathome.EnvVerboseErrors, "maybe")
+
+	_, err := setup(CommandConfig{Viper: viper.New(), TLSEnabled: true, LogLevel: "error"}, "")
+	if err == nil || !strings.Contains(err.Error(), "invalid value for FREEATHOME_VERBOSE_ERRORS") {
+		t.Errorf("Expected invalid value error, got %v", err)
+	}
+}
+
 // TestSetupWithInvalidConfigFile tests setup with an invalid config file
 func TestSetupWithInvalidConfigFile(t *testing.T) {
 	// Create a temporary config file with invalid YAML
@@ -341,12 +507,24 @@ func TestGetDeviceList(t *testing.T) {
 			outputFormat: "text",
 			prettify:     false,
 			responseBody: `{
-  "other-uuid": ["ABB7F595EC47", "ABB7013B85DE"]
+  "other-uuid": ["ABB7F595EC47", "ABB7013B85DE"],
+  "another-uuid": ["ABB7F5947E20"]
 }`,
 			responseCode: http.StatusOK,
 			expectError:  false,
 			expectOutput: "No devices found for system access point\n",
 		},
+		{
+			name:         "Devices for a single non-empty UUID",
+			outputFormat: "text",
+			prettify:     false,
+			responseBody: `{
+  "other-uuid": ["ABB7F595EC47", "ABB7013B85DE"]
+}`,
+			responseCode: http.StatusOK,
+			expectError:  false,
+			expectOutput: "ABB7F595EC47\nABB7013B85DE\n",
+		},
 		{
 			name:          "HTTP error response",
 			outputFormat:  "text",
@@ -518,6 +696,45 @@ func TestSetupFunctionExists(t *testing.T) {
 	}
 }
 
+// TestStreamConfigurationJSON tests that the streamed configuration output is byte-equivalent to the buffered output
+func TestStreamConfigurationJSON(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration.json"))
+	if err != nil {
+		t.Fatalf("Failed to read configuration fixture: %v", err)
+	}
+	var configuration models.Configuration
+	if err := json.Unmarshal(data, &configuration); err != nil {
+		t.Fatalf("Failed to parse configuration fixture: %v", err)
+	}
+
+	for _, prettify := range []bool{false, true} {
+		t.Run(fmt.Sprintf("prettify=%t", prettify), func(t *testing.T) {
+			setupPathMock(t, &pathRoundTripper{responses: []pathResponse{{"GET /configuration", string(data)}}})
+			sysAp, _ := setupFunc(CommandConfig{}, "")
+
+			var streamed bytes.Buffer
+			if err := streamConfigurationJSON(&streamed, sysAp, GetCommandConfig{OutputFormat: "json", Prettify: prettify}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			// The buffered output is the marshaled configuration printed with a trailing newline
+			var buffered []byte
+			if prettify {
+				buffered, err = json.MarshalIndent(&configuration, "", "  ")
+			} else {
+				buffered, err = json.Marshal(&configuration)
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			buffered = append(buffered, '\n')
+
+			if !bytes.Equal(streamed.Bytes(), buffered) {
+				t.Errorf("Expected the streamed output to equal the buffered output, got %d bytes instead of %d", streamed.Len(), len(buffered))
+			}
+		})
+	}
+}
+
 func TestGetConfiguration(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -915,7 +1132,7 @@ func TestGetDevice(t *testing.T) {
 }`,
 			responseCode: http.StatusOK,
 			expectError:  false,
-			expectOutput: `{"00000000-0000-0000-0000-000000000000":{"devices":{"ABB7F595EC47":{"displayName":"Living Room Light","room":"Living Room","floor":"Ground Floor","interface":"KNX","nativeId":"1.1.1","channels":{"ch0000":{}},"parameters":{"param1":"value1"}}}}}
+			expectOutput: `{"00000000-0000-0000-0000-000000000000":{"devices":{"ABB7F595EC47":{"displayName":"Living Room Light","room":"Living Room","floor":"Ground Floor","interface":"KNX","nativeId":"1.1.1","channels":{"ch0000":{"name":"Light Control"}},"parameters":{"param1":"value1"}}}}}
 `,
 		},
 		{
@@ -956,7 +1173,9 @@ func TestGetDevice(t *testing.T) {
         "interface": "KNX",
         "nativeId": "1.1.1",
         "channels": {
-          "ch0000": {}
+          "ch0000": {
+            "name": "Light Control"
+          }
         },
         "parameters": {
           "param1": "value1"
@@ -997,6 +1216,25 @@ func TestGetDevice(t *testing.T) {
 			expectError:  false,
 			expectOutput: "Device Serial: ABB7F595EC47\n  Display Name: Living Room Light\n  Room: Living Room\n  Floor: Ground Floor\n  Interface: KNX\n  Native ID: 1.1.1\n  Channels: 1\n  Parameters: 1\n",
 		},
+		{
+			name:         "Successful text output with reachability",
+			serial:       "ABB7F595EC47",
+			outputFormat: "text",
+			prettify:     false,
+			responseBody: `{
+  "00000000-0000-0000-0000-000000000000": {
+    "devices": {
+      "ABB7F595EC47": {
+        "displayName": "Living Room Light",
+        "unresponsive": true
+      }
+    }
+  }
+}`,
+			responseCode: http.StatusOK,
+			expectError:  false,
+			expectOutput: "Device Serial: ABB7F595EC47\n  Display Name: Living Room Light\n  Reachable: false\n",
+		},
 		{
 			name:         "Device with minimal fields",
 			serial:       "ABB7F595EC47",
@@ -1037,12 +1275,33 @@ func TestGetDevice(t *testing.T) {
         "displayName": "Living Room Light"
       }
     }
+  },
+  "another-uuid": {
+    "devices": {}
   }
 }`,
 			responseCode: http.StatusOK,
 			expectError:  false,
 			expectOutput: "No device found with serial: ABB7F595EC47\n",
 		},
+		{
+			name:         "Device for a single non-empty UUID",
+			serial:       "ABB7F595EC47",
+			outputFormat: "text",
+			prettify:     false,
+			responseBody: `{
+  "other-uuid": {
+    "devices": {
+      "ABB7F595EC47": {
+        "displayName": "Living Room Light"
+      }
+    }
+  }
+}`,
+			responseCode: http.StatusOK,
+			expectError:  false,
+			expectOutput: "Device Serial: ABB7F595EC47\n  Display Name: Living Room Light\n",
+		},
 		{
 			name:         "Device not found in devices map",
 			serial:       "ABB7F595EC47",
@@ -1491,12 +1750,31 @@ func TestGetDatapoint(t *testing.T) {
 			responseBody: `{
   "other-uuid": {
     "values": ["100"]
+  },
+  "another-uuid": {
+    "values": ["0"]
   }
 }`,
 			responseCode: http.StatusOK,
 			expectError:  false,
 			expectOutput: "No datapoint found: ABB7F595EC47.ch0000.idp0000\n",
 		},
+		{
+			name:         "Datapoint for a single non-empty UUID",
+			serial:       "ABB7F595EC47",
+			channel:      "ch0000",
+			datapoint:    "idp0000",
+			outputFormat: "text",
+			prettify:     false,
+			responseBody: `{
+  "other-uuid": {
+    "values": ["100"]
+  }
+}`,
+			responseCode: http.StatusOK,
+			expectError:  false,
+			expectOutput: "Datapoint: ABB7F595EC47.ch0000.idp0000\n  Values: [100]\n",
+		},
 		{
 			name:          "HTTP error response",
 			serial:        "ABB7F595EC47",
@@ -1669,3 +1947,131 @@ func TestGetDatapointFunctionExists(t *testing.T) {
 		t.Logf("GetDatapoint function exists but failed as expected: %v", err)
 	}
 }
+
+// TestGetDatapointWithUnits tests that the unit from the configuration is appended to the values and the description is shown
+func TestGetDatapointWithUnits(t *testing.T) {
+	configuration, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration_units.json"))
+	if err != nil {
+		t.Fatalf("failed to read configuration fixture: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		datapoint string
+		expected  string
+	}{
+		{name: "Datapoint with unit", datapoint: "odp0010", expected: "Datapoint: ABB7F595EC47.ch0000.odp0010\n  Description: Measured temperature\n  Values: [21.5 °C]\n"},
+		{name: "Datapoint without unit", datapoint: "odp0000", expected: "Datapoint: ABB7F595EC47.ch0000.odp0000\n  Values: [21.5]\n"},
+		{name: "Datapoint not in configuration", datapoint: "odp0001", expected: "Datapoint: ABB7F595EC47.ch0000.odp0001\n  Values: [21.5]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupPathMock(t, &pathRoundTripper{responses: []pathResponse{
+				{"GET /configuration", string(configuration)},
+				{"GET /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"values":["21.5"]}}`},
+			}})
+
+			var err error
+			output := captureStdout(t, func() {
+				err = GetDatapointWithUnits(GetCommandConfig{OutputFormat: "text"}, "ABB7F595EC47", "ch0000", tt.datapoint)
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+// TestGetDatapointWithUnitsConfigurationError tests that a failure to retrieve the configuration is reported
+func TestGetDatapointWithUnitsConfigurationError(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{
+		{"GET /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"values":["21.5"]}}`},
+	}})
+
+	var err error
+	captureStdout(t, func() {
+		err = GetDatapointWithUnits(GetCommandConfig{OutputFormat: "text"}, "ABB7F595EC47", "ch0000", "odp0010")
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to get configuration") {
+		t.Errorf("Expected configuration error, got %v", err)
+	}
+}
+
+// TestGetDatapointScaled tests that the scale from the config file is applied and unscaled datapoints pass through
+func TestGetDatapointScaled(t *testing.T) {
+	configuration, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration_units.json"))
+	if err != nil {
+		t.Fatalf("failed to read configuration fixture: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		datapoint    string
+		outputFormat string
+		expected     string
+	}{
+		{name: "Scaled datapoint", datapoint: "odp0010", outputFormat: "text", expected: "Datapoint: ABB7F595EC47.ch0000.odp0010\n  Value: 21.5 °C\n"},
+		{name: "Unscaled datapoint", datapoint: "idp0016", outputFormat: "text", expected: "Datapoint: ABB7F595EC47.ch0000.idp0016\n  Value: 215 °C\n"},
+		{name: "Datapoint without unit", datapoint: "odp0000", outputFormat: "text", expected: "Datapoint: ABB7F595EC47.ch0000.odp0000\n  Value: 215\n"},
+		{name: "Scaled datapoint as JSON", datapoint: "odp0010", outputFormat: "json", expected: `{"datapoint":"ABB7F595EC47/ch0000/odp0010","value":21.5,"unit":"°C"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &pathRoundTripper{responses: []pathResponse{
+				{"GET /configuration", string(configuration)},
+				{"GET /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"values":["215"]}}`},
+			}}
+			scales, err := parseScales([]string{"ABB7F595EC47/ch0000/odp0010=0.1"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// The scales are part of the configuration of the system access point, as setup would create it from the config file
+			sysApConfig := freeathome.NewConfig("test-host", "test-user", "test-pass")
+			sysApConfig.Logger = freeathome.NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+			sysApConfig.Client = resty.New().SetTransport(transport)
+			sysApConfig.DatapointScales = scales
+			sysAp := freeathome.MustNewSystemAccessPoint(sysApConfig)
+			setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+				return sysAp, nil
+			}
+			t.Cleanup(func() {
+				setupFunc = setup
+			})
+
+			output := captureStdout(t, func() {
+				err = GetDatapointScaled(GetCommandConfig{OutputFormat: tt.outputFormat}, "ABB7F595EC47", "ch0000", tt.datapoint)
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+// TestFormatValueWithUnit tests appending units to values
+func TestFormatValueWithUnit(t *testing.T) {
+	tests := []struct {
+		value    string
+		unit     string
+		expected string
+	}{
+		{value: "21.5", unit: "°C", expected: "21.5 °C"},
+		{value: "21.5", unit: "", expected: "21.5"},
+		{value: "", unit: "°C", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if actual := formatValueWithUnit(tt.value, tt.unit); actual != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+const (
+	// homeAssistantTopicPrefix is the prefix of the state topics of the entities
+	homeAssistantTopicPrefix = "freeathome"
+	// homeAssistantManufacturer is the manufacturer of the devices shown in Home Assistant
+	homeAssistantManufacturer = "Busch-Jaeger"
+)
+
+// homeAssistantDevice groups the entities of a free@home device in Home Assistant
+type homeAssistantDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name,omitempty"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+// homeAssistantConfig is the payload of an MQTT discovery config topic
+type homeAssistantConfig struct {
+	Name              string              `json:"name"`
+	UniqueID          string              `json:"unique_id"`
+	StateTopic        string              `json:"state_topic"`
+	PayloadOn         string              `json:"payload_on,omitempty"`
+	PayloadOff        string              `json:"payload_off,omitempty"`
+	UnitOfMeasurement string              `json:"unit_of_measurement,omitempty"`
+	Device            homeAssistantDevice `json:"device"`
+}
+
+// homeAssistantEntity is a read only entity announced to Home Assistant via MQTT discovery
+type homeAssistantEntity struct {
+	// Component is the Home Assistant component of the entity, i.e. binary_sensor or sensor
+	Component string
+	// ObjectID identifies the entity in the discovery topic
+	ObjectID string
+	Config   homeAssistantConfig
+	// State is the datapoint whose updates are published to the state topic
+	State models.DatapointKey
+	// InitialState is the value of the state datapoint in the configuration, empty if it is unknown
+	InitialState string
+}
+
+// discoveryTopic returns the discovery config topic of the entity below the discovery prefix
+func (e homeAssistantEntity) discoveryTopic(prefix string) string {
+	return fmt.Sprintf("%s/%s/%s/config", prefix, e.Component, e.ObjectID)
+}
+
+// homeAssistantEntities derives the entities from the configuration of a system access point, sorted by serial and channel.
+// Switch and dimmer channels with a switch datapoint become binary sensors reporting the on/off state, the outputs of sensor
+// channels become sensors. No commands are accepted, the entities only reflect the state of the system access point.
+func homeAssistantEntities(sysApConfig models.SysAP) []homeAssistantEntity {
+	var entities []homeAssistantEntity
+	for _, serial := range slices.Sorted(maps.Keys(sysApConfig.Devices)) {
+		device := sysApConfig.Devices[serial]
+		if device.Channels == nil {
+			continue
+		}
+		haDevice := homeAssistantDevice{
+			Identifiers:  []string{homeAssistantTopicPrefix + "_" + serial},
+			Name:         stringValue(device.DisplayName),
+			Manufacturer: homeAssistantManufacturer,
+		}
+
+		for _, channelID := range slices.Sorted(maps.Keys(*device.Channels)) {
+			channel := (*device.Channels)[channelID]
+			if channel == nil {
+				continue
+			}
+			name := stringValue(channel.DisplayName)
+			if name == "" {
+				name = serial + "/" + channelID
+			}
+
+			if isSwitchChannel(channel) {
+				if entity, ok := homeAssistantSwitch(serial, channelID, channel, name, haDevice); ok {
+					entities = append(entities, entity)
+				}
+				continue
+			}
+			if channel.IsSensor() {
+				entities = append(entities, homeAssistantSensors(serial, channelID, channel, name, haDevice)...)
+			}
+		}
+	}
+	return entities
+}
+
+// isSwitchChannel reports whether the channel can be switched on and off
+func isSwitchChannel(channel *models.Channel) bool {
+	if channel.FunctionID == nil {
+		return false
+	}
+	return models.IsFunctionType("switch", *channel.FunctionID) || models.IsFunctionType("dimmer", *channel.FunctionID)
+}
+
+// homeAssistantSwitch creates the binary sensor entity of a switch channel. The state is read from the on/off state output
+// if there is one, otherwise from the switch input. It reports false if the channel has no switch datapoint.
+func homeAssistantSwitch(serial string, channelID string, channel *models.Channel, name string, device homeAssistantDevice) (homeAssistantEntity, bool) {
+	input, ok := channel.SwitchDatapoint()
+	if !ok {
+		return homeAssistantEntity{}, false
+	}
+	state := models.DatapointKey{Serial: serial, Channel: channelID, Datapoint: input}
+	if output, ok := channel.SwitchStateDatapoint(); ok {
+		state.Datapoint = output
+	}
+
+	objectID := homeAssistantObjectID(state)
+	return homeAssistantEntity{
+		Component: "binary_sensor",
+		ObjectID:  objectID,
+		Config: homeAssistantConfig{
+			Name:       name,
+			UniqueID:   objectID,
+			StateTopic: homeAssistantTopic(state),
+			PayloadOn:  "1",
+			PayloadOff: "0",
+			Device:     device,
+		},
+		State:        state,
+		InitialState: datapointValue(channel, state.Datapoint),
+	}, true
+}
+
+// homeAssistantSensors creates a sensor entity for every output of a sensor channel
+func homeAssistantSensors(serial string, channelID string, channel *models.Channel, name string, device homeAssistantDevice) []homeAssistantEntity {
+	var entities []homeAssistantEntity
+	for _, outputID := range slices.Sorted(maps.Keys(*channel.Outputs)) {
+		output := (*channel.Outputs)[outputID]
+		state := models.DatapointKey{Serial: serial, Channel: channelID, Datapoint: outputID}
+		label := stringValue(output.Description)
+		if label == "" {
+			label = outputID
+		}
+
+		objectID := homeAssistantObjectID(state)
+		entities = append(entities, homeAssistantEntity{
+			Component: "sensor",
+			ObjectID:  objectID,
+			Config: homeAssistantConfig{
+				Name:              name + " " + label,
+				UniqueID:          objectID,
+				StateTopic:        homeAssistantTopic(state),
+				UnitOfMeasurement: stringValue(output.Unit),
+				Device:            device,
+			},
+			State:        state,
+			InitialState: stringValue(output.Value),
+		})
+	}
+	return entities
+}
+
+// homeAssistantObjectID returns the object identifier of the entity of a datapoint, which only contains characters allowed
+// in discovery topics
+func homeAssistantObjectID(key models.DatapointKey) string {
+	return strings.Join([]string{homeAssistantTopicPrefix, key.Serial, key.Channel, key.Datapoint}, "_")
+}
+
+// homeAssistantTopic returns the state topic of a datapoint
+func homeAssistantTopic(key models.DatapointKey) string {
+	return strings.Join([]string{homeAssistantTopicPrefix, key.Serial, key.Channel, key.Datapoint, "state"}, "/")
+}
+
+// datapointValue returns the value of an input or output datapoint of the channel from the configuration, empty if unknown
+func datapointValue(channel *models.Channel, datapoint string) string {
+	for _, datapoints := range []*map[string]models.InOutPut{channel.Inputs, channel.Outputs} {
+		if datapoints == nil {
+			continue
+		}
+		if value, exists := (*datapoints)[datapoint]; exists {
+			return stringValue(value.Value)
+		}
+	}
+	return ""
+}
+
+// stringValue returns the value of an optional string, empty if it is nil
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// homeAssistantBridge announces the entities to Home Assistant and publishes their state updates
+type homeAssistantBridge struct {
+	publish  func(topic string, payload []byte, retain bool) error
+	prefix   string
+	entities []homeAssistantEntity
+	// states contains the state topics by the datapoint whose updates are published to them
+	states map[models.DatapointKey][]string
+}
+
+// newHomeAssistantBridge creates a bridge for the entities, publishing with the discovery prefix
+func newHomeAssistantBridge(publish func(topic string, payload []byte, retain bool) error, prefix string, entities []homeAssistantEntity) *homeAssistantBridge {
+	bridge := &homeAssistantBridge{
+		publish:  publish,
+		prefix:   prefix,
+		entities: entities,
+		states:   map[models.DatapointKey][]string{},
+	}
+	for _, entity := range entities {
+		bridge.states[entity.State] = append(bridge.states[entity.State], entity.Config.StateTopic)
+	}
+	return bridge
+}
+
+// announce publishes the retained discovery configs, so Home Assistant creates the entities, followed by the known states
+func (b *homeAssistantBridge) announce() error {
+	for _, entity := range b.entities {
+		payload, err := json.Marshal(entity.Config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal Home Assistant discovery config to JSON: %w", err)
+		}
+		if err := b.publish(entity.discoveryTopic(b.prefix), payload, true); err != nil {
+			return fmt.Errorf("failed to publish Home Assistant discovery config: %w", err)
+		}
+	}
+	for _, entity := range b.entities {
+		if entity.InitialState == "" {
+			continue
+		}
+		if err := b.publish(entity.Config.StateTopic, []byte(entity.InitialState), true); err != nil {
+			return fmt.Errorf("failed to publish Home Assistant state: %w", err)
+		}
+	}
+	return nil
+}
+
+// handle publishes a datapoint update to the state topics of the entities of the datapoint
+func (b *homeAssistantBridge) handle(update models.DatapointUpdate) {
+	key := models.DatapointKey{Serial: update.Serial, Channel: update.Channel, Datapoint: update.Datapoint}
+	for _, topic := range b.states[key] {
+		if err := b.publish(topic, []byte(update.Value), true); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to publish Home Assistant state: %v\n", err)
+		}
+	}
+}
+
+// startHomeAssistantBridge derives the entities from the configuration, connects to the MQTT broker and announces the
+// entities. The returned client must be closed to disconnect from the broker.
+func startHomeAssistantBridge(sysAp *freeathome.SystemAccessPoint, brokerURL string, prefix string) (*homeAssistantBridge, *mqttClient, error) {
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get configuration for Home Assistant discovery: %w", err)
+	}
+	sysApConfig, err := sysApConfiguration(*configuration, sysAp.GetUUID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := newMQTTClient(brokerURL, homeAssistantTopicPrefix+"-"+sysAp.GetHostName())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bridge := newHomeAssistantBridge(client.publish, prefix, homeAssistantEntities(sysApConfig))
+	if err := bridge.announce(); err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+
+	return bridge, client, nil
+}
+
+// redactURL returns the URL without the password of the user info, e.g. to print the broker URL
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return parsed.Redacted()
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// updateHook runs a command for every datapoint update matching the filter. The command template is split into
+// arguments before the placeholders are substituted, and the command is executed without a shell, so substituted
+// values cannot inject additional arguments or commands.
+type updateHook struct {
+	// args are the arguments of the command template
+	args []string
+	// filter is a pattern matched against the serial/channel/datapoint key of the update, empty matches all updates
+	filter string
+	// running limits the number of concurrently running commands
+	running chan struct{}
+	// run executes the command with the expanded arguments
+	run func(args []string) error
+	// waitGroup tracks the running commands
+	waitGroup sync.WaitGroup
+}
+
+// newUpdateHook creates a hook running the command template for updates matching the filter with at most the given number of concurrent commands
+func newUpdateHook(template, filter string, concurrency int) (*updateHook, error) {
+	args := strings.Fields(template)
+	if len(args) == 0 {
+		return nil, errors.New("update hook command must not be empty")
+	}
+	if concurrency < 1 {
+		return nil, fmt.Errorf("update hook concurrency must be at least 1, got %d", concurrency)
+	}
+	if _, err := path.Match(filter, ""); err != nil {
+		return nil, fmt.Errorf("invalid update hook filter %q: %w", filter, err)
+	}
+
+	return &updateHook{
+		args:    args,
+		filter:  filter,
+		running: make(chan struct{}, concurrency),
+		run:     runHookCommand,
+	}, nil
+}
+
+// matches reports whether the update matches the filter of the hook
+func (h *updateHook) matches(update models.DatapointUpdate) bool {
+	if h.filter == "" {
+		return true
+	}
+	key := models.DatapointKey{Serial: update.Serial, Channel: update.Channel, Datapoint: update.Datapoint}
+	matched, _ := path.Match(h.filter, key.String())
+	return matched
+}
+
+// expand substitutes the placeholders in the arguments of the command template with the sanitized fields of the update
+func (h *updateHook) expand(update models.DatapointUpdate) []string {
+	replacer := strings.NewReplacer(
+		"{device}", sanitizeHookValue(update.Serial),
+		"{serial}", sanitizeHookValue(update.Serial),
+		"{channel}", sanitizeHookValue(update.Channel),
+		"{datapoint}", sanitizeHookValue(update.Datapoint),
+		"{value}", sanitizeHookValue(update.Value),
+	)
+
+	args := make([]string, len(h.args))
+	for i, arg := range h.args {
+		args[i] = replacer.Replace(arg)
+	}
+	return args
+}
+
+// handle runs the command for the update if it matches the filter. If the maximum number of commands is already running,
+// the update is skipped instead of queued, so that a burst of updates cannot pile up processes.
+func (h *updateHook) handle(update models.DatapointUpdate) {
+	if !h.matches(update) {
+		return
+	}
+
+	select {
+	case h.running <- struct{}{}:
+	default:
+		fmt.Fprintf(os.Stderr, "Skipping update hook for %s/%s/%s, %d commands are still running\n", update.Serial, update.Channel, update.Datapoint, cap(h.running))
+		return
+	}
+
+	args := h.expand(update)
+	h.waitGroup.Add(1)
+	go func() {
+		defer h.waitGroup.Done()
+		defer func() {
+			<-h.running
+		}()
+
+		if err := h.run(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Update hook command failed: %v\n", err)
+		}
+	}()
+}
+
+// wait blocks until all running commands have finished
+func (h *updateHook) wait() {
+	h.waitGroup.Wait()
+}
+
+// sanitizeHookValue removes control characters from a value received from the system access point
+func sanitizeHookValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// runHookCommand executes the command with the given arguments, forwarding its output to the standard streams
+func runHookCommand(args []string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// hookUpdate returns a datapoint update for the hook tests
+func hookUpdate(value string) models.DatapointUpdate {
+	return models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: value}
+}
+
+// TestUpdateHookExpand tests that the placeholders of the command template are replaced per argument
+func TestUpdateHookExpand(t *testing.T) {
+	hook, err := newUpdateHook("notify.sh {device} --point={channel}.{datapoint} {value}", "", 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{name: "Plain value", value: "1", expected: []string{"notify.sh", "ABB7F595EC47", "--point=ch0000.odp0000", "1"}},
+		{name: "Value with spaces stays one argument", value: "1; rm -rf /", expected: []string{"notify.sh", "ABB7F595EC47", "--point=ch0000.odp0000", "1; rm -rf /"}},
+		{name: "Control characters are removed", value: "1\n\x1b[31mred", expected: []string{"notify.sh", "ABB7F595EC47", "--point=ch0000.odp0000", "1[31mred"}},
+		{name: "Placeholders in values are not expanded", value: "{device}", expected: []string{"notify.sh", "ABB7F595EC47", "--point=ch0000.odp0000", "{device}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := hook.expand(hookUpdate(tt.value)); !slices.Equal(actual, tt.expected) {
+				t.Errorf("Expected arguments %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// TestUpdateHookMatches tests that updates are matched against the serial/channel/datapoint filter
+func TestUpdateHookMatches(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected bool
+	}{
+		{filter: "", expected: true},
+		{filter: "ABB7F595EC47/ch0000/odp0000", expected: true},
+		{filter: "ABB7F595EC47/*/odp0000", expected: true},
+		{filter: "*/*/odp*", expected: true},
+		{filter: "ABB7F595EC47/*/idp*", expected: false},
+		{filter: "ABB7013B85DE/*/*", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			hook, err := newUpdateHook("notify.sh", tt.filter, 1)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual := hook.matches(hookUpdate("1")); actual != tt.expected {
+				t.Errorf("Expected %t, got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// TestNewUpdateHookInvalid tests that invalid hook configurations are rejected
+func TestNewUpdateHookInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		filter      string
+		concurrency int
+		expected    string
+	}{
+		{name: "Empty command", template: "  ", concurrency: 1, expected: "update hook command must not be empty"},
+		{name: "No concurrency", template: "notify.sh", concurrency: 0, expected: "update hook concurrency must be at least 1, got 0"},
+		{name: "Invalid filter", template: "notify.sh", filter: "[", concurrency: 1, expected: `invalid update hook filter "[": syntax error in pattern`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newUpdateHook(tt.template, tt.filter, tt.concurrency)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestUpdateHookConcurrencyLimit tests that no more than the configured number of commands run at the same time
+func TestUpdateHookConcurrencyLimit(t *testing.T) {
+	hook, err := newUpdateHook("notify.sh {value}", "", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Block the commands until all updates were handled
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	var running, maxRunning, executed atomic.Int32
+	started.Add(2)
+	hook.run = func(args []string) error {
+		current := running.Add(1)
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		executed.Add(1)
+		started.Done()
+		<-release
+		running.Add(-1)
+		return nil
+	}
+
+	for i := range 5 {
+		hook.handle(hookUpdate(string(rune('0' + i))))
+	}
+	started.Wait()
+	close(release)
+	hook.wait()
+
+	if maxRunning.Load() != 2 {
+		t.Errorf("Expected at most 2 concurrent commands, got %d", maxRunning.Load())
+	}
+	if executed.Load() != 2 {
+		t.Errorf("Expected 2 executed commands while the cap was reached, got %d", executed.Load())
+	}
+
+	// Capacity is available again once the commands have finished
+	hook.run = func(args []string) error {
+		executed.Add(1)
+		return nil
+	}
+	hook.handle(hookUpdate("5"))
+	hook.wait()
+	if executed.Load() != 3 {
+		t.Errorf("Expected 3 executed commands, got %d", executed.Load())
+	}
+}
+
+// TestUpdateHookSkipsUnmatchedUpdates tests that the command is not run for updates not matching the filter
+func TestUpdateHookSkipsUnmatchedUpdates(t *testing.T) {
+	hook, err := newUpdateHook("notify.sh", "ABB7013B85DE/*/*", 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hook.run = func(args []string) error {
+		t.Errorf("Unexpected command execution: %v", args)
+		return nil
+	}
+
+	hook.handle(hookUpdate("1"))
+	hook.wait()
+}
+
+// TestRunHookCommand tests that a command that cannot be executed returns an error
+func TestRunHookCommand(t *testing.T) {
+	if err := runHookCommand([]string{"/nonexistent/freeathome-hook"}); err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// GetInterfaces retrieves the configuration of the system access point and displays how many devices are on each interface
+func GetInterfaces(config GetCommandConfig) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Get configuration
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
+	}
+	interfaces := configuration.Interfaces()
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		return outputCommandJSON(interfaces, "interfaces", config.Prettify, config.Envelope, sysAp.GetHostName(), "get interfaces")
+	}
+
+	if len(interfaces) == 0 {
+		fmt.Println("No interfaces found")
+		return nil
+	}
+
+	// Output as plain text (one interface per line)
+	for _, name := range slices.Sorted(maps.Keys(interfaces)) {
+		fmt.Printf("%s: %d devices\n", name, len(interfaces[name]))
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestGetInterfacesText tests that the number of devices is listed for each interface
+func TestGetInterfacesText(t *testing.T) {
+	setupScenesMock(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetInterfaces(GetCommandConfig{OutputFormat: "text"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "TP: 64 devices\nsysap: 1 devices\n"
+	if output != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// TestGetInterfacesJSON tests that the serials of the devices on each interface are printed as JSON
+func TestGetInterfacesJSON(t *testing.T) {
+	setupScenesMock(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetInterfaces(GetCommandConfig{OutputFormat: "json"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var interfaces map[string][]string
+	if err := json.Unmarshal([]byte(output), &interfaces); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(interfaces["TP"]) != 64 || len(interfaces["sysap"]) != 1 {
+		t.Errorf("Expected 64 TP devices and 1 sysap device, got %v", interfaces)
+	}
+}
+
+// TestGetInterfacesEmpty tests the text output without devices
+func TestGetInterfacesEmpty(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{{"GET /configuration", `{"00000000-0000-0000-0000-000000000000":{"devices":{}}}`}}})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetInterfaces(GetCommandConfig{OutputFormat: "text"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "No interfaces found\n" {
+		t.Errorf("Expected no interfaces output, got '%s'", output)
+	}
+}
+
+// TestGetInterfacesError tests that an error is returned if the configuration cannot be retrieved
+func TestGetInterfacesError(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{})
+
+	if err := GetInterfaces(GetCommandConfig{OutputFormat: "text"}); err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// keypressInput is the input the monitor reads keypresses from
+var keypressInput io.Reader = os.Stdin
+
+// readDeadliner is implemented by inputs whose blocked reads can be interrupted, e.g. pipes
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// watchKeypresses passes the keypresses read from the input to the handler until the handler returns false, the input
+// is closed or the context is cancelled. It returns as soon as the context is cancelled, even if a read is still blocked,
+// so that a shutdown is not held up by waiting for input. A blocked read is interrupted if the input supports read deadlines.
+func watchKeypresses(ctx context.Context, input io.Reader, handle func(char rune) bool) {
+	keys := make(chan rune)
+	go func() {
+		defer close(keys)
+		reader := bufio.NewReader(input)
+		for {
+			char, _, err := reader.ReadRune()
+			if err != nil {
+				return
+			}
+			select {
+			case keys <- char:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if deadliner, ok := input.(readDeadliner); ok {
+				_ = deadliner.SetReadDeadline(time.Now())
+			}
+			return
+		case char, ok := <-keys:
+			if !ok || !handle(char) {
+				return
+			}
+		}
+	}
+}
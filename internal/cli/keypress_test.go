@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWatchKeypressesStopsWhenHandlerDeclines tests that keypresses are handled until the handler returns false
+func TestWatchKeypressesStopsWhenHandlerDeclines(t *testing.T) {
+	var handled []rune
+	watchKeypresses(t.Context(), strings.NewReader("pqu"), func(char rune) bool {
+		handled = append(handled, char)
+		return char != 'q'
+	})
+
+	if string(handled) != "pq" {
+		t.Errorf("Expected keypresses 'pq' to be handled, got '%s'", string(handled))
+	}
+}
+
+// TestWatchKeypressesStopsAtEndOfInput tests that the reader stops once the input is closed instead of spinning
+func TestWatchKeypressesStopsAtEndOfInput(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		watchKeypresses(t.Context(), strings.NewReader(""), func(rune) bool { return true })
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the keypress reader to stop at the end of the input")
+	}
+}
+
+// TestWatchKeypressesStopsOnCancelWithOpenInput tests that a cancelled context stops the reader while stdin is open and no key is pressed
+func TestWatchKeypressesStopsOnCancelWithOpenInput(t *testing.T) {
+	tests := []struct {
+		name string
+		pipe func(t *testing.T) (io.Reader, io.Closer)
+	}{
+		{name: "Input without read deadlines", pipe: func(t *testing.T) (io.Reader, io.Closer) {
+			r, w := io.Pipe()
+			return r, w
+		}},
+		{name: "Input with read deadlines", pipe: func(t *testing.T) (io.Reader, io.Closer) {
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("failed to create pipe: %v", err)
+			}
+			t.Cleanup(func() { _ = r.Close() })
+			return r, w
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, writer := tt.pipe(t)
+			defer func() { _ = writer.Close() }()
+
+			ctx, cancel := context.WithCancel(t.Context())
+			done := make(chan struct{})
+			go func() {
+				watchKeypresses(ctx, input, func(rune) bool { return true })
+				close(done)
+			}()
+
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Expected the keypress reader to stop after the context was cancelled")
+			}
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package cli
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
 )
 
 // MonitorCommandConfig is a struct that contains the configuration for the monitor command
@@ -16,16 +20,258 @@ type MonitorCommandConfig struct {
 	Timeout                 int
 	MaxReconnectionAttempts int
 	ExponentialBackoff      bool
+	Schema                  bool
+	UnixSocket              string
+	SSEAddr                 string
+	ResolveNames            bool
+	SQLite                  string
+	Timing                  bool
+	Uptime                  bool
+	OnUpdate                string
+	OnUpdateFilter          string
+	OnUpdateConcurrency     int
+	Aggregate               []string
+	AggregatePower          []string
+	AggregateInterval       int
+	Capture                 string
+	HomeAssistant           string
+	HomeAssistantPrefix     string
+	Thresholds              []string
+	MaskSerials             bool
 }
 
 // Monitor connects to the free@home system access point via WebSocket and monitors real-time events
 func Monitor(config MonitorCommandConfig) error {
+	// Mask the device serials in the log output and the printed and exported updates if requested, e.g. to share logs publicly.
+	// The masks are stable for the session only.
+	// The schema and the captured frames are written from the raw frames, which contain the serials, so they cannot be masked.
+	var masker *freeathome.SerialMasker
+	if config.MaskSerials {
+		if config.Schema || config.Capture != "" {
+			return errors.New("--mask-serials cannot be combined with --schema or --capture, as the raw frames contain the device serials")
+		}
+		masker = freeathome.NewSerialMasker()
+		config.SerialMasker = masker
+	}
+
 	// Setup system access point
 	sysAp, err := setupFunc(config.CommandConfig, "")
 	if err != nil {
 		return err
 	}
 
+	// Collect the handlers for raw messages
+	var messageHandlers []func([]byte)
+
+	// Print the inferred schema of the first few messages if requested
+	if config.Schema {
+		var received atomic.Int32
+		messageHandlers = append(messageHandlers, func(message []byte) {
+			if received.Add(1) > schemaMessageCount {
+				return
+			}
+			if err := printSchema(message); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to infer message schema: %v\n", err)
+			}
+		})
+	}
+
+	// Record the raw frames to a file that can be replayed if requested
+	if config.Capture != "" {
+		capture, err := newFrameCapture(config.Capture)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = capture.Close()
+		}()
+
+		messageHandlers = append(messageHandlers, capture.record)
+		fmt.Printf("Capturing received frames to %s\n", config.Capture)
+	}
+
+	// Suppress insignificant changes of numeric datapoints if requested
+	var thresholds *thresholdFilter
+	if len(config.Thresholds) > 0 {
+		thresholds, err = newThresholdFilter(config.Thresholds)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Collect the handlers for datapoint updates and established connections
+	var datapointHandlers []func(models.DatapointUpdate)
+	connectedHandlers := []func(){func() {
+		fmt.Printf("Connected to %s\n", sysAp.GetHostName())
+	}}
+
+	// Serve updates as newline delimited JSON on a Unix domain socket if requested
+	if config.UnixSocket != "" {
+		server, err := newUnixSocketServer(config.UnixSocket)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = server.Close()
+		}()
+
+		datapointHandlers = append(datapointHandlers, func(update models.DatapointUpdate) {
+			line, err := formatNDJSON(maskUpdate(masker, update))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to format datapoint update: %v\n", err)
+				return
+			}
+			server.broadcast(line)
+		})
+		fmt.Printf("Serving updates on unix socket %s\n", config.UnixSocket)
+	}
+
+	// Serve updates as server-sent events if requested
+	if config.SSEAddr != "" {
+		server, err := newSSEServer(config.SSEAddr, sseHeartbeatInterval)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = server.Close()
+		}()
+
+		datapointHandlers = append(datapointHandlers, func(update models.DatapointUpdate) {
+			if err := server.broadcast(maskUpdate(masker, update)); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to format datapoint update: %v\n", err)
+			}
+		})
+		fmt.Printf("Serving updates as server-sent events on http://%s/events\n", server.listener.Addr())
+	}
+
+	// Record updates in a SQLite database if requested
+	if config.SQLite != "" {
+		recorder, err := newSQLiteRecorder(config.SQLite)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = recorder.Close()
+		}()
+
+		datapointHandlers = append(datapointHandlers, func(update models.DatapointUpdate) {
+			recorder.record(maskUpdate(masker, update))
+		})
+		fmt.Printf("Recording updates to sqlite database %s\n", config.SQLite)
+	}
+
+	// Announce the devices to Home Assistant via MQTT discovery and publish their state updates if requested
+	if config.HomeAssistant != "" {
+		bridge, client, err := startHomeAssistantBridge(sysAp, config.HomeAssistant, config.HomeAssistantPrefix)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = client.Close()
+		}()
+
+		datapointHandlers = append(datapointHandlers, bridge.handle)
+		fmt.Printf("Publishing %d Home Assistant entities to %s\n", len(bridge.entities), redactURL(config.HomeAssistant))
+	}
+
+	// Run a command for matching updates if requested
+	if config.OnUpdate != "" {
+		hook, err := newUpdateHook(config.OnUpdate, config.OnUpdateFilter, config.OnUpdateConcurrency)
+		if err != nil {
+			return err
+		}
+		defer hook.wait()
+
+		datapointHandlers = append(datapointHandlers, hook.handle)
+	}
+
+	// Aggregate the values of numeric datapoints and print periodic summaries if requested
+	var aggregator *datapointAggregator
+	if len(config.Aggregate) > 0 || len(config.AggregatePower) > 0 {
+		if config.AggregateInterval < 1 {
+			return fmt.Errorf("aggregate interval must be at least 1 second, got %d", config.AggregateInterval)
+		}
+		aggregator, err = newDatapointAggregator(config.Aggregate, config.AggregatePower)
+		if err != nil {
+			return err
+		}
+
+		datapointHandlers = append(datapointHandlers, aggregator.handle)
+	}
+
+	// Print updates annotated with the friendly device names and the timing if requested. Updates are also printed if
+	// thresholds are set, as only the updates passing the thresholds are emitted to the handlers.
+	if config.ResolveNames || config.Timing || thresholds != nil {
+		format := func(update models.DatapointUpdate) string {
+			return formatUpdate(maskUpdate(masker, update))
+		}
+		if config.ResolveNames {
+			resolver := newNameResolver(sysAp.GetConfiguration, sysAp.GetUUID())
+			resolver.masker = masker
+			if err := resolver.refresh(); err != nil {
+				return err
+			}
+
+			// Refresh the names on every reconnection, the configuration was already fetched for the first connection
+			var connections atomic.Int32
+			connectedHandlers = append(connectedHandlers, func() {
+				if connections.Add(1) == 1 {
+					return
+				}
+				resolver.refreshInBackground(func(err error) {
+					fmt.Fprintf(os.Stderr, "Failed to refresh device names: %v\n", err)
+				})
+			})
+			format = resolver.annotate
+		}
+
+		if config.Timing {
+			timer := newUpdateTimer(time.Now())
+			formatName := format
+			format = func(update models.DatapointUpdate) string {
+				return timer.annotate(update, formatName(update))
+			}
+		}
+
+		datapointHandlers = append(datapointHandlers, func(update models.DatapointUpdate) {
+			fmt.Println(format(update))
+		})
+	}
+
+	// Register the message and datapoint handlers
+	if len(messageHandlers) > 0 {
+		sysAp.SetMessageHandler(func(message []byte) {
+			for _, handler := range messageHandlers {
+				handler(message)
+			}
+		})
+	}
+	if len(datapointHandlers) > 0 {
+		sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+			if thresholds != nil && !thresholds.allow(update) {
+				return
+			}
+			for _, handler := range datapointHandlers {
+				handler(update)
+			}
+		})
+	}
+	sysAp.SetConnectedHandler(func() {
+		for _, handler := range connectedHandlers {
+			handler()
+		}
+	})
+
+	// Report failed connection attempts with a concise line, the full error is logged at debug level
+	sysAp.SetConnectionFailedHandler(func(attempt, maxAttempts int, err error) {
+		fmt.Println(formatConnectionFailed(sysAp.GetHostName(), attempt, maxAttempts))
+	})
+
+	// Report why a lost connection is re-established
+	sysAp.SetReconnectingHandler(func(reason *freeathome.DisconnectError) {
+		fmt.Println(formatReconnecting(sysAp.GetHostName(), reason))
+	})
+
 	// Create context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -34,29 +280,63 @@ func Monitor(config MonitorCommandConfig) error {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
-	// Create error channel for the shutdown
-	shutdown := make(chan error, 1)
-
-	// Setup keypress handling for graceful shutdown
+	// Reload the host name from the configuration on SIGHUP, so that a system access point that got a new address is found on
+	// the next reconnect. Toggle pausing the reconnection on SIGUSR1, e.g. during maintenance of the system access point,
+	// which is not available on Windows.
+	reloadSigs := make(chan os.Signal, 1)
+	signal.Notify(reloadSigs, syscall.SIGHUP)
+	defer signal.Stop(reloadSigs)
+	pauseSigs := make(chan os.Signal, 1)
+	notifyPauseSignal(pauseSigs)
+	defer signal.Stop(pauseSigs)
 	go func() {
-		reader := bufio.NewReader(os.Stdin)
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			default:
-				char, _, err := reader.ReadRune()
-				if err != nil {
-					break
-				}
-				if char == 'q' || char == 'Q' {
-					// Send SIGINT to trigger graceful shutdown
-					sigs <- syscall.SIGINT
+			case <-reloadSigs:
+				reloadHostName(config.CommandConfig, sysAp)
+			case <-pauseSigs:
+				toggleReconnectionPaused(sysAp)
+			}
+		}
+	}()
+
+	// Print the aggregate summaries periodically
+	if aggregator != nil {
+		go func() {
+			ticker := time.NewTicker(time.Duration(config.AggregateInterval) * time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
 					return
+				case <-ticker.C:
+					fmt.Println(aggregator.summary())
 				}
 			}
+		}()
+	}
+
+	// Create error channel for the shutdown
+	shutdown := make(chan error, 1)
+
+	// Setup keypress handling for graceful shutdown, the reader stops with the context so it cannot hold up a shutdown by signal
+	go watchKeypresses(ctx, keypressInput, func(char rune) bool {
+		switch char {
+		case 'q', 'Q':
+			// Send SIGINT to trigger graceful shutdown
+			sigs <- syscall.SIGINT
+			return false
+		case 'p', 'P':
+			toggleReconnectionPaused(sysAp)
+		case 'u', 'U':
+			if config.Uptime {
+				fmt.Println(formatUptime(sysAp.GetConnectionUptime()))
+			}
 		}
-	}()
+		return true
+	})
 
 	go func() {
 		// First signal triggers graceful shutdown
@@ -71,9 +351,13 @@ func Monitor(config MonitorCommandConfig) error {
 		shutdown <- fmt.Errorf("forced shutdown requested")
 	}()
 
-	fmt.Println("Press 'q' or Ctrl+C to exit")
+	fmt.Println("Press 'q' or Ctrl+C to exit, 'p' or send SIGUSR1 to pause or resume reconnecting, send SIGHUP to reload the host name")
+	if config.Uptime {
+		fmt.Println("Press 'u' to print the connection uptime")
+	}
 
 	// Connect to the system access point websocket
+	fmt.Printf("Connecting to %s, will retry if it is unreachable...\n", sysAp.GetHostName())
 	timeout := time.Duration(config.Timeout) * time.Second
 	go func() {
 		shutdown <- sysAp.ConnectWebSocket(ctx, config.MaxReconnectionAttempts, config.ExponentialBackoff, timeout)
@@ -81,9 +365,62 @@ func Monitor(config MonitorCommandConfig) error {
 
 	// Handle both forced shutdown and WebSocket connection errors
 	err = <-shutdown
+
+	// Print the uptime over the session if requested
+	if config.Uptime {
+		fmt.Println(formatUptime(sysAp.GetConnectionUptime()))
+	}
+
+	// Print the final aggregate summary
+	if aggregator != nil {
+		fmt.Println(aggregator.summary())
+	}
 	if err != nil && err != context.Canceled {
 		return err
 	}
 
 	return nil
 }
+
+// toggleReconnectionPaused pauses or resumes the reconnection of the web socket and prints the new state
+func toggleReconnectionPaused(sysAp *freeathome.SystemAccessPoint) {
+	if sysAp.ToggleReconnectionPaused() {
+		fmt.Println("Reconnection paused, press 'p' or send SIGUSR1 again to resume")
+		return
+	}
+	fmt.Println("Reconnection resumed")
+}
+
+// reloadHostName reads the host name from the configuration again and applies it to the system access point. A changed host
+// name takes effect on the next connection attempt, the current connection is kept.
+func reloadHostName(config CommandConfig, sysAp *freeathome.SystemAccessPoint) {
+	cfg, err := load(config.Viper, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to reload configuration: %v\n", err)
+		return
+	}
+	if cfg.Hostname == "" || cfg.Hostname == sysAp.GetHostName() {
+		return
+	}
+	sysAp.SetHostName(cfg.Hostname)
+	fmt.Printf("Host name changed to %s, used from the next connection attempt\n", cfg.Hostname)
+}
+
+// maskUpdate returns the update with its serial masked by the masker, a nil masker returns the update unchanged
+func maskUpdate(masker *freeathome.SerialMasker, update models.DatapointUpdate) models.DatapointUpdate {
+	update.Serial = masker.Mask(update.Serial)
+	return update
+}
+
+// formatReconnecting formats the line reported when a lost connection is re-established, including the cause of the disconnect
+func formatReconnecting(host string, reason *freeathome.DisconnectError) string {
+	return fmt.Sprintf("Connection to %s lost (%s: %v), reconnecting...", host, reason.Cause, reason.Err)
+}
+
+// formatConnectionFailed formats a failed connection attempt as a concise line for the user
+func formatConnectionFailed(host string, attempt, maxAttempts int) string {
+	if attempt < maxAttempts {
+		return fmt.Sprintf("Could not connect to %s (attempt %d of %d), retrying...", host, attempt, maxAttempts)
+	}
+	return fmt.Sprintf("Could not connect to %s (attempt %d of %d), giving up", host, attempt, maxAttempts)
+}
@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,6 +23,7 @@ func TestMonitorCommandConfig(t *testing.T) {
 		Timeout:                 30,
 		MaxReconnectionAttempts: 3,
 		ExponentialBackoff:      true,
+		Schema:                  true,
 	}
 
 	assert.NotNil(t, config.Viper)
@@ -28,6 +33,7 @@ func TestMonitorCommandConfig(t *testing.T) {
 	assert.Equal(t, 30, config.Timeout)
 	assert.Equal(t, 3, config.MaxReconnectionAttempts)
 	assert.True(t, config.ExponentialBackoff)
+	assert.True(t, config.Schema)
 }
 
 func TestSetupMonitorWithInvalidConfig(t *testing.T) {
@@ -46,3 +52,83 @@ func TestSetupMonitorWithInvalidConfig(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "hostname not configured")
 }
+
+func TestToggleReconnectionPaused(t *testing.T) {
+	sysAp := freeathome.NewSystemAccessPointWithDefaults("test-host", "test-user", "test-pass")
+
+	// The first toggle pauses the reconnection
+	output := captureStdout(t, func() {
+		toggleReconnectionPaused(sysAp)
+	})
+	assert.True(t, sysAp.IsReconnectionPaused())
+	assert.Contains(t, output, "Reconnection paused")
+
+	// The second toggle resumes the reconnection
+	output = captureStdout(t, func() {
+		toggleReconnectionPaused(sysAp)
+	})
+	assert.False(t, sysAp.IsReconnectionPaused())
+	assert.Contains(t, output, "Reconnection resumed")
+}
+
+func TestReloadHostName(t *testing.T) {
+	sysAp := freeathome.NewSystemAccessPointWithDefaults("old-host", "test-user", "test-pass")
+	configFile := createTestConfigFile(t, "hostname: new-host\nusername: test-user\npassword: test-pass\n")
+	t.Setenv(EnvConfigFile, configFile)
+	config := CommandConfig{Viper: viper.New()}
+
+	// A changed host name is applied for the next connection attempt
+	output := captureStdout(t, func() {
+		reloadHostName(config, sysAp)
+	})
+	assert.Equal(t, "new-host", sysAp.GetHostName())
+	assert.Contains(t, output, "Host name changed to new-host")
+
+	// An unchanged host name is not reported again
+	output = captureStdout(t, func() {
+		reloadHostName(config, sysAp)
+	})
+	assert.Equal(t, "new-host", sysAp.GetHostName())
+	assert.Empty(t, output)
+}
+
+func TestFormatReconnecting(t *testing.T) {
+	reason := &freeathome.DisconnectError{Cause: freeathome.DisconnectCauseServerClose, Err: errors.New("websocket: close 1001 (going away)")}
+	assert.Equal(t, "Connection to 192.168.1.100 lost (server close: websocket: close 1001 (going away)), reconnecting...", formatReconnecting("192.168.1.100", reason))
+}
+
+func TestFormatConnectionFailed(t *testing.T) {
+	assert.Equal(t, "Could not connect to 192.168.1.100 (attempt 1 of 3), retrying...", formatConnectionFailed("192.168.1.100", 1, 3))
+	assert.Equal(t, "Could not connect to 192.168.1.100 (attempt 3 of 3), giving up", formatConnectionFailed("192.168.1.100", 3, 3))
+}
+
+func TestMaskUpdate(t *testing.T) {
+	update := models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"}
+	masker := freeathome.NewSerialMasker()
+
+	masked := maskUpdate(masker, update)
+	assert.Equal(t, masker.Mask("ABB7F595EC47"), masked.Serial)
+	assert.Equal(t, masked, maskUpdate(masker, update))
+	assert.NotEqual(t, masked.Serial, maskUpdate(masker, models.DatapointUpdate{Serial: "ABB700000001"}).Serial)
+	assert.Equal(t, update, maskUpdate(nil, update))
+}
+
+// TestMonitorMaskSerialsWithRawFrames tests that masking the serials cannot be combined with the raw frame output
+func TestMonitorMaskSerialsWithRawFrames(t *testing.T) {
+	originalSetup := setupFunc
+	defer func() { setupFunc = originalSetup }()
+	setupFunc = func(CommandConfig, string) (*freeathome.SystemAccessPoint, error) {
+		t.Fatal("Expected the system access point not to be set up")
+		return nil, nil
+	}
+
+	for _, config := range []MonitorCommandConfig{
+		{MaskSerials: true, Schema: true},
+		{MaskSerials: true, Capture: "frames.jsonl"},
+	} {
+		err := Monitor(config)
+		if err == nil || !strings.Contains(err.Error(), "--mask-serials cannot be combined with --schema or --capture") {
+			t.Errorf("Expected mask serials error, got %v", err)
+		}
+	}
+}
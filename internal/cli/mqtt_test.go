@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// fakeMQTTBroker accepts a single client, acknowledges the connection with the return code and passes the received
+// packets to the channel
+func fakeMQTTBroker(t *testing.T, returnCode byte) (string, <-chan packets.ControlPacket) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan packets.ControlPacket, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for {
+			packet, err := packets.ReadPacket(conn)
+			if err != nil {
+				close(received)
+				return
+			}
+			if _, ok := packet.(*packets.ConnectPacket); ok {
+				connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+				connack.ReturnCode = returnCode
+				_ = connack.Write(conn)
+			}
+			received <- packet
+		}
+	}()
+
+	return "mqtt://user:secret@" + listener.Addr().String(), received
+}
+
+// nextPacket returns the next packet received by the fake broker
+func nextPacket(t *testing.T, received <-chan packets.ControlPacket) packets.ControlPacket {
+	t.Helper()
+	select {
+	case packet := <-received:
+		return packet
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for packet")
+		return nil
+	}
+}
+
+// TestMQTTClientPublish tests the packets sent to the broker when connecting, publishing and disconnecting
+func TestMQTTClientPublish(t *testing.T) {
+	brokerURL, received := fakeMQTTBroker(t, packets.Accepted)
+
+	client, err := newMQTTClient(brokerURL, "test-client")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	connect, ok := nextPacket(t, received).(*packets.ConnectPacket)
+	if !ok {
+		t.Fatal("Expected a connect packet")
+	}
+	if connect.ClientIdentifier != "test-client" || connect.Username != "user" || string(connect.Password) != "secret" {
+		t.Errorf("Unexpected connect packet: %s", connect)
+	}
+
+	if err := client.publish("a/b", []byte("1"), true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	publish, ok := nextPacket(t, received).(*packets.PublishPacket)
+	if !ok {
+		t.Fatal("Expected a publish packet")
+	}
+	if publish.TopicName != "a/b" || string(publish.Payload) != "1" || !publish.Retain || publish.Qos != 0 {
+		t.Errorf("Expected retained publish of 1 to a/b, got %s", publish)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, ok := nextPacket(t, received).(*packets.DisconnectPacket); !ok {
+		t.Error("Expected a disconnect packet")
+	}
+}
+
+// TestMQTTClientConnectionRefused tests that a refused connection is reported
+func TestMQTTClientConnectionRefused(t *testing.T) {
+	brokerURL, _ := fakeMQTTBroker(t, packets.ErrRefusedNotAuthorised)
+
+	_, err := newMQTTClient(brokerURL, "test-client")
+	if err == nil || !strings.Contains(err.Error(), "failed to connect to MQTT broker") {
+		t.Errorf("Expected connection refused error, got %v", err)
+	}
+}
+
+// TestMQTTClientInvalidURL tests that unsupported broker URLs are rejected
+func TestMQTTClientInvalidURL(t *testing.T) {
+	_, err := newMQTTClient("http://localhost", "test-client")
+	if err == nil || !strings.Contains(err.Error(), "unsupported MQTT broker URL scheme: http") {
+		t.Errorf("Expected unsupported scheme error, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// deviceName contains the friendly name and room of a device
+type deviceName struct {
+	Name string
+	Room string
+}
+
+// nameResolver resolves device serials to friendly names using the configuration of the system access point
+type nameResolver struct {
+	// fetch retrieves the configuration of the system access point
+	fetch func() (*models.Configuration, error)
+	// uuid identifies the system access point within the configuration
+	uuid string
+	// names maps device serials to their friendly names
+	names map[string]deviceName
+	// mutex protects access to names
+	mutex sync.RWMutex
+	// refreshMutex serializes the refreshes, so that an older configuration never replaces a newer one
+	refreshMutex sync.Mutex
+	// masker masks the serials in the annotated updates if set, the names are still resolved by the real serial
+	masker *freeathome.SerialMasker
+}
+
+// newNameResolver creates a name resolver that retrieves the configuration using the given function and resolves the names
+// of the devices of the system access point with the given UUID
+func newNameResolver(fetch func() (*models.Configuration, error), uuid string) *nameResolver {
+	return &nameResolver{
+		fetch: fetch,
+		uuid:  uuid,
+		names: map[string]deviceName{},
+	}
+}
+
+// refresh retrieves the configuration and rebuilds the serial to name map.
+// The previous map is kept if the configuration cannot be retrieved.
+func (r *nameResolver) refresh() error {
+	r.refreshMutex.Lock()
+	defer r.refreshMutex.Unlock()
+
+	configuration, err := r.fetch()
+	if err != nil {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+
+	names := map[string]deviceName{}
+	if configuration != nil {
+		sysAp, err := sysApConfiguration(*configuration, r.uuid)
+		if err != nil {
+			return err
+		}
+		for serial, device := range sysAp.Devices {
+			var name deviceName
+			if device.DisplayName != nil {
+				name.Name = *device.DisplayName
+			}
+			// Room identifiers are only unique within a floor
+			if device.Floor != nil && device.Room != nil {
+				if room, exists := sysAp.Floorplan.Floors[*device.Floor].Rooms[*device.Room]; exists {
+					name.Room = room.Name
+				}
+			}
+			names[serial] = name
+		}
+	}
+
+	r.mutex.Lock()
+	r.names = names
+	r.mutex.Unlock()
+	return nil
+}
+
+// refreshInBackground refreshes the names without blocking the caller, e.g. the connected handler of the web socket.
+// The annotated updates use the previous names until the refresh completes, errors are passed to onError.
+func (r *nameResolver) refreshInBackground(onError func(error)) {
+	go func() {
+		if err := r.refresh(); err != nil {
+			onError(err)
+		}
+	}()
+}
+
+// annotate formats a datapoint update, enriched with the friendly device name and room if they are known.
+// Unknown devices fall back to the serial only.
+func (r *nameResolver) annotate(update models.DatapointUpdate) string {
+	r.mutex.RLock()
+	name, exists := r.names[update.Serial]
+	r.mutex.RUnlock()
+
+	serial := r.masker.Mask(update.Serial)
+	device := serial
+	switch {
+	case exists && name.Name != "" && name.Room != "":
+		device = fmt.Sprintf("%s (%s, %s)", serial, name.Name, name.Room)
+	case exists && name.Name != "":
+		device = fmt.Sprintf("%s (%s)", serial, name.Name)
+	case exists && name.Room != "":
+		device = fmt.Sprintf("%s (%s)", serial, name.Room)
+	}
+
+	return fmt.Sprintf("%s %s.%s = %s", device, update.Channel, update.Datapoint, update.Value)
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// namesConfiguration is a configuration fixture with a named device in a room and a device without name
+const namesConfiguration = `{
+  "00000000-0000-0000-0000-000000000000": {
+    "devices": {
+      "ABB7F595EC47": {"displayName": "Ceiling Light", "floor": "01", "room": "02"},
+      "ABB7013B85DE": {"displayName": "Blinds"},
+      "ABB7F5947E20": {}
+    },
+    "floorplan": {
+      "floors": {
+        "01": {"name": "Ground Floor", "rooms": {"02": {"name": "Living Room"}}}
+      }
+    },
+    "sysapName": "SysAP",
+    "users": {}
+  }
+}`
+
+// fetchConfiguration returns a function that returns the parsed configuration
+func fetchConfiguration(t *testing.T, body string) func() (*models.Configuration, error) {
+	t.Helper()
+
+	return func() (*models.Configuration, error) {
+		var configuration models.Configuration
+		if err := json.Unmarshal([]byte(body), &configuration); err != nil {
+			return nil, err
+		}
+		return &configuration, nil
+	}
+}
+
+// TestNameResolverAnnotate tests that updates are annotated with the device name and room
+func TestNameResolverAnnotate(t *testing.T) {
+	resolver := newNameResolver(fetchConfiguration(t, namesConfiguration), models.EmptyUUID)
+	if err := resolver.refresh(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		serial   string
+		expected string
+	}{
+		{name: "Name and room", serial: "ABB7F595EC47", expected: "ABB7F595EC47 (Ceiling Light, Living Room) ch0000.odp0000 = 1"},
+		{name: "Name only", serial: "ABB7013B85DE", expected: "ABB7013B85DE (Blinds) ch0000.odp0000 = 1"},
+		{name: "Device without name", serial: "ABB7F5947E20", expected: "ABB7F5947E20 ch0000.odp0000 = 1"},
+		{name: "Unknown device", serial: "UNKNOWN", expected: "UNKNOWN ch0000.odp0000 = 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolver.annotate(models.DatapointUpdate{
+				Serial:    tt.serial,
+				Channel:   "ch0000",
+				Datapoint: "odp0000",
+				Value:     "1",
+			})
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestNameResolverAnnotateMasked tests that the names are resolved by the real serial while the masked serial is printed
+func TestNameResolverAnnotateMasked(t *testing.T) {
+	resolver := newNameResolver(fetchConfiguration(t, namesConfiguration), models.EmptyUUID)
+	if err := resolver.refresh(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resolver.masker = freeathome.NewSerialMasker()
+
+	result := resolver.annotate(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+	expected := resolver.masker.Mask("ABB7F595EC47") + " (Ceiling Light, Living Room) ch0000.odp0000 = 1"
+	if result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
+// TestNameResolverRefresh tests that a refresh replaces the names and keeps them if the configuration cannot be retrieved
+func TestNameResolverRefresh(t *testing.T) {
+	body := namesConfiguration
+	var fetchErr error
+	resolver := newNameResolver(func() (*models.Configuration, error) {
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return fetchConfiguration(t, body)()
+	}, models.EmptyUUID)
+	if err := resolver.refresh(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Rename the device and refresh
+	body = strings.Replace(namesConfiguration, "Blinds", "Shutter", 1)
+	if err := resolver.refresh(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	update := models.DatapointUpdate{Serial: "ABB7013B85DE", Channel: "ch0000", Datapoint: "odp0000", Value: "1"}
+	if result := resolver.annotate(update); result != "ABB7013B85DE (Shutter) ch0000.odp0000 = 1" {
+		t.Errorf("Expected refreshed name, got '%s'", result)
+	}
+
+	// A failing refresh keeps the previous names
+	fetchErr = errors.New("offline")
+	err := resolver.refresh()
+	if err == nil || !strings.Contains(err.Error(), "failed to get configuration: offline") {
+		t.Errorf("Expected error to contain 'failed to get configuration: offline', got '%v'", err)
+	}
+	if result := resolver.annotate(update); result != "ABB7013B85DE (Shutter) ch0000.odp0000 = 1" {
+		t.Errorf("Expected previous name to be kept, got '%s'", result)
+	}
+}
+
+// TestNameResolverRefreshInBackground tests that the names are refreshed without blocking the caller
+func TestNameResolverRefreshInBackground(t *testing.T) {
+	release := make(chan struct{})
+	fetched := make(chan struct{})
+	resolver := newNameResolver(func() (*models.Configuration, error) {
+		defer close(fetched)
+		<-release
+		return fetchConfiguration(t, namesConfiguration)()
+	}, models.EmptyUUID)
+
+	// The caller is not blocked while the configuration is retrieved
+	resolver.refreshInBackground(func(err error) {
+		t.Errorf("Unexpected error: %v", err)
+	})
+	update := models.DatapointUpdate{Serial: "ABB7013B85DE", Channel: "ch0000", Datapoint: "odp0000", Value: "1"}
+	if result := resolver.annotate(update); result != "ABB7013B85DE ch0000.odp0000 = 1" {
+		t.Errorf("Expected no name before the refresh completed, got '%s'", result)
+	}
+
+	close(release)
+	<-fetched
+	// Acquiring the refresh mutex waits until the names are stored
+	resolver.refreshMutex.Lock()
+	resolver.refreshMutex.Unlock()
+	if result := resolver.annotate(update); result != "ABB7013B85DE (Blinds) ch0000.odp0000 = 1" {
+		t.Errorf("Expected refreshed name, got '%s'", result)
+	}
+}
+
+// TestNameResolverRefreshInBackgroundError tests that errors of a background refresh are passed to the callback
+func TestNameResolverRefreshInBackgroundError(t *testing.T) {
+	resolver := newNameResolver(func() (*models.Configuration, error) {
+		return nil, errors.New("offline")
+	}, models.EmptyUUID)
+
+	errs := make(chan error, 1)
+	resolver.refreshInBackground(func(err error) {
+		errs <- err
+	})
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "failed to get configuration: offline") {
+			t.Errorf("Expected error to contain 'failed to get configuration: offline', got '%v'", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the refresh error")
+	}
+}
+
+// TestNameResolverMultipleSysAps tests that the names of the selected system access point are resolved and that a
+// configuration with several system access points requires one to be selected
+func TestNameResolverMultipleSysAps(t *testing.T) {
+	body := `{
+  "sysap-a": {"devices": {"ABB7F595EC47": {"displayName": "Ceiling Light"}}},
+  "sysap-b": {"devices": {"ABB7F595EC47": {"displayName": "Garage Light"}}}
+}`
+
+	resolver := newNameResolver(fetchConfiguration(t, body), models.EmptyUUID)
+	if err := resolver.refresh(); err == nil || !strings.Contains(err.Error(), "with --sysap-uuid") {
+		t.Errorf("Expected an error pointing to --sysap-uuid, got %v", err)
+	}
+
+	resolver = newNameResolver(fetchConfiguration(t, body), "sysap-b")
+	if err := resolver.refresh(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	update := models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"}
+	if result := resolver.annotate(update); result != "ABB7F595EC47 (Garage Light) ch0000.odp0000 = 1" {
+		t.Errorf("Expected the name of the selected system access point, got '%s'", result)
+	}
+}
@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// formatNDJSON formats a datapoint update as a single line of newline delimited JSON
+func formatNDJSON(update models.DatapointUpdate) ([]byte, error) {
+	line, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal datapoint update to JSON: %w", err)
+	}
+
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestFormatNDJSON tests that a datapoint update is formatted as a single JSON line
+func TestFormatNDJSON(t *testing.T) {
+	update := models.DatapointUpdate{
+		Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		SysApID:   models.EmptyUUID,
+		Serial:    "ABB7F595EC47",
+		Channel:   "ch0000",
+		Datapoint: "odp0000",
+		Value:     "1",
+	}
+
+	line, err := formatNDJSON(update)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"timestamp":"2025-01-01T12:00:00Z","sysApId":"00000000-0000-0000-0000-000000000000","serial":"ABB7F595EC47","channel":"ch0000","datapoint":"odp0000","value":"1"}` + "\n"
+	if string(line) != expected {
+		t.Errorf("Expected line '%s', got '%s'", expected, string(line))
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyPauseSignal relays SIGUSR1, which toggles pausing the reconnection of the monitor, to the channel
+func notifyPauseSignal(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
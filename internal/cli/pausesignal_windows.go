@@ -0,0 +1,8 @@
+//go:build windows
+
+package cli
+
+import "os"
+
+// notifyPauseSignal does nothing, as Windows has no SIGUSR1. The reconnection can still be paused with the 'p' key.
+func notifyPauseSignal(chan<- os.Signal) {}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+)
+
+// Refresh retrieves the configuration from the system access point again and summarizes the refreshed configuration
+func Refresh(config CommandConfig) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config, "")
+	if err != nil {
+		return err
+	}
+
+	// Refresh configuration
+	configuration, err := sysAp.RefreshConfiguration()
+	if err != nil {
+		return handleSysApError(err, "refresh configuration", config.TLSEnabled, config.SkipTLSVerify)
+	}
+
+	// Summarize the refreshed configuration of every system access point
+	fmt.Println("Configuration refreshed")
+	for _, id := range configuration.SysApIDs() {
+		sysApConfiguration := (*configuration)[id]
+		fmt.Printf("  %s: %d devices\n", sysApConfiguration.SysApName, len(sysApConfiguration.Devices))
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+const refreshConfiguration = `{"00000000-0000-0000-0000-000000000000":{"devices":{"ABB7F595EC47":{}},"floorplan":{"floors":{}},"sysapName":"Test","users":{}}}`
+
+// TestRefresh tests that the configuration is retrieved again and summarized
+func TestRefresh(t *testing.T) {
+	transport := &pathRoundTripper{responses: []pathResponse{
+		{"GET /configuration", refreshConfiguration},
+	}}
+	setupPathMock(t, transport)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = Refresh(CommandConfig{})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"Configuration refreshed", "Test: 1 devices"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got '%s'", expected, output)
+		}
+	}
+	if len(transport.requests) != 1 || transport.requests[0] != "GET /fhapi/v1/api/rest/configuration" {
+		t.Errorf("Expected only the configuration to be retrieved, got %v", transport.requests)
+	}
+}
+
+// TestRefreshError tests that a failure to retrieve the configuration is returned
+func TestRefreshError(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{})
+
+	err := Refresh(CommandConfig{})
+	if err == nil || !strings.Contains(err.Error(), "failed to refresh configuration") {
+		t.Errorf("Expected refresh error, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// sysApEntry returns the entry of a response for the system access point, following the same rule as the accessors of the
+// models, see models.SysApEntry
+func sysApEntry[M ~map[string]V, V any](response M) (V, bool) {
+	return models.SysApEntry(response)
+}
+
+// sysApConfiguration returns the configuration of the system access point with the given UUID. If the configuration contains
+// several system access points, the error points to the flag selecting one of them.
+func sysApConfiguration(configuration models.Configuration, uuid string) (models.SysAP, error) {
+	sysAp, err := configuration.SysAp(uuid)
+	var multipleErr *models.MultipleSysApsError
+	if errors.As(err, &multipleErr) {
+		return sysAp, fmt.Errorf("%w with --sysap-uuid", err)
+	}
+	return sysAp, err
+}
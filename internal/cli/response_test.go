@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestSysApEntry tests that the entry of the empty UUID is preferred and the only entry is used as a fallback
+func TestSysApEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		response models.DeviceList
+		expected []string
+		found    bool
+	}{
+		{name: "Empty UUID", response: models.DeviceList{models.EmptyUUID: {"ABB7F595EC47"}, "other-uuid": {"ABB7013B85DE"}}, expected: []string{"ABB7F595EC47"}, found: true},
+		{name: "Single non-empty UUID", response: models.DeviceList{"other-uuid": {"ABB7013B85DE"}}, expected: []string{"ABB7013B85DE"}, found: true},
+		{name: "Multiple non-empty UUIDs", response: models.DeviceList{"other-uuid": {"ABB7013B85DE"}, "another-uuid": {"ABB7F5947E20"}}, found: false},
+		{name: "Empty response", response: models.DeviceList{}, found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			devices, found := sysApEntry(tt.response)
+			if found != tt.found {
+				t.Fatalf("Expected found to be %t, got %t", tt.found, found)
+			}
+			if !slices.Equal(devices, tt.expected) {
+				t.Errorf("Expected devices %v, got %v", tt.expected, devices)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// parseScales parses datapoint scales in the format "serial/channel/datapoint=factor unit", e.g.
+// "ABB7F595EC47/ch0000/odp0010=0.1 °C". The unit is optional and the datapoint may also be given as serial.channel.datapoint.
+func parseScales(specs []string) (map[models.DatapointKey]models.DatapointScale, error) {
+	scales := map[models.DatapointKey]models.DatapointScale{}
+	for _, spec := range specs {
+		key, value, err := parseDatapointSpec(spec, "scale", "serial/channel/datapoint=factor unit")
+		if err != nil {
+			return nil, err
+		}
+		factorText, unit, _ := strings.Cut(strings.TrimSpace(value), " ")
+		factor, err := strconv.ParseFloat(factorText, 64)
+		if err != nil || factor == 0 || math.IsNaN(factor) || math.IsInf(factor, 0) {
+			return nil, fmt.Errorf("invalid scale %q: expected a non-zero factor", spec)
+		}
+		scales[key] = models.DatapointScale{Factor: factor, Unit: strings.TrimSpace(unit)}
+	}
+	return scales, nil
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+func TestParseScales(t *testing.T) {
+	scales, err := parseScales([]string{"ABB7F595EC47/ch0000/odp0010=0.1 °C", "ABB700000001.ch0001.odp0002=100"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[models.DatapointKey]models.DatapointScale{
+		{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0010"}: {Factor: 0.1, Unit: "°C"},
+		{Serial: "ABB700000001", Channel: "ch0001", Datapoint: "odp0002"}: {Factor: 100},
+	}
+	if len(scales) != len(expected) {
+		t.Fatalf("Expected %d scales, got %v", len(expected), scales)
+	}
+	for key, scale := range expected {
+		if scales[key] != scale {
+			t.Errorf("Expected scale %v for %s, got %v", scale, key, scales[key])
+		}
+	}
+
+	for _, spec := range []string{"ABB7F595EC47/ch0000/odp0010", "invalid=0.1", "ABB7F595EC47/ch0000/odp0010=0", "ABB7F595EC47/ch0000/odp0010=tenth °C"} {
+		if _, err := parseScales([]string{spec}); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// GetScenes retrieves and displays the scenes of the system access point with their scene actuators
+func GetScenes(config GetCommandConfig) error {
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Get scenes
+	scenesResponse, err := sysAp.GetScenes()
+	if err != nil {
+		return handleSysApError(err, "get scenes", config.TLSEnabled, config.SkipTLSVerify)
+	}
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		return outputCommandJSON(scenesResponse, "scenes", config.Prettify, config.Envelope, sysAp.GetHostName(), "get scenes")
+	}
+
+	// Get scenes for the system access point
+	var scenes []models.SceneActuator
+	if scenesResponse != nil {
+		scenes, _ = sysApEntry(*scenesResponse)
+	}
+	if len(scenes) == 0 {
+		fmt.Println("No scenes found")
+		return nil
+	}
+
+	// Output as plain text (one scene per line)
+	for _, scene := range scenes {
+		fmt.Printf("%s: %s/%s\n", scene.Name, scene.Serial, scene.Channel)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// setupScenesMock answers configuration requests with the configuration fixture
+func setupScenesMock(t *testing.T) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration.json"))
+	if err != nil {
+		t.Fatalf("Failed to read configuration fixture: %v", err)
+	}
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{{"GET /configuration", string(data)}}})
+}
+
+// TestGetScenesText tests that the scenes are listed with the serials and channels of their actuators
+func TestGetScenesText(t *testing.T) {
+	setupScenesMock(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetScenes(GetCommandConfig{OutputFormat: "text"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `Alles aus: FFFF48020001/ch0000
+Alles aus: FFFF48020004/ch0000
+Essen: FFFF48000004/ch0000
+Kochen: FFFF48000009/ch0000
+Wohnzimmer TV: FFFF48000001/ch0000
+Zu Bett Gehen: FFFF48000006/ch0000
+`
+	if output != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+// TestGetScenesJSON tests that the scenes are printed as JSON
+func TestGetScenesJSON(t *testing.T) {
+	setupScenesMock(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetScenes(GetCommandConfig{OutputFormat: "json"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var scenes models.Scenes
+	if err := json.Unmarshal([]byte(output), &scenes); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(scenes[models.EmptyUUID]) != 6 {
+		t.Errorf("Expected 6 scenes, got %d", len(scenes[models.EmptyUUID]))
+	}
+}
+
+// TestGetScenesEmpty tests the text output without scenes
+func TestGetScenesEmpty(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{{"GET /configuration", `{"00000000-0000-0000-0000-000000000000":{"devices":{}}}`}}})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = GetScenes(GetCommandConfig{OutputFormat: "text"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "No scenes found\n" {
+		t.Errorf("Expected no scenes output, got '%s'", output)
+	}
+}
+
+// TestGetScenesError tests that an error is returned if the configuration cannot be retrieved
+func TestGetScenesError(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{})
+
+	if err := GetScenes(GetCommandConfig{OutputFormat: "text"}); err == nil {
+		t.Error("Expected an error")
+	}
+}
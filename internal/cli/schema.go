@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// schemaMessageCount is the number of received messages for which the monitor prints the inferred schema
+const schemaMessageCount = 3
+
+// inferSchema infers the JSON structure of a message. Objects keep their keys, arrays are
+// represented by the schema of their first element and all other values are replaced by their type name.
+func inferSchema(message []byte) (any, error) {
+	var value any
+	if err := json.Unmarshal(message, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	return schemaOf(value), nil
+}
+
+// schemaOf returns the schema of a decoded JSON value
+func schemaOf(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		schema := make(map[string]any, len(v))
+		for key, item := range v {
+			schema[key] = schemaOf(item)
+		}
+		return schema
+	case []any:
+		if len(v) == 0 {
+			return []any{}
+		}
+		return []any{schemaOf(v[0])}
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// printSchema prints the inferred schema of a message as indented JSON
+func printSchema(message []byte) error {
+	schema, err := inferSchema(message)
+	if err != nil {
+		return err
+	}
+
+	// Marshal the schema, map keys are sorted by the JSON encoder
+	jsonData, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema to JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestInferSchema tests that the schema of a websocket message is inferred correctly
+func TestInferSchema(t *testing.T) {
+	message := `{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F59451FB/ch0000/odp0000":"0"},"devices":{},"devicesAdded":["ABB7F59451FB"],"devicesRemoved":[],"scenesTriggered":{},"parameters":{"enabled":true,"count":1,"missing":null}}}`
+
+	schema, err := inferSchema([]byte(message))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]any{
+		"00000000-0000-0000-0000-000000000000": map[string]any{
+			"datapoints": map[string]any{
+				"ABB7F59451FB/ch0000/odp0000": "string",
+			},
+			"devices":         map[string]any{},
+			"devicesAdded":    []any{"string"},
+			"devicesRemoved":  []any{},
+			"scenesTriggered": map[string]any{},
+			"parameters": map[string]any{
+				"enabled": "boolean",
+				"count":   "number",
+				"missing": "null",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("Expected schema %v, got %v", expected, schema)
+	}
+}
+
+// TestInferSchemaInvalidMessage tests that an invalid message returns an error
+func TestInferSchemaInvalidMessage(t *testing.T) {
+	schema, err := inferSchema([]byte("invalid json"))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+	if !strings.Contains(err.Error(), "failed to parse message") {
+		t.Errorf("Expected error to contain 'failed to parse message', got '%s'", err.Error())
+	}
+	if schema != nil {
+		t.Errorf("Expected nil schema, got %v", schema)
+	}
+}
+
+// TestPrintSchema tests that the inferred schema is printed as indented JSON
+func TestPrintSchema(t *testing.T) {
+	// Capture stdout for output testing
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	err := printSchema([]byte(`{"b":1,"a":["x"]}`))
+
+	// Close pipe and read output
+	_ = w.Close()
+	output, _ := io.ReadAll(r)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "{\n  \"a\": [\n    \"string\"\n  ],\n  \"b\": \"number\"\n}\n"
+	if string(output) != expected {
+		t.Errorf("Expected output '%s', got '%s'", expected, string(output))
+	}
+
+	// Verify the output is valid JSON
+	var decoded map[string]any
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		t.Errorf("Expected valid JSON output, got error: %v", err)
+	}
+}
@@ -2,8 +2,9 @@ package cli
 
 import (
 	"fmt"
-
-	"github.com/pgerke/freeathome/v2/pkg/models"
+	"math"
+	"strconv"
+	"strings"
 )
 
 // SetCommandConfig is a struct that contains the configuration for the set command
@@ -11,6 +12,7 @@ type SetCommandConfig struct {
 	CommandConfig
 	OutputFormat string
 	Prettify     bool
+	Envelope     bool
 }
 
 // SetDatapoint sets a specific datapoint value
@@ -29,7 +31,7 @@ func SetDatapoint(config SetCommandConfig, serial string, channel string, datapo
 
 	// Output depending on output format
 	if config.OutputFormat == "json" {
-		return outputJSON(datapointResponse, "datapoint", config.Prettify)
+		return outputCommandJSON(datapointResponse, "datapoint", config.Prettify, config.Envelope, sysAp.GetHostName(), "set datapoint")
 	}
 
 	// Check if datapoint response is empty
@@ -38,8 +40,8 @@ func SetDatapoint(config SetCommandConfig, serial string, channel string, datapo
 		return nil
 	}
 
-	// Get datapoint for the system access point (using EmptyUUID as key)
-	datapointData, exists := (*datapointResponse)[models.EmptyUUID]
+	// Get datapoint for the system access point
+	datapointData, exists := sysApEntry(*datapointResponse)
 	if !exists {
 		fmt.Printf("Failed to set datapoint: %s.%s.%s\n", serial, channel, datapoint)
 		return nil
@@ -55,3 +57,40 @@ func SetDatapoint(config SetCommandConfig, serial string, channel string, datapo
 
 	return nil
 }
+
+// ParseDatapointValue converts a datapoint value given in the specified format (auto, decimal or hex) to the canonical
+// decimal string expected by the system access point. In auto mode, values with a 0x prefix are parsed as hex and all
+// other values are passed on unchanged.
+func ParseDatapointValue(value string, format string) (string, error) {
+	switch format {
+	case "", "auto":
+		if hasHexPrefix(value) {
+			return ParseDatapointValue(value, "hex")
+		}
+		return value, nil
+	case "decimal":
+		// NaN and infinity are parsed as well, but cannot be sent as a datapoint value
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", fmt.Errorf("invalid decimal value: %s", value)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case "hex":
+		digits := value
+		if hasHexPrefix(digits) {
+			digits = digits[2:]
+		}
+		number, err := strconv.ParseUint(digits, 16, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid hex value: %s", value)
+		}
+		return strconv.FormatUint(number, 10), nil
+	default:
+		return "", fmt.Errorf("unsupported value format: %s", format)
+	}
+}
+
+// hasHexPrefix reports whether the value starts with 0x or 0X
+func hasHexPrefix(value string) bool {
+	return strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X")
+}
@@ -2,12 +2,14 @@ package cli
 
 import (
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/pgerke/freeathome/v2/pkg/freeathome"
 	"github.com/spf13/viper"
 )
@@ -124,12 +126,32 @@ func TestSetDatapoint(t *testing.T) {
 			responseBody: `{
   "other-uuid": {
     "status": "success"
+  },
+  "another-uuid": {
+    "status": "success"
   }
 }`,
 			responseCode: http.StatusOK,
 			expectError:  false,
 			expectOutput: "Failed to set datapoint: ABB7F595EC47.ch0000.idp0000\n",
 		},
+		{
+			name:         "Datapoint for a single non-empty UUID",
+			serial:       "ABB7F595EC47",
+			channel:      "ch0000",
+			datapoint:    "idp0000",
+			value:        "1",
+			outputFormat: "text",
+			prettify:     false,
+			responseBody: `{
+  "other-uuid": {
+    "status": "success"
+  }
+}`,
+			responseCode: http.StatusOK,
+			expectError:  false,
+			expectOutput: "Datapoint set successfully: ABB7F595EC47.ch0000.idp0000\n  Response: map[status:success]\n",
+		},
 		{
 			name:          "HTTP error response",
 			serial:        "ABB7F595EC47",
@@ -549,3 +571,99 @@ func TestSetDatapointWithSpecialCharacters(t *testing.T) {
 		})
 	}
 }
+
+// TestParseDatapointValue tests the conversion of hex, decimal and auto detected values to the canonical decimal string
+func TestParseDatapointValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		format      string
+		expected    string
+		expectError bool
+	}{
+		{name: "Auto hex", value: "0xFF", format: "auto", expected: "255"},
+		{name: "Auto hex upper case prefix", value: "0X1a", format: "auto", expected: "26"},
+		{name: "Auto decimal", value: "42", format: "auto", expected: "42"},
+		{name: "Auto text is unchanged", value: "on", format: "auto", expected: "on"},
+		{name: "Empty format is auto", value: "0x10", format: "", expected: "16"},
+		{name: "Auto invalid hex", value: "0xZZ", format: "auto", expectError: true},
+		{name: "Hex with prefix", value: "0xff", format: "hex", expected: "255"},
+		{name: "Hex without prefix", value: "FF", format: "hex", expected: "255"},
+		{name: "Hex zero", value: "0", format: "hex", expected: "0"},
+		{name: "Hex invalid", value: "G1", format: "hex", expectError: true},
+		{name: "Hex empty", value: "0x", format: "hex", expectError: true},
+		{name: "Decimal integer", value: "255", format: "decimal", expected: "255"},
+		{name: "Decimal leading zeros", value: "007", format: "decimal", expected: "7"},
+		{name: "Decimal fraction", value: "21.50", format: "decimal", expected: "21.5"},
+		{name: "Decimal negative", value: "-3", format: "decimal", expected: "-3"},
+		{name: "Decimal rejects hex", value: "0xFF", format: "decimal", expectError: true},
+		{name: "Decimal rejects NaN", value: "NaN", format: "decimal", expectError: true},
+		{name: "Decimal rejects infinity", value: "Inf", format: "decimal", expectError: true},
+		{name: "Decimal rejects negative infinity", value: "-infinity", format: "decimal", expectError: true},
+		{name: "Decimal rejects overflow", value: "1e400", format: "decimal", expectError: true},
+		{name: "Unsupported format", value: "1", format: "octal", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseDatapointValue(tt.value, tt.format)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got value '%s'", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected value '%s', got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// TestSetDatapointParsedValue tests that a parsed value is sent to the system access point as canonical decimal string
+func TestSetDatapointParsedValue(t *testing.T) {
+	for _, tt := range []struct {
+		value    string
+		format   string
+		expected string
+	}{
+		{value: "0xFF", format: "auto", expected: "255"},
+		{value: "ff", format: "hex", expected: "255"},
+		{value: "0255", format: "decimal", expected: "255"},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			transport := &MockRoundTripper{Response: newTestResponse(http.StatusOK, `{"00000000-0000-0000-0000-000000000000":{"status":"success"}}`)}
+			sysApConfig := freeathome.NewConfig("test-host", "test-user", "test-pass")
+			sysApConfig.Logger = freeathome.NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+			sysApConfig.Client = resty.New().SetTransport(transport)
+			setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+				return freeathome.MustNewSystemAccessPoint(sysApConfig), nil
+			}
+			t.Cleanup(func() {
+				setupFunc = setup
+			})
+
+			value, err := ParseDatapointValue(tt.value, tt.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			captureStdout(t, func() {
+				err = SetDatapoint(SetCommandConfig{OutputFormat: "json"}, "ABB7F595EC47", "ch0000", "idp0000", value)
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			body, err := io.ReadAll(transport.Request.Body)
+			if err != nil {
+				t.Fatalf("Failed to read request body: %v", err)
+			}
+			if string(body) != tt.expected {
+				t.Errorf("Expected sent value '%s', got '%s'", tt.expected, string(body))
+			}
+		})
+	}
+}
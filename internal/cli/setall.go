@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// SetAllCommandConfig is a struct that contains the configuration for the set all command
+type SetAllCommandConfig struct {
+	SetCommandConfig
+	Function string
+	Value    string
+	// Confirmed must be set to write to all matching devices, as the command can switch off an entire installation
+	Confirmed bool
+}
+
+// SetAll writes the value to the switch datapoint of every channel of the given function type, e.g. to turn off all lights.
+// The datapoints are written using the batch mechanism. It returns an error after the output if any datapoint could not be
+// written.
+func SetAll(config SetAllCommandConfig) error {
+	if !slices.Contains(models.FunctionTypes(), config.Function) {
+		return fmt.Errorf("unsupported function type: %s, supported function types are: %s", config.Function, strings.Join(models.FunctionTypes(), ", "))
+	}
+	if !config.Confirmed {
+		return fmt.Errorf("setting the value of all %s devices requires confirmation with --yes", config.Function)
+	}
+
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Find the switch datapoints of the matching channels in the configuration
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
+	}
+	sysApConfig, err := sysApConfiguration(*configuration, sysAp.GetUUID())
+	if err != nil {
+		return err
+	}
+	addresses := switchDatapoints(sysApConfig, config.Function)
+	if len(addresses) == 0 {
+		fmt.Printf("No %s devices found\n", config.Function)
+		return nil
+	}
+
+	results := writeBatch(sysAp, addresses, config.Value)
+
+	// Output depending on output format
+	if config.OutputFormat == "json" {
+		if err := outputCommandJSON(results, "set all results", config.Prettify, config.Envelope, sysAp.GetHostName(), "set all"); err != nil {
+			return err
+		}
+	} else {
+		printWriteResults(results)
+	}
+
+	// Report failed datapoints after the output, so that the successfully written datapoints are not lost
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to set %d of %d datapoints", failed, len(results))
+	}
+
+	return nil
+}
+
+// switchDatapoints returns the sorted addresses of the switch datapoints of all channels of the given function type.
+// Channels without a switch datapoint are skipped.
+func switchDatapoints(sysApConfig models.SysAP, functionType string) []string {
+	var addresses []string
+	for _, serial := range slices.Sorted(maps.Keys(sysApConfig.Devices)) {
+		device := sysApConfig.Devices[serial]
+		if device.Channels == nil {
+			continue
+		}
+		for _, channelID := range slices.Sorted(maps.Keys(*device.Channels)) {
+			channel := (*device.Channels)[channelID]
+			if channel == nil || channel.FunctionID == nil || !models.IsFunctionType(functionType, *channel.FunctionID) {
+				continue
+			}
+			if datapoint, ok := channel.SwitchDatapoint(); ok {
+				addresses = append(addresses, models.DatapointKey{Serial: serial, Channel: channelID, Datapoint: datapoint}.String())
+			}
+		}
+	}
+	return addresses
+}
+
+// printWriteResults prints the write results as a table of addresses and results
+func printWriteResults(results []batchWriteResult) {
+	width := len("ADDRESS")
+	for _, result := range results {
+		width = max(width, len(result.Address))
+	}
+
+	fmt.Printf("%-*s  %s\n", width, "ADDRESS", "RESULT")
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Printf("%-*s  error: %s\n", width, result.Address, result.Error)
+		} else {
+			fmt.Printf("%-*s  ok\n", width, result.Address)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// setAllConfiguration contains switch actuators, a dimmer, a switch channel without switch datapoint and a blind actuator
+const setAllConfiguration = `{"00000000-0000-0000-0000-000000000000":{"devices":{
+	"ABB700000002":{"channels":{
+		"ch0001":{"functionId":"7","inputs":{"idp0000":{"pairingId":1}}},
+		"ch0000":{"functionId":"0007","inputs":{"idp0002":{"pairingId":2},"idp0000":{"pairingId":1}}}
+	}},
+	"ABB700000001":{"channels":{
+		"ch0000":{"functionId":"12","inputs":{"idp0000":{"pairingId":1}}},
+		"ch0001":{"functionId":"7","inputs":{"idp0001":{"pairingId":2}}}
+	}},
+	"ABB700000003":{"channels":{
+		"ch0000":{"functionId":"9","inputs":{"idp0000":{"pairingId":32}}}
+	}}
+}}}`
+
+// TestSetAllTargetsSwitchDatapoints tests that the switch datapoints of all matching channels are written
+func TestSetAllTargetsSwitchDatapoints(t *testing.T) {
+	transport := &pathRoundTripper{responses: []pathResponse{
+		{"GET /configuration", setAllConfiguration},
+		{"PUT /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"result":"OK"}}`},
+	}}
+	setupPathMock(t, transport)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = SetAll(SetAllCommandConfig{SetCommandConfig: SetCommandConfig{OutputFormat: "text"}, Function: "switch", Value: "0", Confirmed: true})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var written []string
+	for _, request := range transport.requests {
+		if path, ok := strings.CutPrefix(request, "PUT "); ok {
+			written = append(written, path[strings.LastIndex(path, "/")+1:])
+		}
+	}
+	slices.Sort(written)
+	expected := []string{"ABB700000002.ch0000.idp0000", "ABB700000002.ch0001.idp0000"}
+	if !slices.Equal(written, expected) {
+		t.Errorf("Expected datapoints %v to be written, got %v", expected, written)
+	}
+
+	expectedOutput := `ADDRESS                      RESULT
+ABB700000002/ch0000/idp0000  ok
+ABB700000002/ch0001/idp0000  ok
+`
+	if output != expectedOutput {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expectedOutput, output)
+	}
+}
+
+// TestSetAllRequiresConfirmation tests that nothing is written without confirmation
+func TestSetAllRequiresConfirmation(t *testing.T) {
+	transport := &pathRoundTripper{responses: []pathResponse{
+		{"GET /configuration", setAllConfiguration},
+	}}
+	setupCalls := setupPathMock(t, transport)
+
+	err := SetAll(SetAllCommandConfig{Function: "switch", Value: "0"})
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("Expected a confirmation error, got %v", err)
+	}
+	if *setupCalls != 0 || len(transport.requests) != 0 {
+		t.Errorf("Expected no requests without confirmation, got %d setup calls and requests %v", *setupCalls, transport.requests)
+	}
+}
+
+// TestSetAllUnsupportedFunction tests that an unknown function type is rejected
+func TestSetAllUnsupportedFunction(t *testing.T) {
+	err := SetAll(SetAllCommandConfig{Function: "blind", Value: "0", Confirmed: true})
+	if err == nil || !strings.Contains(err.Error(), "unsupported function type: blind") {
+		t.Errorf("Expected an unsupported function type error, got %v", err)
+	}
+}
+
+// TestSetAllReportsFailures tests that failed writes are reported after the output
+func TestSetAllReportsFailures(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{
+		{"GET /configuration", setAllConfiguration},
+	}})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = SetAll(SetAllCommandConfig{SetCommandConfig: SetCommandConfig{OutputFormat: "text"}, Function: "dimmer", Value: "0", Confirmed: true})
+	})
+	if err == nil || err.Error() != "failed to set 1 of 1 datapoints" {
+		t.Errorf("Expected failure summary, got %v", err)
+	}
+	if !strings.Contains(output, "ABB700000001/ch0000/idp0000  error:") {
+		t.Errorf("Expected the failed datapoint in the output, got:\n%s", output)
+	}
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// shellPrompt is printed before each command is read in the shell
+const shellPrompt = "fh> "
+
+// ShellCommandConfig is a struct that contains the configuration for the shell command
+type ShellCommandConfig struct {
+	CommandConfig
+	OutputFormat string
+	Prettify     bool
+	Envelope     bool
+}
+
+// shellCommand describes a command that can be run in the shell
+type shellCommand struct {
+	// args is the number of arguments the command requires
+	args int
+	// usage describes the arguments of the command
+	usage string
+	// run runs the command with the given arguments
+	run func(session *shellSession, args []string) error
+}
+
+// shellSession contains the state of a shell session
+type shellSession struct {
+	config ShellCommandConfig
+}
+
+// getConfig returns the configuration for get commands run in the session
+func (s *shellSession) getConfig() GetCommandConfig {
+	return GetCommandConfig{
+		CommandConfig: s.config.CommandConfig,
+		OutputFormat:  s.config.OutputFormat,
+		Prettify:      s.config.Prettify,
+		Envelope:      s.config.Envelope,
+	}
+}
+
+// shellCommands contains the commands that can be run in the shell, dispatching to the command handlers
+var shellCommands = map[string]shellCommand{
+	"devicelist": {
+		run: func(s *shellSession, args []string) error { return GetDeviceList(s.getConfig()) },
+	},
+	"configuration": {
+		run: func(s *shellSession, args []string) error { return GetConfiguration(s.getConfig()) },
+	},
+	"device": {
+		args:  1,
+		usage: "[serial]",
+		run:   func(s *shellSession, args []string) error { return GetDevice(s.getConfig(), args[0]) },
+	},
+	"device-state": {
+		args:  1,
+		usage: "[serial]",
+		run:   func(s *shellSession, args []string) error { return GetDeviceState(s.getConfig(), args[0]) },
+	},
+	"channels": {
+		args:  1,
+		usage: "[serial]",
+		run:   func(s *shellSession, args []string) error { return GetChannels(s.getConfig(), args[0]) },
+	},
+	"datapoint": {
+		args:  3,
+		usage: "[serial] [channel] [datapoint]",
+		run: func(s *shellSession, args []string) error {
+			return GetDatapoint(s.getConfig(), args[0], args[1], args[2])
+		},
+	},
+	"set": {
+		args:  4,
+		usage: "[serial] [channel] [datapoint] [value]",
+		run: func(s *shellSession, args []string) error {
+			return SetDatapoint(SetCommandConfig(s.getConfig()), args[0], args[1], args[2], args[3])
+		},
+	},
+	"output": {
+		args:  1,
+		usage: "[json|text]",
+		run: func(s *shellSession, args []string) error {
+			if args[0] != "json" && args[0] != "text" {
+				return fmt.Errorf("unsupported output format: %s", args[0])
+			}
+			s.config.OutputFormat = args[0]
+			return nil
+		},
+	},
+}
+
+// Shell reads commands from the input and runs them using a single system access point for the whole session,
+// so the connection setup is only done once. The session ends on 'exit', 'quit' or the end of the input.
+func Shell(config ShellCommandConfig, input io.Reader) error {
+	// Setup system access point once for the whole session
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+
+	// Let the command handlers reuse the system access point
+	previousSetup := setupFunc
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		return sysAp, nil
+	}
+	defer func() {
+		setupFunc = previousSetup
+	}()
+
+	session := &shellSession{config: config}
+	fmt.Printf("Connected to %s. Type 'help' for a list of commands, 'exit' to quit.\n", sysAp.GetHostName())
+
+	scanner := bufio.NewScanner(input)
+	for {
+		fmt.Print(shellPrompt)
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		name, args := fields[0], fields[1:]
+		switch name {
+		case "exit", "quit":
+			return nil
+		case "help":
+			printShellHelp()
+			continue
+		}
+
+		command, exists := shellCommands[name]
+		if !exists {
+			fmt.Printf("Unknown command: %s. Type 'help' for a list of commands.\n", name)
+			continue
+		}
+		if len(args) != command.args {
+			fmt.Printf("Usage: %s %s\n", name, command.usage)
+			continue
+		}
+
+		// Errors of a command do not end the session
+		if err := command.run(session, args); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	}
+}
+
+// printShellHelp prints the commands that can be run in the shell
+func printShellHelp() {
+	fmt.Println("Available commands:")
+	for _, name := range slices.Sorted(maps.Keys(shellCommands)) {
+		fmt.Printf("  %s\n", strings.TrimSpace(name+" "+shellCommands[name].usage))
+	}
+	fmt.Println("  help")
+	fmt.Println("  exit")
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestShell tests that scripted commands are dispatched to the command handlers using a single system access point
+func TestShell(t *testing.T) {
+	transport := &pathRoundTripper{responses: []pathResponse{
+		{"GET /devicelist", `{"00000000-0000-0000-0000-000000000000":["ABB7F595EC47"]}`},
+		{"GET /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"values":["1"]}}`},
+		{"PUT /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"ABB7F595EC47/ch0000/idp0000":"OK"}}`},
+	}}
+	setupCalls := setupPathMock(t, transport)
+
+	input := strings.Join([]string{
+		"devicelist",
+		"output text",
+		"devicelist",
+		"",
+		"datapoint ABB7F595EC47 ch0000 odp0000",
+		"set ABB7F595EC47 ch0000 idp0000 1",
+		"datapoint ABB7F595EC47",
+		"unknown",
+		"output yaml",
+		"exit",
+		"devicelist",
+	}, "\n")
+
+	var err error
+	output := captureStdout(t, func() {
+		err = Shell(ShellCommandConfig{OutputFormat: "json"}, bytes.NewBufferString(input))
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The system access point is set up once for the whole session
+	if *setupCalls != 1 {
+		t.Errorf("Expected 1 setup call, got %d", *setupCalls)
+	}
+
+	// The commands after exit are not run
+	expectedRequests := []string{
+		"GET /fhapi/v1/api/rest/devicelist",
+		"GET /fhapi/v1/api/rest/devicelist",
+		"GET /fhapi/v1/api/rest/datapoint/00000000-0000-0000-0000-000000000000/ABB7F595EC47.ch0000.odp0000",
+		"PUT /fhapi/v1/api/rest/datapoint/00000000-0000-0000-0000-000000000000/ABB7F595EC47.ch0000.idp0000",
+	}
+	if strings.Join(transport.requests, "\n") != strings.Join(expectedRequests, "\n") {
+		t.Errorf("Expected requests %v, got %v", expectedRequests, transport.requests)
+	}
+
+	for _, expected := range []string{
+		"Connected to test-host.",
+		`{"00000000-0000-0000-0000-000000000000":["ABB7F595EC47"]}`,
+		"fh> ABB7F595EC47\n",
+		"Datapoint: ABB7F595EC47.ch0000.odp0000",
+		"Datapoint set successfully: ABB7F595EC47.ch0000.idp0000",
+		"Usage: datapoint [serial] [channel] [datapoint]",
+		"Unknown command: unknown.",
+		"Error: unsupported output format: yaml",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got '%s'", expected, output)
+		}
+	}
+
+	// The setup function is restored after the session
+	if _, err := setupFunc(CommandConfig{}, ""); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if *setupCalls != 2 {
+		t.Error("Expected the setup function to be restored after the session")
+	}
+}
+
+// TestShellHelpAndEndOfInput tests the help command and that the session ends at the end of the input
+func TestShellHelpAndEndOfInput(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = Shell(ShellCommandConfig{OutputFormat: "json"}, bytes.NewBufferString("help\n"))
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"Available commands:", "  devicelist\n", "  set [serial] [channel] [datapoint] [value]\n", "  exit\n"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got '%s'", expected, output)
+		}
+	}
+}
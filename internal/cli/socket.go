@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// socketWriteTimeout is the maximum time a write to a socket client may take before the client is dropped
+const socketWriteTimeout = time.Second
+
+// unixSocketServer serves newline delimited JSON updates to all clients connected to a Unix domain socket
+type unixSocketServer struct {
+	listener net.Listener
+	path     string
+	clients  map[net.Conn]struct{}
+	mutex    sync.Mutex
+	done     chan struct{}
+}
+
+// newUnixSocketServer creates a Unix domain socket at the given path and starts accepting clients
+func newUnixSocketServer(path string) (*unixSocketServer, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket %s: %w", path, err)
+	}
+
+	server := &unixSocketServer{
+		listener: listener,
+		path:     path,
+		clients:  make(map[net.Conn]struct{}),
+		done:     make(chan struct{}),
+	}
+	go server.accept()
+
+	return server, nil
+}
+
+// accept accepts clients until the listener is closed
+func (s *unixSocketServer) accept() {
+	defer close(s.done)
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mutex.Lock()
+		s.clients[conn] = struct{}{}
+		s.mutex.Unlock()
+	}
+}
+
+// broadcast writes a line to all connected clients. Clients that cannot be written to are dropped.
+func (s *unixSocketServer) broadcast(line []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for conn := range s.clients {
+		_ = conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+		if _, err := conn.Write(line); err != nil {
+			_ = conn.Close()
+			delete(s.clients, conn)
+		}
+	}
+}
+
+// Close stops accepting clients, disconnects all connected clients and removes the socket file
+func (s *unixSocketServer) Close() error {
+	err := s.listener.Close()
+	<-s.done
+
+	s.mutex.Lock()
+	for conn := range s.clients {
+		_ = conn.Close()
+		delete(s.clients, conn)
+	}
+	s.mutex.Unlock()
+
+	// The listener usually removes the socket file itself, make sure it is gone
+	if removeErr := os.Remove(s.path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+		err = errors.Join(err, removeErr)
+	}
+
+	return err
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// waitForSocketClients waits until the expected number of clients is connected to the server
+func waitForSocketClients(t *testing.T, server *unixSocketServer, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		server.mutex.Lock()
+		count := len(server.clients)
+		server.mutex.Unlock()
+		if count == expected {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d socket clients", expected)
+}
+
+// TestUnixSocketServerBroadcast tests that a connected client receives a pushed update as a JSON line
+func TestUnixSocketServerBroadcast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.sock")
+	server, err := newUnixSocketServer(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	// Connect a client
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect to unix socket: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	waitForSocketClients(t, server, 1)
+
+	// Push an update
+	update := models.DatapointUpdate{
+		Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Serial:    "ABB7F595EC47",
+		Channel:   "ch0000",
+		Datapoint: "odp0000",
+		Value:     "1",
+	}
+	line, err := formatNDJSON(update)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server.broadcast(line)
+
+	// Read the update
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	received, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("Failed to read from unix socket: %v", err)
+	}
+
+	var decoded models.DatapointUpdate
+	if err := json.Unmarshal(received, &decoded); err != nil {
+		t.Fatalf("Expected a JSON line, got error: %v", err)
+	}
+	if decoded != update {
+		t.Errorf("Expected update %+v, got %+v", update, decoded)
+	}
+}
+
+// TestUnixSocketServerDropsDisconnectedClients tests that clients that disconnected are dropped on the next broadcast
+func TestUnixSocketServerDropsDisconnectedClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.sock")
+	server, err := newUnixSocketServer(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect to unix socket: %v", err)
+	}
+	waitForSocketClients(t, server, 1)
+	_ = conn.Close()
+
+	// Writing to a closed connection eventually fails, which drops the client
+	for range 10 {
+		server.broadcast([]byte("{}\n"))
+	}
+	waitForSocketClients(t, server, 0)
+}
+
+// TestUnixSocketServerClose tests that closing the server removes the socket file
+func TestUnixSocketServerClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.sock")
+	server, err := newUnixSocketServer(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect to unix socket: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	waitForSocketClients(t, server, 1)
+
+	if err := server.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected socket file to be removed, got: %v", err)
+	}
+	waitForSocketClients(t, server, 0)
+}
+
+// TestUnixSocketServerInvalidPath tests that an invalid socket path returns an error
+func TestUnixSocketServerInvalidPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "updates.sock")
+	server, err := newUnixSocketServer(path)
+	if err == nil {
+		_ = server.Close()
+		t.Fatal("Expected error but got none")
+	}
+}
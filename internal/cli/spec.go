@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// parseDatapointSpec splits a specification in the format "serial.channel.datapoint=value" into the datapoint key and the
+// value. The datapoint may also be given as serial/channel/datapoint. Letters are matched case-insensitively, the key is
+// normalized to the case of received updates. The kind and the format name the specification in errors, e.g. "threshold"
+// and "serial.channel.datapoint=threshold".
+func parseDatapointSpec(spec string, kind string, format string) (models.DatapointKey, string, error) {
+	address, value, found := strings.Cut(spec, "=")
+	if !found {
+		return models.DatapointKey{}, "", fmt.Errorf("invalid %s %q: expected %s", kind, spec, format)
+	}
+	address = strings.TrimSpace(address)
+	if !strings.Contains(address, "/") {
+		address = strings.ReplaceAll(address, ".", "/")
+	}
+	key, err := models.ParseDatapointKey(address)
+	if err != nil {
+		return models.DatapointKey{}, "", err
+	}
+	return key.Normalize(), value, nil
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestParseDatapointSpec tests that datapoint specifications are split and normalized in every accepted notation
+func TestParseDatapointSpec(t *testing.T) {
+	expected := models.DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001"}
+	for _, spec := range []string{"ABB7F595EC47.ch0000.odp0001=1.5", "ABB7F595EC47/ch0000/odp0001=1.5", "abb7f595ec47.CH0000.ODP0001=1.5", " ABB7F595EC47/ch0000/odp0001 =1.5"} {
+		key, value, err := parseDatapointSpec(spec, "threshold", "serial.channel.datapoint=threshold")
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", spec, err)
+		}
+		if key != expected || value != "1.5" {
+			t.Errorf("Expected %s=1.5 for %q, got %s=%s", expected, spec, key, value)
+		}
+	}
+
+	_, _, err := parseDatapointSpec("ABB7F595EC47.ch0000.odp0001", "threshold", "serial.channel.datapoint=threshold")
+	if err == nil || !strings.Contains(err.Error(), `invalid threshold "ABB7F595EC47.ch0000.odp0001": expected serial.channel.datapoint=threshold`) {
+		t.Errorf("Expected a missing value error, got %v", err)
+	}
+	if _, _, err := parseDatapointSpec("ABB7F595EC47.ch0000=1", "threshold", "serial.channel.datapoint=threshold"); err == nil {
+		t.Error("Expected an error for an invalid datapoint")
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+
+	// Register the pure Go SQLite driver
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// sqliteBatchSize is the maximum number of updates that are written in a single transaction
+	sqliteBatchSize = 100
+	// sqliteFlushInterval is the maximum time an update is buffered before it is written
+	sqliteFlushInterval = time.Second
+)
+
+// sqliteSchema creates the table for the datapoint updates if it does not exist
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS datapoint_updates (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp TEXT NOT NULL,
+	sysap TEXT NOT NULL,
+	device TEXT NOT NULL,
+	channel TEXT NOT NULL,
+	datapoint TEXT NOT NULL,
+	value TEXT NOT NULL
+)`
+
+// sqliteInsert inserts a single datapoint update
+const sqliteInsert = `INSERT INTO datapoint_updates (timestamp, sysap, device, channel, datapoint, value) VALUES (?, ?, ?, ?, ?, ?)`
+
+// sqliteRecorder writes datapoint updates into a SQLite database.
+// Updates are buffered and written in batched transactions using a prepared statement.
+type sqliteRecorder struct {
+	db      *sql.DB
+	insert  *sql.Stmt
+	updates chan models.DatapointUpdate
+	done    chan struct{}
+	closed  bool
+	mutex   sync.RWMutex
+}
+
+// newSQLiteRecorder opens the SQLite database with the given data source name, creates the schema if absent and
+// starts writing the recorded updates in the background
+func newSQLiteRecorder(dataSourceName string) (*sqliteRecorder, error) {
+	db, err := sql.Open("sqlite", dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database %s: %w", dataSourceName, err)
+	}
+
+	// SQLite only supports a single writer, a single connection also keeps in-memory databases alive
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+
+	insert, err := db.Prepare(sqliteInsert)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to prepare sqlite statement: %w", err)
+	}
+
+	recorder := &sqliteRecorder{
+		db:      db,
+		insert:  insert,
+		updates: make(chan models.DatapointUpdate, sqliteBatchSize),
+		done:    make(chan struct{}),
+	}
+	go recorder.run()
+
+	return recorder, nil
+}
+
+// record queues a datapoint update for writing. Updates recorded after closing are dropped.
+func (r *sqliteRecorder) record(update models.DatapointUpdate) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if !r.closed {
+		r.updates <- update
+	}
+}
+
+// run collects the queued updates and writes them when the batch is full, the flush interval elapsed or the recorder is closed
+func (r *sqliteRecorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(sqliteFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.DatapointUpdate, 0, sqliteBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := r.write(batch); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write %d datapoint updates to sqlite: %v\n", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case update, ok := <-r.updates:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, update)
+			if len(batch) >= sqliteBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// write inserts the updates in a single transaction
+func (r *sqliteRecorder) write(updates []models.DatapointUpdate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	insert := tx.Stmt(r.insert)
+	for _, update := range updates {
+		if _, err := insert.Exec(update.Timestamp.Format(time.RFC3339Nano), update.SysApID, update.Serial, update.Channel, update.Datapoint, update.Value); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Close writes the queued updates and closes the database
+func (r *sqliteRecorder) Close() error {
+	r.mutex.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.updates)
+	}
+	r.mutex.Unlock()
+	<-r.done
+
+	_ = r.insert.Close()
+	return r.db.Close()
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// openSharedMemoryDatabase opens a named in-memory database that is shared between connections of the test,
+// so that it outlives the recorder
+func openSharedMemoryDatabase(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+
+	dataSourceName := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := sql.Open("sqlite", dataSourceName)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db, dataSourceName
+}
+
+// readRecordedUpdates reads all recorded updates in insertion order
+func readRecordedUpdates(t *testing.T, db *sql.DB) []models.DatapointUpdate {
+	t.Helper()
+
+	rows, err := db.Query(`SELECT timestamp, sysap, device, channel, datapoint, value FROM datapoint_updates ORDER BY id`)
+	if err != nil {
+		t.Fatalf("Failed to query updates: %v", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var updates []models.DatapointUpdate
+	for rows.Next() {
+		var update models.DatapointUpdate
+		var timestamp string
+		if err := rows.Scan(&timestamp, &update.SysApID, &update.Serial, &update.Channel, &update.Datapoint, &update.Value); err != nil {
+			t.Fatalf("Failed to scan update: %v", err)
+		}
+		update.Timestamp, err = time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			t.Fatalf("Failed to parse timestamp: %v", err)
+		}
+		updates = append(updates, update)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Failed to read updates: %v", err)
+	}
+	return updates
+}
+
+// TestSQLiteRecorder tests that recorded updates are inserted with the correct fields
+func TestSQLiteRecorder(t *testing.T) {
+	db, dataSourceName := openSharedMemoryDatabase(t)
+
+	recorder, err := newSQLiteRecorder(dataSourceName)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Record more updates than fit into a single batch
+	timestamp := time.Date(2025, 1, 1, 12, 0, 0, 123456789, time.UTC)
+	var expected []models.DatapointUpdate
+	for i := range sqliteBatchSize + 5 {
+		update := models.DatapointUpdate{
+			Timestamp: timestamp.Add(time.Duration(i) * time.Second),
+			SysApID:   models.EmptyUUID,
+			Serial:    "ABB7F595EC47",
+			Channel:   "ch0000",
+			Datapoint: fmt.Sprintf("odp%04d", i%10),
+			Value:     fmt.Sprint(i),
+		}
+		expected = append(expected, update)
+		recorder.record(update)
+	}
+
+	// Closing writes the remaining updates, later updates are dropped
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	recorder.record(models.DatapointUpdate{Timestamp: timestamp, Value: "dropped"})
+
+	updates := readRecordedUpdates(t, db)
+	if len(updates) != len(expected) {
+		t.Fatalf("Expected %d updates, got %d", len(expected), len(updates))
+	}
+	for i := range expected {
+		if !updates[i].Timestamp.Equal(expected[i].Timestamp) {
+			t.Errorf("Expected timestamp %s, got %s", expected[i].Timestamp, updates[i].Timestamp)
+		}
+		updates[i].Timestamp = expected[i].Timestamp
+		if updates[i] != expected[i] {
+			t.Errorf("Expected update %+v, got %+v", expected[i], updates[i])
+		}
+	}
+}
+
+// TestSQLiteRecorderExistingSchema tests that an existing table is reused without losing its rows
+func TestSQLiteRecorderExistingSchema(t *testing.T) {
+	db, dataSourceName := openSharedMemoryDatabase(t)
+
+	for range 2 {
+		recorder, err := newSQLiteRecorder(dataSourceName)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		recorder.record(models.DatapointUpdate{Timestamp: time.Now(), Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+		if err := recorder.Close(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if updates := readRecordedUpdates(t, db); len(updates) != 2 {
+		t.Errorf("Expected 2 updates, got %d", len(updates))
+	}
+}
+
+// TestSQLiteRecorderOpenError tests that an invalid database path returns an error
+func TestSQLiteRecorderOpenError(t *testing.T) {
+	_, err := newSQLiteRecorder(t.TempDir() + "/missing/updates.db")
+	if err == nil {
+		t.Error("Expected error for a database in a missing directory")
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// sseHeartbeatInterval is the interval between two heartbeat comments, which keep idle connections open through proxies
+const sseHeartbeatInterval = 15 * time.Second
+
+// sseClientBufferSize is the number of events buffered per client, clients that fall further behind are dropped
+const sseClientBufferSize = 16
+
+// sseServer serves updates as server-sent events to all clients connected to its /events endpoint
+type sseServer struct {
+	listener          net.Listener
+	server            *http.Server
+	heartbeatInterval time.Duration
+	clients           map[chan []byte]struct{}
+	mutex             sync.Mutex
+	done              chan struct{}
+}
+
+// newSSEServer listens on the given address and starts serving the /events endpoint
+func newSSEServer(addr string, heartbeatInterval time.Duration) (*sseServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := &sseServer{
+		listener:          listener,
+		heartbeatInterval: heartbeatInterval,
+		clients:           make(map[chan []byte]struct{}),
+		done:              make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events", s.handleEvents)
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		defer close(s.done)
+		_ = s.server.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+// handleEvents streams the events to the client until it disconnects or the server is closed
+func (s *sseServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events := s.addClient()
+	defer s.removeClient(events)
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			_, err = w.Write(event)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// addClient registers a new client and returns the channel its events are sent to
+func (s *sseServer) addClient() chan []byte {
+	events := make(chan []byte, sseClientBufferSize)
+	s.mutex.Lock()
+	s.clients[events] = struct{}{}
+	s.mutex.Unlock()
+	return events
+}
+
+// removeClient unregisters the client and closes its channel, if that has not happened yet
+func (s *sseServer) removeClient(events chan []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, exists := s.clients[events]; exists {
+		delete(s.clients, events)
+		close(events)
+	}
+}
+
+// broadcast sends the update as an event to all connected clients. Clients whose buffer is full are dropped.
+func (s *sseServer) broadcast(update models.DatapointUpdate) error {
+	event, err := formatSSEEvent(update)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for events := range s.clients {
+		select {
+		case events <- event:
+		default:
+			delete(s.clients, events)
+			close(events)
+		}
+	}
+	return nil
+}
+
+// Close stops the server and disconnects all connected clients
+func (s *sseServer) Close() error {
+	err := s.server.Close()
+	<-s.done
+
+	s.mutex.Lock()
+	for events := range s.clients {
+		delete(s.clients, events)
+		close(events)
+	}
+	s.mutex.Unlock()
+
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// formatSSEEvent formats a datapoint update as a server-sent event with a JSON payload
+func formatSSEEvent(update models.DatapointUpdate) ([]byte, error) {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal datapoint update to JSON: %w", err)
+	}
+
+	return fmt.Appendf(nil, "event: update\ndata: %s\n\n", data), nil
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// waitForSSEClients waits until the expected number of clients is connected to the server
+func waitForSSEClients(t *testing.T, server *sseServer, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		server.mutex.Lock()
+		count := len(server.clients)
+		server.mutex.Unlock()
+		if count == expected {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d SSE clients", expected)
+}
+
+// connectSSEClient connects a client to the events endpoint of the server
+func connectSSEClient(t *testing.T, server *sseServer) *http.Response {
+	t.Helper()
+
+	resp, err := http.Get("http://" + server.listener.Addr().String() + "/events")
+	if err != nil {
+		t.Fatalf("Failed to connect to SSE server: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+	return resp
+}
+
+// readSSELine reads the next line of the event stream, waiting at most a second
+func readSSELine(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+
+	lines := make(chan string, 1)
+	go func() {
+		line, _ := reader.ReadString('\n')
+		lines <- line
+	}()
+
+	select {
+	case line := <-lines:
+		return strings.TrimSuffix(line, "\n")
+	case <-time.After(time.Second):
+		t.Fatal("Timed out reading from SSE stream")
+		return ""
+	}
+}
+
+// TestSSEServerBroadcast tests that a connected client receives a pushed update as a JSON event
+func TestSSEServerBroadcast(t *testing.T) {
+	server, err := newSSEServer("127.0.0.1:0", time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	resp := connectSSEClient(t, server)
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Expected content type 'text/event-stream', got '%s'", contentType)
+	}
+	if cacheControl := resp.Header.Get("Cache-Control"); cacheControl != "no-cache" {
+		t.Errorf("Expected cache control 'no-cache', got '%s'", cacheControl)
+	}
+	waitForSSEClients(t, server, 1)
+
+	// Push an update
+	update := models.DatapointUpdate{
+		Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Serial:    "ABB7F595EC47",
+		Channel:   "ch0000",
+		Datapoint: "odp0000",
+		Value:     "1",
+	}
+	if err := server.broadcast(update); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Read the event
+	reader := bufio.NewReader(resp.Body)
+	if line := readSSELine(t, reader); line != "event: update" {
+		t.Errorf("Expected event line 'event: update', got '%s'", line)
+	}
+	line := readSSELine(t, reader)
+	data, found := strings.CutPrefix(line, "data: ")
+	if !found {
+		t.Fatalf("Expected a data line, got '%s'", line)
+	}
+
+	var decoded models.DatapointUpdate
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("Expected JSON data, got error: %v", err)
+	}
+	if decoded != update {
+		t.Errorf("Expected update %+v, got %+v", update, decoded)
+	}
+	if line := readSSELine(t, reader); line != "" {
+		t.Errorf("Expected the event to end with an empty line, got '%s'", line)
+	}
+}
+
+// TestSSEServerHeartbeat tests that heartbeat comments are sent to idle clients
+func TestSSEServerHeartbeat(t *testing.T) {
+	server, err := newSSEServer("127.0.0.1:0", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	resp := connectSSEClient(t, server)
+	if line := readSSELine(t, bufio.NewReader(resp.Body)); line != ": heartbeat" {
+		t.Errorf("Expected heartbeat comment, got '%s'", line)
+	}
+}
+
+// TestSSEServerDropsDisconnectedClients tests that clients are removed when they disconnect
+func TestSSEServerDropsDisconnectedClients(t *testing.T) {
+	server, err := newSSEServer("127.0.0.1:0", time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+
+	resp := connectSSEClient(t, server)
+	waitForSSEClients(t, server, 1)
+	_ = resp.Body.Close()
+	waitForSSEClients(t, server, 0)
+}
+
+// TestSSEServerClose tests that closing the server disconnects the connected clients
+func TestSSEServerClose(t *testing.T) {
+	server, err := newSSEServer("127.0.0.1:0", time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	connectSSEClient(t, server)
+	waitForSSEClients(t, server, 1)
+
+	if err := server.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	waitForSSEClients(t, server, 0)
+}
+
+// TestSSEServerInvalidAddress tests that an invalid address returns an error
+func TestSSEServerInvalidAddress(t *testing.T) {
+	server, err := newSSEServer("invalid:address:0", time.Hour)
+	if err == nil {
+		_ = server.Close()
+		t.Fatal("Expected error but got none")
+	}
+}
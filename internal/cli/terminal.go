@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// terminalDetector reports whether the file descriptor refers to a terminal. It can be replaced in tests.
+var terminalDetector = func(fd uintptr) (bool, error) {
+	return term.IsTerminal(int(fd)), nil
+}
+
+// isTerminal reports whether the file is a terminal. It defaults to false, i.e. no colors and no interactive output, if
+// the detection fails or panics, e.g. for an unusual file descriptor on some platforms.
+func isTerminal(file *os.File) (terminal bool) {
+	if file == nil {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			terminal = false
+		}
+	}()
+
+	terminal, err := terminalDetector(file.Fd())
+	if err != nil {
+		return false
+	}
+	return terminal
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setTerminalDetector replaces the terminal detector for the duration of the test
+func setTerminalDetector(t *testing.T, detector func(fd uintptr) (bool, error)) {
+	t.Helper()
+	original := terminalDetector
+	terminalDetector = detector
+	t.Cleanup(func() { terminalDetector = original })
+}
+
+func TestIsTerminalPipe(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+	defer func() { _ = writer.Close() }()
+
+	assert.False(t, isTerminal(reader))
+	assert.False(t, isTerminal(writer))
+	assert.False(t, isTerminal(nil))
+}
+
+func TestIsTerminalDetector(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector func(fd uintptr) (bool, error)
+		expected bool
+	}{
+		{name: "Terminal", detector: func(uintptr) (bool, error) { return true, nil }, expected: true},
+		{name: "No terminal", detector: func(uintptr) (bool, error) { return false, nil }, expected: false},
+		{name: "Error", detector: func(uintptr) (bool, error) { return true, errors.New("bad file descriptor") }, expected: false},
+		{name: "Panic", detector: func(uintptr) (bool, error) { panic("unsupported platform") }, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTerminalDetector(t, tt.detector)
+			assert.Equal(t, tt.expected, isTerminal(os.Stderr))
+		})
+	}
+}
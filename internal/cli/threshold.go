@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// thresholdFilter suppresses numeric updates of datapoints that differ from the last emitted value by no more than the
+// threshold of the datapoint. Updates of other datapoints and non-numeric values always pass.
+type thresholdFilter struct {
+	thresholds map[models.DatapointKey]float64
+	// last contains the last emitted numeric value per datapoint
+	last  map[models.DatapointKey]float64
+	mutex sync.Mutex
+}
+
+// newThresholdFilter creates a filter from thresholds in the format "serial.channel.datapoint=threshold", the datapoint may
+// also be given as serial/channel/datapoint
+func newThresholdFilter(specs []string) (*thresholdFilter, error) {
+	filter := &thresholdFilter{
+		thresholds: map[models.DatapointKey]float64{},
+		last:       map[models.DatapointKey]float64{},
+	}
+
+	for _, spec := range specs {
+		key, value, err := parseDatapointSpec(spec, "threshold", "serial.channel.datapoint=threshold")
+		if err != nil {
+			return nil, err
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || threshold < 0 || math.IsNaN(threshold) {
+			return nil, fmt.Errorf("invalid threshold %q: expected a non-negative number", spec)
+		}
+		filter.thresholds[key] = threshold
+	}
+
+	return filter, nil
+}
+
+// allow reports whether the update is emitted. The first numeric value of a datapoint is always emitted, further values
+// only if they differ from the last emitted value by more than the threshold.
+func (f *thresholdFilter) allow(update models.DatapointUpdate) bool {
+	key := models.DatapointKey{Serial: update.Serial, Channel: update.Channel, Datapoint: update.Datapoint}
+	threshold, exists := f.thresholds[key]
+	if !exists {
+		return true
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(update.Value), 64)
+	if err != nil {
+		return true
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if last, exists := f.last[key]; exists && math.Abs(value-last) <= threshold {
+		return false
+	}
+	f.last[key] = value
+	return true
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestThresholdFilter tests that only significant changes of numeric values are emitted
+func TestThresholdFilter(t *testing.T) {
+	filter, err := newThresholdFilter([]string{"ABB700000001.ch0000.odp0000=0.5"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var emitted []string
+	for _, value := range []string{"21.0", "21.2", "21.5", "21.6", "20.9", "20.5", "unavailable", "19.9", "20.2"} {
+		if filter.allow(models.DatapointUpdate{Serial: "ABB700000001", Channel: "ch0000", Datapoint: "odp0000", Value: value}) {
+			emitted = append(emitted, value)
+		}
+	}
+
+	// Changes are compared with the last emitted value, not the last received one
+	expected := []string{"21.0", "21.6", "20.9", "unavailable", "19.9"}
+	if !slices.Equal(emitted, expected) {
+		t.Errorf("Expected emitted values %v, got %v", expected, emitted)
+	}
+}
+
+// TestThresholdFilterOtherDatapoints tests that updates of datapoints without threshold always pass
+func TestThresholdFilterOtherDatapoints(t *testing.T) {
+	filter, err := newThresholdFilter([]string{"ABB700000001/ch0000/odp0000=1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for range 2 {
+		if !filter.allow(models.DatapointUpdate{Serial: "ABB700000001", Channel: "ch0000", Datapoint: "odp0001", Value: "1"}) {
+			t.Error("Expected the update of a datapoint without threshold to pass")
+		}
+	}
+}
+
+// TestNewThresholdFilterCaseInsensitive tests that thresholds apply to updates regardless of the case of the address
+func TestNewThresholdFilterCaseInsensitive(t *testing.T) {
+	filter, err := newThresholdFilter([]string{"abb700000001.CH0000.ODP0000=0.5"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	update := models.DatapointUpdate{Serial: "ABB700000001", Channel: "ch0000", Datapoint: "odp0000", Value: "20"}
+	if !filter.allow(update) {
+		t.Fatal("Expected the first value to pass")
+	}
+	update.Value = "20.3"
+	if filter.allow(update) {
+		t.Error("Expected a change within the threshold to be suppressed")
+	}
+}
+
+// TestNewThresholdFilterInvalid tests that invalid thresholds are rejected
+func TestNewThresholdFilterInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"ABB700000001.ch0000.odp0000",
+		"ABB700000001.ch0000=0.5",
+		"ABB700000001.ch0000.odp0000=abc",
+		"ABB700000001.ch0000.odp0000=-1",
+	} {
+		if _, err := newThresholdFilter([]string{spec}); err == nil {
+			t.Errorf("Expected an error for threshold %q", spec)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// updateTiming describes the timing of a datapoint update relative to the start of the monitor and the previous update of the datapoint
+type updateTiming struct {
+	// Elapsed is the time since the start of the monitor
+	Elapsed time.Duration
+	// Delta is the time since the previous update of the same datapoint, only set if the datapoint was updated before
+	Delta time.Duration
+	// Repeated indicates whether the datapoint was updated before
+	Repeated bool
+}
+
+// updateTimer computes the timing of datapoint updates based on their timestamps, which are taken from the clock of the system access point
+type updateTimer struct {
+	start    time.Time
+	lastSeen map[models.DatapointKey]time.Time
+	mutex    sync.Mutex
+}
+
+// newUpdateTimer creates an update timer measuring the elapsed time from the given start
+func newUpdateTimer(start time.Time) *updateTimer {
+	return &updateTimer{
+		start:    start,
+		lastSeen: map[models.DatapointKey]time.Time{},
+	}
+}
+
+// track computes the timing of the update and remembers it as the latest update of its datapoint
+func (t *updateTimer) track(update models.DatapointUpdate) updateTiming {
+	key := models.DatapointKey{Serial: update.Serial, Channel: update.Channel, Datapoint: update.Datapoint}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	timing := updateTiming{Elapsed: update.Timestamp.Sub(t.start)}
+	if previous, exists := t.lastSeen[key]; exists {
+		timing.Delta = update.Timestamp.Sub(previous)
+		timing.Repeated = true
+	}
+	t.lastSeen[key] = update.Timestamp
+	return timing
+}
+
+// annotate prefixes the formatted update with its timing, the delta is shown as - for the first update of a datapoint
+func (t *updateTimer) annotate(update models.DatapointUpdate, line string) string {
+	timing := t.track(update)
+	delta := "-"
+	if timing.Repeated {
+		delta = formatTimingDuration(timing.Delta)
+	}
+	return fmt.Sprintf("[+%s, delta %s] %s", formatTimingDuration(timing.Elapsed), delta, line)
+}
+
+// formatTimingDuration formats a duration rounded to milliseconds
+func formatTimingDuration(duration time.Duration) string {
+	return duration.Round(time.Millisecond).String()
+}
+
+// formatUpdate formats a datapoint update as a single line without device names
+func formatUpdate(update models.DatapointUpdate) string {
+	return fmt.Sprintf("%s %s.%s = %s", update.Serial, update.Channel, update.Datapoint, update.Value)
+}
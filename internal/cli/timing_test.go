@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestUpdateTimerTrack tests that the elapsed time and the delta per datapoint are computed across a sequence of updates
+func TestUpdateTimerTrack(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	timer := newUpdateTimer(start)
+
+	update := func(datapoint string, offset time.Duration) models.DatapointUpdate {
+		return models.DatapointUpdate{Timestamp: start.Add(offset), Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: datapoint, Value: "1"}
+	}
+
+	tests := []struct {
+		update   models.DatapointUpdate
+		expected updateTiming
+	}{
+		{update: update("odp0000", 2*time.Second), expected: updateTiming{Elapsed: 2 * time.Second}},
+		{update: update("odp0001", 3*time.Second), expected: updateTiming{Elapsed: 3 * time.Second}},
+		{update: update("odp0000", 7*time.Second), expected: updateTiming{Elapsed: 7 * time.Second, Delta: 5 * time.Second, Repeated: true}},
+		{update: update("odp0001", 7500*time.Millisecond), expected: updateTiming{Elapsed: 7500 * time.Millisecond, Delta: 4500 * time.Millisecond, Repeated: true}},
+		{update: update("odp0000", 7*time.Second), expected: updateTiming{Elapsed: 7 * time.Second, Delta: 0, Repeated: true}},
+	}
+
+	for i, tt := range tests {
+		if actual := timer.track(tt.update); actual != tt.expected {
+			t.Errorf("Update %d: expected timing %+v, got %+v", i, tt.expected, actual)
+		}
+	}
+}
+
+// TestUpdateTimerTrackDistinguishesDevices tests that the same datapoint of different devices is tracked separately
+func TestUpdateTimerTrackDistinguishesDevices(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	timer := newUpdateTimer(start)
+
+	timer.track(models.DatapointUpdate{Timestamp: start.Add(time.Second), Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"})
+	timing := timer.track(models.DatapointUpdate{Timestamp: start.Add(2 * time.Second), Serial: "ABB7013B85DE", Channel: "ch0000", Datapoint: "odp0000"})
+	if timing.Repeated {
+		t.Errorf("Expected the first update of the device not to be repeated, got %+v", timing)
+	}
+}
+
+// TestUpdateTimerAnnotate tests that the timing is prefixed to the formatted update
+func TestUpdateTimerAnnotate(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	timer := newUpdateTimer(start)
+
+	first := models.DatapointUpdate{Timestamp: start.Add(1500 * time.Millisecond), Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"}
+	second := first
+	second.Timestamp = start.Add(time.Minute + 2*time.Second + 250*time.Microsecond)
+	second.Value = "0"
+
+	expected := "[+1.5s, delta -] ABB7F595EC47 ch0000.odp0000 = 1"
+	if actual := timer.annotate(first, formatUpdate(first)); actual != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, actual)
+	}
+	expected = "[+1m2s, delta 1m0.5s] ABB7F595EC47 ch0000.odp0000 = 0"
+	if actual := timer.annotate(second, formatUpdate(second)); actual != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, actual)
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// renderTopologyDOT renders the floorplan and the device placement of the configuration as a Graphviz DOT graph.
+// Each system access point, floor and room is rendered as a cluster and each device as a node in the cluster of its room.
+// Devices without a known floor or room are placed in the innermost known cluster. The output is sorted for stable diffs.
+func renderTopologyDOT(configuration models.Configuration) string {
+	var builder strings.Builder
+	builder.WriteString("digraph topology {\n")
+	builder.WriteString("  node [shape=box];\n")
+
+	for _, sysApID := range slices.Sorted(maps.Keys(configuration)) {
+		sysAp := configuration[sysApID]
+
+		// Group the devices by floor and room
+		placement := map[string]map[string][]string{}
+		for _, serial := range slices.Sorted(maps.Keys(sysAp.Devices)) {
+			device := sysAp.Devices[serial]
+			floorID, roomID := "", ""
+			if device.Floor != nil {
+				if floor, exists := sysAp.Floorplan.Floors[*device.Floor]; exists {
+					floorID = *device.Floor
+					// Room identifiers are only unique within a floor
+					if device.Room != nil {
+						if _, exists := floor.Rooms[*device.Room]; exists {
+							roomID = *device.Room
+						}
+					}
+				}
+			}
+			if placement[floorID] == nil {
+				placement[floorID] = map[string][]string{}
+			}
+			placement[floorID][roomID] = append(placement[floorID][roomID], serial)
+		}
+
+		fmt.Fprintf(&builder, "  subgraph %s {\n", quoteDOT("cluster_"+sysApID))
+		fmt.Fprintf(&builder, "    label=%s;\n", quoteDOT(sysAp.SysApName))
+		writeDeviceNodes(&builder, "    ", sysApID, sysAp.Devices, placement[""][""])
+
+		for _, floorID := range slices.Sorted(maps.Keys(sysAp.Floorplan.Floors)) {
+			floor := sysAp.Floorplan.Floors[floorID]
+			fmt.Fprintf(&builder, "    subgraph %s {\n", quoteDOT("cluster_"+sysApID+"_"+floorID))
+			fmt.Fprintf(&builder, "      label=%s;\n", quoteDOT(floor.Name))
+			writeDeviceNodes(&builder, "      ", sysApID, sysAp.Devices, placement[floorID][""])
+
+			for _, roomID := range slices.Sorted(maps.Keys(floor.Rooms)) {
+				fmt.Fprintf(&builder, "      subgraph %s {\n", quoteDOT("cluster_"+sysApID+"_"+floorID+"_"+roomID))
+				fmt.Fprintf(&builder, "        label=%s;\n", quoteDOT(floor.Rooms[roomID].Name))
+				writeDeviceNodes(&builder, "        ", sysApID, sysAp.Devices, placement[floorID][roomID])
+				builder.WriteString("      }\n")
+			}
+			builder.WriteString("    }\n")
+		}
+		builder.WriteString("  }\n")
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// writeDeviceNodes writes a node for each of the given devices, labelled with the display name and serial
+func writeDeviceNodes(builder *strings.Builder, indent string, sysApID string, devices map[string]models.Device, serials []string) {
+	for _, serial := range serials {
+		label := serial
+		if device := devices[serial]; device.DisplayName != nil && *device.DisplayName != "" {
+			label = *device.DisplayName + "\n" + serial
+		}
+		fmt.Fprintf(builder, "%s%s [label=%s];\n", indent, quoteDOT(sysApID+"/"+serial), quoteDOT(label))
+	}
+}
+
+// quoteDOT quotes a string as a DOT identifier, escaping quotes, backslashes and line breaks
+func quoteDOT(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// loadConfigurationFixture loads the configuration fixture from the testdata directory
+func loadConfigurationFixture(t *testing.T) models.Configuration {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration.json"))
+	if err != nil {
+		t.Fatalf("Failed to read configuration fixture: %v", err)
+	}
+	var configuration models.Configuration
+	if err := json.Unmarshal(data, &configuration); err != nil {
+		t.Fatalf("Failed to parse configuration fixture: %v", err)
+	}
+	return configuration
+}
+
+// TestRenderTopologyDOT tests that the floors and rooms of the fixture are rendered as clusters and the devices as nodes
+func TestRenderTopologyDOT(t *testing.T) {
+	dot := renderTopologyDOT(loadConfigurationFixture(t))
+
+	if !strings.HasPrefix(dot, "digraph topology {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("Expected a digraph, got '%s'", dot)
+	}
+	for _, expected := range []string{
+		`subgraph "cluster_00000000-0000-0000-0000-000000000000" {`,
+		`label="Gerke";`,
+		`subgraph "cluster_00000000-0000-0000-0000-000000000000_02" {`,
+		`label="Erdgeschoss";`,
+		`subgraph "cluster_00000000-0000-0000-0000-000000000000_02_0D" {`,
+		`label="Wohnzimmer";`,
+		`"00000000-0000-0000-0000-000000000000/ABB7F595EC47" [label="Sensoreinheit 2-fach\nABB7F595EC47"];`,
+		`"00000000-0000-0000-0000-000000000000/ABB7013B85DE" [label="Dimming actuator 4gang\nABB7013B85DE"];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("Expected DOT output to contain '%s'", expected)
+		}
+	}
+
+	// Every device is rendered exactly once
+	if count := strings.Count(dot, "[label="); count != 76 {
+		t.Errorf("Expected 76 device nodes, got %d", count)
+	}
+
+	// The device is placed in the cluster of its room
+	room := dot[strings.Index(dot, `subgraph "cluster_00000000-0000-0000-0000-000000000000_02_0D" {`):]
+	room = room[:strings.Index(room, "}")]
+	if !strings.Contains(room, "ABB7F595EC47") {
+		t.Errorf("Expected device ABB7F595EC47 in the cluster of room 0D, got '%s'", room)
+	}
+}
+
+// TestRenderTopologyDOTPlacement tests the placement of devices with unknown floors or rooms and the quoting of labels
+func TestRenderTopologyDOTPlacement(t *testing.T) {
+	floor, room, unknown := "01", "01", "99"
+	name := `Lamp "Desk"`
+	configuration := models.Configuration{
+		models.EmptyUUID: models.SysAP{
+			SysApName: "Home",
+			Floorplan: models.Floorplan{Floors: models.Floors{
+				"01": {Name: "Ground", Rooms: models.Rooms{"01": {Name: "Kitchen"}}},
+			}},
+			Devices: map[string]models.Device{
+				"ABB700000001": {Floor: &floor, Room: &room, DisplayName: &name},
+				"ABB700000002": {Floor: &floor, Room: &unknown},
+				"ABB700000003": {},
+			},
+		},
+	}
+
+	expected := `digraph topology {
+  node [shape=box];
+  subgraph "cluster_00000000-0000-0000-0000-000000000000" {
+    label="Home";
+    "00000000-0000-0000-0000-000000000000/ABB700000003" [label="ABB700000003"];
+    subgraph "cluster_00000000-0000-0000-0000-000000000000_01" {
+      label="Ground";
+      "00000000-0000-0000-0000-000000000000/ABB700000002" [label="ABB700000002"];
+      subgraph "cluster_00000000-0000-0000-0000-000000000000_01_01" {
+        label="Kitchen";
+        "00000000-0000-0000-0000-000000000000/ABB700000001" [label="Lamp \"Desk\"\nABB700000001"];
+      }
+    }
+  }
+}
+`
+	if actual := renderTopologyDOT(configuration); actual != expected {
+		t.Errorf("Expected DOT output '%s', got '%s'", expected, actual)
+	}
+}
+
+// TestRenderTopologyDOTEmpty tests the rendering of an empty configuration
+func TestRenderTopologyDOTEmpty(t *testing.T) {
+	expected := "digraph topology {\n  node [shape=box];\n}\n"
+	if actual := renderTopologyDOT(models.Configuration{}); actual != expected {
+		t.Errorf("Expected DOT output '%s', got '%s'", expected, actual)
+	}
+}
@@ -0,0 +1,13 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// formatUptime formats the uptime percentage of the web socket connection with the connected and disconnected time
+func formatUptime(uptime freeathome.ConnectionUptime) string {
+	return fmt.Sprintf("Uptime: %.2f%% (connected %s, disconnected %s)", uptime.Percentage(), uptime.Connected.Round(time.Second), uptime.Disconnected.Round(time.Second))
+}
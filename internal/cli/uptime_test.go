@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+)
+
+// TestFormatUptime tests that the uptime percentage is printed with the connected and disconnected time
+func TestFormatUptime(t *testing.T) {
+	tests := []struct {
+		name     string
+		uptime   freeathome.ConnectionUptime
+		expected string
+	}{
+		{name: "Partial uptime", uptime: freeathome.ConnectionUptime{Connected: 80 * time.Second, Disconnected: 40 * time.Second}, expected: "Uptime: 66.67% (connected 1m20s, disconnected 40s)"},
+		{name: "Full uptime", uptime: freeathome.ConnectionUptime{Connected: time.Hour + 400*time.Millisecond}, expected: "Uptime: 100.00% (connected 1h0m0s, disconnected 0s)"},
+		{name: "No session", uptime: freeathome.ConnectionUptime{}, expected: "Uptime: 0.00% (connected 0s, disconnected 0s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := formatUptime(tt.uptime); actual != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"log/slog"
@@ -91,6 +93,56 @@ func (m *MockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	return m.Response, m.Err
 }
 
+// pathResponse is the response body returned for requests matching the route, i.e. the method and a fragment of the path,
+// e.g. "GET /devicelist"
+type pathResponse struct {
+	route string
+	body  string
+}
+
+// pathRoundTripper returns a fresh response for every request depending on the request method and path.
+// The responses are matched in order and the first matching route wins, so that overlapping fragments are deterministic.
+type pathRoundTripper struct {
+	mutex     sync.Mutex
+	responses []pathResponse
+	requests  []string
+}
+
+// RoundTrip executes a single HTTP transaction and returns the response.
+func (m *pathRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests = append(m.requests, req.Method+" "+req.URL.Path)
+
+	for _, response := range m.responses {
+		method, fragment, _ := strings.Cut(response.route, " ")
+		if req.Method == method && strings.Contains(req.URL.Path, fragment) {
+			return newTestResponse(http.StatusOK, response.body), nil
+		}
+	}
+	return newTestResponse(http.StatusNotFound, "Not Found"), nil
+}
+
+// setupPathMock overrides the setupFunc with a SystemAccessPoint using the path round tripper and counts the setup calls
+func setupPathMock(t *testing.T, transport *pathRoundTripper) *int {
+	t.Helper()
+
+	config := freeathome.NewConfig("test-host", "test-user", "test-pass")
+	config.Logger = freeathome.NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+	config.Client = resty.New().SetTransport(transport)
+	sysAp := freeathome.MustNewSystemAccessPoint(config)
+
+	setupCalls := 0
+	setupFunc = func(config CommandConfig, configFile string) (*freeathome.SystemAccessPoint, error) {
+		setupCalls++
+		return sysAp, nil
+	}
+	t.Cleanup(func() {
+		setupFunc = setup
+	})
+	return &setupCalls
+}
+
 // createTestConfigFile creates a test config file
 func createTestConfigFile(t *testing.T, configData string) string {
 	t.Helper()
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// batchValidationResult contains a datapoint address of a batch file and the reason it is unknown, empty if it is valid
+type batchValidationResult struct {
+	Address string
+	Problem string
+}
+
+// ValidateBatch checks that the datapoint addresses listed in a YAML or JSON file exist in the configuration of the system
+// access point, without reading or writing any datapoint. It returns an error after the output if any address is unknown.
+func ValidateBatch(config CommandConfig, file string) error {
+	addresses, err := loadBatchAddresses(file)
+	if err != nil {
+		return err
+	}
+
+	// Setup system access point
+	sysAp, err := setupFunc(config, "")
+	if err != nil {
+		return err
+	}
+
+	// Fetch the configuration once for all addresses
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return handleSysApError(err, "get configuration", config.TLSEnabled, config.SkipTLSVerify)
+	}
+	if configuration == nil {
+		configuration = &models.Configuration{}
+	}
+	sysApConfig, err := sysApConfiguration(*configuration, sysAp.GetUUID())
+	if err != nil {
+		return err
+	}
+
+	results := validateBatch(sysApConfig, addresses)
+	printValidationResults(results)
+
+	unknown := 0
+	for _, result := range results {
+		if result.Problem != "" {
+			unknown++
+		}
+	}
+	if unknown > 0 {
+		return fmt.Errorf("%d of %d datapoint addresses are unknown", unknown, len(results))
+	}
+	return nil
+}
+
+// validateBatch checks each address against the configuration of the system access point. The results are in the order of
+// the addresses.
+func validateBatch(sysApConfig models.SysAP, addresses []string) []batchValidationResult {
+	// Look up the datapoints only in the configuration of the selected system access point
+	configuration := models.Configuration{models.EmptyUUID: sysApConfig}
+
+	results := make([]batchValidationResult, len(addresses))
+	for i, address := range addresses {
+		results[i].Address = address
+		key, err := models.ParseDatapointKey(address)
+		if err != nil {
+			results[i].Problem = err.Error()
+			continue
+		}
+		if _, exists := configuration.DatapointMetadata(key); exists {
+			continue
+		}
+
+		// Name the first part of the address that does not exist
+		device, exists := sysApConfig.Devices[key.Serial]
+		switch {
+		case !exists:
+			results[i].Problem = fmt.Sprintf("unknown device %s", key.Serial)
+		case device.Channels == nil || (*device.Channels)[key.Channel] == nil:
+			results[i].Problem = fmt.Sprintf("unknown channel %s of device %s", key.Channel, key.Serial)
+		default:
+			results[i].Problem = fmt.Sprintf("unknown datapoint %s of channel %s", key.Datapoint, key.Channel)
+		}
+	}
+	return results
+}
+
+// printValidationResults prints the validation results as a table of addresses and their status
+func printValidationResults(results []batchValidationResult) {
+	width := len("ADDRESS")
+	for _, result := range results {
+		width = max(width, len(result.Address))
+	}
+
+	fmt.Printf("%-*s  %s\n", width, "ADDRESS", "STATUS")
+	for _, result := range results {
+		status := "ok"
+		if result.Problem != "" {
+			status = result.Problem
+		}
+		fmt.Printf("%-*s  %s\n", width, result.Address, status)
+	}
+}
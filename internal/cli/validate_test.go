@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidateBatch tests that valid addresses pass and unknown addresses are flagged without reading or writing datapoints
+func TestValidateBatch(t *testing.T) {
+	configuration, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration.json"))
+	if err != nil {
+		t.Fatalf("Failed to read configuration fixture: %v", err)
+	}
+	transport := &pathRoundTripper{responses: []pathResponse{{"GET /configuration", string(configuration)}}}
+	setupPathMock(t, transport)
+
+	output := captureStdout(t, func() {
+		err = ValidateBatch(CommandConfig{}, filepath.Join("..", "..", "testdata", "batch_validate.yaml"))
+	})
+	if err == nil || err.Error() != "4 of 6 datapoint addresses are unknown" {
+		t.Errorf("Expected error for 4 unknown addresses, got %v", err)
+	}
+
+	// Only the configuration is fetched
+	if len(transport.requests) != 1 || transport.requests[0] != "GET /fhapi/v1/api/rest/configuration" {
+		t.Errorf("Expected only the configuration to be requested, got %v", transport.requests)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	expectedPrefixes := []string{
+		"ADDRESS                      STATUS",
+		"ABB7F595EC47/ch0000/idp0000  ok",
+		"ABB7F595EC47/ch0000/odp0001  ok",
+		"ABB7F595EC47/ch0000/odp0099  unknown datapoint odp0099 of channel ch0000",
+		"ABB7F595EC47/ch0001/odp0000  unknown channel ch0001 of device ABB7F595EC47",
+		"ABB7FFFFFFFF/ch0000/odp0000  unknown device ABB7FFFFFFFF",
+		"invalid-address              invalid datapoint key",
+	}
+	if len(lines) != len(expectedPrefixes) {
+		t.Fatalf("Expected a header and 6 results, got:\n%s", output)
+	}
+	for i, expected := range expectedPrefixes {
+		if !strings.HasPrefix(lines[i], expected) {
+			t.Errorf("Expected line %d to start with '%s', got '%s'", i, expected, lines[i])
+		}
+	}
+}
+
+// TestValidateBatchAllValid tests that no error is returned if all addresses exist
+func TestValidateBatchAllValid(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{
+		{"GET /configuration", `{"00000000-0000-0000-0000-000000000000":{"devices":{"ABB7F595EC47":{"channels":{"ch0000":{"outputs":{"odp0000":{"pairingID":1}}}}}}}}`},
+	}})
+	file := filepath.Join(t.TempDir(), "writes.yaml")
+	if err := os.WriteFile(file, []byte("- ABB7F595EC47/ch0000/odp0000\n"), 0644); err != nil {
+		t.Fatalf("Failed to create batch file: %v", err)
+	}
+
+	var err error
+	output := captureStdout(t, func() {
+		err = ValidateBatch(CommandConfig{}, file)
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "ABB7F595EC47/ch0000/odp0000  ok") {
+		t.Errorf("Expected the address to be valid, got:\n%s", output)
+	}
+}
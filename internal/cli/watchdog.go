@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/freeathome"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+const (
+	// watchdogKeepaliveInterval is the keepalive interval of the web socket connection of the watchdog
+	watchdogKeepaliveInterval = 30 * time.Second
+	// watchdogMaxReconnectionAttempts is the maximum number of reconnection attempts of the watchdog
+	watchdogMaxReconnectionAttempts = 3
+)
+
+// WatchdogCommandConfig is a struct that contains the configuration for the watchdog command
+type WatchdogCommandConfig struct {
+	CommandConfig
+	Enforce bool
+}
+
+// watchdog checks the values of datapoints against their expected values
+type watchdog struct {
+	// expected contains the expected value per watched datapoint
+	expected map[models.DatapointKey]string
+	// keys contains the watched datapoints in the order they were given
+	keys []models.DatapointKey
+	// correct writes the expected value of a deviating datapoint, nil if deviations are not corrected
+	correct func(key models.DatapointKey, value string) error
+}
+
+// newWatchdog creates a watchdog from expectations in the format "serial.channel.datapoint=expected", the datapoint may
+// also be given as serial/channel/datapoint
+func newWatchdog(specs []string) (*watchdog, error) {
+	w := &watchdog{expected: map[models.DatapointKey]string{}}
+	for _, spec := range specs {
+		key, value, err := parseDatapointSpec(spec, "expectation", "serial.channel.datapoint=expected")
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := w.expected[key]; !exists {
+			w.keys = append(w.keys, key)
+		}
+		w.expected[key] = value
+	}
+	return w, nil
+}
+
+// check returns an error if the value of a watched datapoint deviates from its expected value and is not corrected.
+// Values of other datapoints are ignored.
+func (w *watchdog) check(key models.DatapointKey, value string) error {
+	expected, exists := w.expected[key]
+	if !exists || value == expected {
+		return nil
+	}
+	if w.correct == nil {
+		return fmt.Errorf("datapoint %s left its expected value %q, got %q", key, expected, value)
+	}
+
+	fmt.Printf("Datapoint %s deviated from %q to %q, restoring the expected value\n", key, expected, value)
+	if err := w.correct(key, expected); err != nil {
+		return fmt.Errorf("failed to restore datapoint %s to %q: %w", key, expected, err)
+	}
+	return nil
+}
+
+// checkCurrent reads the current values of the watched datapoints and checks them. Datapoints that cannot be read are
+// reported and checked once an update arrives.
+func (w *watchdog) checkCurrent(sysAp *freeathome.SystemAccessPoint) error {
+	addresses := make([]string, len(w.keys))
+	for i, key := range w.keys {
+		addresses[i] = key.String()
+	}
+	for i, result := range readBatch(sysAp, addresses) {
+		if result.Error != "" || len(result.Values) == 0 {
+			fmt.Fprintf(os.Stderr, "Failed to read the current value of %s: %s\n", result.Address, result.Error)
+			continue
+		}
+		if err := w.check(w.keys[i], result.Values[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watch checks the datapoint updates of the system access point and cancels the context with the error of the first
+// deviation that is not corrected
+func (w *watchdog) watch(sysAp *freeathome.SystemAccessPoint, cancel context.CancelCauseFunc) {
+	sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		key := models.DatapointKey{Serial: update.Serial, Channel: update.Channel, Datapoint: update.Datapoint}
+		if err := w.check(key, update.Value); err != nil {
+			cancel(err)
+		}
+	})
+}
+
+// Watchdog watches datapoints via WebSocket and returns an error as soon as one of them leaves its expected value. If
+// enforcing is enabled, a deviating datapoint is set to its expected value instead and an error is only returned if
+// that fails.
+func Watchdog(config WatchdogCommandConfig, specs []string) error {
+	w, err := newWatchdog(specs)
+	if err != nil {
+		return err
+	}
+
+	// Setup system access point
+	sysAp, err := setupFunc(config.CommandConfig, "")
+	if err != nil {
+		return err
+	}
+	if config.Enforce {
+		w.correct = func(key models.DatapointKey, value string) error {
+			_, err := sysAp.SetDatapoint(key.Serial, key.Channel, key.Datapoint, value)
+			return err
+		}
+	}
+
+	// Check the current values before watching the updates
+	if err := w.checkCurrent(sysAp); err != nil {
+		return err
+	}
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithCancelCause(signalCtx)
+	defer cancel(nil)
+
+	w.watch(sysAp, cancel)
+	fmt.Printf("Watching %d datapoints, press Ctrl+C to exit\n", len(w.keys))
+
+	err = sysAp.ConnectWebSocket(ctx, watchdogMaxReconnectionAttempts, true, watchdogKeepaliveInterval)
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	if err != nil && err != context.Canceled {
+		return err
+	}
+	return nil
+}
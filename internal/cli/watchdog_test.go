@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// watchdogMessage returns a web socket message updating the datapoint to the value
+func watchdogMessage(address string, value string) []byte {
+	return []byte(`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"` + address + `":"` + value + `"}}}`)
+}
+
+func TestNewWatchdog(t *testing.T) {
+	w, err := newWatchdog([]string{"ABB7F595EC47.ch0000.odp0000=1", "ABB700000001/ch0001/odp0002=on", "ABB7F595EC47.ch0000.odp0000=0"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[models.DatapointKey]string{
+		{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}: "0",
+		{Serial: "ABB700000001", Channel: "ch0001", Datapoint: "odp0002"}: "on",
+	}
+	if len(w.expected) != len(expected) || len(w.keys) != len(expected) {
+		t.Fatalf("Expected %d watched datapoints, got %v", len(expected), w.expected)
+	}
+	for key, value := range expected {
+		if w.expected[key] != value {
+			t.Errorf("Expected %s to be %q, got %q", key, value, w.expected[key])
+		}
+	}
+
+	// Addresses are matched case-insensitively like the received updates
+	w, err = newWatchdog([]string{"abb7f595ec47.CH0000.ODP0000=1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key := (models.DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}); w.expected[key] != "1" || !slices.Equal(w.keys, []models.DatapointKey{key}) {
+		t.Errorf("Expected the normalized key %s, got %v", key, w.expected)
+	}
+
+	for _, spec := range []string{"ABB7F595EC47.ch0000.odp0000", "invalid=1", "ABB7F595EC47.ch0000=1"} {
+		if _, err := newWatchdog([]string{spec}); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+// TestWatchdogDeviation tests that the watchdog ignores matching values and other datapoints and stops at a deviation
+func TestWatchdogDeviation(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{})
+	sysAp, _ := setupFunc(CommandConfig{}, "")
+	w, err := newWatchdog([]string{"ABB7F595EC47.ch0000.odp0000=1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(t.Context())
+	defer cancel(nil)
+	w.watch(sysAp, cancel)
+
+	sysAp.ProcessMessage(watchdogMessage("ABB7F595EC47/ch0000/odp0000", "1"))
+	sysAp.ProcessMessage(watchdogMessage("ABB7F595EC47/ch0000/odp0001", "0"))
+	if err := context.Cause(ctx); err != nil {
+		t.Fatalf("Expected no deviation, got: %v", err)
+	}
+
+	sysAp.ProcessMessage(watchdogMessage("ABB7F595EC47/ch0000/odp0000", "0"))
+	err = context.Cause(ctx)
+	if err == nil || !strings.Contains(err.Error(), `datapoint ABB7F595EC47/ch0000/odp0000 left its expected value "1", got "0"`) {
+		t.Errorf("Expected a deviation error, got: %v", err)
+	}
+}
+
+// TestWatchdogEnforce tests that a deviation is corrected by writing the expected value instead of stopping the watchdog
+func TestWatchdogEnforce(t *testing.T) {
+	transport := &pathRoundTripper{responses: []pathResponse{
+		{"PUT /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"result":"OK"}}`},
+	}}
+	setupPathMock(t, transport)
+	sysAp, _ := setupFunc(CommandConfig{}, "")
+	w, err := newWatchdog([]string{"ABB7F595EC47.ch0000.idp0000=1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	w.correct = func(key models.DatapointKey, value string) error {
+		_, err := sysAp.SetDatapoint(key.Serial, key.Channel, key.Datapoint, value)
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(t.Context())
+	defer cancel(nil)
+	w.watch(sysAp, cancel)
+
+	output := captureStdout(t, func() {
+		sysAp.ProcessMessage(watchdogMessage("ABB7F595EC47/ch0000/idp0000", "0"))
+	})
+	if err := context.Cause(ctx); err != nil {
+		t.Fatalf("Expected the deviation to be corrected, got: %v", err)
+	}
+	if !strings.Contains(output, "restoring the expected value") {
+		t.Errorf("Expected the correction to be reported, got: %s", output)
+	}
+	if !slices.ContainsFunc(transport.requests, func(request string) bool {
+		return strings.HasPrefix(request, "PUT ") && strings.HasSuffix(request, "/ABB7F595EC47.ch0000.idp0000")
+	}) {
+		t.Errorf("Expected a corrective write, got requests %v", transport.requests)
+	}
+
+	// A failed correction stops the watchdog
+	transport.mutex.Lock()
+	transport.responses = nil
+	transport.mutex.Unlock()
+	_ = captureStdout(t, func() {
+		sysAp.ProcessMessage(watchdogMessage("ABB7F595EC47/ch0000/idp0000", "0"))
+	})
+	if err := context.Cause(ctx); err == nil || !strings.Contains(err.Error(), "failed to restore datapoint") {
+		t.Errorf("Expected a failed correction error, got: %v", err)
+	}
+}
+
+// TestWatchdogCheckCurrent tests that a deviating current value is detected before watching the updates
+func TestWatchdogCheckCurrent(t *testing.T) {
+	setupPathMock(t, &pathRoundTripper{responses: []pathResponse{
+		{"GET /datapoint/", `{"00000000-0000-0000-0000-000000000000":{"values":["0"]}}`},
+	}})
+
+	err := Watchdog(WatchdogCommandConfig{}, []string{"ABB7F595EC47.ch0000.odp0000=1"})
+	if err == nil || !strings.Contains(err.Error(), "left its expected value") {
+		t.Errorf("Expected a deviation error, got: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package freeathome
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// auditLogEntry represents a single write operation recorded in the audit log
+type auditLogEntry struct {
+	// Timestamp is the time at which the write operation completed
+	Timestamp time.Time `json:"timestamp"`
+	// Operation is the name of the write operation
+	Operation string `json:"operation"`
+	// Target identifies the datapoint or proxy device that was written
+	Target string `json:"target"`
+	// Value is the value that was written
+	Value string `json:"value"`
+	// Result is the response returned by the system access point, if the write succeeded
+	Result any `json:"result,omitempty"`
+	// Error is the error message, if the write failed
+	Error string `json:"error,omitempty"`
+}
+
+// writeAuditLog writes a JSON line describing a write operation to the configured audit log writer.
+// Nothing is written if no audit log writer is configured.
+func (sysAp *SystemAccessPoint) writeAuditLog(operation string, target string, value string, result any, err error) {
+	if sysAp.config.WriteAuditLog == nil {
+		return
+	}
+
+	entry := auditLogEntry{
+		Timestamp: sysAp.clock.Now().UTC(),
+		Operation: operation,
+		Target:    target,
+		Value:     value,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Result = result
+	}
+
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		sysAp.config.Logger.Error("failed to marshal audit log entry", "error", marshalErr)
+		return
+	}
+	line = append(line, '\n')
+
+	// Serialize writes so that concurrent operations do not interleave lines
+	sysAp.auditMutex.Lock()
+	defer sysAp.auditMutex.Unlock()
+	if _, writeErr := sysAp.config.WriteAuditLog.Write(line); writeErr != nil {
+		sysAp.config.Logger.Error("failed to write audit log entry", "error", writeErr)
+	}
+}
@@ -0,0 +1,59 @@
+package freeathome
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// reconnectionBackoffBase is the delay before the second web socket connection attempt
+	reconnectionBackoffBase = 2 * time.Second
+	// reconnectionBackoffMax is the maximum delay between two web socket connection attempts
+	reconnectionBackoffMax = 30 * time.Second
+)
+
+// Backoff computes exponentially growing delays between attempts of an operation. The first delay is the base delay,
+// every further delay doubles the previous one up to the maximum delay. A Backoff is not safe for concurrent use.
+type Backoff struct {
+	// Base is the first delay
+	Base time.Duration
+	// Max caps the delays
+	Max time.Duration
+	// Jitter is the fraction of each delay that is randomized, between 0 and 1. A delay d is reduced by a random
+	// duration of up to Jitter*d, so clients that failed at the same time do not retry at the same time.
+	Jitter float64
+	// attempt is the number of delays returned since the last reset
+	attempt int
+	// random returns a random number in [0, 1), it can be replaced in tests
+	random func() float64
+}
+
+// NewBackoff creates a backoff with the given base and maximum delay and jitter fraction.
+func NewBackoff(base, max time.Duration, jitter float64) *Backoff {
+	return &Backoff{Base: base, Max: max, Jitter: jitter, random: rand.Float64}
+}
+
+// Next returns the delay before the next attempt and advances the attempt.
+func (b *Backoff) Next() time.Duration {
+	delay := b.Base
+	for i := 0; i < b.attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	delay = min(delay, b.Max)
+	b.attempt++
+
+	if b.Jitter > 0 && b.random != nil {
+		delay -= time.Duration(float64(delay) * min(b.Jitter, 1) * b.random())
+	}
+	return delay
+}
+
+// Reset starts the sequence of delays from the base delay again, e.g. after the operation succeeded.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Attempt returns the number of delays returned since the last reset.
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
@@ -0,0 +1,77 @@
+package freeathome
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBackoffSequence tests that the delays double from the base delay up to the maximum delay.
+func TestBackoffSequence(t *testing.T) {
+	backoff := NewBackoff(100*time.Millisecond, time.Second, 0)
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, want := range expected {
+		if got := backoff.Next(); got != want {
+			t.Errorf("Expected delay %v for attempt %d, got %v", want, i+1, got)
+		}
+	}
+	if backoff.Attempt() != len(expected) {
+		t.Errorf("Expected attempt %d, got %d", len(expected), backoff.Attempt())
+	}
+}
+
+// TestBackoffCap tests that the delay does not overflow after many attempts.
+func TestBackoffCap(t *testing.T) {
+	backoff := NewBackoff(time.Second, 30*time.Second, 0)
+	for range 100 {
+		backoff.Next()
+	}
+	if got := backoff.Next(); got != 30*time.Second {
+		t.Errorf("Expected the delay to be capped at 30s, got %v", got)
+	}
+}
+
+// TestBackoffReset tests that a reset starts the sequence from the base delay again.
+func TestBackoffReset(t *testing.T) {
+	backoff := NewBackoff(time.Second, time.Minute, 0)
+	backoff.Next()
+	backoff.Next()
+
+	backoff.Reset()
+	if backoff.Attempt() != 0 {
+		t.Errorf("Expected attempt 0 after reset, got %d", backoff.Attempt())
+	}
+	if got := backoff.Next(); got != time.Second {
+		t.Errorf("Expected the base delay after reset, got %v", got)
+	}
+}
+
+// TestBackoffJitter tests that the jitter reduces the delay by up to the jitter fraction.
+func TestBackoffJitter(t *testing.T) {
+	backoff := NewBackoff(time.Second, time.Minute, 0.5)
+
+	backoff.random = func() float64 { return 0 }
+	if got := backoff.Next(); got != time.Second {
+		t.Errorf("Expected no reduction for a random value of 0, got %v", got)
+	}
+	backoff.random = func() float64 { return 0.5 }
+	if got := backoff.Next(); got != 1500*time.Millisecond {
+		t.Errorf("Expected the 2s delay to be reduced by 25%%, got %v", got)
+	}
+
+	// Delays with real random values stay within the jitter range
+	backoff = NewBackoff(time.Second, time.Minute, 0.2)
+	for range 100 {
+		if got := backoff.Next(); got < 800*time.Millisecond || got > time.Second {
+			t.Fatalf("Expected the delay to be between 800ms and 1s, got %v", got)
+		}
+		backoff.Reset()
+	}
+}
@@ -0,0 +1,76 @@
+package freeathome
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// ClockSkew describes the difference between the clock of the system access point and the local clock.
+type ClockSkew struct {
+	// SysApTime is the time of the system access point, taken from the Date header of a response with a resolution of one second
+	SysApTime time.Time
+	// LocalTime is the local time in the middle of the request, compensating for the round trip time
+	LocalTime time.Time
+	// Skew is the time the clock of the system access point is ahead of the local clock, negative if it is behind
+	Skew time.Duration
+}
+
+// Exceeds reports whether the absolute skew is greater than the threshold.
+func (s ClockSkew) Exceeds(threshold time.Duration) bool {
+	return s.Skew > threshold || s.Skew < -threshold
+}
+
+// newClockSkew computes the skew between the time of the system access point and the local time in the middle of the
+// request that was sent and received at the given local times.
+func newClockSkew(sysApTime, sent, received time.Time) ClockSkew {
+	local := sent.Add(received.Sub(sent) / 2)
+	return ClockSkew{
+		SysApTime: sysApTime,
+		LocalTime: local,
+		Skew:      sysApTime.Sub(local),
+	}
+}
+
+// GetSysApTime retrieves the current time of the system access point from the Date header of the device list response.
+// The Date header has a resolution of one second.
+func (sysAp *SystemAccessPoint) GetSysApTime() (time.Time, error) {
+	skew, err := sysAp.GetClockSkew()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return skew.SysApTime, nil
+}
+
+// GetClockSkew compares the time of the system access point to the local clock. Time-sensitive features like timestamps
+// and history can be confused if the clocks differ, which can be checked with Exceeds. The request is counted as a device
+// list call in the statistics.
+func (sysAp *SystemAccessPoint) GetClockSkew() (skew ClockSkew, err error) {
+	const errorMessage = "failed to get system access point time"
+
+	span := sysAp.startSpan("GetClockSkew")
+	sent := sysAp.clock.Now()
+	resp, err := sysAp.request(nil).Get(sysAp.GetUrl("devicelist"))
+	received := sysAp.clock.Now()
+	defer func() {
+		endRestSpan(span, resp, err)
+		sysAp.stats.record(models.OperationDeviceList, err)
+	}()
+
+	if err := checkRestResponse(sysAp, resp, err, errorMessage); err != nil {
+		return ClockSkew{}, err
+	}
+
+	date := resp.Header().Get("Date")
+	if date == "" {
+		return ClockSkew{}, fmt.Errorf("%s: response contains no Date header", errorMessage)
+	}
+	sysApTime, err := http.ParseTime(date)
+	if err != nil {
+		return ClockSkew{}, fmt.Errorf("%s: invalid Date header %q: %w", errorMessage, date, err)
+	}
+
+	return newClockSkew(sysApTime, sent, received), nil
+}
@@ -0,0 +1,131 @@
+package freeathome
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestNewClockSkew tests the skew computation, which compensates for the round trip time of the request.
+func TestNewClockSkew(t *testing.T) {
+	local := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		sysApTime time.Time
+		sent      time.Time
+		received  time.Time
+		expected  time.Duration
+	}{
+		{name: "In sync", sysApTime: local, sent: local, received: local, expected: 0},
+		{name: "Ahead", sysApTime: local.Add(90 * time.Second), sent: local, received: local, expected: 90 * time.Second},
+		{name: "Behind", sysApTime: local.Add(-2 * time.Minute), sent: local, received: local, expected: -2 * time.Minute},
+		{name: "Round trip", sysApTime: local.Add(time.Second), sent: local, received: local.Add(2 * time.Second), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skew := newClockSkew(tt.sysApTime, tt.sent, tt.received)
+			if skew.Skew != tt.expected {
+				t.Errorf("Expected skew %v, got %v", tt.expected, skew.Skew)
+			}
+			if !skew.SysApTime.Equal(tt.sysApTime) {
+				t.Errorf("Expected system access point time %v, got %v", tt.sysApTime, skew.SysApTime)
+			}
+		})
+	}
+}
+
+// TestClockSkewExceeds tests the threshold behavior for clocks ahead and behind.
+func TestClockSkewExceeds(t *testing.T) {
+	tests := []struct {
+		skew     time.Duration
+		expected bool
+	}{
+		{skew: 0, expected: false},
+		{skew: 30 * time.Second, expected: false},
+		{skew: -30 * time.Second, expected: false},
+		{skew: 31 * time.Second, expected: true},
+		{skew: -31 * time.Second, expected: true},
+	}
+
+	for _, tt := range tests {
+		if exceeds := (ClockSkew{Skew: tt.skew}).Exceeds(30 * time.Second); exceeds != tt.expected {
+			t.Errorf("Expected Exceeds for skew %v to be %t, got %t", tt.skew, tt.expected, exceeds)
+		}
+	}
+}
+
+// TestSystemAccessPointGetClockSkew tests that the skew is computed from the Date header against the injected clock.
+func TestSystemAccessPointGetClockSkew(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	sysAp.clock = clock
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	setHeaderTestResponse(roundtripper)
+	roundtripper.Response.Header.Set("Date", clock.now.Add(-45*time.Second).Format(http.TimeFormat))
+
+	skew, err := sysAp.GetClockSkew()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if skew.Skew != -45*time.Second {
+		t.Errorf("Expected skew -45s, got %v", skew.Skew)
+	}
+	if !skew.LocalTime.Equal(clock.now) {
+		t.Errorf("Expected local time %v, got %v", clock.now, skew.LocalTime)
+	}
+	if !skew.Exceeds(30 * time.Second) {
+		t.Error("Expected skew to exceed 30s")
+	}
+	if roundtripper.Request.URL.Path != "/fhapi/v1/api/rest/devicelist" {
+		t.Errorf("Expected request to the device list, got %s", roundtripper.Request.URL.Path)
+	}
+
+	sysApTime, err := sysAp.GetSysApTime()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := clock.now.Add(-45 * time.Second); !sysApTime.Equal(expected) {
+		t.Errorf("Expected system access point time %v, got %v", expected, sysApTime)
+	}
+	if stats := sysAp.Stats()[models.OperationDeviceList]; stats.Successes != 2 || stats.Failures != 0 {
+		t.Errorf("Expected 2 successful device list calls, got %+v", stats)
+	}
+}
+
+// TestSystemAccessPointGetClockSkewErrors tests that failed requests and missing or invalid Date headers are reported and counted.
+func TestSystemAccessPointGetClockSkewErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      *http.Response
+		err           error
+		errorContains string
+	}{
+		{name: "Request error", err: errors.New("connection refused"), errorContains: "connection refused"},
+		{name: "Status error", response: &http.Response{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized", Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, errorContains: "failed to get system access point time"},
+		{name: "Missing Date header", response: &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Header: make(http.Header)}, errorContains: "response contains no Date header"},
+		{name: "Invalid Date header", response: &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{"Date": []string{"yesterday"}}}, errorContains: `invalid Date header "yesterday"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sysAp, _, _ := setupSysAp(t, true, false)
+			sysAp.config.Client.SetTransport(&MockRoundTripper{Response: tt.response, Err: tt.err})
+
+			_, err := sysAp.GetClockSkew()
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf(expectedErrorGotValue, tt.errorContains, err)
+			}
+			if stats := sysAp.Stats()[models.OperationDeviceList]; stats.Successes != 0 || stats.Failures != 1 {
+				t.Errorf("Expected 1 failed device list call, got %+v", stats)
+			}
+		})
+	}
+}
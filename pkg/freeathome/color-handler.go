@@ -9,17 +9,38 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
+	"golang.org/x/term"
 )
 
+// wrapIndent is the indentation of continuation lines of wrapped log lines
+const wrapIndent = "    "
+
+// minWrapWidth is the smallest width log lines are wrapped to, narrower widths disable wrapping
+const minWrapWidth = 20
+
 // ColorHandler wraps a slog.Handler and colors the level based on severity
 type ColorHandler struct {
 	out  io.Writer
 	opts *slog.HandlerOptions
 	base slog.Handler
+	// width returns the width log lines are wrapped to, nil or a width of 0 disables wrapping
+	width func() int
+	// now returns the timestamp of the log lines, nil uses the time of the record
+	now func() time.Time
 }
 
+// logField is a part of a log line that is colored as a whole
+type logField struct {
+	text     string
+	colorize func(a ...any) string
+}
+
+// logSegment is a part of a log line that is kept on a single line when wrapping, e.g. a single attribute
+type logSegment []logField
+
 // NewColorHandler creates a colorized slog handler
 func NewColorHandler(out io.Writer, opts *slog.HandlerOptions) *ColorHandler {
 	if opts == nil {
@@ -33,6 +54,37 @@ func NewColorHandler(out io.Writer, opts *slog.HandlerOptions) *ColorHandler {
 	}
 }
 
+// WithLineWrapping returns a copy of the handler that wraps log lines to the width of the terminal with the given file descriptor.
+// The width is queried for every record, so resizing the terminal is respected. Segments that do not fit into a line on their own
+// are continued on the next lines. If the width cannot be determined, e.g. because the output is not a terminal, lines are not wrapped.
+func (h *ColorHandler) WithLineWrapping(fd int) *ColorHandler {
+	return &ColorHandler{
+		out:  h.out,
+		opts: h.opts,
+		base: h.base,
+		width: func() int {
+			width, _, err := term.GetSize(fd)
+			if err != nil {
+				return 0
+			}
+			return width
+		},
+		now: h.now,
+	}
+}
+
+// WithTimeFunc returns a copy of the handler that takes the timestamp of the log lines from the given function instead of
+// the time of the record, e.g. for deterministic output in tests or to synchronize with another time source.
+func (h *ColorHandler) WithTimeFunc(now func() time.Time) *ColorHandler {
+	return &ColorHandler{
+		out:   h.out,
+		opts:  h.opts,
+		base:  h.base,
+		width: h.width,
+		now:   now,
+	}
+}
+
 // Enabled checks if the handler is enabled for the given level
 func (h *ColorHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return h.base.Enabled(ctx, level)
@@ -41,54 +93,132 @@ func (h *ColorHandler) Enabled(ctx context.Context, level slog.Level) bool {
 // Handle formats the log message with colors and prints it to the console
 func (h *ColorHandler) Handle(ctx context.Context, r slog.Record) error {
 	// Timestamp in gray
-	timestampColor := color.New(color.FgWhite).SprintFunc()
-	timestamp := timestampColor(fmt.Sprintf("time=%s", r.Time.Format(time.RFC3339)))
+	timestamp := r.Time
+	if h.now != nil {
+		timestamp = h.now()
+	}
+	segments := []logSegment{
+		{{text: fmt.Sprintf("time=%s", timestamp.Format(time.RFC3339)), colorize: color.New(color.FgWhite).SprintFunc()}},
+	}
 
 	// Level in color
-	levelColor := levelColor(r.Level)
-	levelText := levelColor(fmt.Sprintf("level=%s", r.Level.String()))
-
-	// Message in cyan
-	msgColor := color.New(color.FgHiWhite).SprintFunc()
-	message := msgColor(fmt.Sprintf("msg=%s", logfmtEscape(fmt.Sprint(r.Message))))
+	segments = append(segments, logSegment{{text: fmt.Sprintf("level=%s", r.Level.String()), colorize: levelColor(r.Level)}})
 
 	// Source in magenta
-	sourceColor := color.New(color.FgMagenta).SprintFunc()
-	sourceText := ""
 	if h.opts.AddSource && r.PC != 0 {
 		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
-		sourceText = sourceColor(fmt.Sprintf(" source=%s:%d", frame.File, frame.Line))
+		segments = append(segments, logSegment{{text: fmt.Sprintf("source=%s:%d", frame.File, frame.Line), colorize: color.New(color.FgMagenta).SprintFunc()}})
 	}
 
+	// Message in cyan
+	segments = append(segments, logSegment{{text: fmt.Sprintf("msg=%s", logfmtEscape(fmt.Sprint(r.Message))), colorize: color.New(color.FgHiWhite).SprintFunc()}})
+
 	// Attributes in green and yellow
-	attrText := ""
 	r.Attrs(func(a slog.Attr) bool {
-		keyColor := color.New(color.FgCyan).SprintFunc()
-		valColor := color.New(color.FgHiGreen).SprintFunc()
-		attrText += fmt.Sprintf(" %s=%s", keyColor(a.Key), valColor(logfmtEscape(fmt.Sprint(a.Value))))
+		segments = append(segments, logSegment{
+			{text: a.Key, colorize: color.New(color.FgCyan).SprintFunc()},
+			{text: "=", colorize: fmt.Sprint},
+			{text: logfmtEscape(fmt.Sprint(a.Value)), colorize: color.New(color.FgHiGreen).SprintFunc()},
+		})
 		return true
 	})
 
 	// Print the formatted log message
-	_, err := fmt.Fprintf(h.out, "%s %s%s %s%s\n", timestamp, levelText, sourceText, message, attrText)
+	width := 0
+	if h.width != nil {
+		width = h.width()
+	}
+	_, err := fmt.Fprintf(h.out, "%s\n", wrapSegments(segments, width))
 	return err
 }
 
+// wrapSegments joins the segments of a log line with spaces. If the width is at least minWrapWidth, the line is wrapped
+// between segments so that no line exceeds the width. Segments that are wider than the remaining line are continued on the
+// next lines, so no text is lost.
+func wrapSegments(segments []logSegment, width int) string {
+	var builder strings.Builder
+	if width < minWrapWidth {
+		for i, segment := range segments {
+			if i > 0 {
+				builder.WriteString(" ")
+			}
+			builder.WriteString(segment.render())
+		}
+		return builder.String()
+	}
+
+	lineWidth := 0
+	for i, segment := range segments {
+		if i > 0 {
+			if lineWidth+1+segment.width() <= width {
+				builder.WriteString(" ")
+				lineWidth++
+			} else {
+				builder.WriteString("\n" + wrapIndent)
+				lineWidth = len(wrapIndent)
+			}
+		}
+		lineWidth = segment.renderWrapped(&builder, lineWidth, width)
+	}
+	return builder.String()
+}
+
+// width returns the number of characters of the segment without colors
+func (s logSegment) width() int {
+	width := 0
+	for _, field := range s {
+		width += utf8.RuneCountInString(field.text)
+	}
+	return width
+}
+
+// render colors the fields of the segment
+func (s logSegment) render() string {
+	var builder strings.Builder
+	for _, field := range s {
+		builder.WriteString(field.colorize(field.text))
+	}
+	return builder.String()
+}
+
+// renderWrapped colors the fields of the segment and writes them to the builder, starting at the given width of the current
+// line. Once a line reaches the width, the segment is continued on an indented line. It returns the width of the last line.
+func (s logSegment) renderWrapped(builder *strings.Builder, lineWidth int, width int) int {
+	for _, field := range s {
+		runes := []rune(field.text)
+		for len(runes) > 0 {
+			if lineWidth >= width {
+				builder.WriteString("\n" + wrapIndent)
+				lineWidth = len(wrapIndent)
+			}
+			n := min(len(runes), width-lineWidth)
+			builder.WriteString(field.colorize(string(runes[:n])))
+			runes = runes[n:]
+			lineWidth += n
+		}
+	}
+	return lineWidth
+}
+
 // WithAttrs adds attributes to the handler
 func (h *ColorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &ColorHandler{
-		out:  h.out,
-		opts: h.opts,
-		base: h.base.WithAttrs(attrs),
+		out:   h.out,
+		opts:  h.opts,
+		base:  h.base.WithAttrs(attrs),
+		width: h.width,
+		now:   h.now,
 	}
 }
 
 // WithGroup adds a group to the handler
 func (h *ColorHandler) WithGroup(name string) slog.Handler {
 	return &ColorHandler{
-		out:  h.out,
-		opts: h.opts,
-		base: h.base.WithGroup(name),
+		out:   h.out,
+		opts:  h.opts,
+		base:  h.base.WithGroup(name),
+		width: h.width,
+		now:   h.now,
 	}
 }
 
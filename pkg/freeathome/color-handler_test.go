@@ -9,6 +9,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
 )
 
 type DummyHandler struct {
@@ -330,3 +333,175 @@ func TestLogFmtEscape(t *testing.T) {
 		}
 	}
 }
+
+func TestWrapSegments(t *testing.T) {
+	previous := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = previous
+	})
+
+	field := func(text string) logField {
+		return logField{text: text, colorize: fmt.Sprint}
+	}
+	segments := []logSegment{
+		{field("level=INFO")},
+		{field("msg=connected")},
+		{field("host"), field("="), field("sysap.local")},
+		{field("path=/fhapi/v1/api/rest/configuration/00000000-0000-0000-0000-000000000000")},
+	}
+
+	tests := []struct {
+		name     string
+		width    int
+		expected string
+	}{
+		{
+			name:     "Unknown width",
+			width:    0,
+			expected: "level=INFO msg=connected host=sysap.local path=/fhapi/v1/api/rest/configuration/00000000-0000-0000-0000-000000000000",
+		},
+		{
+			name:     "Width below minimum",
+			width:    minWrapWidth - 1,
+			expected: "level=INFO msg=connected host=sysap.local path=/fhapi/v1/api/rest/configuration/00000000-0000-0000-0000-000000000000",
+		},
+		{
+			name:     "Wide enough",
+			width:    200,
+			expected: "level=INFO msg=connected host=sysap.local path=/fhapi/v1/api/rest/configuration/00000000-0000-0000-0000-000000000000",
+		},
+		{
+			name:     "Wrapped without truncation",
+			width:    30,
+			expected: "level=INFO msg=connected\n    host=sysap.local\n    path=/fhapi/v1/api/rest/co\n    nfiguration/00000000-0000-\n    0000-0000-000000000000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := wrapSegments(segments, test.width)
+			if actual != test.expected {
+				t.Errorf("wrapSegments(%d) = %q; expected %q", test.width, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestColorHandlerHandleLineWrapping(t *testing.T) {
+	previous := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = previous
+	})
+
+	var output strings.Builder
+	handler := &ColorHandler{
+		out:   &output,
+		opts:  &slog.HandlerOptions{},
+		base:  slog.NewTextHandler(io.Discard, nil),
+		width: func() int { return 40 },
+	}
+
+	record := slog.NewRecord(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), slog.LevelWarn, "web socket connection lost, reconnecting", 0)
+	record.AddAttrs(slog.String("host", "sysap.local"), slog.String("error", strings.Repeat("x", 60)))
+	if err := handler.Handle(t.Context(), record); err != nil {
+		t.Fatalf("Handle returned an error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	expected := []string{
+		"time=2025-01-01T12:00:00Z level=WARN",
+		`    msg="web socket connection lost, rec`,
+		`    onnecting" host=sysap.local`,
+		"    error=xxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+		"    xxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected lines %q, got %q", expected, lines)
+	}
+	for _, line := range lines {
+		if width := utf8.RuneCountInString(line); width > 40 {
+			t.Errorf("Expected line to be at most 40 characters, got %d: %q", width, line)
+		}
+	}
+}
+
+func TestColorHandlerWithLineWrappingUnknownWidth(t *testing.T) {
+	var output strings.Builder
+	handler := NewColorHandler(&output, nil).WithLineWrapping(-1)
+
+	// The width cannot be determined for an invalid file descriptor, so wrapping is disabled
+	if width := handler.width(); width != 0 {
+		t.Errorf("Expected width 0, got %d", width)
+	}
+
+	// The width is kept when attributes or groups are added
+	if wrapped, ok := handler.WithAttrs(nil).(*ColorHandler); !ok || wrapped.width == nil {
+		t.Error("Expected WithAttrs to keep the line wrapping")
+	}
+	if wrapped, ok := handler.WithGroup("group").(*ColorHandler); !ok || wrapped.width == nil {
+		t.Error("Expected WithGroup to keep the line wrapping")
+	}
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, strings.Repeat("a", 200), 0)
+	if err := handler.Handle(t.Context(), record); err != nil {
+		t.Fatalf("Handle returned an error: %v", err)
+	}
+	if lines := strings.Count(output.String(), "\n"); lines != 1 {
+		t.Errorf("Expected a single line, got %d", lines)
+	}
+}
+
+func TestColorHandlerWithTimeFunc(t *testing.T) {
+	previous := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = previous
+	})
+
+	var output strings.Builder
+	fixed := time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)
+	handler := NewColorHandler(&output, nil).WithTimeFunc(func() time.Time { return fixed })
+
+	// The injected time replaces the time of the record
+	record := slog.NewRecord(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), slog.LevelInfo, "connected", 0)
+	if err := handler.Handle(t.Context(), record); err != nil {
+		t.Fatalf("Handle returned an error: %v", err)
+	}
+	if expected := "time=2025-06-01T08:30:00Z level=INFO msg=connected\n"; output.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, output.String())
+	}
+
+	// The time function is kept when attributes or groups are added
+	if wrapped, ok := handler.WithAttrs(nil).(*ColorHandler); !ok || wrapped.now == nil {
+		t.Error("Expected WithAttrs to keep the time function")
+	}
+	if wrapped, ok := handler.WithGroup("group").(*ColorHandler); !ok || wrapped.now == nil {
+		t.Error("Expected WithGroup to keep the time function")
+	}
+	if wrapped := handler.WithLineWrapping(-1); wrapped.now == nil {
+		t.Error("Expected WithLineWrapping to keep the time function")
+	}
+}
+
+func TestColorHandlerWithTimeFuncComposed(t *testing.T) {
+	previous := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = previous
+	})
+
+	// The color handler is wrapped by the redaction and masking handlers like in the CLI
+	var output strings.Builder
+	masker := NewSerialMasker()
+	colorHandler := NewColorHandler(&output, nil).WithTimeFunc(func() time.Time { return time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC) })
+	logger := slog.New(NewSerialMaskHandler(NewRedactHandler(colorHandler, "s3cr3t"), masker))
+
+	logger.Info("data point update", "device", "ABB7F595EC47", "password", "s3cr3t")
+
+	expected := "time=2025-06-01T08:30:00Z level=INFO msg=\"data point update\" device=" + masker.Mask("ABB7F595EC47") + " password=****\n"
+	if output.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, output.String())
+	}
+}
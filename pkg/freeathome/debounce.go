@@ -0,0 +1,116 @@
+package freeathome
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// datapointDebouncer forwards a datapoint update only after no further update of the same datapoint was received
+// for the quiet period, so that bursts of updates are coalesced into the final value.
+//
+// Updates are forwarded one at a time, even if the quiet periods of several datapoints elapse at once, so the forward
+// function is never called concurrently. The updates of a datapoint are forwarded in the order they were received.
+// Pending updates are forwarded by flush, after which no update added before is forwarded anymore.
+type datapointDebouncer struct {
+	// clock provides the timers for the quiet period
+	clock clock
+	// quietPeriod is the time a datapoint has to be stable before its update is forwarded
+	quietPeriod time.Duration
+	// forward is called with the settled update of a datapoint
+	forward func(models.DatapointUpdate)
+	// forwardMutex serializes the calls of forward, it is acquired before mutex
+	forwardMutex sync.Mutex
+	// pending contains the updates waiting for the quiet period to elapse, identified by their datapoint
+	pending lruMap[string, *pendingDatapointUpdate]
+	// mutex protects access to pending
+	mutex sync.Mutex
+}
+
+// pendingDatapointUpdate is an update waiting for the quiet period of its datapoint to elapse
+type pendingDatapointUpdate struct {
+	update models.DatapointUpdate
+	timer  timer
+}
+
+// newDatapointDebouncer creates a debouncer that forwards settled updates after the quiet period. At most maxPending
+// datapoints wait for their quiet period, zero or less does not limit them.
+func newDatapointDebouncer(clock clock, quietPeriod time.Duration, maxPending int, forward func(models.DatapointUpdate)) *datapointDebouncer {
+	return &datapointDebouncer{
+		clock:       clock,
+		quietPeriod: quietPeriod,
+		forward:     forward,
+		pending:     lruMap[string, *pendingDatapointUpdate]{maxEntries: maxPending},
+	}
+}
+
+// add replaces the pending update of the datapoint and restarts its quiet period. If too many datapoints are pending, the
+// update of the least recently updated datapoint is forwarded immediately, so that it is not lost.
+func (d *datapointDebouncer) add(update models.DatapointUpdate) {
+	key := update.SysApID + "/" + update.Serial + "/" + update.Channel + "/" + update.Datapoint
+
+	d.forwardMutex.Lock()
+	defer d.forwardMutex.Unlock()
+
+	d.mutex.Lock()
+	if previous, exists := d.pending.get(key); exists {
+		previous.timer.Stop()
+	}
+	pending := &pendingDatapointUpdate{update: update}
+	pending.timer = d.clock.AfterFunc(d.quietPeriod, func() {
+		d.settle(key, pending)
+	})
+	evicted, wasEvicted := d.pending.set(key, pending)
+	if wasEvicted {
+		evicted.value.timer.Stop()
+	}
+	d.mutex.Unlock()
+
+	if wasEvicted {
+		d.forward(evicted.value.update)
+	}
+}
+
+// settle forwards the pending update of the datapoint once its quiet period elapsed, unless it was replaced by a newer
+// update or flushed in the meantime.
+func (d *datapointDebouncer) settle(key string, pending *pendingDatapointUpdate) {
+	d.forwardMutex.Lock()
+	defer d.forwardMutex.Unlock()
+
+	d.mutex.Lock()
+	if current, _ := d.pending.get(key); current != pending {
+		d.mutex.Unlock()
+		return
+	}
+	d.pending.delete(key)
+	d.mutex.Unlock()
+
+	d.forward(pending.update)
+}
+
+// flush stops the quiet periods and forwards all pending updates immediately, the least recently updated datapoint first.
+// Once flush returned, no update added before is forwarded anymore, e.g. after the web socket connection stopped.
+func (d *datapointDebouncer) flush() {
+	d.forwardMutex.Lock()
+	defer d.forwardMutex.Unlock()
+
+	d.mutex.Lock()
+	var keys []string
+	var updates []models.DatapointUpdate
+	d.pending.all(func(key string, pending *pendingDatapointUpdate) {
+		pending.timer.Stop()
+		keys = append(keys, key)
+		updates = append(updates, pending.update)
+	})
+	for _, key := range keys {
+		d.pending.delete(key)
+	}
+	d.mutex.Unlock()
+
+	slices.Reverse(updates)
+	for _, update := range updates {
+		d.forward(update)
+	}
+}
@@ -0,0 +1,240 @@
+package freeathome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestDatapointDebouncerSettledValue tests that a burst of updates is coalesced into the final value after the quiet period.
+func TestDatapointDebouncerSettledValue(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var forwarded []models.DatapointUpdate
+	debouncer := newDatapointDebouncer(clock, time.Second, 0, func(update models.DatapointUpdate) {
+		forwarded = append(forwarded, update)
+	})
+
+	// Feed rapid changes, each within the quiet period of the previous one
+	for i := range 10 {
+		debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001", Value: fmt.Sprint(i * 10)})
+		clock.Sleep(500 * time.Millisecond)
+	}
+	if len(forwarded) != 0 {
+		t.Fatalf("Expected no update during the burst, got %v", forwarded)
+	}
+
+	// The settled value is forwarded once the quiet period elapsed
+	clock.Sleep(500 * time.Millisecond)
+	if len(forwarded) != 1 {
+		t.Fatalf("Expected 1 forwarded update, got %d", len(forwarded))
+	}
+	if forwarded[0].Value != "90" {
+		t.Errorf("Expected settled value '90', got '%s'", forwarded[0].Value)
+	}
+
+	// Nothing else is forwarded later
+	clock.Sleep(time.Minute)
+	if len(forwarded) != 1 {
+		t.Errorf("Expected no further updates, got %v", forwarded)
+	}
+}
+
+// TestDatapointDebouncerMaxPending tests that the least recently updated pending datapoint is forwarded immediately once
+// too many datapoints are pending, and that its quiet period does not forward it again.
+func TestDatapointDebouncerMaxPending(t *testing.T) {
+	clock := &fakeClock{}
+	var forwarded []string
+	debouncer := newDatapointDebouncer(clock, time.Second, 2, func(update models.DatapointUpdate) {
+		forwarded = append(forwarded, update.Datapoint+"="+update.Value)
+	})
+
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001", Value: "2"})
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "3"})
+	if len(forwarded) != 0 {
+		t.Fatalf("Expected no update within the limit, got %v", forwarded)
+	}
+
+	// odp0001 is the least recently updated datapoint
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0002", Value: "4"})
+	if fmt.Sprint(forwarded) != "[odp0001=2]" {
+		t.Fatalf("Expected [odp0001=2], got %v", forwarded)
+	}
+	if pending := debouncer.pending.len(); pending != 2 {
+		t.Errorf("Expected 2 pending datapoints, got %d", pending)
+	}
+
+	clock.Sleep(time.Second)
+	if fmt.Sprint(forwarded) != "[odp0001=2 odp0000=3 odp0002=4]" && fmt.Sprint(forwarded) != "[odp0001=2 odp0002=4 odp0000=3]" {
+		t.Errorf("Expected the remaining datapoints to settle once, got %v", forwarded)
+	}
+}
+
+// TestDatapointDebouncerIndependentDatapoints tests that the quiet period is tracked per datapoint.
+func TestDatapointDebouncerIndependentDatapoints(t *testing.T) {
+	clock := &fakeClock{}
+	var forwarded []string
+	debouncer := newDatapointDebouncer(clock, time.Second, 0, func(update models.DatapointUpdate) {
+		forwarded = append(forwarded, update.Datapoint+"="+update.Value)
+	})
+
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+	clock.Sleep(600 * time.Millisecond)
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001", Value: "2"})
+	clock.Sleep(600 * time.Millisecond)
+
+	// The first datapoint settled, the second one is still within its quiet period
+	if fmt.Sprint(forwarded) != "[odp0000=1]" {
+		t.Fatalf("Expected [odp0000=1], got %v", forwarded)
+	}
+
+	clock.Sleep(600 * time.Millisecond)
+	if fmt.Sprint(forwarded) != "[odp0000=1 odp0001=2]" {
+		t.Errorf("Expected [odp0000=1 odp0001=2], got %v", forwarded)
+	}
+}
+
+// TestDatapointDebouncerFlush tests that pending updates are forwarded on flush, the least recently updated first, and
+// that their quiet periods do not forward them again.
+func TestDatapointDebouncerFlush(t *testing.T) {
+	clock := &fakeClock{}
+	var forwarded []string
+	debouncer := newDatapointDebouncer(clock, time.Second, 0, func(update models.DatapointUpdate) {
+		forwarded = append(forwarded, update.Datapoint+"="+update.Value)
+	})
+
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+	debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001", Value: "2"})
+	debouncer.flush()
+	if fmt.Sprint(forwarded) != "[odp0000=1 odp0001=2]" {
+		t.Fatalf("Expected [odp0000=1 odp0001=2], got %v", forwarded)
+	}
+	if pending := debouncer.pending.len(); pending != 0 {
+		t.Errorf("Expected no pending datapoints, got %d", pending)
+	}
+
+	clock.Sleep(time.Minute)
+	if len(forwarded) != 2 {
+		t.Errorf("Expected no further updates, got %v", forwarded)
+	}
+}
+
+// TestDatapointDebouncerSerializesForward tests that updates of different datapoints settling at once are not forwarded
+// concurrently.
+func TestDatapointDebouncerSerializesForward(t *testing.T) {
+	var active, maxActive atomic.Int32
+	var forwarded atomic.Int32
+	debouncer := newDatapointDebouncer(&realClock{}, time.Millisecond, 0, func(update models.DatapointUpdate) {
+		current := active.Add(1)
+		if current > maxActive.Load() {
+			maxActive.Store(current)
+		}
+		time.Sleep(time.Millisecond)
+		active.Add(-1)
+		forwarded.Add(1)
+	})
+
+	for i := range 20 {
+		debouncer.add(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: fmt.Sprintf("odp%04d", i), Value: "1"})
+	}
+	time.Sleep(100 * time.Millisecond)
+	debouncer.flush()
+
+	if forwarded.Load() != 20 {
+		t.Errorf("Expected 20 forwarded updates, got %d", forwarded.Load())
+	}
+	if maxActive.Load() != 1 {
+		t.Errorf("Expected updates to be forwarded one at a time, got %d at once", maxActive.Load())
+	}
+}
+
+// TestSystemAccessPointWebSocketDatapointDebounce tests that web socket datapoint updates are debounced if configured.
+func TestSystemAccessPointWebSocketDatapointDebounce(t *testing.T) {
+	ws, _, _ := setupSysApWebSocket(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ws.sysAp.clock = clock
+	ws.sysAp.config.DatapointDebounce = 200 * time.Millisecond
+
+	var updates []models.DatapointUpdate
+	ws.sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		updates = append(updates, update)
+	})
+
+	for _, value := range []string{"10", "35", "70"} {
+		message, _ := json.Marshal(models.WebSocketMessage{
+			models.EmptyUUID: models.Message{Datapoints: map[string]string{"ABB7F595EC47/ch0000/odp0001": value}},
+		})
+		ws.processMessage(message)
+		clock.Sleep(100 * time.Millisecond)
+	}
+	if len(updates) != 0 {
+		t.Fatalf("Expected no update during the burst, got %v", updates)
+	}
+
+	clock.Sleep(100 * time.Millisecond)
+	if len(updates) != 1 || updates[0].Value != "70" {
+		t.Fatalf("Expected the settled value '70', got %v", updates)
+	}
+	if expected := time.Date(2025, 1, 1, 0, 0, 0, 200*int(time.Millisecond), time.UTC); !updates[0].Timestamp.Equal(expected) {
+		t.Errorf("Expected the timestamp of the last update %v, got %v", expected, updates[0].Timestamp)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketFlushesDebouncedUpdates tests that updates pending debouncing are passed on before
+// ConnectWebSocket returns.
+func TestSystemAccessPointConnectWebSocketFlushesDebouncedUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, _, _ := setupSysAp(t, false, false)
+	sysAp.config.DatapointDebounce = time.Hour
+	var updates []models.DatapointUpdate
+	sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		updates = append(updates, update)
+	})
+
+	// The server sends a single datapoint update and closes the connection once the update is pending
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		message, _ := json.Marshal(models.WebSocketMessage{
+			models.EmptyUUID: models.Message{Datapoints: map[string]string{"ABB7F595EC47/ch0000/odp0001": "70"}},
+		})
+		_ = conn.WriteMessage(websocket.TextMessage, message)
+		for ctx.Err() == nil {
+			debouncer := sysAp.datapointDebouncer()
+			debouncer.mutex.Lock()
+			pending := debouncer.pending.len()
+			debouncer.mutex.Unlock()
+			if pending == 1 {
+				cancel()
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}))
+	defer server.Close()
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	if err := sysAp.ConnectWebSocket(ctx, 1, false, time.Hour); err != nil && err != context.Canceled {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if len(updates) != 1 || updates[0].Value != "70" {
+		t.Errorf("Expected the pending update to be passed on, got %v", updates)
+	}
+}
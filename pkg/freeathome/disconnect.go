@@ -0,0 +1,58 @@
+package freeathome
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/gorilla/websocket"
+)
+
+// DisconnectCause describes why an established web socket connection was lost.
+type DisconnectCause string
+
+const (
+	// DisconnectCauseReadError indicates that reading from the connection failed, e.g. because the network connection broke.
+	DisconnectCauseReadError DisconnectCause = "read error"
+	// DisconnectCausePingFailure indicates that a keepalive ping could not be sent and the connection was closed.
+	DisconnectCausePingFailure DisconnectCause = "ping failure"
+	// DisconnectCauseIdleTimeout indicates that no data was received before the read deadline of the connection expired.
+	DisconnectCauseIdleTimeout DisconnectCause = "idle timeout"
+	// DisconnectCauseServerClose indicates that the system access point closed the connection with a close frame.
+	DisconnectCauseServerClose DisconnectCause = "server close"
+)
+
+// DisconnectError is the error that caused an established web socket connection to be lost. It is passed to the
+// reconnecting handler, so consumers can see why the connection is re-established.
+type DisconnectError struct {
+	// Cause classifies the reason of the disconnect
+	Cause DisconnectCause
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the error message including the cause of the disconnect.
+func (e *DisconnectError) Error() string {
+	return fmt.Sprintf("web socket disconnected (%s): %v", e.Cause, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DisconnectError) Unwrap() error {
+	return e.Err
+}
+
+// newReadDisconnectError classifies an error returned by reading from the connection.
+func newReadDisconnectError(err error) *DisconnectError {
+	// An abnormal closure is reported if the connection broke without a close frame
+	var closeError *websocket.CloseError
+	if errors.As(err, &closeError) && closeError.Code != websocket.CloseAbnormalClosure {
+		return &DisconnectError{Cause: DisconnectCauseServerClose, Err: err}
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) && netError.Timeout() {
+		return &DisconnectError{Cause: DisconnectCauseIdleTimeout, Err: err}
+	}
+
+	return &DisconnectError{Cause: DisconnectCauseReadError, Err: err}
+}
@@ -0,0 +1,166 @@
+package freeathome
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestNewReadDisconnectError tests the classification of errors returned by reading from the connection.
+func TestNewReadDisconnectError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected DisconnectCause
+	}{
+		{name: "Close frame", err: &websocket.CloseError{Code: websocket.CloseGoingAway, Text: "restart"}, expected: DisconnectCauseServerClose},
+		{name: "Abnormal closure", err: &websocket.CloseError{Code: websocket.CloseAbnormalClosure}, expected: DisconnectCauseReadError},
+		{name: "Deadline exceeded", err: os.ErrDeadlineExceeded, expected: DisconnectCauseIdleTimeout},
+		{name: "Other error", err: errors.New("connection reset by peer"), expected: DisconnectCauseReadError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := newReadDisconnectError(tt.err)
+			if reason.Cause != tt.expected {
+				t.Errorf("Expected cause %q, got %q", tt.expected, reason.Cause)
+			}
+			if !errors.Is(reason, tt.err) {
+				t.Errorf("Expected the disconnect error to wrap %v", tt.err)
+			}
+		})
+	}
+}
+
+// TestDisconnectErrorMessage tests that the error message contains the cause and the underlying error.
+func TestDisconnectErrorMessage(t *testing.T) {
+	err := &DisconnectError{Cause: DisconnectCausePingFailure, Err: errors.New("broken pipe")}
+	if expected := "web socket disconnected (ping failure): broken pipe"; err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+// TestSystemAccessPointWebSocketPingFailureDisconnect tests that a failed ping closes the connection and takes precedence
+// over the read error caused by closing it.
+func TestSystemAccessPointWebSocketPingFailureDisconnect(t *testing.T) {
+	ws, _, _ := setupSysApWebSocket(t, true, false)
+	pingErr := errors.New("broken pipe")
+	conn := &closeRecordingConn{MockConn: MockConn{err: pingErr, mu: &sync.Mutex{}}}
+	ws.setActiveConnection(conn)
+
+	messageReceivedChannel := make(chan struct{})
+	ws.webSocketKeepaliveLoop(messageReceivedChannel, conn, 10*time.Millisecond)
+
+	if !conn.closed {
+		t.Error("Expected the connection to be closed after the failed ping")
+	}
+	reason := ws.takeDisconnectReason(errors.New("use of closed network connection"))
+	if reason == nil || reason.Cause != DisconnectCausePingFailure || !errors.Is(reason, pingErr) {
+		t.Errorf("Expected a ping failure caused by %v, got %v", pingErr, reason)
+	}
+	if reason := ws.takeDisconnectReason(nil); reason != nil {
+		t.Errorf("Expected the disconnect cause to be reset, got %v", reason)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketReconnectingHandler tests that the reconnecting handler receives the cause of the
+// lost connection.
+func TestSystemAccessPointConnectWebSocketReconnectingHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		drop     func(conn *websocket.Conn)
+		expected DisconnectCause
+	}{
+		{
+			name: "Server close",
+			drop: func(conn *websocket.Conn) {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "restart"))
+			},
+			expected: DisconnectCauseServerClose,
+		},
+		{
+			name:     "Read error",
+			drop:     func(conn *websocket.Conn) { _ = conn.NetConn().Close() },
+			expected: DisconnectCauseReadError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			sysAp, buf, _ := setupSysAp(t, false, false)
+
+			// The first connection is dropped, the second one is kept open until the test finishes
+			var mutex sync.Mutex
+			connections := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				upgrader := websocket.Upgrader{}
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					t.Errorf("Failed to upgrade WebSocket: %v", err)
+					return
+				}
+				defer func() { _ = conn.Close() }()
+
+				mutex.Lock()
+				connections++
+				first := connections == 1
+				mutex.Unlock()
+				if first {
+					tt.drop(conn)
+					return
+				}
+				<-ctx.Done()
+			}))
+			defer server.Close()
+			sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+			var reasons []*DisconnectError
+			sysAp.SetReconnectingHandler(func(reason *DisconnectError) {
+				reasons = append(reasons, reason)
+			})
+			connected := 0
+			sysAp.SetConnectedHandler(func() {
+				connected++
+				if connected == 2 {
+					cancel()
+				}
+			})
+
+			err := sysAp.ConnectWebSocket(ctx, 3, false, time.Hour)
+			if err != nil && err != context.Canceled {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(reasons) != 1 {
+				t.Fatalf("Expected the reconnecting handler to be called once, got %d", len(reasons))
+			}
+			if reasons[0].Cause != tt.expected {
+				t.Errorf("Expected cause %q, got %q (%v)", tt.expected, reasons[0].Cause, reasons[0].Err)
+			}
+			if logOutput := buf.String(); !strings.Contains(logOutput, `msg="reconnecting to web socket" cause="`+string(tt.expected)+`"`) {
+				t.Errorf("Expected the reconnect to be logged with its cause, got: %s", logOutput)
+			}
+		})
+	}
+}
+
+// closeRecordingConn is a mock connection that records whether it was closed
+type closeRecordingConn struct {
+	MockConn
+	closed bool
+}
+
+func (c *closeRecordingConn) Close() error {
+	c.closed = true
+	return nil
+}
@@ -0,0 +1,76 @@
+package freeathome
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables that are used to configure a system access point
+const (
+	// EnvHostname is the environment variable containing the hostname or IP address of the system access point (required)
+	EnvHostname = "FREEATHOME_HOSTNAME"
+	// EnvUsername is the environment variable containing the username for authentication (required)
+	EnvUsername = "FREEATHOME_USERNAME"
+	// EnvPassword is the environment variable containing the password for authentication (required)
+	EnvPassword = "FREEATHOME_PASSWORD"
+	// EnvTLSEnabled is the environment variable indicating whether TLS is enabled (optional, default true)
+	EnvTLSEnabled = "FREEATHOME_TLS_ENABLED"
+	// EnvSkipTLSVerify is the environment variable indicating whether TLS certificate verification is skipped (optional, default false)
+	EnvSkipTLSVerify = "FREEATHOME_SKIP_TLS_VERIFY"
+	// EnvVerboseErrors is the environment variable indicating whether verbose errors are logged (optional, default false)
+	EnvVerboseErrors = "FREEATHOME_VERBOSE_ERRORS"
+)
+
+// ConfigFromEnv creates a new Config from the FREEATHOME_* environment variables.
+// Hostname, username and password are required, the optional boolean variables override the defaults of NewConfig.
+// Empty variables are treated as not set.
+func ConfigFromEnv() (*Config, error) {
+	// Check the required variables
+	var missing []string
+	values := map[string]string{}
+	for _, name := range []string{EnvHostname, EnvUsername, EnvPassword} {
+		value := os.Getenv(name)
+		if value == "" {
+			missing = append(missing, name)
+		}
+		values[name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	config := NewConfig(values[EnvHostname], values[EnvUsername], values[EnvPassword])
+
+	// Apply the optional variables
+	for name, target := range map[string]*bool{
+		EnvTLSEnabled:    &config.TLSEnabled,
+		EnvSkipTLSVerify: &config.SkipTLSVerify,
+		EnvVerboseErrors: &config.VerboseErrors,
+	} {
+		value, set, err := LookupEnvBool(name)
+		if err != nil {
+			return nil, err
+		}
+		if set {
+			*target = value
+		}
+	}
+
+	return config, nil
+}
+
+// LookupEnvBool parses the boolean environment variable with the given name like ConfigFromEnv does.
+// It reports false if the variable is empty or not set and returns an error if the value is not a valid boolean.
+func LookupEnvBool(name string) (bool, bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return false, false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	return parsed, true, nil
+}
@@ -0,0 +1,130 @@
+package freeathome
+
+import (
+	"strings"
+	"testing"
+)
+
+// setRequiredEnv sets the required environment variables for the test
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+
+	t.Setenv(EnvHostname, "env-host")
+	t.Setenv(EnvUsername, "env-user")
+	t.Setenv(EnvPassword, "env-pass")
+}
+
+func TestConfigFromEnvAllVariables(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv(EnvTLSEnabled, "false")
+	t.Setenv(EnvSkipTLSVerify, "true")
+	t.Setenv(EnvVerboseErrors, "1")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.Hostname != "env-host" || config.Username != "env-user" || config.Password != "env-pass" {
+		t.Errorf("Unexpected credentials: %s, %s, %s", config.Hostname, config.Username, config.Password)
+	}
+	if config.TLSEnabled {
+		t.Error("Expected TLS to be disabled")
+	}
+	if !config.SkipTLSVerify {
+		t.Error("Expected TLS verification to be skipped")
+	}
+	if !config.VerboseErrors {
+		t.Error("Expected verbose errors to be enabled")
+	}
+}
+
+func TestConfigFromEnvDefaults(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv(EnvTLSEnabled, "")
+	t.Setenv(EnvSkipTLSVerify, "")
+	t.Setenv(EnvVerboseErrors, "")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Optional variables that are not set keep the defaults of NewConfig
+	defaults := NewConfig("env-host", "env-user", "env-pass")
+	if config.TLSEnabled != defaults.TLSEnabled || config.SkipTLSVerify != defaults.SkipTLSVerify || config.VerboseErrors != defaults.VerboseErrors {
+		t.Errorf("Expected defaults to be kept, got %+v", config)
+	}
+	if config.ReconnectionStabilityWindow != defaults.ReconnectionStabilityWindow {
+		t.Errorf("Expected reconnection stability window %v, got %v", defaults.ReconnectionStabilityWindow, config.ReconnectionStabilityWindow)
+	}
+}
+
+func TestConfigFromEnvMissingVariables(t *testing.T) {
+	t.Setenv(EnvHostname, "env-host")
+	t.Setenv(EnvUsername, "")
+	t.Setenv(EnvPassword, "")
+
+	config, err := ConfigFromEnv()
+	if err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+	if config != nil {
+		t.Error(expectedNil)
+	}
+
+	expected := "missing environment variables: FREEATHOME_USERNAME, FREEATHOME_PASSWORD"
+	if err.Error() != expected {
+		t.Errorf(expectedErrorGotValue, expected, err)
+	}
+}
+
+func TestConfigFromEnvInvalidBoolean(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv(EnvTLSEnabled, "")
+	t.Setenv(EnvSkipTLSVerify, "maybe")
+	t.Setenv(EnvVerboseErrors, "")
+
+	_, err := ConfigFromEnv()
+	if err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+	if !strings.Contains(err.Error(), "invalid value for FREEATHOME_SKIP_TLS_VERIFY") {
+		t.Errorf("Expected error to contain 'invalid value for FREEATHOME_SKIP_TLS_VERIFY', got '%v'", err)
+	}
+}
+
+func TestLookupEnvBool(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    bool
+		set     bool
+		wantErr bool
+	}{
+		{name: "Not set", value: "", want: false, set: false},
+		{name: "True", value: "true", want: true, set: true},
+		{name: "Zero", value: "0", want: false, set: true},
+		{name: "Invalid", value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvVerboseErrors, tt.value)
+
+			value, set, err := LookupEnvBool(EnvVerboseErrors)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "invalid value for FREEATHOME_VERBOSE_ERRORS") {
+					t.Errorf("Expected invalid value error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if value != tt.want || set != tt.set {
+				t.Errorf("Expected %t (set %t), got %t (set %t)", tt.want, tt.set, value, set)
+			}
+		})
+	}
+}
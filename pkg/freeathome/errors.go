@@ -0,0 +1,64 @@
+package freeathome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ErrReadOnly is returned by write operations if the client is in read-only mode.
+var ErrReadOnly = errors.New("client is in read-only mode")
+
+// APIError is returned when the system access point responds with an HTTP error status.
+type APIError struct {
+	// Message describes the operation that failed
+	Message string
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Status is the HTTP status text of the response
+	Status string
+	// Body is the body of the response
+	Body string
+}
+
+// Error returns the error message including the response body.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Message, e.Body)
+}
+
+// IsRetryable reports whether an operation that failed with the given error may succeed when retried.
+// Network errors, timeouts and server side API errors (5xx) are retryable. Client side API errors (4xx),
+// errors parsing the response and cancelled contexts are not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// API errors are retryable if the server failed
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode >= http.StatusInternalServerError
+	}
+
+	// Response bodies that cannot be parsed will not change on retry
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &syntaxError) || errors.As(err, &typeError) {
+		return false
+	}
+
+	// A cancelled context is a deliberate decision of the caller
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Network errors, including timeouts, are transient
+	var netError net.Error
+	return errors.As(err, &netError)
+}
@@ -0,0 +1,97 @@
+package freeathome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestAPIErrorMessage(t *testing.T) {
+	err := &APIError{Message: "failed to get device", StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: "Not Found"}
+
+	expected := "failed to get device: Not Found"
+	if err.Error() != expected {
+		t.Errorf(expectedErrorGotValue, expected, err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	var syntaxError error = json.Unmarshal([]byte("invalid json"), &struct{}{})
+	var typeError error = json.Unmarshal([]byte(`{"values": 1}`), &struct {
+		Values []string `json:"values"`
+	}{})
+	if syntaxError == nil || typeError == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Nil error", err: nil, expected: false},
+		{name: "Internal server error", err: &APIError{StatusCode: http.StatusInternalServerError}, expected: true},
+		{name: "Service unavailable", err: &APIError{StatusCode: http.StatusServiceUnavailable}, expected: true},
+		{name: "Wrapped server error", err: fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusBadGateway}), expected: true},
+		{name: "Bad request", err: &APIError{StatusCode: http.StatusBadRequest}, expected: false},
+		{name: "Unauthorized", err: &APIError{StatusCode: http.StatusUnauthorized}, expected: false},
+		{name: "Not found", err: &APIError{StatusCode: http.StatusNotFound}, expected: false},
+		{name: "Syntax error", err: syntaxError, expected: false},
+		{name: "Unmarshal type error", err: typeError, expected: false},
+		{name: "Network error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, expected: true},
+		{name: "URL timeout error", err: &url.Error{Op: "Get", URL: "https://localhost", Err: timeoutError{}}, expected: true},
+		{name: "Deadline exceeded", err: context.DeadlineExceeded, expected: true},
+		{name: "Context cancelled", err: &url.Error{Op: "Get", URL: "https://localhost", Err: context.Canceled}, expected: false},
+		{name: "Unknown error", err: errors.New("unknown"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsRetryable(tt.err); result != tt.expected {
+				t.Errorf("Expected IsRetryable to return %t for %v, got %t", tt.expected, tt.err, result)
+			}
+		})
+	}
+}
+
+func TestSystemAccessPointErrorResponseReturnsAPIError(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Status:     "503 Service Unavailable",
+			Body:       io.NopCloser(strings.NewReader("Service Unavailable")),
+			Header:     make(http.Header),
+		},
+	})
+
+	_, err := sysAp.GetDeviceList()
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("Expected APIError, got %T: %v", err, err)
+	}
+	if apiError.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, apiError.StatusCode)
+	}
+	if apiError.Body != "Service Unavailable" {
+		t.Errorf("Expected body 'Service Unavailable', got '%s'", apiError.Body)
+	}
+	if !IsRetryable(err) {
+		t.Error("Expected error to be retryable")
+	}
+}
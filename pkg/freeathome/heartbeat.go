@@ -0,0 +1,40 @@
+package freeathome
+
+import (
+	"sync"
+	"time"
+)
+
+// startHeartbeat logs that the client is still running at the given interval, together with the number of web socket
+// messages received since the previous heartbeat. This distinguishes a hung process from a quiet system access point.
+// The returned function stops the heartbeat.
+func (sysAp *SystemAccessPoint) startHeartbeat(interval time.Duration) (stop func()) {
+	var mutex sync.Mutex
+	var current timer
+	stopped := false
+
+	// Count the messages from the start of the heartbeat
+	sysAp.receivedMessages.Store(0)
+
+	var beat func()
+	beat = func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if stopped {
+			return
+		}
+		sysAp.config.Logger.Log("still running", "messages", sysAp.receivedMessages.Swap(0), "interval", interval)
+		current = sysAp.clock.AfterFunc(interval, beat)
+	}
+
+	mutex.Lock()
+	current = sysAp.clock.AfterFunc(interval, beat)
+	mutex.Unlock()
+
+	return func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		stopped = true
+		current.Stop()
+	}
+}
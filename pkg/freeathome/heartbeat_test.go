@@ -0,0 +1,68 @@
+package freeathome
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// expectHeartbeat checks that exactly the expected number of heartbeats with the given message counts were logged.
+func expectHeartbeat(t *testing.T, records chan slog.Record, expected ...uint64) {
+	t.Helper()
+
+	for _, count := range expected {
+		select {
+		case record := <-records:
+			if record.Message != "still running" {
+				t.Fatalf("Expected heartbeat log record, got: %s", record.Message)
+			}
+			record.Attrs(func(attr slog.Attr) bool {
+				if attr.Key == "messages" && attr.Value.Uint64() != count {
+					t.Errorf("Expected %d messages since the last heartbeat, got %d", count, attr.Value.Uint64())
+				}
+				return true
+			})
+		default:
+			t.Fatalf("Expected heartbeat with %d messages, got none", count)
+		}
+	}
+
+	select {
+	case record := <-records:
+		t.Fatalf("Expected no further log record, got: %s", record.Message)
+	default:
+	}
+}
+
+// TestSystemAccessPointHeartbeat tests that heartbeats fire on the interval with the number of messages since the previous heartbeat.
+func TestSystemAccessPointHeartbeat(t *testing.T) {
+	sysAp, _, records := setupSysAp(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	sysAp.clock = clock
+
+	// Messages received before the heartbeat started are not counted
+	sysAp.receivedMessages.Add(5)
+	stop := sysAp.startHeartbeat(time.Minute)
+
+	// No heartbeat before the interval elapsed
+	sysAp.receivedMessages.Add(3)
+	clock.Sleep(30 * time.Second)
+	expectHeartbeat(t, records)
+
+	clock.Sleep(30 * time.Second)
+	expectHeartbeat(t, records, 3)
+
+	// A quiet system access point still produces heartbeats
+	clock.Sleep(time.Minute)
+	expectHeartbeat(t, records, 0)
+
+	sysAp.receivedMessages.Add(2)
+	clock.Sleep(time.Minute)
+	expectHeartbeat(t, records, 2)
+
+	// No heartbeat after stopping
+	stop()
+	sysAp.receivedMessages.Add(1)
+	clock.Sleep(time.Minute)
+	expectHeartbeat(t, records)
+}
@@ -0,0 +1,80 @@
+package freeathome
+
+import "container/list"
+
+// lruMap is a map that evicts its least recently updated entry once it holds more than maxEntries entries, so that the
+// memory of per-datapoint state is bounded on large installations. The zero value is an unbounded, empty map.
+// It is not safe for concurrent use.
+type lruMap[K comparable, V any] struct {
+	// maxEntries is the maximum number of entries, zero or less keeps the map unbounded
+	maxEntries int
+	// entries maps the keys to their elements in order
+	entries map[K]*list.Element
+	// order contains the entries from the most to the least recently updated
+	order *list.List
+}
+
+// lruEntry is an entry of an lruMap
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// get returns the value of the key without changing the order of the entries.
+func (m *lruMap[K, V]) get(key K) (V, bool) {
+	element, exists := m.entries[key]
+	if !exists {
+		var zero V
+		return zero, false
+	}
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+// set sets the value of the key and marks it as the most recently updated entry. If the map exceeds its maximum number of
+// entries afterwards, the least recently updated entry is removed and returned.
+func (m *lruMap[K, V]) set(key K, value V) (evicted lruEntry[K, V], wasEvicted bool) {
+	if m.entries == nil {
+		m.entries = map[K]*list.Element{}
+		m.order = list.New()
+	}
+
+	if element, exists := m.entries[key]; exists {
+		element.Value.(*lruEntry[K, V]).value = value
+		m.order.MoveToFront(element)
+		return evicted, false
+	}
+	m.entries[key] = m.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+
+	if m.maxEntries <= 0 || m.order.Len() <= m.maxEntries {
+		return evicted, false
+	}
+	oldest := m.order.Back()
+	m.order.Remove(oldest)
+	evicted = *oldest.Value.(*lruEntry[K, V])
+	delete(m.entries, evicted.key)
+	return evicted, true
+}
+
+// delete removes the entry of the key, if it exists.
+func (m *lruMap[K, V]) delete(key K) {
+	if element, exists := m.entries[key]; exists {
+		m.order.Remove(element)
+		delete(m.entries, key)
+	}
+}
+
+// len returns the number of entries.
+func (m *lruMap[K, V]) len() int {
+	return len(m.entries)
+}
+
+// all calls yield for every entry from the most to the least recently updated.
+func (m *lruMap[K, V]) all(yield func(key K, value V)) {
+	if m.order == nil {
+		return
+	}
+	for element := m.order.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*lruEntry[K, V])
+		yield(entry.key, entry.value)
+	}
+}
@@ -0,0 +1,84 @@
+package freeathome
+
+import (
+	"fmt"
+	"testing"
+)
+
+// lruKeys returns the keys of the map from the most to the least recently updated
+func lruKeys(m *lruMap[string, int]) []string {
+	var keys []string
+	m.all(func(key string, _ int) {
+		keys = append(keys, key)
+	})
+	return keys
+}
+
+// TestLRUMapCap tests that the map never exceeds its maximum number of entries.
+func TestLRUMapCap(t *testing.T) {
+	m := lruMap[string, int]{maxEntries: 3}
+	evictions := 0
+	for i := range 10 {
+		if _, wasEvicted := m.set(fmt.Sprint(i), i); wasEvicted {
+			evictions++
+		}
+		if m.len() > 3 {
+			t.Fatalf("Expected at most 3 entries, got %d", m.len())
+		}
+	}
+	if evictions != 7 {
+		t.Errorf("Expected 7 evictions, got %d", evictions)
+	}
+	if keys := fmt.Sprint(lruKeys(&m)); keys != "[9 8 7]" {
+		t.Errorf("Expected the entries [9 8 7], got %s", keys)
+	}
+}
+
+// TestLRUMapEvictsLeastRecentlyUpdated tests that updating an entry protects it from eviction, while reading it does not.
+func TestLRUMapEvictsLeastRecentlyUpdated(t *testing.T) {
+	m := lruMap[string, int]{maxEntries: 2}
+	m.set("a", 1)
+	m.set("b", 2)
+
+	// Updating a makes b the least recently updated entry
+	if _, wasEvicted := m.set("a", 3); wasEvicted {
+		t.Fatal("Expected no eviction when updating an existing entry")
+	}
+	evicted, wasEvicted := m.set("c", 4)
+	if !wasEvicted || evicted.key != "b" || evicted.value != 2 {
+		t.Errorf("Expected b=2 to be evicted, got %s=%d (%t)", evicted.key, evicted.value, wasEvicted)
+	}
+
+	// Reading a does not refresh it
+	if value, exists := m.get("a"); !exists || value != 3 {
+		t.Errorf("Expected a=3, got %d (%t)", value, exists)
+	}
+	evicted, wasEvicted = m.set("d", 5)
+	if !wasEvicted || evicted.key != "a" {
+		t.Errorf("Expected a to be evicted, got %s (%t)", evicted.key, wasEvicted)
+	}
+	if keys := fmt.Sprint(lruKeys(&m)); keys != "[d c]" {
+		t.Errorf("Expected the entries [d c], got %s", keys)
+	}
+
+	m.delete("c")
+	if _, exists := m.get("c"); exists || m.len() != 1 {
+		t.Errorf("Expected c to be deleted, got %d entries", m.len())
+	}
+}
+
+// TestLRUMapUnbounded tests that the zero value keeps all entries.
+func TestLRUMapUnbounded(t *testing.T) {
+	var m lruMap[string, int]
+	if _, exists := m.get("a"); exists {
+		t.Error("Expected an empty map")
+	}
+	for i := range 1000 {
+		if _, wasEvicted := m.set(fmt.Sprint(i), i); wasEvicted {
+			t.Fatalf("Expected no eviction, got one at entry %d", i)
+		}
+	}
+	if m.len() != 1000 {
+		t.Errorf("Expected 1000 entries, got %d", m.len())
+	}
+}
@@ -0,0 +1,75 @@
+package freeathome
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// defaultPollingInterval is used if no positive polling interval is configured
+const defaultPollingInterval = 10 * time.Second
+
+// isWebSocketBlocked reports whether a failed web socket handshake indicates that web sockets are blocked, e.g. by a proxy
+// that answers the upgrade request with a regular HTTP response. Authentication failures are not considered blocked.
+func isWebSocketBlocked(err error, resp *http.Response) bool {
+	return errors.Is(err, websocket.ErrBadHandshake) && resp != nil && resp.StatusCode != http.StatusUnauthorized
+}
+
+// pollDatapoints periodically requests the configured polling datapoints via REST until the context is cancelled.
+// Values that changed since the previous poll are passed to the datapoint handler like updates received from the web socket.
+func (ws *SystemAccessPointWebSocket) pollDatapoints(ctx context.Context) {
+	interval := ws.sysAp.config.PollingInterval
+	if interval <= 0 {
+		interval = defaultPollingInterval
+	}
+	ws.sysAp.config.Logger.Warn("web socket handshake failed, falling back to polling", "interval", interval, "datapoints", len(ws.sysAp.config.PollingDatapoints))
+
+	values := map[models.DatapointKey]string{}
+	for ctx.Err() == nil {
+		ws.pollDatapointsOnce(values)
+
+		select {
+		case <-ctx.Done():
+		case <-ws.sysAp.clock.After(interval):
+		}
+	}
+	ws.sysAp.config.Logger.Log("context cancelled, stopping polling")
+}
+
+// pollDatapointsOnce requests each polling datapoint once and emits an update for every value that differs from the given previous values.
+// Datapoints that cannot be requested are skipped until the next poll.
+func (ws *SystemAccessPointWebSocket) pollDatapointsOnce(values map[models.DatapointKey]string) {
+	sysApID := ws.sysAp.GetUUID()
+	for _, key := range ws.sysAp.config.PollingDatapoints {
+		response, err := ws.sysAp.GetDatapoint(key.Serial, key.Channel, key.Datapoint)
+		if err != nil {
+			continue
+		}
+
+		datapoint, exists := (*response)[sysApID]
+		if !exists || len(datapoint.Values) == 0 {
+			ws.sysAp.config.Logger.Warn("polled datapoint response contains no value", "datapoint", key.String())
+			continue
+		}
+
+		value := datapoint.Values[0]
+		if previous, polled := values[key]; polled && previous == value {
+			continue
+		}
+		values[key] = value
+
+		ws.sysAp.config.Logger.Log("data point update", "device", key.Serial, "channel", key.Channel, "datapoint", key.Datapoint, "value", value, "source", "polling")
+		ws.sysAp.emitDatapointUpdate(models.DatapointUpdate{
+			Timestamp: ws.sysAp.clock.Now(),
+			SysApID:   sysApID,
+			Serial:    key.Serial,
+			Channel:   key.Channel,
+			Datapoint: key.Datapoint,
+			Value:     value,
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package freeathome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// newBlockedWebSocketServer creates a server that rejects web socket handshakes with the given status code and answers
+// datapoint requests with a value that changes on every second request.
+func newBlockedWebSocketServer(t *testing.T, status int) (*httptest.Server, *int) {
+	t.Helper()
+
+	var mutex sync.Mutex
+	datapointRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/api/ws") {
+			http.Error(w, "web sockets are not allowed", status)
+			return
+		}
+
+		mutex.Lock()
+		value := datapointRequests / 2
+		datapointRequests++
+		mutex.Unlock()
+		_, _ = fmt.Fprintf(w, `{"%s":{"values":["%d"]}}`, models.EmptyUUID, value)
+	}))
+	t.Cleanup(server.Close)
+	return server, &datapointRequests
+}
+
+// TestSystemAccessPointConnectWebSocketPollingFallback tests that the datapoints are polled if the handshake fails and polling is allowed.
+func TestSystemAccessPointConnectWebSocketPollingFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, buf, _ := setupSysAp(t, false, false)
+	sysAp.clock = &fakeClock{}
+	sysAp.config.AllowPollingFallback = true
+	sysAp.config.PollingDatapoints = []models.DatapointKey{{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}}
+
+	server, _ := newBlockedWebSocketServer(t, http.StatusForbidden)
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	// Collect the updates until three distinct values were emitted
+	var updates []models.DatapointUpdate
+	sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		updates = append(updates, update)
+		if len(updates) == 3 {
+			cancel()
+		}
+	})
+
+	err := sysAp.ConnectWebSocket(ctx, 1, false, 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context cancelled error, got: %v", err)
+	}
+
+	// Unchanged values are not emitted again
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 updates, got %d", len(updates))
+	}
+	for i, update := range updates {
+		expected := models.DatapointUpdate{SysApID: models.EmptyUUID, Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: fmt.Sprint(i)}
+		update.Timestamp = expected.Timestamp
+		if update != expected {
+			t.Errorf("Expected update %+v, got %+v", expected, update)
+		}
+	}
+	if !strings.Contains(buf.String(), "web socket handshake failed, falling back to polling") {
+		t.Errorf("Expected polling fallback to be logged, got: %s", buf.String())
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketPollingFallbackDisabled tests that a failed handshake is a failed attempt without polling by default.
+func TestSystemAccessPointConnectWebSocketPollingFallbackDisabled(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, false, false)
+	sysAp.config.PollingDatapoints = []models.DatapointKey{{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}}
+
+	server, datapointRequests := newBlockedWebSocketServer(t, http.StatusForbidden)
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	err := sysAp.ConnectWebSocket(t.Context(), 1, false, 0)
+	if err == nil || err.Error() != "maximum reconnection attempts exceeded" {
+		t.Errorf("Expected maximum reconnection attempts error, got: %v", err)
+	}
+	if *datapointRequests != 0 {
+		t.Errorf("Expected no datapoint requests, got %d", *datapointRequests)
+	}
+	if strings.Contains(buf.String(), "falling back to polling") {
+		t.Errorf("Expected no polling fallback, got: %s", buf.String())
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketPollingFallbackUnauthorized tests that authentication failures do not fall back to polling.
+func TestSystemAccessPointConnectWebSocketPollingFallbackUnauthorized(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, false, false)
+	sysAp.config.AllowPollingFallback = true
+	sysAp.config.PollingDatapoints = []models.DatapointKey{{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}}
+
+	server, datapointRequests := newBlockedWebSocketServer(t, http.StatusUnauthorized)
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	err := sysAp.ConnectWebSocket(t.Context(), 1, false, 0)
+	if err == nil || err.Error() != "maximum reconnection attempts exceeded" {
+		t.Errorf("Expected maximum reconnection attempts error, got: %v", err)
+	}
+	if *datapointRequests != 0 {
+		t.Errorf("Expected no datapoint requests, got %d", *datapointRequests)
+	}
+}
+
+// TestIsWebSocketBlocked tests which handshake failures are considered as blocked web sockets.
+func TestIsWebSocketBlocked(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		resp     *http.Response
+		expected bool
+	}{
+		{name: "Forbidden by proxy", err: websocket.ErrBadHandshake, resp: &http.Response{StatusCode: http.StatusForbidden}, expected: true},
+		{name: "Bad gateway", err: websocket.ErrBadHandshake, resp: &http.Response{StatusCode: http.StatusBadGateway}, expected: true},
+		{name: "Unauthorized", err: websocket.ErrBadHandshake, resp: &http.Response{StatusCode: http.StatusUnauthorized}, expected: false},
+		{name: "No response", err: websocket.ErrBadHandshake, resp: nil, expected: false},
+		{name: "Network error", err: errors.New("connection refused"), resp: nil, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := isWebSocketBlocked(test.err, test.resp); actual != test.expected {
+				t.Errorf("Expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+// TestPollDatapointsOnceSkipsFailedDatapoints tests that datapoints that cannot be polled are skipped.
+func TestPollDatapointsOnceSkipsFailedDatapoints(t *testing.T) {
+	ws, buf, _ := setupSysApWebSocket(t, false, false)
+	ws.sysAp.clock = &fakeClock{}
+	ws.sysAp.config.PollingDatapoints = []models.DatapointKey{
+		{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"},
+		{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001"},
+		{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0002"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "odp0000"):
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		case strings.HasSuffix(r.URL.Path, "odp0001"):
+			_, _ = fmt.Fprintf(w, `{"%s":{"values":[]}}`, models.EmptyUUID)
+		default:
+			_, _ = fmt.Fprintf(w, `{"%s":{"values":["1"]}}`, models.EmptyUUID)
+		}
+	}))
+	defer server.Close()
+	ws.sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	var updates []models.DatapointUpdate
+	ws.sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		updates = append(updates, update)
+	})
+
+	values := map[models.DatapointKey]string{}
+	ws.pollDatapointsOnce(values)
+
+	if len(updates) != 1 || updates[0].Datapoint != "odp0002" || updates[0].Value != "1" {
+		t.Errorf("Expected a single update of odp0002, got %+v", updates)
+	}
+	if !strings.Contains(buf.String(), "polled datapoint response contains no value") {
+		t.Errorf("Expected missing value to be logged, got: %s", buf.String())
+	}
+}
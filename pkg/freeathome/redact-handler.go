@@ -0,0 +1,115 @@
+package freeathome
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const (
+	// redactedValue replaces secrets in log output
+	redactedValue = "****"
+	// minSecretLength is the minimum length of a secret to be redacted, shorter secrets would redact unrelated text
+	minSecretLength = 4
+)
+
+// basicAuthRegex matches a basic authentication header value with a base64 token of at least 8 characters, the token is only
+// redacted if it decodes to user:password credentials
+var basicAuthRegex = regexp.MustCompile(`(?i)(basic\s+)([a-z0-9+/]{8,}=*)`)
+
+// RedactHandler wraps a slog.Handler and replaces secrets in log messages and attributes before they reach the wrapped handler.
+// The configured secrets, e.g. the password, and the credentials of basic authentication header values are replaced with ****.
+// Secrets shorter than 4 characters are not redacted.
+type RedactHandler struct {
+	base     slog.Handler
+	replacer *strings.Replacer
+}
+
+// NewRedactHandler creates a slog handler that redacts the given secrets and basic authentication credentials. Secrets shorter
+// than 4 characters are ignored.
+func NewRedactHandler(base slog.Handler, secrets ...string) *RedactHandler {
+	var replacements []string
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			replacements = append(replacements, secret, redactedValue)
+		}
+	}
+
+	return &RedactHandler{
+		base:     base,
+		replacer: strings.NewReplacer(replacements...),
+	}
+}
+
+// Enabled checks if the wrapped handler is enabled for the given level
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+// Handle redacts the message and attributes of the record and passes it to the wrapped handler
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, h.redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.base.Handle(ctx, redacted)
+}
+
+// WithAttrs redacts the attributes and adds them to the wrapped handler
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	return &RedactHandler{
+		base:     h.base.WithAttrs(redacted),
+		replacer: h.replacer,
+	}
+}
+
+// WithGroup adds a group to the wrapped handler
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{
+		base:     h.base.WithGroup(name),
+		replacer: h.replacer,
+	}
+}
+
+// redact replaces the secrets and basic authentication credentials in the text
+func (h *RedactHandler) redact(text string) string {
+	return basicAuthRegex.ReplaceAllStringFunc(h.replacer.Replace(text), func(match string) string {
+		parts := basicAuthRegex.FindStringSubmatch(match)
+		credentials, err := base64.StdEncoding.DecodeString(parts[2])
+		if err != nil || !strings.Contains(string(credentials), ":") {
+			return match
+		}
+		return parts[1] + redactedValue
+	})
+}
+
+// redactAttr redacts the value of the attribute. Values that are not strings are formatted to find secrets,
+// they are only replaced by their redacted string representation if they contain a secret.
+func (h *RedactHandler) redactAttr(a slog.Attr) slog.Attr {
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, h.redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = h.redactAttr(member)
+		}
+		return slog.Group(a.Key, redacted...)
+	case slog.KindAny:
+		text := fmt.Sprint(value.Any())
+		if redacted := h.redact(text); redacted != text {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
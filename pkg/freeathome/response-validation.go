@@ -0,0 +1,85 @@
+package freeathome
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// findUncapturedFields returns the paths of all fields contained in the raw response body that are not captured by the
+// given deserialized model. The model is serialized again and both representations are compared as generic JSON values.
+// Fields with a null value are ignored, because they are omitted by optional model fields. Keys are matched
+// case-insensitively, like the JSON decoder does.
+func findUncapturedFields(body []byte, model any) ([]string, error) {
+	var raw any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	serialized, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	var known any
+	if err := json.Unmarshal(serialized, &known); err != nil {
+		return nil, err
+	}
+
+	fields := collectUncapturedFields(raw, known, "", nil)
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// collectUncapturedFields recursively compares the raw and known JSON values and appends the paths of uncaptured fields.
+func collectUncapturedFields(raw any, known any, path string, fields []string) []string {
+	switch rawValue := raw.(type) {
+	case map[string]any:
+		knownValue, _ := known.(map[string]any)
+		for key, item := range rawValue {
+			if item == nil {
+				continue
+			}
+			itemPath := joinFieldPath(path, key)
+			knownItem, ok := lookupField(knownValue, key)
+			if !ok {
+				fields = append(fields, itemPath)
+				continue
+			}
+			fields = collectUncapturedFields(item, knownItem, itemPath, fields)
+		}
+	case []any:
+		knownValue, _ := known.([]any)
+		for i, item := range rawValue {
+			if i >= len(knownValue) {
+				break
+			}
+			fields = collectUncapturedFields(item, knownValue[i], joinFieldPath(path, strconv.Itoa(i)), fields)
+		}
+	}
+
+	return fields
+}
+
+// lookupField looks up a field by key. Like the JSON decoder, it prefers an exact match but falls back to a case-insensitive match.
+func lookupField(object map[string]any, key string) (any, bool) {
+	if value, ok := object[key]; ok {
+		return value, true
+	}
+	for candidate, value := range object {
+		if strings.EqualFold(candidate, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// joinFieldPath appends a key to a dot separated field path.
+func joinFieldPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return fmt.Sprintf("%s.%s", path, key)
+}
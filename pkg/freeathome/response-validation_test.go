@@ -0,0 +1,102 @@
+package freeathome
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+const responseWithUnknownField = `{"00000000-0000-0000-0000-000000000000":{"values":["1"],"unit":"°C"}}`
+
+// TestFindUncapturedFields tests that fields not captured by the model are reported.
+func TestFindUncapturedFields(t *testing.T) {
+	model := models.GetDataPointResponse{
+		models.EmptyUUID: models.GetDataPoint{Values: []string{"1"}},
+	}
+
+	fields, err := findUncapturedFields([]byte(responseWithUnknownField), &model)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"00000000-0000-0000-0000-000000000000.unit"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, fields)
+	}
+}
+
+// TestFindUncapturedFieldsCaseInsensitiveAndNull tests that keys are matched case-insensitively and null values are ignored.
+func TestFindUncapturedFieldsCaseInsensitiveAndNull(t *testing.T) {
+	body := `{"00000000-0000-0000-0000-000000000000":{"devices":{"ABB7F595EC47":{"displayName":"Lamp","nativeID":"1","room":null}}}}`
+	displayName := "Lamp"
+	nativeID := "1"
+	model := models.DeviceResponse{
+		models.EmptyUUID: models.Devices{
+			Devices: map[string]models.Device{
+				"ABB7F595EC47": {DisplayName: &displayName, NativeID: &nativeID},
+			},
+		},
+	}
+
+	fields, err := findUncapturedFields([]byte(body), &model)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fields) != 0 {
+		t.Errorf("Expected no uncaptured fields, got %v", fields)
+	}
+}
+
+// TestFindUncapturedFieldsInvalidBody tests that an invalid body returns an error.
+func TestFindUncapturedFieldsInvalidBody(t *testing.T) {
+	_, err := findUncapturedFields([]byte("invalid json"), &models.GetDataPointResponse{})
+	if err == nil {
+		t.Error(expectedErrorGotNil)
+	}
+}
+
+// TestSystemAccessPointStrictResponseValidation tests that a warning names the uncaptured fields if strict response validation is enabled.
+func TestSystemAccessPointStrictResponseValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		strict        bool
+		expectWarning bool
+	}{
+		{name: "Strict response validation enabled", strict: true, expectWarning: true},
+		{name: "Strict response validation disabled", strict: false, expectWarning: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sysAp, buf, _ := setupSysAp(t, true, false)
+			sysAp.config.StrictResponseValidation = tt.strict
+			sysAp.config.Client.SetTransport(&MockRoundTripper{
+				Response: &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(responseWithUnknownField)),
+					Header:     make(http.Header),
+				},
+			})
+
+			result, err := sysAp.GetDatapoint("abcd1234", "ch0000", "odp0001")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (*result)[models.EmptyUUID].Values[0] != "1" {
+				t.Errorf("Expected datapoint value to be '1', got '%s'", (*result)[models.EmptyUUID].Values[0])
+			}
+
+			logOutput := buf.String()
+			hasWarning := strings.Contains(logOutput, "level=WARN") &&
+				strings.Contains(logOutput, "response contains fields not captured by the model") &&
+				strings.Contains(logOutput, "fields=00000000-0000-0000-0000-000000000000.unit")
+			if hasWarning != tt.expectWarning {
+				t.Errorf(unexpectedLogOutput, logOutput)
+			}
+		})
+	}
+}
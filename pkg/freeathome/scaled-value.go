@@ -0,0 +1,43 @@
+package freeathome
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// ScaledValue reads the datapoint and returns its numeric value, scaled by the scale configured for the datapoint in
+// Config.DatapointScales, and its unit. The unit of the scale takes precedence over the unit of the cached configuration.
+// Datapoints without a scale are returned unscaled, with the unit of the cached configuration if it was retrieved before.
+func (sysAp *SystemAccessPoint) ScaledValue(key models.DatapointKey) (float64, string, error) {
+	response, err := sysAp.GetDatapoint(key.Serial, key.Channel, key.Datapoint)
+	if err != nil {
+		return 0, "", err
+	}
+
+	datapoint, exists := (*response)[sysAp.GetUUID()]
+	if !exists || len(datapoint.Values) == 0 {
+		return 0, "", fmt.Errorf("datapoint %s has no value", key)
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(datapoint.Values[0]), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("datapoint %s has no numeric value: %q", key, datapoint.Values[0])
+	}
+
+	unit := ""
+	if configuration := sysAp.GetCachedConfiguration(); configuration != nil {
+		metadata, _ := configuration.DatapointMetadata(key)
+		unit = metadata.Unit
+	}
+	if scale, exists := sysAp.config.DatapointScales[key]; exists {
+		if scale.Factor != 0 {
+			value *= scale.Factor
+		}
+		if scale.Unit != "" {
+			unit = scale.Unit
+		}
+	}
+	return value, unit, nil
+}
@@ -0,0 +1,71 @@
+package freeathome
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestSystemAccessPointScaledValue tests that a configured scale is applied and unscaled datapoints pass through.
+func TestSystemAccessPointScaledValue(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+	respond := func(value string) {
+		body := `{"00000000-0000-0000-0000-000000000000":{"values":["` + value + `"]}}`
+		roundtripper.Response = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}
+	}
+
+	// The units are taken from the cached configuration unless the scale provides one
+	var configuration models.Configuration
+	if err := json.NewDecoder(loadTestResponseBody(t, "configuration_units.json")).Decode(&configuration); err != nil {
+		t.Fatalf("Failed to decode configuration: %v", err)
+	}
+	sysAp.cachedConfiguration = &configuration
+
+	measured := models.DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0010"}
+	target := models.DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "idp0016"}
+	sysAp.config.DatapointScales = map[models.DatapointKey]models.DatapointScale{
+		measured: {Factor: 0.1},
+		{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}: {Factor: 100, Unit: "%"},
+	}
+
+	tests := []struct {
+		name  string
+		key   models.DatapointKey
+		raw   string
+		value float64
+		unit  string
+	}{
+		{name: "Scaled with configuration unit", key: measured, raw: "215", value: 21.5, unit: "°C"},
+		{name: "Scaled with scale unit", key: models.DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}, raw: "0.5", value: 50, unit: "%"},
+		{name: "Unscaled", key: target, raw: "21.5", value: 21.5, unit: "°C"},
+		{name: "Unknown datapoint", key: models.DatapointKey{Serial: "ABB700000001", Channel: "ch0000", Datapoint: "odp0000"}, raw: " 3 ", value: 3, unit: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			respond(tt.raw)
+			value, unit, err := sysAp.ScaledValue(tt.key)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if math.Abs(value-tt.value) > 1e-9 {
+				t.Errorf("Expected value %v, got %v", tt.value, value)
+			}
+			if unit != tt.unit {
+				t.Errorf("Expected unit %q, got %q", tt.unit, unit)
+			}
+		})
+	}
+
+	// Values that are not numeric cannot be scaled
+	respond("on")
+	if _, _, err := sysAp.ScaledValue(measured); err == nil || !strings.Contains(err.Error(), "no numeric value") {
+		t.Errorf("Expected a non-numeric value error, got: %v", err)
+	}
+}
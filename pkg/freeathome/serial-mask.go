@@ -0,0 +1,150 @@
+package freeathome
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// maskedSerialLength is the number of hex digits of the keyed hash used in a masked serial
+const maskedSerialLength = 12
+
+// serialAttributeKeys are the keys of the log attributes containing device serials
+var serialAttributeKeys = map[string]bool{
+	"device": true,
+	"serial": true,
+}
+
+// datapointKeySerialRegex matches the serial of a datapoint key in a text, e.g. ABB7F595EC47 in ABB7F595EC47/ch0000/odp0000
+var datapointKeySerialRegex = regexp.MustCompile(`(?i)\b([0-9a-z]{12})([/.]ch[0-9a-f]{4})`)
+
+// quotedSerialRegex matches JSON strings of 12 alphanumeric characters, which are serials if they contain a digit, e.g. the
+// serials in raw web socket frames
+var quotedSerialRegex = regexp.MustCompile(`"([0-9A-Za-z]{12})"`)
+
+// SerialMasker pseudonymizes device serials, e.g. to share logs publicly. A masker always masks the same serial the same
+// way, while the masks of maskers with different keys cannot be correlated.
+type SerialMasker struct {
+	key []byte
+}
+
+// NewSerialMasker creates a serial masker with a random key, so that the masks are only stable for the lifetime of the masker.
+func NewSerialMasker() *SerialMasker {
+	key := make([]byte, sha256.Size)
+	_, _ = rand.Read(key)
+	return NewSerialMaskerWithKey(key)
+}
+
+// NewSerialMaskerWithKey creates a serial masker with the given key, e.g. to keep the masks stable across sessions.
+func NewSerialMaskerWithKey(key []byte) *SerialMasker {
+	return &SerialMasker{key: key}
+}
+
+// Mask returns the pseudonym of the serial, derived from a keyed hash of the serial. A nil masker returns the serial unchanged.
+func (m *SerialMasker) Mask(serial string) string {
+	if m == nil {
+		return serial
+	}
+
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(serial))
+	return "masked-" + hex.EncodeToString(mac.Sum(nil))[:maskedSerialLength]
+}
+
+// maskText masks the serials of datapoint keys and the serials quoted as JSON strings in the text, e.g. in a datapoint key
+// attribute or a raw web socket frame
+func (m *SerialMasker) maskText(text string) string {
+	text = datapointKeySerialRegex.ReplaceAllStringFunc(text, func(match string) string {
+		parts := datapointKeySerialRegex.FindStringSubmatch(match)
+		return m.Mask(parts[1]) + parts[2]
+	})
+	return quotedSerialRegex.ReplaceAllStringFunc(text, func(match string) string {
+		serial := strings.Trim(match, `"`)
+		if !strings.ContainsAny(serial, "0123456789") {
+			return match
+		}
+		return `"` + m.Mask(serial) + `"`
+	})
+}
+
+// SerialMaskHandler wraps a slog.Handler and masks the device serials before they reach the wrapped handler. The device and
+// serial attributes are masked as a whole, other attributes are masked where they contain datapoint keys, e.g.
+// ABB7F595EC47/ch0000/odp0000, or serials quoted as JSON strings, e.g. in raw web socket frames.
+type SerialMaskHandler struct {
+	base   slog.Handler
+	masker *SerialMasker
+}
+
+// NewSerialMaskHandler creates a slog handler that masks the device serials with the given masker.
+func NewSerialMaskHandler(base slog.Handler, masker *SerialMasker) *SerialMaskHandler {
+	return &SerialMaskHandler{
+		base:   base,
+		masker: masker,
+	}
+}
+
+// Enabled checks if the wrapped handler is enabled for the given level
+func (h *SerialMaskHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+// Handle masks the serial attributes of the record and passes it to the wrapped handler
+func (h *SerialMaskHandler) Handle(ctx context.Context, r slog.Record) error {
+	masked := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		masked.AddAttrs(h.maskAttr(a))
+		return true
+	})
+	return h.base.Handle(ctx, masked)
+}
+
+// WithAttrs masks the serial attributes and adds them to the wrapped handler
+func (h *SerialMaskHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		masked[i] = h.maskAttr(a)
+	}
+	return &SerialMaskHandler{
+		base:   h.base.WithAttrs(masked),
+		masker: h.masker,
+	}
+}
+
+// WithGroup adds a group to the wrapped handler
+func (h *SerialMaskHandler) WithGroup(name string) slog.Handler {
+	return &SerialMaskHandler{
+		base:   h.base.WithGroup(name),
+		masker: h.masker,
+	}
+}
+
+// maskAttr masks the value of a serial attribute and the serials contained in other attributes. Values that are not strings
+// are formatted to find serials, they are only replaced by their masked string representation if they contain a serial.
+func (h *SerialMaskHandler) maskAttr(a slog.Attr) slog.Attr {
+	value := a.Value.Resolve()
+	switch {
+	case value.Kind() == slog.KindGroup:
+		group := value.Group()
+		masked := make([]any, len(group))
+		for i, member := range group {
+			masked[i] = h.maskAttr(member)
+		}
+		return slog.Group(a.Key, masked...)
+	case serialAttributeKeys[a.Key] && value.Kind() == slog.KindString:
+		return slog.String(a.Key, h.masker.Mask(value.String()))
+	case value.Kind() == slog.KindString:
+		return slog.String(a.Key, h.masker.maskText(value.String()))
+	case value.Kind() == slog.KindAny:
+		text := fmt.Sprint(value.Any())
+		if masked := h.masker.maskText(text); masked != text {
+			return slog.String(a.Key, masked)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
@@ -0,0 +1,99 @@
+package freeathome
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSerialMaskerConsistentPerSerial(t *testing.T) {
+	masker := NewSerialMasker()
+
+	first := masker.Mask("ABB7F595EC47")
+	if first == "ABB7F595EC47" || !strings.HasPrefix(first, "masked-") {
+		t.Errorf("Expected a masked serial, got %s", first)
+	}
+	if second := masker.Mask("ABB7F595EC47"); second != first {
+		t.Errorf("Expected the same serial to be masked the same way, got %s and %s", first, second)
+	}
+	if other := masker.Mask("ABB700000001"); other == first {
+		t.Errorf("Expected different serials to be masked differently, got %s for both", other)
+	}
+}
+
+func TestSerialMaskerKeys(t *testing.T) {
+	if NewSerialMasker().Mask("ABB7F595EC47") == NewSerialMasker().Mask("ABB7F595EC47") {
+		t.Error("Expected maskers with random keys to mask differently")
+	}
+
+	key := []byte("session key")
+	if NewSerialMaskerWithKey(key).Mask("ABB7F595EC47") != NewSerialMaskerWithKey(key).Mask("ABB7F595EC47") {
+		t.Error("Expected maskers with the same key to mask the same way")
+	}
+
+	var masker *SerialMasker
+	if masked := masker.Mask("ABB7F595EC47"); masked != "ABB7F595EC47" {
+		t.Errorf("Expected a nil masker to return the serial unchanged, got %s", masked)
+	}
+}
+
+func TestSerialMaskHandler(t *testing.T) {
+	var buf bytes.Buffer
+	masker := NewSerialMasker()
+	logger := slog.New(NewSerialMaskHandler(slog.NewTextHandler(&buf, nil), masker))
+
+	logger.With("serial", "ABB700000001").Info("data point update",
+		"device", "ABB7F595EC47",
+		"channel", "ch0000",
+		slog.Group("update", "device", "ABB7F595EC47"),
+	)
+
+	output := buf.String()
+	if strings.Contains(output, "ABB7F595EC47") || strings.Contains(output, "ABB700000001") {
+		t.Errorf("Expected the serials to be masked, got: %s", output)
+	}
+	for _, expected := range []string{
+		"serial=" + masker.Mask("ABB700000001"),
+		"device=" + masker.Mask("ABB7F595EC47"),
+		"update.device=" + masker.Mask("ABB7F595EC47"),
+		"channel=ch0000",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+func TestSerialMaskHandlerDatapointKeysAndFrames(t *testing.T) {
+	var buf bytes.Buffer
+	masker := NewSerialMasker()
+	logger := slog.New(NewSerialMaskHandler(slog.NewTextHandler(&buf, nil), masker))
+
+	logger.Info("polled datapoint",
+		"datapoint", "ABB7F595EC47/ch0000/odp0000",
+		"frame", `{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB700000001/ch0000/odp0000":"1"},"devicesAdded":["6000D2CB27B2"]}}`,
+		"error", errors.New("failed to set ABB7F595EC47.ch0000.idp0000"),
+		"value", "devicesAdded",
+	)
+
+	output := buf.String()
+	for _, serial := range []string{"ABB7F595EC47", "ABB700000001", "6000D2CB27B2"} {
+		if strings.Contains(output, serial) {
+			t.Errorf("Expected serial %s to be masked, got: %s", serial, output)
+		}
+	}
+	for _, expected := range []string{
+		"datapoint=" + masker.Mask("ABB7F595EC47") + "/ch0000/odp0000",
+		masker.Mask("ABB700000001") + "/ch0000/odp0000",
+		`[\"` + masker.Mask("6000D2CB27B2") + `\"]`,
+		"failed to set " + masker.Mask("ABB7F595EC47") + ".ch0000.idp0000",
+		"value=devicesAdded",
+		"00000000-0000-0000-0000-000000000000",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package freeathome
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// operationCounters counts the successful and failed calls of an operation.
+type operationCounters struct {
+	successes atomic.Uint64
+	failures  atomic.Uint64
+}
+
+// operationStats counts the calls per operation. The counters are created on first use and updated atomically.
+type operationStats struct {
+	// counters maps the operations to their *operationCounters
+	counters sync.Map
+}
+
+// record counts a call of the operation as failed if err is not nil, otherwise as successful.
+func (s *operationStats) record(operation models.Operation, err error) {
+	value, _ := s.counters.LoadOrStore(operation, &operationCounters{})
+	counters := value.(*operationCounters)
+	if err != nil {
+		counters.failures.Add(1)
+	} else {
+		counters.successes.Add(1)
+	}
+}
+
+// snapshot returns the current counts of all operations that were called.
+func (s *operationStats) snapshot() models.ClientStats {
+	stats := models.ClientStats{}
+	s.counters.Range(func(key, value any) bool {
+		counters := value.(*operationCounters)
+		stats[key.(models.Operation)] = models.OperationStats{
+			Successes: counters.successes.Load(),
+			Failures:  counters.failures.Load(),
+		}
+		return true
+	})
+	return stats
+}
+
+// Stats returns the number of successful and failed calls per operation since the client was created,
+// e.g. to surface error rates without wrapping every call.
+func (sysAp *SystemAccessPoint) Stats() models.ClientStats {
+	return sysAp.stats.snapshot()
+}
+
+// datapointCounts counts the datapoint updates received per device. If the number of devices exceeds the maximum number
+// of entries of the counts, the count of the least recently updated device is dropped.
+type datapointCounts struct {
+	// counts maps the device serials to their number of datapoint updates
+	counts lruMap[string, uint64]
+	// mutex protects access to counts
+	mutex sync.Mutex
+}
+
+// record counts a datapoint update of the device with the given serial.
+func (c *datapointCounts) record(serial string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	count, _ := c.counts.get(serial)
+	c.counts.set(serial, count+1)
+}
+
+// snapshot returns the current counts of all devices that sent datapoint updates.
+func (c *datapointCounts) snapshot() map[string]uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	counts := make(map[string]uint64, c.counts.len())
+	c.counts.all(func(serial string, count uint64) {
+		counts[serial] = count
+	})
+	return counts
+}
+
+// DatapointCounts returns the number of datapoint updates received from the web socket per device serial since the client
+// was created, e.g. to identify a device flooding the stream. At most Config.StateMapMaxEntries devices are counted.
+func (sysAp *SystemAccessPoint) DatapointCounts() map[string]uint64 {
+	return sysAp.datapointCounts.snapshot()
+}
@@ -0,0 +1,152 @@
+package freeathome
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestSystemAccessPointStats tests that the counters increment for successful and failed calls of each operation.
+func TestSystemAccessPointStats(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	if stats := sysAp.Stats(); len(stats) != 0 {
+		t.Errorf("Expected no stats before the first call, got %v", stats)
+	}
+
+	respond := func(status int, body string) {
+		roundtripper.Response = &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}
+		roundtripper.Err = nil
+	}
+
+	// Two successful and two failed device list calls
+	respond(http.StatusOK, `{}`)
+	_, _ = sysAp.GetDeviceList()
+	respond(http.StatusOK, `{}`)
+	_, _ = sysAp.GetDeviceList()
+	respond(http.StatusInternalServerError, "Internal Server Error")
+	_, _ = sysAp.GetDeviceList()
+	respond(http.StatusOK, `not json`)
+	_, _ = sysAp.GetDeviceList()
+
+	// A failed request and a successful datapoint call
+	roundtripper.Err = errors.New("connection refused")
+	_, _ = sysAp.GetDatapoint("ABB7F595EC47", "ch0000", "odp0000")
+	respond(http.StatusOK, `{}`)
+	_, _ = sysAp.SetDatapoint("ABB7F595EC47", "ch0000", "idp0000", "1")
+
+	// A write blocked in read-only mode
+	sysAp.config.ReadOnly = true
+	_, _ = sysAp.SetDatapoint("ABB7F595EC47", "ch0000", "idp0000", "1")
+
+	expected := models.ClientStats{
+		models.OperationDeviceList:   {Successes: 2, Failures: 2},
+		models.OperationDatapointGet: {Failures: 1},
+		models.OperationDatapointSet: {Successes: 1, Failures: 1},
+	}
+	if stats := sysAp.Stats(); !maps.Equal(stats, expected) {
+		t.Errorf("Expected stats %v, got %v", expected, stats)
+	}
+}
+
+// TestSystemAccessPointStatsConcurrent tests that concurrent calls are counted without losing increments.
+func TestSystemAccessPointStatsConcurrent(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+
+	var waitGroup sync.WaitGroup
+	for i := range 50 {
+		waitGroup.Go(func() {
+			var err error
+			if i%5 == 0 {
+				err = errors.New("test error")
+			}
+			for range 20 {
+				sysAp.stats.record(models.OperationConfiguration, err)
+			}
+		})
+	}
+	waitGroup.Wait()
+
+	expected := models.OperationStats{Successes: 800, Failures: 200}
+	if stats := sysAp.Stats()[models.OperationConfiguration]; stats != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+// TestSystemAccessPointDatapointCounts tests that the datapoints of each web socket message are logged and counted per device.
+func TestSystemAccessPointDatapointCounts(t *testing.T) {
+	sysAp, _, records := setupSysAp(t, true, false)
+
+	if counts := sysAp.DatapointCounts(); len(counts) != 0 {
+		t.Errorf("Expected no datapoint counts before the first message, got %v", counts)
+	}
+
+	messages := []string{
+		`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"1","ABB7F595EC47/ch0000/odp0001":"0","ABB700000001/ch0000/odp0000":"1"}}}`,
+		`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"0"}}}`,
+		`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0001/odp0000":"1","invalid":"1"}}}`,
+	}
+	var logged []int64
+	for _, message := range messages {
+		sysAp.ProcessMessage([]byte(message))
+		for len(records) > 0 {
+			record := <-records
+			if record.Message != "processing web socket message" {
+				continue
+			}
+			if record.Level != slog.LevelDebug {
+				t.Errorf("Expected debug level, got %s", record.Level)
+			}
+			record.Attrs(func(attr slog.Attr) bool {
+				if attr.Key == "datapoints" {
+					logged = append(logged, attr.Value.Int64())
+				}
+				return true
+			})
+		}
+	}
+
+	// The per-message count includes invalid keys, which are not attributed to a device
+	if !slices.Equal(logged, []int64{3, 1, 2}) {
+		t.Errorf("Expected the datapoint counts 3, 1 and 2 to be logged, got %v", logged)
+	}
+
+	expected := map[string]uint64{
+		"ABB7F595EC47": 4,
+		"ABB700000001": 1,
+	}
+	if counts := sysAp.DatapointCounts(); !maps.Equal(counts, expected) {
+		t.Errorf("Expected datapoint counts %v, got %v", expected, counts)
+	}
+}
+
+// TestSystemAccessPointDatapointCountsMaxEntries tests that only the most recently updated devices are counted if the
+// number of state entries is limited.
+func TestSystemAccessPointDatapointCountsMaxEntries(t *testing.T) {
+	config := NewConfig("localhost", "user", "password")
+	config.Logger = NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+	config.StateMapMaxEntries = 2
+	sysAp := MustNewSystemAccessPoint(config)
+
+	for _, serial := range []string{"ABB700000001", "ABB700000002", "ABB700000001", "ABB700000003"} {
+		sysAp.ProcessMessage([]byte(`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"` + serial + `/ch0000/odp0000":"1"}}}`))
+	}
+
+	expected := map[string]uint64{
+		"ABB700000001": 2,
+		"ABB700000003": 1,
+	}
+	if counts := sysAp.DatapointCounts(); !maps.Equal(counts, expected) {
+		t.Errorf("Expected datapoint counts %v, got %v", expected, counts)
+	}
+}
@@ -0,0 +1,91 @@
+package freeathome
+
+import (
+	"sync"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// subscriptionBufferSize is the number of updates buffered per subscriber, further updates are dropped until the
+// subscriber catches up
+const subscriptionBufferSize = 16
+
+// subscriptions fans the datapoint updates out to the subscribers of the updated device
+type subscriptions struct {
+	// subscribers contains the channels of the subscribers, identified by the serial of the subscribed device
+	subscribers map[string]map[chan models.DatapointUpdate]struct{}
+	// mutex protects access to subscribers. It is held while sending, so that a channel cannot be closed during a send.
+	mutex sync.Mutex
+}
+
+// add registers a new subscriber of the device and returns its channel
+func (s *subscriptions) add(serial string) chan models.DatapointUpdate {
+	updates := make(chan models.DatapointUpdate, subscriptionBufferSize)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.subscribers == nil {
+		s.subscribers = map[string]map[chan models.DatapointUpdate]struct{}{}
+	}
+	if s.subscribers[serial] == nil {
+		s.subscribers[serial] = map[chan models.DatapointUpdate]struct{}{}
+	}
+	s.subscribers[serial][updates] = struct{}{}
+	return updates
+}
+
+// remove unregisters the subscriber and closes its channel, if that has not happened yet
+func (s *subscriptions) remove(serial string, updates chan models.DatapointUpdate) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, exists := s.subscribers[serial][updates]; !exists {
+		return
+	}
+	delete(s.subscribers[serial], updates)
+	if len(s.subscribers[serial]) == 0 {
+		delete(s.subscribers, serial)
+	}
+	close(updates)
+}
+
+// active reports whether there is at least one subscriber
+func (s *subscriptions) active() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.subscribers) > 0
+}
+
+// publish sends the update to the subscribers of its device and returns the number of subscribers whose buffer was full
+func (s *subscriptions) publish(update models.DatapointUpdate) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	dropped := 0
+	for updates := range s.subscribers[update.Serial] {
+		select {
+		case updates <- update:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Subscribe returns a channel receiving the datapoint updates of the device with the given serial, in addition to the
+// datapoint handler. Updates are debounced like for the datapoint handler. If the subscriber does not keep up, updates are
+// dropped once its buffer is full. The returned function unsubscribes, which stops the delivery and closes the channel.
+// It may be called more than once.
+func (sysAp *SystemAccessPoint) Subscribe(serial string) (<-chan models.DatapointUpdate, func()) {
+	updates := sysAp.subscriptions.add(serial)
+	return updates, func() {
+		sysAp.subscriptions.remove(serial, updates)
+	}
+}
+
+// publishDatapointUpdate passes a datapoint update to the datapoint handler and the subscribers of its device.
+func (sysAp *SystemAccessPoint) publishDatapointUpdate(update models.DatapointUpdate) {
+	if handler := sysAp.datapointHandler(); handler != nil {
+		handler(update)
+	}
+	if dropped := sysAp.subscriptions.publish(update); dropped > 0 {
+		sysAp.config.Logger.Warn("subscriber buffer full, dropping datapoint update", "serial", update.Serial, "subscribers", dropped)
+	}
+}
@@ -0,0 +1,90 @@
+package freeathome
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestSystemAccessPointSubscribe tests that a subscriber receives the updates of its device only.
+func TestSystemAccessPointSubscribe(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	updates, unsubscribe := sysAp.Subscribe("ABB7F595EC47")
+	defer unsubscribe()
+
+	sysAp.emitDatapointUpdate(models.DatapointUpdate{Serial: "ABB700000001", Channel: "ch0000", Datapoint: "odp0000", Value: "0"})
+	sysAp.emitDatapointUpdate(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+
+	select {
+	case update := <-updates:
+		if update.Serial != "ABB7F595EC47" || update.Value != "1" {
+			t.Errorf("Expected the update of the subscribed device, got %+v", update)
+		}
+	default:
+		t.Fatal("Expected an update of the subscribed device")
+	}
+	select {
+	case update := <-updates:
+		t.Errorf("Expected no further update, got %+v", update)
+	default:
+	}
+}
+
+// TestSystemAccessPointSubscribeWithHandler tests that the datapoint handler still receives all updates next to the subscribers.
+func TestSystemAccessPointSubscribeWithHandler(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	handled := 0
+	sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		handled++
+	})
+	first, unsubscribeFirst := sysAp.Subscribe("ABB7F595EC47")
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := sysAp.Subscribe("ABB7F595EC47")
+	defer unsubscribeSecond()
+
+	sysAp.emitDatapointUpdate(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+
+	if handled != 1 {
+		t.Errorf("Expected the datapoint handler to be called once, got %d", handled)
+	}
+	if len(first) != 1 || len(second) != 1 {
+		t.Errorf("Expected every subscriber to receive the update, got %d and %d", len(first), len(second))
+	}
+}
+
+// TestSystemAccessPointUnsubscribe tests that unsubscribing closes the channel and stops the delivery.
+func TestSystemAccessPointUnsubscribe(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	updates, unsubscribe := sysAp.Subscribe("ABB7F595EC47")
+
+	unsubscribe()
+	// Unsubscribing again must not panic
+	unsubscribe()
+
+	if _, ok := <-updates; ok {
+		t.Error("Expected the channel to be closed")
+	}
+	sysAp.emitDatapointUpdate(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+	if sysAp.subscriptions.active() {
+		t.Error("Expected no active subscriptions")
+	}
+}
+
+// TestSystemAccessPointSubscribeBufferFull tests that updates are dropped with a warning if a subscriber does not keep up.
+func TestSystemAccessPointSubscribeBufferFull(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, true, false)
+	updates, unsubscribe := sysAp.Subscribe("ABB7F595EC47")
+	defer unsubscribe()
+
+	for range subscriptionBufferSize + 1 {
+		sysAp.emitDatapointUpdate(models.DatapointUpdate{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000", Value: "1"})
+	}
+
+	if len(updates) != subscriptionBufferSize {
+		t.Errorf("Expected %d buffered updates, got %d", subscriptionBufferSize, len(updates))
+	}
+	if logOutput := buf.String(); !strings.Contains(logOutput, "level=WARN") || !strings.Contains(logOutput, "subscriber buffer full") {
+		t.Errorf(unexpectedLogOutput, logOutput)
+	}
+}
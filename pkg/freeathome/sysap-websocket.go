@@ -1,13 +1,17 @@
 package freeathome
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,9 +19,16 @@ import (
 	"github.com/pgerke/freeathome/v2/pkg/models"
 )
 
+// defaultMessageBufferSize is used if no positive message buffer size is configured
+const defaultMessageBufferSize = 10
+
+// messageBufferFullWarningInterval is the minimum time between two warnings about a full message buffer
+const messageBufferFullWarningInterval = time.Minute
+
 type connection interface {
 	ReadMessage() (messageType int, p []byte, err error)
 	WriteControl(messageType int, data []byte, deadline time.Time) error
+	Close() error
 }
 
 // SystemAccessPointWebSocket represents a web socket connection to a system access point.
@@ -28,14 +39,51 @@ type SystemAccessPointWebSocket struct {
 	waitGroup sync.WaitGroup
 	// onMessageHandled is a callback function that is called when a message is handled.
 	onMessageHandled func()
+	// handlersMutex protects access to onMessageHandled
+	handlersMutex sync.RWMutex
 	// reconnectionAttempts tracks the number of failed reconnection attempts
 	reconnectionAttempts int
 	// maxReconnectionAttempts is the maximum number of reconnection attempts before giving up
 	maxReconnectionAttempts int
 	// exponentialBackoff controls whether exponential backoff is used between reconnection attempts
 	exponentialBackoff bool
-	// reconnectionMutex protects access to reconnectionAttempts
+	// firstFailureAt is the time of the first failed attempt since the last stable connection, zero if there was none
+	firstFailureAt time.Time
+	// backoff computes the delays between failed reconnection attempts
+	backoff *Backoff
+	// reconnectionMutex protects access to reconnectionAttempts, firstFailureAt and backoff
 	reconnectionMutex sync.Mutex
+	// activeConnection is the currently established web socket connection, nil if there is none
+	activeConnection connection
+	// connectionMutex protects access to activeConnection
+	connectionMutex sync.Mutex
+	// abandoned is closed when the shutdown timeout elapsed and the remaining goroutines are abandoned
+	abandoned chan struct{}
+	// lastBufferFullWarning is the time the last full message buffer warning was logged, only accessed by the message loop
+	lastBufferFullWarning time.Time
+	// suppressedBufferFullWarnings counts the full message buffer warnings suppressed since the last warning, only accessed by the message loop
+	suppressedBufferFullWarnings int
+	// span records the connection lifecycle events as span events
+	span models.Span
+	// pendingDisconnect is the cause of the disconnect recorded while the connection is being closed, e.g. by a failed ping
+	pendingDisconnect *DisconnectError
+	// lastDisconnect is the cause of the last lost connection, which is reported by the next connection attempt.
+	// It is only accessed by the connection loop.
+	lastDisconnect *DisconnectError
+}
+
+// setMessageHandledHandler registers a callback function that is called whenever a message was handled.
+func (ws *SystemAccessPointWebSocket) setMessageHandledHandler(handler func()) {
+	ws.handlersMutex.Lock()
+	defer ws.handlersMutex.Unlock()
+	ws.onMessageHandled = handler
+}
+
+// addSpanEvent adds a connection lifecycle event to the span of the web socket, if there is one.
+func (ws *SystemAccessPointWebSocket) addSpanEvent(name string, attrs ...any) {
+	if ws.span != nil {
+		ws.span.AddEvent(name, attrs...)
+	}
 }
 
 // GetWebSocketUrl constructs a WebSocket URL string for the SystemAccessPoint.
@@ -46,23 +94,52 @@ func (ws *SystemAccessPointWebSocket) getWebSocketUrl() string {
 	} else {
 		protocol = "ws"
 	}
-	return fmt.Sprintf("%s://%s/fhapi/v1/api/ws", protocol, ws.sysAp.config.Hostname)
+	return fmt.Sprintf("%s://%s/fhapi/%s/api/ws", protocol, ws.sysAp.GetHostName(), apiVersion(ws.sysAp.config))
 }
 
 // ConnectWebSocket establishes a web socket connection to the system access point.
-func (sysAp *SystemAccessPoint) ConnectWebSocket(ctx context.Context, maxReconnectionAttempts int, exponentialBackoff bool, keepaliveInterval time.Duration) error {
+// A keepalive interval of zero or less disables sending keepalive ping messages.
+func (sysAp *SystemAccessPoint) ConnectWebSocket(ctx context.Context, maxReconnectionAttempts int, exponentialBackoff bool, keepaliveInterval time.Duration) (err error) {
+	// Trace the connection lifecycle until the connection attempts stop
+	span := sysAp.startSpan("ConnectWebSocket")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
 	// Create a new web socket connection
 	ws := SystemAccessPointWebSocket{
+		span:                    span,
 		sysAp:                   sysAp,
 		waitGroup:               sync.WaitGroup{},
 		maxReconnectionAttempts: maxReconnectionAttempts,
 		exponentialBackoff:      exponentialBackoff,
+		backoff:                 NewBackoff(reconnectionBackoffBase, reconnectionBackoffMax, 0),
 		reconnectionMutex:       sync.Mutex{},
 		reconnectionAttempts:    0,
+		abandoned:               make(chan struct{}),
 	}
 
-	// Wait for all processes to finish before returning
-	defer ws.waitGroup.Wait()
+	// Track the uptime from the first connection attempt
+	sysAp.uptime.begin(sysAp.clock.Now())
+
+	// Log a heartbeat while the connection attempts are running
+	if sysAp.config.HeartbeatInterval > 0 {
+		stopHeartbeat := sysAp.startHeartbeat(sysAp.config.HeartbeatInterval)
+		defer stopHeartbeat()
+	}
+
+	// Wait for all processes to finish before returning. Once the context is cancelled,
+	// the wait is bounded by the shutdown timeout. Updates pending debouncing are passed on afterwards.
+	finished := make(chan struct{})
+	go ws.enforceShutdownTimeout(ctx, finished)
+	defer func() {
+		ws.waitForGoroutines()
+		close(finished)
+		sysAp.flushDatapointUpdates()
+	}()
 
 	// Start the connection loop
 	for {
@@ -72,9 +149,15 @@ func (sysAp *SystemAccessPoint) ConnectWebSocket(ctx context.Context, maxReconne
 			ws.sysAp.config.Logger.Log("context cancelled, stopping web socket connection attempts")
 			return ctx.Err()
 		default:
+			// Wait while the connection attempts are paused
+			if !ws.waitWhileReconnectionPaused(ctx) {
+				continue
+			}
+
 			// Check if we've exceeded the maximum reconnection attempts
 			ws.reconnectionMutex.Lock()
 			currentAttempts := ws.reconnectionAttempts
+			firstFailureAt := ws.firstFailureAt
 			ws.reconnectionMutex.Unlock()
 
 			if currentAttempts >= ws.maxReconnectionAttempts {
@@ -82,86 +165,186 @@ func (sysAp *SystemAccessPoint) ConnectWebSocket(ctx context.Context, maxReconne
 				return errors.New("maximum reconnection attempts exceeded")
 			}
 
+			// Check if we've exceeded the maximum reconnection duration since the first failure
+			maxDuration := ws.sysAp.config.MaxReconnectDuration
+			if maxDuration > 0 && !firstFailureAt.IsZero() {
+				if elapsed := ws.sysAp.clock.Now().Sub(firstFailureAt); elapsed >= maxDuration {
+					ws.sysAp.config.Logger.Error("maximum reconnection duration exceeded", "elapsed", elapsed, "max", maxDuration, "attempts", currentAttempts)
+					return errors.New("maximum reconnection duration exceeded")
+				}
+			}
+
 			// Attempt to establish a web socket connection
 			ws.webSocketConnectionLoop(ctx, keepaliveInterval)
 		}
 	}
 }
 
+// waitWhileReconnectionPaused blocks while the connection attempts are paused. The paused time is not counted towards
+// the maximum reconnection duration. It returns false if the context was cancelled while waiting.
+func (ws *SystemAccessPointWebSocket) waitWhileReconnectionPaused(ctx context.Context) bool {
+	ws.sysAp.reconnectionPauseMutex.Lock()
+	paused, resumed := ws.sysAp.reconnectionPaused, ws.sysAp.reconnectionResumed
+	ws.sysAp.reconnectionPauseMutex.Unlock()
+	if !paused {
+		return true
+	}
+
+	ws.sysAp.config.Logger.Log("web socket connection attempts paused")
+	pausedAt := ws.sysAp.clock.Now()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-resumed:
+	}
+
+	ws.reconnectionMutex.Lock()
+	if !ws.firstFailureAt.IsZero() {
+		ws.firstFailureAt = ws.firstFailureAt.Add(ws.sysAp.clock.Now().Sub(pausedAt))
+	}
+	ws.reconnectionMutex.Unlock()
+	ws.sysAp.config.Logger.Log("web socket connection attempts resumed")
+	return true
+}
+
+// enforceShutdownTimeout closes the active connection if the web socket does not shut down within the shutdown timeout
+// after the context was cancelled. This unblocks a pending read, goroutines that are still stuck afterwards are abandoned.
+func (ws *SystemAccessPointWebSocket) enforceShutdownTimeout(ctx context.Context, finished <-chan struct{}) {
+	select {
+	case <-finished:
+		return
+	case <-ctx.Done():
+	}
+
+	timeout := ws.sysAp.config.ShutdownTimeout
+	if timeout <= 0 {
+		return
+	}
+
+	select {
+	case <-finished:
+	case <-ws.sysAp.clock.After(timeout):
+		ws.sysAp.config.Logger.Warn("web socket shutdown timed out, closing connection and abandoning remaining goroutines", "timeout", timeout)
+		ws.connectionMutex.Lock()
+		if ws.activeConnection != nil {
+			_ = ws.activeConnection.Close()
+		}
+		ws.connectionMutex.Unlock()
+		close(ws.abandoned)
+	}
+}
+
+// waitForGoroutines waits until all goroutines have finished or have been abandoned after the shutdown timeout.
+func (ws *SystemAccessPointWebSocket) waitForGoroutines() {
+	done := make(chan struct{})
+	go func() {
+		ws.waitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ws.abandoned:
+	}
+}
+
+// setActiveConnection sets the currently established web socket connection and resets the recorded disconnect cause.
+func (ws *SystemAccessPointWebSocket) setActiveConnection(conn connection) {
+	ws.connectionMutex.Lock()
+	defer ws.connectionMutex.Unlock()
+	ws.activeConnection = conn
+	ws.pendingDisconnect = nil
+}
+
+// newDialer creates the dialer for the web socket connection from the default dialer according to the configuration.
+func (ws *SystemAccessPointWebSocket) newDialer() *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = ws.sysAp.config.EnableCompression
+	if ws.sysAp.config.TLSEnabled && ws.sysAp.config.SkipTLSVerify {
+		dialer.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &dialer
+}
+
 // webSocketConnectionLoop establishes a web socket connection and starts the message loop.
 func (ws *SystemAccessPointWebSocket) webSocketConnectionLoop(ctx context.Context, keepaliveInterval time.Duration) {
 	// Add a wait group to ensure all processes are finished before returning
 	ws.waitGroup.Add(1)
 	defer ws.waitGroup.Done()
 
-	// Create a custom dialer for WebSocket connection
-	dialer := websocket.DefaultDialer
-	if ws.sysAp.config.TLSEnabled && ws.sysAp.config.SkipTLSVerify {
-		dialer = &websocket.Dialer{
-			Proxy:            http.ProxyFromEnvironment,
-			HandshakeTimeout: 45 * time.Second,
-			TLSClientConfig:  &tls.Config{InsecureSkipVerify: true},
+	// Report why the connection is re-established if the previous connection was lost
+	if reason := ws.lastDisconnect; reason != nil {
+		ws.lastDisconnect = nil
+		ws.addSpanEvent("reconnecting", "cause", string(reason.Cause), "error", reason.Err.Error())
+		ws.sysAp.config.Logger.Log("reconnecting to web socket", "cause", reason.Cause, "error", reason.Err)
+		if handler := ws.sysAp.reconnectingHandler(); handler != nil {
+			handler(reason)
+		}
+	}
+
+	// Obtain the current credentials, which may have been rotated since the last attempt, unless authentication is disabled
+	header := http.Header{}
+	if !ws.sysAp.config.DisableAuth {
+		username, password, err := ws.sysAp.credentials(ctx)
+		if err != nil {
+			ws.sysAp.emitError(err)
+			ws.addSpanEvent("connection failed", "error", err.Error())
+			ws.registerFailedConnection(ctx, "failed to obtain web socket credentials", err)
+			return
 		}
+		header.Set(authHeaderName(ws.sysAp.config), basicAuthorization(username, password))
 	}
 
 	// Create a new web socket connection
-	basicAuth := base64.StdEncoding.EncodeToString(fmt.Appendf(nil, "%s:%s", ws.sysAp.config.Client.UserInfo.Username, ws.sysAp.config.Client.UserInfo.Password))
-	conn, _, err := dialer.Dial(ws.getWebSocketUrl(), http.Header{
-		"Authorization": []string{fmt.Sprintf("Basic %s", basicAuth)},
-	})
+	conn, resp, err := ws.newDialer().Dial(ws.getWebSocketUrl(), header)
 
 	// Check for errors
 	if err != nil {
-		// Safely increment reconnection attempts
-		ws.reconnectionMutex.Lock()
-		ws.reconnectionAttempts++
-		currentAttempts := ws.reconnectionAttempts
-		ws.reconnectionMutex.Unlock()
-
-		// Prepare error message with backoff information
-		errorAttrs := []any{"error", err, "attempt", currentAttempts, "max", ws.maxReconnectionAttempts}
-		if ws.exponentialBackoff && currentAttempts < ws.maxReconnectionAttempts {
-			backoffDuration := calculateBackoffDuration(currentAttempts)
-			errorAttrs = append(errorAttrs, "backoff", backoffDuration)
-		}
-
-		ws.sysAp.config.Logger.Error("failed to connect to web socket", errorAttrs...)
 		ws.sysAp.emitError(err)
-
-		// Apply exponential backoff if enabled and we haven't exceeded max attempts
-		if ws.exponentialBackoff && currentAttempts < ws.maxReconnectionAttempts {
-			backoffDuration := calculateBackoffDuration(currentAttempts)
-			select {
-			case <-ctx.Done():
-				return
-			case <-ws.sysAp.clock.After(backoffDuration):
-				// Continue to next attempt
-			}
+		ws.addSpanEvent("connection failed", "error", err.Error())
+		if ws.sysAp.config.AllowPollingFallback && len(ws.sysAp.config.PollingDatapoints) > 0 && isWebSocketBlocked(err, resp) {
+			ws.sysAp.config.Logger.Error("failed to connect to web socket", "error", err, "status", resp.Status)
+			ws.pollDatapoints(ctx)
+			return
 		}
-
+		ws.registerFailedConnection(ctx, "failed to connect to web socket", err)
 		return
 	}
 
+	// The server may decline compression, in which case the connection continues uncompressed
+	if ws.sysAp.config.EnableCompression && (resp == nil || !strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")) {
+		ws.sysAp.config.Logger.Debug("web socket compression not supported by the server, continuing uncompressed")
+	}
+
+	// Track the connection so it can be closed if the shutdown times out or a ping fails
+	ws.setActiveConnection(conn)
+	defer ws.setActiveConnection(nil)
+
+	// Serialize the outbound writes, the connection does not support concurrent writers
+	writer := newWebSocketWriter(conn)
+	defer writer.close()
+
 	// Create connection channels
 	messageReceivedChannel := make(chan struct{}, 1)
-	webSocketMessageChannel := make(chan []byte, 10)
-	defer func() {
-		close(messageReceivedChannel)
-		close(webSocketMessageChannel)
-	}()
+	webSocketMessageChannel := ws.newMessageChannel()
 
-	// Start keepalive and message handler goroutines
-	go ws.webSocketKeepaliveLoop(messageReceivedChannel, conn, keepaliveInterval)
+	// Start keepalive and message handler goroutines. A keepalive interval of zero or less disables the keepalive.
+	if keepaliveInterval > 0 {
+		go ws.webSocketKeepaliveLoop(messageReceivedChannel, writer, keepaliveInterval)
+	} else {
+		ws.sysAp.config.Logger.Debug("keepalive disabled, relying on read activity only")
+	}
 	go ws.webSocketMessageHandler(webSocketMessageChannel)
 
-	// Reset reconnection attempts on successful connection
-	ws.reconnectionMutex.Lock()
-	ws.reconnectionAttempts = 0
-	ws.reconnectionMutex.Unlock()
-
 	// Start the message loop
+	connectedAt := ws.sysAp.clock.Now()
+	ws.sysAp.uptime.markConnected(connectedAt)
+	ws.addSpanEvent("connected")
 	ws.sysAp.config.Logger.Log("web socket connected successfully, starting message loop")
-	err = ws.webSocketMessageLoop(ctx, messageReceivedChannel, webSocketMessageChannel, conn)
+	if handler := ws.sysAp.connectedHandler(); handler != nil {
+		handler()
+	}
+	err = ws.runMessageLoop(ctx, messageReceivedChannel, webSocketMessageChannel, conn)
 
 	// Check for errors
 	if err != nil {
@@ -169,9 +352,133 @@ func (ws *SystemAccessPointWebSocket) webSocketConnectionLoop(ctx context.Contex
 		ws.sysAp.emitError(err)
 	}
 
+	// Record why the connection was lost, unless it was closed on purpose
+	reason := ws.takeDisconnectReason(err)
+	if ctx.Err() == nil {
+		ws.lastDisconnect = reason
+	}
+
 	// Close the web socket connection
 	err = conn.Close()
+	ws.sysAp.uptime.markDisconnected(ws.sysAp.clock.Now())
+	if reason != nil {
+		ws.addSpanEvent("disconnected", "cause", string(reason.Cause))
+	} else {
+		ws.addSpanEvent("disconnected")
+	}
 	ws.sysAp.config.Logger.Debug("web socket connection closed", "error", err)
+
+	// Evaluate the connection stability unless the connection was closed on purpose
+	if ctx.Err() == nil {
+		ws.evaluateConnectionStability(ctx, connectedAt)
+	}
+}
+
+// disconnect records the cause of the disconnect and closes the active connection, which stops the message loop. If a
+// cause was already recorded for the connection, the first cause is kept.
+func (ws *SystemAccessPointWebSocket) disconnect(reason *DisconnectError) {
+	ws.connectionMutex.Lock()
+	defer ws.connectionMutex.Unlock()
+	if ws.pendingDisconnect == nil {
+		ws.pendingDisconnect = reason
+	}
+	if ws.activeConnection != nil {
+		_ = ws.activeConnection.Close()
+	}
+}
+
+// takeDisconnectReason returns the cause of the disconnect of the connection and resets it for the next connection. A cause
+// recorded while closing the connection takes precedence over the error of the message loop, which is classified otherwise.
+// It returns nil if the message loop stopped without an error.
+func (ws *SystemAccessPointWebSocket) takeDisconnectReason(err error) *DisconnectError {
+	ws.connectionMutex.Lock()
+	reason := ws.pendingDisconnect
+	ws.pendingDisconnect = nil
+	ws.connectionMutex.Unlock()
+
+	if reason != nil {
+		return reason
+	}
+	if err == nil {
+		return nil
+	}
+	return newReadDisconnectError(err)
+}
+
+// evaluateConnectionStability resets the reconnection attempts if the connection stayed up for at least the stability window.
+// Connections that were closed before the stability window elapsed are treated as failed attempts, so flapping connections escalate the backoff.
+func (ws *SystemAccessPointWebSocket) evaluateConnectionStability(ctx context.Context, connectedAt time.Time) {
+	uptime := ws.sysAp.clock.Now().Sub(connectedAt)
+	if uptime >= ws.sysAp.config.ReconnectionStabilityWindow {
+		ws.reconnectionMutex.Lock()
+		ws.reconnectionAttempts = 0
+		ws.firstFailureAt = time.Time{}
+		ws.backoff.Reset()
+		ws.reconnectionMutex.Unlock()
+		return
+	}
+
+	ws.registerFailedAttempt(ctx, ws.sysAp.config.Logger.Warn, "web socket connection closed before the stability window elapsed", "uptime", uptime, "window", ws.sysAp.config.ReconnectionStabilityWindow)
+}
+
+// registerFailedConnection registers a failed connection attempt and passes it to the connection failed handler. The error
+// is only logged at debug level if a handler is registered, as the handler reports the failure.
+func (ws *SystemAccessPointWebSocket) registerFailedConnection(ctx context.Context, message string, err error) {
+	handler := ws.sysAp.connectionFailedHandler()
+	if handler == nil {
+		ws.registerFailedAttempt(ctx, ws.sysAp.config.Logger.Error, message, "error", err)
+		return
+	}
+
+	// The handler is called after the attempt was counted, but before the backoff
+	ws.registerFailedAttempt(ctx, func(message string, optionalParams ...any) {
+		ws.sysAp.config.Logger.Debug(message, optionalParams...)
+		ws.reconnectionMutex.Lock()
+		attempt := ws.reconnectionAttempts
+		ws.reconnectionMutex.Unlock()
+		handler(attempt, ws.maxReconnectionAttempts, err)
+	}, message, "error", err)
+}
+
+// registerFailedAttempt increments the reconnection attempts, logs the failure and applies the exponential backoff if enabled.
+func (ws *SystemAccessPointWebSocket) registerFailedAttempt(ctx context.Context, log func(message string, optionalParams ...any), message string, attrs ...any) {
+	// Safely increment reconnection attempts
+	ws.reconnectionMutex.Lock()
+	ws.reconnectionAttempts++
+	currentAttempts := ws.reconnectionAttempts
+	if ws.firstFailureAt.IsZero() {
+		ws.firstFailureAt = ws.sysAp.clock.Now()
+	}
+	backoffDuration := ws.backoff.Next()
+	ws.reconnectionMutex.Unlock()
+
+	// Prepare log message with backoff information
+	applyBackoff := ws.exponentialBackoff && currentAttempts < ws.maxReconnectionAttempts
+	attrs = append(attrs, "attempt", currentAttempts, "max", ws.maxReconnectionAttempts)
+	if applyBackoff {
+		attrs = append(attrs, "backoff", backoffDuration)
+	}
+	log(message, attrs...)
+
+	// Apply exponential backoff if enabled and we haven't exceeded max attempts
+	if applyBackoff {
+		select {
+		case <-ctx.Done():
+		case <-ws.sysAp.clock.After(backoffDuration):
+			// Continue to next attempt
+		}
+	}
+}
+
+// runMessageLoop runs the message loop and closes the connection channels once it has returned. The message loop is the only
+// sender on the channels, so closing them after it stopped sending rules out a send on a closed channel during shutdown.
+func (ws *SystemAccessPointWebSocket) runMessageLoop(ctx context.Context, messageReceivedChannel chan struct{}, webSocketMessageChannel chan []byte, conn connection) error {
+	defer func() {
+		close(messageReceivedChannel)
+		close(webSocketMessageChannel)
+	}()
+
+	return ws.webSocketMessageLoop(ctx, messageReceivedChannel, webSocketMessageChannel, conn)
 }
 
 // webSocketMessageLoop starts a loop to read messages from the web socket connection.
@@ -200,13 +507,21 @@ func (ws *SystemAccessPointWebSocket) webSocketMessageLoop(ctx context.Context,
 				return err
 			}
 
-			// Signal that a message has been received
+			// Count the message for the heartbeat
+			ws.sysAp.receivedMessages.Add(1)
+
+			// Log the raw frame if requested
+			if ws.sysAp.config.LogRawFrames {
+				ws.logRawFrame(messageType, message)
+			}
+
+			// Signal that a message has been received. If a signal is already pending, there is no need
+			// to send another one. This also prevents blocking if no keepalive loop is consuming the signals.
 			select {
 			case messageReceivedChannel <- struct{}{}:
 				// Message sent successfully
-			case <-ctx.Done():
-				// Context cancelled, exit immediately
-				return ctx.Err()
+			default:
+				// Signal already pending
 			}
 
 			// Check if the message type is text
@@ -218,6 +533,16 @@ func (ws *SystemAccessPointWebSocket) webSocketMessageLoop(ctx context.Context,
 			// Pipe the message to the message handler
 			ws.sysAp.config.Logger.Debug("received text message from web socket")
 			select {
+			case webSocketMessageChannel <- message:
+				// Message sent successfully
+				continue
+			default:
+				// The buffer is full, the message handler does not keep up
+				ws.warnMessageBufferFull(cap(webSocketMessageChannel))
+			}
+
+			// Block until the message handler has capacity again
+			select {
 			case webSocketMessageChannel <- message:
 				// Message sent successfully
 			case <-ctx.Done():
@@ -228,6 +553,41 @@ func (ws *SystemAccessPointWebSocket) webSocketMessageLoop(ctx context.Context,
 	}
 }
 
+// newMessageChannel creates the channel buffering the received messages for the message handler with the configured size.
+// A message buffer size of zero or less uses the default size.
+func (ws *SystemAccessPointWebSocket) newMessageChannel() chan []byte {
+	size := ws.sysAp.config.MessageBufferSize
+	if size <= 0 {
+		size = defaultMessageBufferSize
+	}
+	return make(chan []byte, size)
+}
+
+// warnMessageBufferFull logs a warning that the message buffer is full and the message loop blocks until the message handler
+// catches up. The warning is logged at most once per interval, the number of suppressed warnings is included in the next one.
+func (ws *SystemAccessPointWebSocket) warnMessageBufferFull(size int) {
+	now := ws.sysAp.clock.Now()
+	if !ws.lastBufferFullWarning.IsZero() && now.Sub(ws.lastBufferFullWarning) < messageBufferFullWarningInterval {
+		ws.suppressedBufferFullWarnings++
+		return
+	}
+
+	ws.sysAp.config.Logger.Warn("web socket message buffer full, waiting for the message handler", "size", size, "suppressed", ws.suppressedBufferFullWarnings)
+	ws.lastBufferFullWarning = now
+	ws.suppressedBufferFullWarnings = 0
+}
+
+// logRawFrame logs a raw web socket frame at debug level, truncated to the configured length if it is positive.
+func (ws *SystemAccessPointWebSocket) logRawFrame(messageType int, frame []byte) {
+	maxLength := ws.sysAp.config.RawFrameLogLength
+	truncated := maxLength > 0 && len(frame) > maxLength
+	if truncated {
+		frame = frame[:maxLength]
+	}
+
+	ws.sysAp.config.Logger.Debug("raw web socket frame", "type", messageType, "truncated", truncated, "frame", string(frame))
+}
+
 // processWebSocketMessage processes a message received from the web socket connection.
 func (ws *SystemAccessPointWebSocket) webSocketMessageHandler(webSocketMessageChannel <-chan []byte) {
 	// Add a wait group to ensure all processes are finished before returning
@@ -249,7 +609,8 @@ func (ws *SystemAccessPointWebSocket) webSocketMessageHandler(webSocketMessageCh
 	ws.sysAp.config.Logger.Log("webSocketMessageChannel closed, stopping message handler")
 }
 
-func (ws *SystemAccessPointWebSocket) webSocketKeepaliveLoop(messageReceivedChannel <-chan struct{}, conn connection, interval time.Duration) {
+// webSocketKeepaliveLoop sends a ping message whenever no message was received for the interval.
+func (ws *SystemAccessPointWebSocket) webSocketKeepaliveLoop(messageReceivedChannel <-chan struct{}, conn controlWriter, interval time.Duration) {
 	// Add a wait group to ensure all processes are finished before returning
 	ws.waitGroup.Add(1)
 	defer ws.waitGroup.Done()
@@ -283,6 +644,7 @@ func (ws *SystemAccessPointWebSocket) webSocketKeepaliveLoop(messageReceivedChan
 			if err != nil {
 				ws.sysAp.config.Logger.Error("failed to send ping message", "error", err)
 				ws.sysAp.emitError(err)
+				ws.disconnect(&DisconnectError{Cause: DisconnectCausePingFailure, Err: err})
 				return
 			}
 		}
@@ -292,56 +654,100 @@ func (ws *SystemAccessPointWebSocket) webSocketKeepaliveLoop(messageReceivedChan
 func (ws *SystemAccessPointWebSocket) processMessage(message []byte) {
 	defer func() {
 		// Call the onMessageHandled callback if it is set
-		if ws.onMessageHandled != nil {
-			ws.onMessageHandled()
+		ws.handlersMutex.RLock()
+		handler := ws.onMessageHandled
+		ws.handlersMutex.RUnlock()
+		if handler != nil {
+			handler()
 		}
 	}()
 
-	// Unmarshal the message into a WebSocketMessage struct
-	var msg models.WebSocketMessage
-	err := json.Unmarshal(message, &msg)
+	// Pass the raw message to the message handler if it is set
+	if handler := ws.sysAp.messageHandler(); handler != nil {
+		handler(message)
+	}
 
-	if err != nil {
-		ws.sysAp.config.Logger.Error("failed to unmarshal message", "error", err)
-		ws.sysAp.emitError(err)
-		return
+	// Decode the JSON objects of the frame in sequence. Some proxies concatenate several messages into a single frame,
+	// so data following the first object is decoded as further messages instead of failing the whole frame.
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	for decoded := 0; ; decoded++ {
+		var msg models.WebSocketMessage
+		err := decoder.Decode(&msg)
+		if errors.Is(err, io.EOF) && decoded > 0 {
+			return
+		}
+		if err != nil {
+			ws.sysAp.config.Logger.Error("failed to unmarshal message", "error", err, "decoded", decoded)
+			ws.sysAp.emitError(err)
+			return
+		}
+		if decoded == 1 {
+			ws.sysAp.config.Logger.Warn("web socket frame contains multiple JSON objects, processing each in sequence")
+		}
+
+		ws.processWebSocketMessage(msg)
 	}
+}
 
+// processWebSocketMessage processes the data point updates of a decoded web socket message.
+func (ws *SystemAccessPointWebSocket) processWebSocketMessage(msg models.WebSocketMessage) {
 	// Check if the message is empty
-	if len(msg[models.EmptyUUID].Datapoints) == 0 {
+	datapointCount := 0
+	for _, sysApMessage := range msg {
+		datapointCount += len(sysApMessage.Datapoints)
+	}
+	if datapointCount == 0 {
 		ws.sysAp.config.Logger.Warn("web socket message has no datapoints")
 		return
 	}
+	ws.sysAp.config.Logger.Debug("processing web socket message", "datapoints", datapointCount)
 
-	// Process data point updates
-	for key, datapoint := range msg[models.EmptyUUID].Datapoints {
+	// Process data point updates of all system access points in a stable order
+	sysApIDs := slices.Sorted(maps.Keys(msg))
+	for _, sysApID := range sysApIDs {
+		ws.processDatapoints(sysApID, msg[sysApID].Datapoints)
+	}
+}
+
+// ProcessMessage processes a raw web socket message as if it was received from the web socket, e.g. to replay captured
+// messages offline. The registered message and datapoint handlers are called, the datapoint handler synchronously
+// unless debouncing is enabled.
+func (sysAp *SystemAccessPoint) ProcessMessage(message []byte) {
+	ws := SystemAccessPointWebSocket{sysAp: sysAp}
+	ws.processMessage(message)
+}
+
+// processDatapoints processes the data point updates of a single system access point.
+func (ws *SystemAccessPointWebSocket) processDatapoints(sysApID string, datapoints map[string]string) {
+	for key, datapoint := range datapoints {
 		// Check if the key matches the expected format
-		if !ws.sysAp.datapointRegex.MatchString(key) {
+		parsed, err := models.ParseDatapointKey(key)
+		if err != nil {
 			ws.sysAp.config.Logger.Warn(`Ignored datapoint with invalid key format`, "key", key)
 			continue
 		}
+		ws.sysAp.datapointCounts.record(parsed.Serial)
 
-		// Log the datapoint update
-		ws.sysAp.config.Logger.Log("data point update",
-			"device", ws.sysAp.datapointRegex.FindStringSubmatch(key)[1],
-			"channel", ws.sysAp.datapointRegex.FindStringSubmatch(key)[2],
-			"datapoint", ws.sysAp.datapointRegex.FindStringSubmatch(key)[3],
+		// Log the datapoint update, the system access point is only logged if it is not the local one
+		attrs := []any{
+			"device", parsed.Serial,
+			"channel", parsed.Channel,
+			"datapoint", parsed.Datapoint,
 			"value", datapoint,
-		)
+		}
+		if sysApID != models.EmptyUUID {
+			attrs = append(attrs, "sysap", sysApID)
+		}
+		ws.sysAp.config.Logger.Log("data point update", attrs...)
+
+		// Pass the update to the datapoint handler if it is set
+		ws.sysAp.emitDatapointUpdate(models.DatapointUpdate{
+			Timestamp: ws.sysAp.clock.Now(),
+			SysApID:   sysApID,
+			Serial:    parsed.Serial,
+			Channel:   parsed.Channel,
+			Datapoint: parsed.Datapoint,
+			Value:     datapoint,
+		})
 	}
 }
-
-// calculateBackoffDuration calculates the exponential backoff duration for a given attempt number.
-// The backoff follows the formula: baseDelay * (2^attempt) with a maximum cap.
-func calculateBackoffDuration(attempt int) time.Duration {
-	baseDelay := time.Second
-	maxDelay := 30 * time.Second
-
-	// Calculate exponential backoff: baseDelay * (2^attempt)
-	backoffDuration := min(
-		baseDelay*time.Duration(1<<attempt),
-		maxDelay,
-	)
-
-	return backoffDuration
-}
@@ -8,8 +8,11 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -60,7 +63,7 @@ func TestSystemAccessPointWebSocketMessageHandler(t *testing.T) {
 	// Send messages to the WebSocketMessageChannel
 	var wg sync.WaitGroup
 	wg.Add(4)
-	ws.onMessageHandled = wg.Done
+	ws.setMessageHandledHandler(wg.Done)
 	go func() {
 		webSocketMessageChannel <- validMessageBytes
 		webSocketMessageChannel <- invalidMessage
@@ -113,6 +116,173 @@ func TestSystemAccessPointWebSocketMessageHandlerMissingChannel(t *testing.T) {
 	}
 }
 
+// TestSystemAccessPointWebSocketMessageHandlerRawMessage tests that the registered message handler receives the raw message.
+func TestSystemAccessPointWebSocketMessageHandlerRawMessage(t *testing.T) {
+	ws, _, _ := setupSysApWebSocket(t, true, false)
+
+	var received [][]byte
+	ws.sysAp.SetMessageHandler(func(message []byte) {
+		received = append(received, message)
+	})
+
+	ws.processMessage([]byte(testMessageValid))
+
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 raw message, got %d", len(received))
+	}
+	if string(received[0]) != testMessageValid {
+		t.Errorf("Expected raw message '%s', got '%s'", testMessageValid, string(received[0]))
+	}
+
+	// Remove the handler and verify it is no longer called
+	ws.sysAp.SetMessageHandler(nil)
+	ws.processMessage([]byte(testMessageValid))
+	if len(received) != 1 {
+		t.Errorf("Expected handler not to be called after removal, got %d messages", len(received))
+	}
+}
+
+// TestSystemAccessPointWebSocketDatapointHandler tests that the registered datapoint handler receives valid datapoint updates.
+func TestSystemAccessPointWebSocketDatapointHandler(t *testing.T) {
+	ws, _, _ := setupSysApWebSocket(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	ws.sysAp.clock = clock
+
+	var updates []models.DatapointUpdate
+	ws.sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		updates = append(updates, update)
+	})
+
+	message := models.WebSocketMessage{
+		models.EmptyUUID: models.Message{
+			Datapoints: map[string]string{
+				"ABB7F595EC47/ch0001/odp0000":      "1",
+				"Test123":                          "1",
+				"ABB7F595EC4\u212a/ch0001/odp0000": "1",
+			},
+		},
+	}
+	messageBytes, _ := json.Marshal(message)
+	ws.processMessage(messageBytes)
+
+	if len(updates) != 1 {
+		t.Fatalf("Expected 1 datapoint update, got %d", len(updates))
+	}
+	expected := models.DatapointUpdate{
+		Timestamp: clock.now,
+		SysApID:   models.EmptyUUID,
+		Serial:    "ABB7F595EC47",
+		Channel:   "ch0001",
+		Datapoint: "odp0000",
+		Value:     "1",
+	}
+	if updates[0] != expected {
+		t.Errorf("Expected update %+v, got %+v", expected, updates[0])
+	}
+}
+
+// TestSystemAccessPointProcessMessage tests that messages processed offline reach the message and datapoint handlers.
+func TestSystemAccessPointProcessMessage(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+
+	var messages []string
+	sysAp.SetMessageHandler(func(message []byte) {
+		messages = append(messages, string(message))
+	})
+	var updates []models.DatapointUpdate
+	sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		updates = append(updates, update)
+	})
+
+	message := `{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0001/odp0000":"1"}}}`
+	sysAp.ProcessMessage([]byte(message))
+
+	if len(messages) != 1 || messages[0] != message {
+		t.Errorf("Expected the raw message to be passed to the message handler, got %v", messages)
+	}
+	if len(updates) != 1 || updates[0].Serial != "ABB7F595EC47" || updates[0].Value != "1" {
+		t.Errorf("Expected 1 datapoint update of ABB7F595EC47, got %+v", updates)
+	}
+}
+
+// TestSystemAccessPointWebSocketConcatenatedMessages tests that every JSON object of a frame with concatenated messages is processed.
+func TestSystemAccessPointWebSocketConcatenatedMessages(t *testing.T) {
+	first := `{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"1"}}}`
+	second := `{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"0"}}}`
+	tests := []struct {
+		name           string
+		frame          string
+		expectedValues []string
+		expectedError  bool
+	}{
+		{name: "Directly concatenated", frame: first + second, expectedValues: []string{"1", "0"}},
+		{name: "Separated by whitespace", frame: first + "\n" + second + "\n", expectedValues: []string{"1", "0"}},
+		{name: "Trailing garbage", frame: first + "garbage", expectedValues: []string{"1"}, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, buf, _ := setupSysApWebSocket(t, true, false)
+
+			var values []string
+			ws.sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+				values = append(values, update.Value)
+			})
+			var errs []error
+			ws.sysAp.SetErrorHandler(func(err error) {
+				errs = append(errs, err)
+			})
+
+			ws.processMessage([]byte(tt.frame))
+
+			if !slices.Equal(values, tt.expectedValues) {
+				t.Errorf("Expected values %v, got %v", tt.expectedValues, values)
+			}
+			if (len(errs) > 0) != tt.expectedError {
+				t.Errorf("Expected error %t, got %v", tt.expectedError, errs)
+			}
+			if len(tt.expectedValues) > 1 && !strings.Contains(buf.String(), "web socket frame contains multiple JSON objects") {
+				t.Errorf("Expected a warning about multiple JSON objects, got: %s", buf.String())
+			}
+		})
+	}
+}
+
+// TestSystemAccessPointWebSocketDatapointHandlerMultipleSysAps tests that datapoint updates of all system access points are processed and tagged.
+func TestSystemAccessPointWebSocketDatapointHandlerMultipleSysAps(t *testing.T) {
+	ws, buf, _ := setupSysApWebSocket(t, true, false)
+
+	var updates []models.DatapointUpdate
+	ws.sysAp.SetDatapointHandler(func(update models.DatapointUpdate) {
+		updates = append(updates, update)
+	})
+
+	otherUUID := "11111111-1111-1111-1111-111111111111"
+	message := models.WebSocketMessage{
+		models.EmptyUUID: models.Message{
+			Datapoints: map[string]string{"ABB7F595EC47/ch0001/odp0000": "1"},
+		},
+		otherUUID: models.Message{
+			Datapoints: map[string]string{"ABB7F595EC47/ch0001/odp0000": "0"},
+		},
+	}
+	messageBytes, _ := json.Marshal(message)
+	ws.processMessage(messageBytes)
+
+	if len(updates) != 2 {
+		t.Fatalf("Expected 2 datapoint updates, got %d", len(updates))
+	}
+	if updates[0].SysApID != models.EmptyUUID || updates[0].Value != "1" {
+		t.Errorf("Expected first update from %s with value '1', got %+v", models.EmptyUUID, updates[0])
+	}
+	if updates[1].SysApID != otherUUID || updates[1].Value != "0" {
+		t.Errorf("Expected second update from %s with value '0', got %+v", otherUUID, updates[1])
+	}
+	if logOutput := buf.String(); !strings.Contains(logOutput, "sysap="+otherUUID) {
+		t.Errorf("Expected log output to contain the system access point of the second update, got: %s", logOutput)
+	}
+}
+
 // TestSystemAccessPointConnectWebSocketSuccess tests the successful connection of the WebSocket.
 func TestSystemAccessPointConnectWebSocketSuccess(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
@@ -139,7 +309,7 @@ func TestSystemAccessPointConnectWebSocketSuccess(t *testing.T) {
 	}))
 	defer server.Close()
 
-	sysAp.config.Hostname = strings.TrimPrefix(server.URL, "http://")
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
 
 	// Wait for the expected record in a separate goroutine
 	go func() {
@@ -167,6 +337,241 @@ func TestSystemAccessPointConnectWebSocketSuccess(t *testing.T) {
 	}
 }
 
+// TestSystemAccessPointConnectWebSocketReconnectsToNewHostName tests that a host name changed while connected is used for
+// the next connection attempt, while the established connection is kept until it is lost.
+func TestSystemAccessPointConnectWebSocketReconnectsToNewHostName(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, _, records := setupSysAp(t, false, false)
+
+	// Drain the log records, so that logging does not block the connection loop
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-records:
+			}
+		}
+	}()
+
+	// The new server ends the test once it was dialed
+	var newServerDialed atomic.Bool
+	newServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		newServerDialed.Store(true)
+		cancel()
+	}))
+	defer newServer.Close()
+
+	// The old server changes the host name while the connection is established and drops the connection afterwards
+	var oldServerDials atomic.Int32
+	oldServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		oldServerDials.Add(1)
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		sysAp.SetHostName(strings.TrimPrefix(newServer.URL, "http://"))
+		_ = conn.Close()
+	}))
+	defer oldServer.Close()
+
+	sysAp.SetHostName(strings.TrimPrefix(oldServer.URL, "http://"))
+
+	err := sysAp.ConnectWebSocket(ctx, 5, false, 1*time.Hour)
+	if err != nil && err != context.Canceled {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if !newServerDialed.Load() {
+		t.Error("Expected the next connection attempt to dial the new host name")
+	}
+	if dials := oldServerDials.Load(); dials != 1 {
+		t.Errorf("Expected the old host name to be dialed once, got %d", dials)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketConnectedHandler tests that the connected handler is called when the connection is established.
+func TestSystemAccessPointConnectWebSocketConnectedHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, _, _ := setupSysAp(t, false, false)
+
+	// Mock the WebSocket server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		<-ctx.Done()
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	// Cancel the connection as soon as it is established
+	var connected atomic.Int32
+	sysAp.SetConnectedHandler(func() {
+		connected.Add(1)
+		cancel()
+	})
+
+	err := sysAp.ConnectWebSocket(ctx, 1, false, 1*time.Hour)
+	if err != nil && err != context.Canceled {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if connected.Load() != 1 {
+		t.Errorf("Expected connected handler to be called once, got %d", connected.Load())
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketShutdownTimeout tests that the shutdown completes within the shutdown timeout if a goroutine is stuck.
+func TestSystemAccessPointConnectWebSocketShutdownTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, buf, _ := setupSysAp(t, false, false)
+	sysAp.config.ShutdownTimeout = 100 * time.Millisecond
+
+	// Mock the WebSocket server, which sends a single message and then stays silent
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(testMessageValid))
+		<-release
+	}))
+	defer server.Close()
+
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	// The message handler gets stuck and cancels the context, while the message loop is blocked reading
+	sysAp.SetMessageHandler(func(message []byte) {
+		cancel()
+		<-release
+	})
+
+	result := make(chan error, 1)
+	go func() {
+		result <- sysAp.ConnectWebSocket(ctx, 1, false, 1*time.Hour)
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil && err != context.Canceled {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected shutdown to complete within the shutdown timeout")
+	}
+
+	if logOutput := buf.String(); !strings.Contains(logOutput, "web socket shutdown timed out") {
+		t.Errorf("Expected log output to contain 'web socket shutdown timed out', got: %s", logOutput)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketKeepaliveDisabled tests that no ping message is sent when the keepalive is disabled.
+func TestSystemAccessPointConnectWebSocketKeepaliveDisabled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, buf, records := setupSysAp(t, false, false)
+
+	// Mock the WebSocket connection
+	dialer := &websocket.Dialer{}
+	websocket.DefaultDialer = dialer
+
+	// Mock the WebSocket server that counts received ping messages
+	var pings atomic.Int32
+	var conn *websocket.Conn
+	var connMutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		connMutex.Lock()
+		conn = c
+		connMutex.Unlock()
+		c.SetPingHandler(func(string) error {
+			pings.Add(1)
+			return nil
+		})
+
+		// Send two messages to verify the message loop works without the keepalive loop
+		message := `{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"1"}}}`
+		_ = c.WriteMessage(websocket.TextMessage, []byte(message))
+		_ = c.WriteMessage(websocket.TextMessage, []byte(message))
+
+		// Read until the connection is closed to process control messages
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	// Stop the connection once both messages have been processed
+	go func() {
+		updates := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case record := <-records:
+				if strings.Contains(record.Message, "data point update") {
+					updates++
+				}
+				if updates == 2 {
+					// Give a potential keepalive loop the chance to send a ping
+					time.Sleep(50 * time.Millisecond)
+					cancel()
+					connMutex.Lock()
+					if conn != nil {
+						_ = conn.Close()
+					}
+					connMutex.Unlock()
+					return
+				}
+			}
+		}
+	}()
+
+	err := sysAp.ConnectWebSocket(ctx, 1, false, 0)
+	if err != nil && err != context.Canceled {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if pings.Load() != 0 {
+		t.Errorf("Expected no ping messages, got %d", pings.Load())
+	}
+
+	logOutput := buf.String()
+	if !strings.Contains(logOutput, "keepalive disabled") {
+		t.Errorf("Expected log output to contain 'keepalive disabled', got: %s", logOutput)
+	}
+	if strings.Contains(logOutput, "keepalive timer expired") {
+		t.Errorf("Expected no keepalive timer expiry, got: %s", logOutput)
+	}
+}
+
 // TestSystemAccessPointConnectWebSocketSkipTlsVerify tests the successful connection of the WebSocket with skip TLS verify.
 func TestSystemAccessPointConnectWebSocketSkipTlsVerify(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
@@ -193,7 +598,7 @@ func TestSystemAccessPointConnectWebSocketSkipTlsVerify(t *testing.T) {
 	}))
 	defer server.Close()
 
-	sysAp.config.Hostname = strings.TrimPrefix(server.URL, "https://")
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "https://"))
 
 	// Wait for the expected record in a separate goroutine
 	go func() {
@@ -251,7 +656,7 @@ func TestSystemAccessPointConnectWebSocketContextCancelled(t *testing.T) {
 	}))
 	defer server.Close()
 
-	sysAp.config.Hostname = strings.TrimPrefix(server.URL, "http://")
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
 
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -308,16 +713,16 @@ func TestSystemAccessPointConnectWebSocketFailure(t *testing.T) {
 	sysAp, buf, _ := setupSysAp(t, false, false)
 
 	// Set an invalid host name to simulate connection failure
-	sysAp.config.Hostname = "invalid-host"
+	sysAp.SetHostName("invalid-host")
 
 	// set up the error handler
-	sysAp.onError = func(err error) {
+	sysAp.SetErrorHandler(func(err error) {
 		if strings.Contains(err.Error(), "lookup invalid-host") {
 			cancel()
 		} else {
 			t.Errorf("Unexpected error: %v", err)
 		}
-	}
+	})
 
 	// Run ConnectWebSocket in a separate goroutine
 	go func() {
@@ -346,16 +751,16 @@ func TestSystemAccessPointConnectWebSocketFailureWithBackoff(t *testing.T) {
 	sysAp.clock = &fakeClock{}
 
 	// Set an invalid host name to simulate connection failure
-	sysAp.config.Hostname = "invalid-host"
+	sysAp.SetHostName("invalid-host")
 
 	// set up the error handler
-	sysAp.onError = func(err error) {
+	sysAp.SetErrorHandler(func(err error) {
 		if strings.Contains(err.Error(), "lookup invalid-host") {
 			cancel()
 		} else {
 			t.Errorf("Unexpected error: %v", err)
 		}
-	}
+	})
 
 	// Run ConnectWebSocket in a separate goroutine
 	go func() {
@@ -379,13 +784,13 @@ func TestSystemAccessPointConnectWebSocketMaxReconnectionAttempts(t *testing.T)
 	sysAp, buf, _ := setupSysAp(t, false, false)
 
 	// Set an invalid host name to simulate connection failure
-	sysAp.config.Hostname = "invalid-host"
+	sysAp.SetHostName("invalid-host")
 
 	// set up the error handler
 	errorCount := 0
-	sysAp.onError = func(err error) {
+	sysAp.SetErrorHandler(func(err error) {
 		errorCount++
-	}
+	})
 
 	// Run ConnectWebSocket with max 2 reconnection attempts
 	err := sysAp.ConnectWebSocket(t.Context(), 2, false, 1*time.Hour)
@@ -407,6 +812,296 @@ func TestSystemAccessPointConnectWebSocketMaxReconnectionAttempts(t *testing.T)
 	}
 }
 
+// TestSystemAccessPointWebSocketStableConnectionResetsAttempts tests that a connection that stayed up for the stability window resets the reconnection attempts.
+func TestSystemAccessPointWebSocketStableConnectionResetsAttempts(t *testing.T) {
+	ws, buf, _ := setupSysApWebSocket(t, true, false)
+	clock := &fakeClock{}
+	ws.sysAp.clock = clock
+	ws.maxReconnectionAttempts = 5
+	ws.exponentialBackoff = true
+	ws.reconnectionAttempts = 2
+
+	connectedAt := clock.Now()
+	clock.Sleep(ws.sysAp.config.ReconnectionStabilityWindow)
+	ws.evaluateConnectionStability(t.Context(), connectedAt)
+
+	if ws.reconnectionAttempts != 0 {
+		t.Errorf("Expected reconnection attempts to be reset to 0, got %d", ws.reconnectionAttempts)
+	}
+	if len(clock.afterCalls) != 0 {
+		t.Errorf("Expected no backoff, got %v", clock.afterCalls)
+	}
+	if logOutput := buf.String(); logOutput != "" {
+		t.Errorf("Expected no log output, got: %s", logOutput)
+	}
+}
+
+// TestSystemAccessPointWebSocketFlappingConnectionEscalatesBackoff tests that connections closed before the stability window elapsed count as failed attempts.
+func TestSystemAccessPointWebSocketFlappingConnectionEscalatesBackoff(t *testing.T) {
+	ws, buf, _ := setupSysApWebSocket(t, true, false)
+	clock := &fakeClock{}
+	ws.sysAp.clock = clock
+	ws.maxReconnectionAttempts = 5
+	ws.exponentialBackoff = true
+
+	// Simulate three connections that drop after one second each
+	for range 3 {
+		connectedAt := clock.Now()
+		clock.Sleep(time.Second)
+		ws.evaluateConnectionStability(t.Context(), connectedAt)
+	}
+
+	if ws.reconnectionAttempts != 3 {
+		t.Errorf("Expected 3 reconnection attempts, got %d", ws.reconnectionAttempts)
+	}
+	expectedBackoff := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second}
+	if fmt.Sprint(clock.afterCalls) != fmt.Sprint(expectedBackoff) {
+		t.Errorf("Expected backoff durations %v, got %v", expectedBackoff, clock.afterCalls)
+	}
+	if logOutput := buf.String(); !strings.Contains(logOutput, "web socket connection closed before the stability window elapsed") {
+		t.Errorf("Expected log output to contain 'web socket connection closed before the stability window elapsed', got: %s", logOutput)
+	}
+}
+
+// advancingClock is a fake clock that advances its time by the waited duration whenever After is called
+type advancingClock struct {
+	fakeClock
+}
+
+func (ac *advancingClock) After(d time.Duration) <-chan time.Time {
+	ac.Sleep(d)
+	return ac.fakeClock.After(d)
+}
+
+// TestSystemAccessPointConnectWebSocketMaxReconnectDuration tests that the connection attempts stop once the maximum reconnection duration elapsed.
+func TestSystemAccessPointConnectWebSocketMaxReconnectDuration(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, false, false)
+	clock := &advancingClock{fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}}
+	sysAp.clock = clock
+	sysAp.config.MaxReconnectDuration = 10 * time.Second
+
+	// Set an invalid host name to simulate connection failure
+	sysAp.SetHostName("invalid-host")
+
+	// Run ConnectWebSocket with enough attempts that only the duration limit applies
+	err := sysAp.ConnectWebSocket(t.Context(), 100, true, 1*time.Hour)
+
+	// Verify error
+	if err == nil || err.Error() != "maximum reconnection duration exceeded" {
+		t.Errorf("Expected error 'maximum reconnection duration exceeded', got: %v", err)
+	}
+
+	// The backoff durations of 2s, 4s and 8s exceed the 10s limit after the third attempt
+	expectedBackoff := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second}
+	if fmt.Sprint(clock.afterCalls) != fmt.Sprint(expectedBackoff) {
+		t.Errorf("Expected backoff durations %v, got %v", expectedBackoff, clock.afterCalls)
+	}
+
+	if logOutput := buf.String(); !strings.Contains(logOutput, "maximum reconnection duration exceeded") {
+		t.Errorf("Expected log output to contain 'maximum reconnection duration exceeded', got: %s", logOutput)
+	}
+}
+
+// TestSystemAccessPointWebSocketStableConnectionResetsFirstFailure tests that a stable connection restarts the reconnection duration.
+func TestSystemAccessPointWebSocketStableConnectionResetsFirstFailure(t *testing.T) {
+	ws, _, _ := setupSysApWebSocket(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ws.sysAp.clock = clock
+	ws.maxReconnectionAttempts = 5
+
+	ws.registerFailedAttempt(t.Context(), ws.sysAp.config.Logger.Error, "failed")
+	if !ws.firstFailureAt.Equal(clock.Now()) {
+		t.Errorf("Expected first failure at %v, got %v", clock.Now(), ws.firstFailureAt)
+	}
+
+	// A second failure must not move the first failure
+	clock.Sleep(time.Second)
+	ws.registerFailedAttempt(t.Context(), ws.sysAp.config.Logger.Error, "failed")
+	if !ws.firstFailureAt.Equal(clock.Now().Add(-time.Second)) {
+		t.Errorf("Expected first failure to stay at %v, got %v", clock.Now().Add(-time.Second), ws.firstFailureAt)
+	}
+
+	connectedAt := clock.Now()
+	clock.Sleep(ws.sysAp.config.ReconnectionStabilityWindow)
+	ws.evaluateConnectionStability(t.Context(), connectedAt)
+	if !ws.firstFailureAt.IsZero() {
+		t.Errorf("Expected first failure to be reset, got %v", ws.firstFailureAt)
+	}
+}
+
+// TestSystemAccessPointWebSocketNewDialer tests that the dialer is configured according to the configuration.
+func TestSystemAccessPointWebSocketNewDialer(t *testing.T) {
+	ws, _, _ := setupSysApWebSocket(t, true, false)
+
+	dialer := ws.newDialer()
+	if dialer.EnableCompression {
+		t.Error("Expected compression to be disabled by default")
+	}
+	if dialer.TLSClientConfig != nil {
+		t.Error("Expected no custom TLS configuration")
+	}
+
+	ws.sysAp.config.EnableCompression = true
+	ws.sysAp.config.SkipTLSVerify = true
+	dialer = ws.newDialer()
+	if !dialer.EnableCompression {
+		t.Error("Expected compression to be enabled")
+	}
+	if dialer.TLSClientConfig == nil || !dialer.TLSClientConfig.InsecureSkipVerify {
+		t.Error("Expected TLS certificate verification to be skipped")
+	}
+	if websocket.DefaultDialer.EnableCompression {
+		t.Error("Expected the default dialer not to be modified")
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketCompression tests that compression is negotiated if supported and the connection succeeds if not.
+func TestSystemAccessPointConnectWebSocketCompression(t *testing.T) {
+	for _, serverCompression := range []bool{true, false} {
+		t.Run(fmt.Sprintf("server compression %t", serverCompression), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			sysAp, buf, _ := setupSysAp(t, false, false)
+			sysAp.config.EnableCompression = true
+			websocket.DefaultDialer = &websocket.Dialer{}
+
+			// Mock the WebSocket server that reports the negotiated extensions
+			extensions := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				upgrader := websocket.Upgrader{EnableCompression: serverCompression}
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					t.Errorf("Failed to upgrade WebSocket: %v", err)
+					return
+				}
+				extensions <- r.Header.Get("Sec-WebSocket-Extensions")
+				cancel()
+				<-r.Context().Done()
+				_ = conn.Close()
+			}))
+			defer server.Close()
+			sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+			err := sysAp.ConnectWebSocket(ctx, 1, false, 1*time.Hour)
+			if err != nil && err != context.Canceled {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+
+			if requested := <-extensions; !strings.Contains(requested, "permessage-deflate") {
+				t.Errorf("Expected the client to request permessage-deflate, got '%s'", requested)
+			}
+			declined := strings.Contains(buf.String(), "web socket compression not supported by the server")
+			if declined == serverCompression {
+				t.Errorf("Expected compression declined log to be %t, got: %s", !serverCompression, buf.String())
+			}
+		})
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketReconnectionPaused tests that no connection attempts are made while paused and that resuming continues them.
+func TestSystemAccessPointConnectWebSocketReconnectionPaused(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, buf, _ := setupSysAp(t, false, false)
+
+	// Set an invalid host name to count the connection attempts by the reported errors
+	sysAp.SetHostName("invalid-host")
+	var attempts atomic.Int32
+	sysAp.SetErrorHandler(func(err error) {
+		attempts.Add(1)
+		cancel()
+	})
+
+	sysAp.PauseReconnection()
+	if !sysAp.IsReconnectionPaused() {
+		t.Fatal("Expected reconnection to be paused")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sysAp.ConnectWebSocket(ctx, 1, false, 1*time.Hour)
+	}()
+
+	// No connection is attempted while paused
+	time.Sleep(100 * time.Millisecond)
+	if count := attempts.Load(); count != 0 {
+		t.Fatalf("Expected no connection attempts while paused, got %d", count)
+	}
+	if logOutput := buf.String(); !strings.Contains(logOutput, "web socket connection attempts paused") {
+		t.Errorf("Expected log output to contain 'web socket connection attempts paused', got: %s", logOutput)
+	}
+
+	// Resuming continues the connection attempts
+	if sysAp.ToggleReconnectionPaused() {
+		t.Error("Expected toggle to resume the reconnection")
+	}
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected context canceled, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the connection attempt after resuming")
+	}
+	if count := attempts.Load(); count != 1 {
+		t.Errorf("Expected 1 connection attempt after resuming, got %d", count)
+	}
+	if logOutput := buf.String(); !strings.Contains(logOutput, "web socket connection attempts resumed") {
+		t.Errorf("Expected log output to contain 'web socket connection attempts resumed', got: %s", logOutput)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketReconnectionPausedCancelled tests that cancelling the context ends a paused connection loop.
+func TestSystemAccessPointConnectWebSocketReconnectionPausedCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	sysAp, _, _ := setupSysAp(t, false, false)
+	sysAp.SetHostName("invalid-host")
+	sysAp.SetErrorHandler(func(err error) {
+		t.Errorf("Unexpected connection attempt: %v", err)
+	})
+
+	if !sysAp.ToggleReconnectionPaused() {
+		t.Fatal("Expected toggle to pause the reconnection")
+	}
+	cancel()
+
+	if err := sysAp.ConnectWebSocket(ctx, 1, false, 1*time.Hour); err != context.Canceled {
+		t.Errorf("Expected context canceled, got: %v", err)
+	}
+}
+
+// TestSystemAccessPointWebSocketReconnectionPausedShiftsFirstFailure tests that the paused time does not count towards the maximum reconnection duration.
+func TestSystemAccessPointWebSocketReconnectionPausedShiftsFirstFailure(t *testing.T) {
+	ws, buf, _ := setupSysApWebSocket(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ws.sysAp.clock = clock
+	ws.maxReconnectionAttempts = 5
+	ws.registerFailedAttempt(t.Context(), ws.sysAp.config.Logger.Error, "failed")
+	firstFailureAt := ws.firstFailureAt
+
+	ws.sysAp.PauseReconnection()
+	go func() {
+		// Resume after the wait started and a minute passed
+		for !strings.Contains(buf.String(), "web socket connection attempts paused") {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Sleep(time.Minute)
+		ws.sysAp.ResumeReconnection()
+	}()
+	if !ws.waitWhileReconnectionPaused(t.Context()) {
+		t.Fatal("Expected the wait to end by resuming")
+	}
+
+	if !ws.firstFailureAt.Equal(firstFailureAt.Add(time.Minute)) {
+		t.Errorf("Expected first failure to be shifted to %v, got %v", firstFailureAt.Add(time.Minute), ws.firstFailureAt)
+	}
+	if ws.reconnectionAttempts != 1 {
+		t.Errorf("Expected 1 reconnection attempt, got %d", ws.reconnectionAttempts)
+	}
+}
+
 // TestSystemAccessPointWebSocketMessageLoopTextMessage tests the webSocketMessageLoop method for text messages.
 func TestSystemAccessPointWebSocketMessageLoopTextMessage(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
@@ -444,6 +1139,11 @@ func TestSystemAccessPointWebSocketMessageLoopTextMessage(t *testing.T) {
 		t.Errorf("Expected message 'valid message', got: %s", string(message))
 	}
 
+	// Check if the message was counted for the heartbeat
+	if count := ws.sysAp.receivedMessages.Load(); count == 0 {
+		t.Error("Expected the received message to be counted")
+	}
+
 	// Check the log output safely
 	logOutput := buf.String()
 	if !strings.Contains(logOutput, "received text message from web socket") {
@@ -459,13 +1159,13 @@ func TestSystemAccessPointWebSocketMessageLoopNonTextMessage(t *testing.T) {
 	ws, buf, _ := setupSysApWebSocket(t, true, false)
 	webSocketMessageChannel := make(chan []byte, 10)
 	messageReceivedChannel := make(chan struct{}, 1)
-	ws.sysAp.onError = func(err error) {
+	ws.sysAp.SetErrorHandler(func(err error) {
 		if strings.Contains(err.Error(), "no more messages") {
 			cancel()
 		} else {
 			t.Errorf("Unexpected error: %v", err)
 		}
-	}
+	})
 
 	// Mock a non-text message
 	nonTextMessage := []byte{0x00, 0x01, 0x02}
@@ -498,6 +1198,142 @@ func TestSystemAccessPointWebSocketMessageLoopNonTextMessage(t *testing.T) {
 	}
 }
 
+// TestSystemAccessPointWebSocketMessageLoopLogRawFrames tests that raw frames are logged and truncated at the configured length.
+func TestSystemAccessPointWebSocketMessageLoopLogRawFrames(t *testing.T) {
+	tests := []struct {
+		name          string
+		logRawFrames  bool
+		maxLength     int
+		expectLogged  bool
+		expectedFrame string
+		truncated     bool
+	}{
+		{name: "Disabled", logRawFrames: false, maxLength: 1024, expectLogged: false},
+		{name: "Complete frame", logRawFrames: true, maxLength: 1024, expectLogged: true, expectedFrame: "0123456789", truncated: false},
+		{name: "Truncated frame", logRawFrames: true, maxLength: 4, expectLogged: true, expectedFrame: "0123", truncated: true},
+		{name: "Unlimited length", logRawFrames: true, maxLength: 0, expectLogged: true, expectedFrame: "0123456789", truncated: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, buf, _ := setupSysApWebSocket(t, true, false)
+			ws.sysAp.config.LogRawFrames = tt.logRawFrames
+			ws.sysAp.config.RawFrameLogLength = tt.maxLength
+
+			// The loop returns once the mock connection has no more messages
+			conn := &MockConn{messageType: websocket.TextMessage, r: []byte("0123456789")}
+			err := ws.webSocketMessageLoop(t.Context(), make(chan struct{}, 1), make(chan []byte, 10), conn)
+			if err == nil {
+				t.Fatal(expectedErrorGotNil)
+			}
+
+			logOutput := buf.String()
+			if !tt.expectLogged {
+				if strings.Contains(logOutput, "raw web socket frame") {
+					t.Errorf("Expected no raw frame in log output, got: %s", logOutput)
+				}
+				return
+			}
+			expected := fmt.Sprintf("msg=\"raw web socket frame\" type=1 truncated=%t frame=%s", tt.truncated, tt.expectedFrame)
+			if !strings.Contains(logOutput, expected+"\n") {
+				t.Errorf("Expected log output to contain '%s', got: %s", expected, logOutput)
+			}
+		})
+	}
+}
+
+// TestSystemAccessPointWebSocketNewMessageChannel tests that the message channel is created with the configured buffer size.
+func TestSystemAccessPointWebSocketNewMessageChannel(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		expected   int
+	}{
+		{name: "Configured size", bufferSize: 25, expected: 25},
+		{name: "Zero uses default", bufferSize: 0, expected: 10},
+		{name: "Negative uses default", bufferSize: -1, expected: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, _, _ := setupSysApWebSocket(t, true, false)
+			ws.sysAp.config.MessageBufferSize = tt.bufferSize
+
+			if actual := cap(ws.newMessageChannel()); actual != tt.expected {
+				t.Errorf("Expected buffer size %d, got %d", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// TestSystemAccessPointWebSocketMessageLoopBufferFull tests that a warning is logged if the message buffer is full and the loop blocks.
+func TestSystemAccessPointWebSocketMessageLoopBufferFull(t *testing.T) {
+	ws, _, records := setupSysApWebSocket(t, true, false)
+	ws.sysAp.clock = &fakeClock{}
+
+	// The buffer is already full when the message is received
+	webSocketMessageChannel := make(chan []byte, 1)
+	webSocketMessageChannel <- []byte("queued")
+	conn := &MockConn{messageType: websocket.TextMessage, r: []byte(testMessageValid)}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- ws.webSocketMessageLoop(t.Context(), make(chan struct{}, 1), webSocketMessageChannel, conn)
+	}()
+
+	// Wait for the warning before the handler catches up
+	for record := range records {
+		if record.Message != "web socket message buffer full, waiting for the message handler" {
+			continue
+		}
+		if record.Level != slog.LevelWarn {
+			t.Errorf("Expected warning level, got %s", record.Level)
+		}
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == "size" && attr.Value.Int64() != 1 {
+				t.Errorf("Expected size 1, got %d", attr.Value.Int64())
+			}
+			return true
+		})
+		break
+	}
+
+	// The blocked message is delivered once the buffer has capacity again
+	if message := <-webSocketMessageChannel; string(message) != "queued" {
+		t.Errorf("Expected queued message, got: %s", message)
+	}
+	if message := <-webSocketMessageChannel; string(message) != testMessageValid {
+		t.Errorf("Expected message '%s', got: %s", testMessageValid, message)
+	}
+	if err := <-result; err == nil || err.Error() != "no more messages" {
+		t.Errorf("Expected no more messages error, got: %v", err)
+	}
+}
+
+// TestSystemAccessPointWebSocketWarnMessageBufferFullRateLimited tests that the full buffer warning is logged at most once per interval.
+func TestSystemAccessPointWebSocketWarnMessageBufferFullRateLimited(t *testing.T) {
+	ws, buf, _ := setupSysApWebSocket(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	ws.sysAp.clock = clock
+
+	for range 3 {
+		ws.warnMessageBufferFull(10)
+	}
+	if count := strings.Count(buf.String(), "web socket message buffer full"); count != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %s", count, buf.String())
+	}
+
+	// The next warning after the interval reports the suppressed warnings
+	clock.Sleep(messageBufferFullWarningInterval)
+	ws.warnMessageBufferFull(10)
+	if count := strings.Count(buf.String(), "web socket message buffer full"); count != 2 {
+		t.Fatalf("Expected 2 warnings, got %d: %s", count, buf.String())
+	}
+	if !strings.Contains(buf.String(), "size=10 suppressed=2") {
+		t.Errorf("Expected suppressed warnings to be reported, got: %s", buf.String())
+	}
+}
+
 // TestSystemAccessPointWebSocketMessageLoopMissingChannel tests the webSocketMessageLoop method for missing channels.
 func TestSystemAccessPointWebSocketMessageLoopMissingChannel(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
@@ -644,30 +1480,117 @@ func TestSystemAccessPointGetWsUrlWithTls(t *testing.T) {
 	}
 }
 
-// TestSystemAccessPointCalculateBackoffDuration tests the calculateBackoffDuration method of SystemAccessPoint.
-func TestSystemAccessPointCalculateBackoffDuration(t *testing.T) {
+// TestSystemAccessPointReconnectionBackoff tests the delays between the reconnection attempts of the web socket.
+func TestSystemAccessPointReconnectionBackoff(t *testing.T) {
+	backoff := NewBackoff(reconnectionBackoffBase, reconnectionBackoffMax, 0)
+
 	// Test exponential backoff calculation
 	testCases := []struct {
 		attempt  int
 		expected time.Duration
 	}{
-		{1, 2 * time.Second},   // 1s * 2^1 = 2s
-		{2, 4 * time.Second},   // 1s * 2^2 = 4s
-		{3, 8 * time.Second},   // 1s * 2^3 = 8s
-		{4, 16 * time.Second},  // 1s * 2^4 = 16s
-		{5, 30 * time.Second},  // Capped at 30s
-		{6, 30 * time.Second},  // Capped at 30s
-		{10, 30 * time.Second}, // Capped at 30s
+		{1, 2 * time.Second},  // Base delay
+		{2, 4 * time.Second},  // 2s * 2^1 = 4s
+		{3, 8 * time.Second},  // 2s * 2^2 = 8s
+		{4, 16 * time.Second}, // 2s * 2^3 = 16s
+		{5, 30 * time.Second}, // Capped at 30s
+		{6, 30 * time.Second}, // Capped at 30s
+		{7, 30 * time.Second}, // Capped at 30s
 	}
 
 	for _, tc := range testCases {
-		result := calculateBackoffDuration(tc.attempt)
+		result := backoff.Next()
 		if result != tc.expected {
 			t.Errorf("For attempt %d, expected %v, got %v", tc.attempt, tc.expected, result)
 		}
 	}
 }
 
+// TestSystemAccessPointWebSocketShutdownDuringMessageFlow tests that cancelling the context while messages are flowing stops
+// the message loop before the message channel is closed, so that neither a send on a closed channel panics nor a goroutine hangs.
+func TestSystemAccessPointWebSocketShutdownDuringMessageFlow(t *testing.T) {
+	for i := range 50 {
+		ws, _, records := setupSysApWebSocket(t, true, false)
+		ws.sysAp.config.MessageBufferSize = 1
+
+		// Drain the log records, the message handler would block once the record channel is full
+		drainCtx, stopDrain := context.WithCancel(t.Context())
+		go func() {
+			for {
+				select {
+				case <-drainCtx.Done():
+					return
+				case <-records:
+				}
+			}
+		}()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		conn := &streamingConn{message: []byte(testMessageValid)}
+		messageReceivedChannel := make(chan struct{}, 1)
+		webSocketMessageChannel := ws.newMessageChannel()
+		go ws.webSocketMessageHandler(webSocketMessageChannel)
+
+		result := make(chan error, 1)
+		go func() {
+			result <- ws.runMessageLoop(ctx, messageReceivedChannel, webSocketMessageChannel, conn)
+		}()
+
+		// Cancel once messages are flowing
+		for conn.reads.Load() < 10 {
+			runtime.Gosched()
+		}
+		cancel()
+
+		select {
+		case <-result:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Iteration %d: expected the message loop to stop after cancellation", i)
+		}
+
+		handlerDone := make(chan struct{})
+		go func() {
+			ws.waitGroup.Wait()
+			close(handlerDone)
+		}()
+		select {
+		case <-handlerDone:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Iteration %d: expected the message handler to stop after the message channel was closed", i)
+		}
+		stopDrain()
+	}
+}
+
+// streamingConn is a connection that returns the same text message on every read, simulating a steady message flow
+type streamingConn struct {
+	message []byte
+	reads   atomic.Int64
+}
+
+func (c *streamingConn) ReadMessage() (int, []byte, error) {
+	c.reads.Add(1)
+	return websocket.TextMessage, c.message, nil
+}
+
+func (c *streamingConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	return nil
+}
+
+func (c *streamingConn) Close() error {
+	return nil
+}
+
+// TestSystemAccessPointGetWsUrlWithApiVersion tests that the getWebSocketUrl method uses the configured API version.
+func TestSystemAccessPointGetWsUrlWithApiVersion(t *testing.T) {
+	ws, _, _ := setupSysApWebSocket(t, true, false)
+	ws.sysAp.config.APIVersion = "v2"
+
+	if actual, expected := ws.getWebSocketUrl(), "wss://localhost/fhapi/v2/api/ws"; actual != expected {
+		t.Errorf("Expected URL '%s', got '%s'", expected, actual)
+	}
+}
+
 type MockConn struct {
 	messageRead   bool
 	messageType   int
@@ -690,6 +1613,10 @@ func (m *MockConn) ReadMessage() (int, []byte, error) {
 	return m.messageType, m.r, m.err
 }
 
+func (m *MockConn) Close() error {
+	return nil
+}
+
 func (m *MockConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
 	if m.mu != nil {
 		m.mu.Lock()
@@ -706,3 +1633,267 @@ func (m *MockConn) WriteControl(messageType int, data []byte, deadline time.Time
 	})
 	return m.err
 }
+
+// TestSystemAccessPointConnectWebSocketConcurrentHandlerRegistration tests that handlers can be registered while messages are processed.
+// Run with -race to detect unsynchronized access.
+func TestSystemAccessPointConnectWebSocketConcurrentHandlerRegistration(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, _, records := setupSysAp(t, false, false)
+	const messages = 50
+
+	// Drain the log records, so logging does not block
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-records:
+			}
+		}
+	}()
+
+	var conn *websocket.Conn
+	var connMutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		connMutex.Lock()
+		conn = c
+		connMutex.Unlock()
+
+		for i := range messages {
+			message := fmt.Sprintf(`{"00000000-0000-0000-0000-000000000000":{"datapoints":{"ABB7F595EC47/ch0000/odp0000":"%d"}}}`, i)
+			_ = c.WriteMessage(websocket.TextMessage, []byte(message))
+		}
+
+		// Read until the connection is closed to process control messages
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+	// Stop the connection once all updates were received, regardless of which handler received them
+	var updates atomic.Int32
+	datapointHandler := func(update models.DatapointUpdate) {
+		if updates.Add(1) == messages {
+			cancel()
+			connMutex.Lock()
+			if conn != nil {
+				_ = conn.Close()
+			}
+			connMutex.Unlock()
+		}
+	}
+
+	// Register the handlers repeatedly while the messages are processed
+	var registration sync.WaitGroup
+	registration.Add(1)
+	go func() {
+		defer registration.Done()
+		for ctx.Err() == nil {
+			sysAp.SetDatapointHandler(datapointHandler)
+			sysAp.SetMessageHandler(func(message []byte) {})
+			sysAp.SetErrorHandler(func(err error) {})
+			sysAp.SetConnectedHandler(func() {})
+		}
+	}()
+	sysAp.SetDatapointHandler(datapointHandler)
+
+	err := sysAp.ConnectWebSocket(ctx, 1, false, 0)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	registration.Wait()
+
+	if actual := updates.Load(); actual != messages {
+		t.Errorf("Expected %d updates, got %d", messages, actual)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketAuthHeader tests that the web socket handshake carries the credentials in the configured header.
+func TestSystemAccessPointConnectWebSocketAuthHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		headerName string
+		expected   string
+	}{
+		{name: "Default header", headerName: "", expected: "Authorization"},
+		{name: "Custom header", headerName: "x-proxy-authorization", expected: "X-Proxy-Authorization"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sysAp, _ := setupSysApWithAuthHeader(t, tt.headerName)
+
+			// Reject the handshake after capturing the request headers
+			headers := make(chan http.Header, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				headers <- r.Header.Clone()
+				http.Error(w, "forbidden", http.StatusForbidden)
+			}))
+			defer server.Close()
+			sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+
+			err := sysAp.ConnectWebSocket(t.Context(), 1, false, 0)
+			if err == nil || err.Error() != "maximum reconnection attempts exceeded" {
+				t.Errorf("Expected maximum reconnection attempts error, got: %v", err)
+			}
+
+			header := <-headers
+			if value := header.Get(tt.expected); value != "Basic dXNlcjpwYXNzd29yZA==" {
+				t.Errorf("Expected credentials in header '%s', got '%s'", tt.expected, value)
+			}
+			if tt.expected != "Authorization" && header.Get("Authorization") != "" {
+				t.Errorf("Expected no 'Authorization' header, got '%s'", header.Get("Authorization"))
+			}
+		})
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketCredentialProvider tests that the credentials are obtained from the provider before every connection attempt.
+func TestSystemAccessPointConnectWebSocketCredentialProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, _, _ := setupSysAp(t, false, false)
+	var calls atomic.Int32
+	sysAp.config.CredentialProvider = func(ctx context.Context) (string, string, error) {
+		return fmt.Sprintf("user%d", calls.Add(1)), "password", nil
+	}
+
+	// Mock the WebSocket server, which rejects the first connection attempt
+	var authorizations []string
+	var authorizationsMutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizationsMutex.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		attempt := len(authorizations)
+		authorizationsMutex.Unlock()
+		if attempt == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		<-ctx.Done()
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+	sysAp.SetConnectedHandler(cancel)
+
+	err := sysAp.ConnectWebSocket(ctx, 2, false, 1*time.Hour)
+	if err != nil && err != context.Canceled {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected the credential provider to be called twice, got %d", calls.Load())
+	}
+	expected := []string{basicAuthorization("user1", "password"), basicAuthorization("user2", "password")}
+	if !slices.Equal(authorizations, expected) {
+		t.Errorf("Expected authorizations %v, got %v", expected, authorizations)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketCredentialProviderError tests that a failure to obtain the credentials counts as a failed connection attempt.
+func TestSystemAccessPointConnectWebSocketCredentialProviderError(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, false, false)
+	sysAp.SetHostName("127.0.0.1:1")
+	sysAp.config.CredentialProvider = func(ctx context.Context) (string, string, error) {
+		return "", "", errors.New("vault unavailable")
+	}
+
+	err := sysAp.ConnectWebSocket(t.Context(), 1, false, 1*time.Hour)
+	if err == nil || err.Error() != "maximum reconnection attempts exceeded" {
+		t.Errorf("Expected maximum reconnection attempts error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "failed to obtain web socket credentials") {
+		t.Errorf("Expected the credential failure to be logged, got: %s", buf.String())
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketDisableAuth tests that the web socket handshake carries no credentials if authentication is disabled.
+func TestSystemAccessPointConnectWebSocketDisableAuth(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	sysAp, _, _ := setupSysAp(t, false, false)
+	sysAp.config.DisableAuth = true
+
+	// Mock the WebSocket server, which records the authorization header of the handshake
+	authorization := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization <- r.Header.Get("Authorization")
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade WebSocket: %v", err)
+			return
+		}
+		<-ctx.Done()
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	sysAp.SetHostName(strings.TrimPrefix(server.URL, "http://"))
+	sysAp.SetConnectedHandler(cancel)
+
+	err := sysAp.ConnectWebSocket(ctx, 1, false, 1*time.Hour)
+	if err != nil && err != context.Canceled {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if value := <-authorization; value != "" {
+		t.Errorf("Expected no 'Authorization' header, got '%s'", value)
+	}
+}
+
+// TestSystemAccessPointConnectWebSocketConnectionFailedHandler tests that failed connection attempts are passed to the
+// connection failed handler and only logged at debug level.
+func TestSystemAccessPointConnectWebSocketConnectionFailedHandler(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, false, false)
+	sysAp.SetHostName("127.0.0.1:1")
+
+	var attempts []int
+	sysAp.SetConnectionFailedHandler(func(attempt, maxAttempts int, err error) {
+		if maxAttempts != 2 {
+			t.Errorf("Expected maximum attempts 2, got %d", maxAttempts)
+		}
+		if err == nil {
+			t.Error("Expected the connection error to be passed")
+		}
+		attempts = append(attempts, attempt)
+	})
+
+	err := sysAp.ConnectWebSocket(t.Context(), 2, false, 1*time.Hour)
+	if err == nil || err.Error() != "maximum reconnection attempts exceeded" {
+		t.Errorf("Expected maximum reconnection attempts error, got: %v", err)
+	}
+	if !slices.Equal(attempts, []int{1, 2}) {
+		t.Errorf("Expected the handler to be called for attempts [1 2], got %v", attempts)
+	}
+
+	logOutput := buf.String()
+	if !strings.Contains(logOutput, `level=DEBUG msg="failed to connect to web socket"`) {
+		t.Errorf("Expected the failed attempt to be logged at debug level, got: %s", logOutput)
+	}
+	if strings.Contains(logOutput, `level=ERROR msg="failed to connect to web socket"`) {
+		t.Errorf("Expected the failed attempt not to be logged as error, got: %s", logOutput)
+	}
+}
@@ -1,18 +1,49 @@
 package freeathome
 
 import (
+	"cmp"
+	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 
 	"github.com/pgerke/freeathome/v2/pkg/models"
 )
 
-// Config represents the configuration for a SystemAccessPoint
+// defaultAuthHeaderName is the header carrying the basic authentication credentials if no other header is configured
+const defaultAuthHeaderName = "Authorization"
+
+// defaultAPIVersion is the version segment of the local API paths if no other version is configured
+const defaultAPIVersion = "v1"
+
+// apiVersionRegex matches the valid version segments of the local API paths, e.g. v1
+var apiVersionRegex = regexp.MustCompile(`^v\d+$`)
+
+// ValidateAPIVersion returns an error if the version is not a valid version segment of the local API paths, i.e. v
+// followed by digits. An empty version is valid and uses the default version.
+func ValidateAPIVersion(version string) error {
+	if version != "" && !apiVersionRegex.MatchString(version) {
+		return fmt.Errorf("invalid API version %q, expected v followed by digits, e.g. v1", version)
+	}
+	return nil
+}
+
+// Config represents the configuration for a SystemAccessPoint.
+// The configuration must not be modified after the SystemAccessPoint was created, use the setters of the
+// SystemAccessPoint to change the host name or UUID at runtime.
 type Config struct {
 	// Hostname is the hostname or IP address of the system access point
 	Hostname string
@@ -20,54 +51,167 @@ type Config struct {
 	Username string
 	// Password is the password for authentication
 	Password string
+	// DisableAuth indicates whether the credentials are omitted from all REST requests and web socket connections,
+	// e.g. for unsecured development system access points. The credential provider is not called if set.
+	DisableAuth bool
+	// CredentialProvider returns the current credentials before every REST request and web socket connection attempt, e.g. if the
+	// credentials are rotated (optional, Username and Password are used if nil)
+	CredentialProvider func(ctx context.Context) (username, password string, err error)
+	// AuthHeaderName is the name of the header carrying the basic authentication credentials, empty uses Authorization.
+	// Gateways in front of the system access point may expect the credentials in a differently named header.
+	AuthHeaderName string
+	// APIVersion is the version segment of the REST and web socket paths, e.g. v1 in /fhapi/v1/api/rest, empty uses v1.
+	// It allows targeting a newer API of future firmware without a new release.
+	APIVersion string
 	// TLSEnabled indicates whether TLS is enabled for communication
 	TLSEnabled bool
 	// SkipTLSVerify indicates whether TLS certificate verification should be skipped
 	SkipTLSVerify bool
+	// ReadOnly indicates whether write operations are rejected with ErrReadOnly without sending a request, so that the
+	// client cannot change the state of any device
+	ReadOnly bool
 	// VerboseErrors indicates whether verbose errors should be logged
 	VerboseErrors bool
+	// ReconnectionStabilityWindow is the duration a web socket connection has to stay up before the reconnection attempts are reset
+	ReconnectionStabilityWindow time.Duration
+	// MaxReconnectDuration is the wall time after the first failed attempt after which the web socket stops reconnecting, zero disables the limit
+	MaxReconnectDuration time.Duration
+	// ShutdownTimeout is the time the web socket waits for its goroutines to finish after the context was cancelled, zero waits indefinitely
+	ShutdownTimeout time.Duration
+	// StrictResponseValidation indicates whether a warning should be logged for response fields that are not captured by the model
+	StrictResponseValidation bool
+	// DatapointDebounce is the quiet period a datapoint has to be stable before its update is passed to the datapoint handler, zero disables debouncing.
+	// Debounced updates are passed on one at a time, never concurrently. Updates still pending when ConnectWebSocket returns are passed on before it
+	// returns, no update is passed on afterwards.
+	DatapointDebounce time.Duration
+	// DatapointScales converts the raw values of datapoints read via ScaledValue, e.g. temperatures sent in tenths of a degree (optional)
+	DatapointScales map[models.DatapointKey]models.DatapointScale
+	// StateMapMaxEntries is the maximum number of entries of the state kept per datapoint or device, i.e. the updates pending
+	// debouncing and the datapoint counters. The least recently updated entry is evicted once the maximum is exceeded, an
+	// evicted pending update is passed on immediately. Zero or less keeps the state unbounded.
+	StateMapMaxEntries int
+	// EnableCompression indicates whether the web socket negotiates permessage-deflate compression with the server
+	EnableCompression bool
+	// AllowPollingFallback indicates whether the datapoints in PollingDatapoints are polled via REST if the web socket handshake
+	// fails in a way that indicates web sockets are blocked, e.g. by a proxy
+	AllowPollingFallback bool
+	// PollingDatapoints are the datapoints that are polled if the web socket falls back to polling
+	PollingDatapoints []models.DatapointKey
+	// PollingInterval is the interval between two polls of the polling datapoints, zero or less uses 10 seconds
+	PollingInterval time.Duration
+	// MessageBufferSize is the number of received web socket messages buffered for the message handler, zero or less uses 10.
+	// The message loop blocks if the buffer is full, which indicates a slow handler.
+	MessageBufferSize int
+	// HeartbeatInterval is the interval at which a heartbeat with the number of web socket messages received since the
+	// previous heartbeat is logged while the web socket is running, zero or less disables the heartbeat
+	HeartbeatInterval time.Duration
+	// LogRawFrames indicates whether every raw web socket frame is logged at debug level
+	LogRawFrames bool
+	// RawFrameLogLength is the maximum number of bytes of a raw web socket frame that are logged, zero or less logs frames completely
+	RawFrameLogLength int
+	// Tracer starts a span for every REST operation and the web socket connection, e.g. to export traces via OpenTelemetry (optional)
+	Tracer models.Tracer
+	// WriteAuditLog receives a JSON line for every write operation performed by the client (optional)
+	WriteAuditLog io.Writer
 	// Logger is the logger to use for logging messages
 	Logger models.Logger
-	// Client is the REST client to use (optional, will create default if nil)
+	// Client is the REST client to use (optional, will create default if nil). The client is configured in place: the
+	// credentials, the TLS settings and, if a credential provider is configured, a hook refreshing the credentials before
+	// every request are set on it. The hook only applies to the requests of the system access point that registered it.
 	Client *resty.Client
 }
 
 // NewConfig creates a new Config with default values
 func NewConfig(hostname, username, password string) *Config {
 	return &Config{
-		Hostname:      hostname,
-		Username:      username,
-		Password:      password,
-		TLSEnabled:    true,
-		SkipTLSVerify: false,
-		VerboseErrors: false,
-		Logger:        nil,
-		Client:        nil,
+		Hostname:                    hostname,
+		Username:                    username,
+		Password:                    password,
+		AuthHeaderName:              defaultAuthHeaderName,
+		APIVersion:                  defaultAPIVersion,
+		TLSEnabled:                  true,
+		SkipTLSVerify:               false,
+		VerboseErrors:               false,
+		Logger:                      nil,
+		Client:                      nil,
+		ReconnectionStabilityWindow: 30 * time.Second,
+		ShutdownTimeout:             5 * time.Second,
+		PollingInterval:             defaultPollingInterval,
+		MessageBufferSize:           defaultMessageBufferSize,
+		RawFrameLogLength:           1024,
 	}
 }
 
 // SystemAccessPoint represents a system access point that can be used to communicate with a free@home system.
 type SystemAccessPoint struct {
+	// UUID is the identifier of the system access point used in requests.
+	// It must not be modified directly once requests are running concurrently, use SetUUID instead.
 	UUID string
+	// configMutex protects UUID and the host name in config against concurrent modification at runtime
+	configMutex sync.RWMutex
 	// config contains the configuration for the system access point
 	config *Config
-	// datapointRegex is the regular expression that is used to match datapoint keys.
-	datapointRegex *regexp.Regexp
 	// clock provides time operations that can be mocked in tests
 	clock clock
 	// onError is a callback function that is called when an error occurs.
 	onError func(error)
+	// onMessage is a callback function that is called with every raw text message received from the web socket.
+	onMessage func([]byte)
+	// onDatapointUpdate is a callback function that is called for every datapoint update received from the web socket.
+	onDatapointUpdate func(models.DatapointUpdate)
+	// onConnected is a callback function that is called whenever the web socket connection is established.
+	onConnected func()
+	// onConnectionFailed is a callback function that is called whenever a web socket connection attempt fails.
+	onConnectionFailed func(attempt, maxAttempts int, err error)
+	// onReconnecting is a callback function that is called whenever the web socket reconnects after a lost connection.
+	onReconnecting func(reason *DisconnectError)
+	// handlersMutex protects access to the callback functions, which may be registered while the web socket is running
+	handlersMutex sync.RWMutex
+	// auditMutex serializes writes to the audit log
+	auditMutex sync.Mutex
+	// virtualDevices contains the last state sent for each virtual device, identified by its serial
+	virtualDevices map[string]models.VirtualDevice
+	// virtualDevicesMutex protects access to virtualDevices
+	virtualDevicesMutex sync.Mutex
+	// debouncer coalesces bursts of datapoint updates if debouncing is enabled, created on first use
+	debouncer *datapointDebouncer
+	// debouncerOnce guards the creation of debouncer
+	debouncerOnce sync.Once
+	// reconnectionPaused indicates whether web socket connection attempts are paused
+	reconnectionPaused bool
+	// reconnectionResumed is closed when paused connection attempts are resumed
+	reconnectionResumed chan struct{}
+	// reconnectionPauseMutex protects access to reconnectionPaused and reconnectionResumed
+	reconnectionPauseMutex sync.Mutex
+	// cachedConfiguration is the configuration most recently retrieved from the system access point
+	cachedConfiguration *models.Configuration
+	// cachedConfigurationMutex protects access to cachedConfiguration
+	cachedConfigurationMutex sync.RWMutex
+	// uptime tracks the connected time of the web socket
+	uptime uptimeTracker
+	// receivedMessages is the number of web socket messages received since the previous heartbeat
+	receivedMessages atomic.Uint64
+	// stats counts the successful and failed calls per operation
+	stats operationStats
+	// datapointCounts counts the datapoint updates received per device
+	datapointCounts datapointCounts
+	// subscriptions contains the subscribers of the datapoint updates of single devices
+	subscriptions subscriptions
 }
 
 // NewSystemAccessPoint creates a new SystemAccessPoint with the specified configuration.
+// The REST client of the configuration is configured in place, see Config.Client.
 func NewSystemAccessPoint(config *Config) (*SystemAccessPoint, error) {
 	if config == nil {
 		return nil, errors.New("config cannot be nil")
 	}
+	if err := ValidateAPIVersion(config.APIVersion); err != nil {
+		return nil, err
+	}
 
-	// Set default logger if not provided
+	// Set default logger if not provided, redacting the password in case it ends up in a log message
 	if config.Logger == nil {
-		config.Logger = NewDefaultLogger(nil)
+		config.Logger = NewDefaultLogger(NewRedactHandler(slog.Default().Handler(), config.Password))
 		config.Logger.Warn("No logger provided for SystemAccessPoint. Using default logger.")
 	}
 
@@ -75,7 +219,14 @@ func NewSystemAccessPoint(config *Config) (*SystemAccessPoint, error) {
 	if config.Client == nil {
 		config.Client = resty.New()
 	}
-	config.Client.SetBasicAuth(config.Username, config.Password)
+	switch {
+	case config.DisableAuth:
+		config.Logger.Warn("Authentication is disabled, requests are sent without credentials.")
+	case authHeaderName(config) == defaultAuthHeaderName:
+		config.Client.SetBasicAuth(config.Username, config.Password)
+	default:
+		config.Client.SetHeader(authHeaderName(config), basicAuthorization(config.Username, config.Password))
+	}
 
 	// Configure TLS settings if TLS is enabled
 	if config.TLSEnabled && config.SkipTLSVerify {
@@ -83,20 +234,29 @@ func NewSystemAccessPoint(config *Config) (*SystemAccessPoint, error) {
 		config.Client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
 	}
 
-	return &SystemAccessPoint{
+	sysAp := &SystemAccessPoint{
 		UUID:           models.EmptyUUID,
 		config:         config,
-		datapointRegex: regexp.MustCompile(models.DatapointPattern),
 		clock:          &realClock{},
-	}, nil
+		virtualDevices: map[string]models.VirtualDevice{},
+	}
+	sysAp.datapointCounts.counts.maxEntries = config.StateMapMaxEntries
+
+	// Refresh the basic authentication before every REST request if a credential provider is configured. The hook stays
+	// registered on the client, so it ignores the requests of other system access points sharing the client.
+	if config.CredentialProvider != nil && !config.DisableAuth {
+		config.Client.OnBeforeRequest(sysAp.refreshCredentials)
+	}
+
+	return sysAp, nil
 }
 
 // MustNewSystemAccessPoint creates a new SystemAccessPoint with the specified configuration.
 // It panics if an error occurs.
 func MustNewSystemAccessPoint(config *Config) *SystemAccessPoint {
 	sysap, err := NewSystemAccessPoint(config)
-	// The error can only occur if the config is nil, which is considered a programming error.
-	// If you are not sure if the config is nil, use NewSystemAccessPoint instead.
+	// The error can only occur if the config is nil or the API version is invalid, which is considered a programming error.
+	// If you are not sure if the config is valid, use NewSystemAccessPoint instead.
 	if err != nil {
 		panic(err)
 	}
@@ -109,18 +269,206 @@ func NewSystemAccessPointWithDefaults(hostname, username, password string) *Syst
 	return MustNewSystemAccessPoint(config)
 }
 
+// emitDatapointUpdate passes a datapoint update to the datapoint handler and the subscribers, debounced if a quiet period is configured.
+func (sysAp *SystemAccessPoint) emitDatapointUpdate(update models.DatapointUpdate) {
+	if sysAp.datapointHandler() == nil && !sysAp.subscriptions.active() {
+		return
+	}
+	if sysAp.config.DatapointDebounce <= 0 {
+		sysAp.publishDatapointUpdate(update)
+		return
+	}
+
+	sysAp.datapointDebouncer().add(update)
+}
+
+// datapointDebouncer returns the debouncer of the datapoint updates, creating it on first use
+func (sysAp *SystemAccessPoint) datapointDebouncer() *datapointDebouncer {
+	sysAp.debouncerOnce.Do(func() {
+		sysAp.debouncer = newDatapointDebouncer(sysAp.clock, sysAp.config.DatapointDebounce, sysAp.config.StateMapMaxEntries, sysAp.publishDatapointUpdate)
+	})
+	return sysAp.debouncer
+}
+
+// flushDatapointUpdates passes the updates pending debouncing on immediately, so that no settled value is lost on shutdown
+func (sysAp *SystemAccessPoint) flushDatapointUpdates() {
+	if sysAp.config.DatapointDebounce > 0 {
+		sysAp.datapointDebouncer().flush()
+	}
+}
+
 // emitError is a helper function to emit errors using the onError callback.
 func (sysAp *SystemAccessPoint) emitError(err error) {
-	if sysAp.onError != nil {
-		sysAp.onError(err)
+	sysAp.handlersMutex.RLock()
+	handler := sysAp.onError
+	sysAp.handlersMutex.RUnlock()
+	if handler != nil {
+		handler(err)
+	}
+}
+
+// SetErrorHandler registers a callback function that is called whenever an error occurs, e.g. a failed request or a lost
+// web socket connection. Passing nil removes a previously registered handler.
+func (sysAp *SystemAccessPoint) SetErrorHandler(handler func(err error)) {
+	sysAp.handlersMutex.Lock()
+	defer sysAp.handlersMutex.Unlock()
+	sysAp.onError = handler
+}
+
+// SetMessageHandler registers a callback function that is called with every raw text message received from the web socket,
+// before the message is parsed. Passing nil removes a previously registered handler.
+func (sysAp *SystemAccessPoint) SetMessageHandler(handler func(message []byte)) {
+	sysAp.handlersMutex.Lock()
+	defer sysAp.handlersMutex.Unlock()
+	sysAp.onMessage = handler
+}
+
+// SetDatapointHandler registers a callback function that is called for every valid datapoint update received from the web socket.
+// Passing nil removes a previously registered handler.
+func (sysAp *SystemAccessPoint) SetDatapointHandler(handler func(update models.DatapointUpdate)) {
+	sysAp.handlersMutex.Lock()
+	defer sysAp.handlersMutex.Unlock()
+	sysAp.onDatapointUpdate = handler
+}
+
+// SetConnectedHandler registers a callback function that is called whenever the web socket connection is established,
+// including every reconnection. Passing nil removes a previously registered handler.
+func (sysAp *SystemAccessPoint) SetConnectedHandler(handler func()) {
+	sysAp.handlersMutex.Lock()
+	defer sysAp.handlersMutex.Unlock()
+	sysAp.onConnected = handler
+}
+
+// SetConnectionFailedHandler registers a callback function that is called whenever a web socket connection attempt fails,
+// with the number of failed attempts, the maximum number of attempts and the error. While a handler is registered, the
+// handler is responsible for reporting the failure and the error is only logged at debug level. Passing nil removes a
+// previously registered handler.
+func (sysAp *SystemAccessPoint) SetConnectionFailedHandler(handler func(attempt, maxAttempts int, err error)) {
+	sysAp.handlersMutex.Lock()
+	defer sysAp.handlersMutex.Unlock()
+	sysAp.onConnectionFailed = handler
+}
+
+// SetReconnectingHandler registers a callback function that is called whenever the web socket starts reconnecting after an
+// established connection was lost, with the error that caused the disconnect. Connections closed because the context was
+// cancelled are not reconnected. Passing nil removes a previously registered handler.
+func (sysAp *SystemAccessPoint) SetReconnectingHandler(handler func(reason *DisconnectError)) {
+	sysAp.handlersMutex.Lock()
+	defer sysAp.handlersMutex.Unlock()
+	sysAp.onReconnecting = handler
+}
+
+// messageHandler returns the registered message handler, nil if there is none
+func (sysAp *SystemAccessPoint) messageHandler() func([]byte) {
+	sysAp.handlersMutex.RLock()
+	defer sysAp.handlersMutex.RUnlock()
+	return sysAp.onMessage
+}
+
+// datapointHandler returns the registered datapoint handler, nil if there is none
+func (sysAp *SystemAccessPoint) datapointHandler() func(models.DatapointUpdate) {
+	sysAp.handlersMutex.RLock()
+	defer sysAp.handlersMutex.RUnlock()
+	return sysAp.onDatapointUpdate
+}
+
+// connectedHandler returns the registered connected handler, nil if there is none
+func (sysAp *SystemAccessPoint) connectedHandler() func() {
+	sysAp.handlersMutex.RLock()
+	defer sysAp.handlersMutex.RUnlock()
+	return sysAp.onConnected
+}
+
+// reconnectingHandler returns the registered reconnecting handler, nil if there is none
+func (sysAp *SystemAccessPoint) reconnectingHandler() func(*DisconnectError) {
+	sysAp.handlersMutex.RLock()
+	defer sysAp.handlersMutex.RUnlock()
+	return sysAp.onReconnecting
+}
+
+// connectionFailedHandler returns the registered connection failed handler, nil if there is none
+func (sysAp *SystemAccessPoint) connectionFailedHandler() func(int, int, error) {
+	sysAp.handlersMutex.RLock()
+	defer sysAp.handlersMutex.RUnlock()
+	return sysAp.onConnectionFailed
+}
+
+// PauseReconnection pauses the web socket connection attempts. An established connection is kept, but after it
+// closes no new connection is attempted until ResumeReconnection is called. Paused time does not count as failed attempts.
+func (sysAp *SystemAccessPoint) PauseReconnection() {
+	sysAp.reconnectionPauseMutex.Lock()
+	defer sysAp.reconnectionPauseMutex.Unlock()
+
+	if !sysAp.reconnectionPaused {
+		sysAp.reconnectionPaused = true
+		sysAp.reconnectionResumed = make(chan struct{})
 	}
 }
 
+// ResumeReconnection resumes web socket connection attempts that were paused by PauseReconnection.
+func (sysAp *SystemAccessPoint) ResumeReconnection() {
+	sysAp.reconnectionPauseMutex.Lock()
+	defer sysAp.reconnectionPauseMutex.Unlock()
+
+	if sysAp.reconnectionPaused {
+		sysAp.reconnectionPaused = false
+		close(sysAp.reconnectionResumed)
+	}
+}
+
+// ToggleReconnectionPaused pauses the web socket connection attempts if they are running and resumes them otherwise.
+// It returns whether the connection attempts are paused afterwards.
+func (sysAp *SystemAccessPoint) ToggleReconnectionPaused() bool {
+	sysAp.reconnectionPauseMutex.Lock()
+	defer sysAp.reconnectionPauseMutex.Unlock()
+
+	if sysAp.reconnectionPaused {
+		sysAp.reconnectionPaused = false
+		close(sysAp.reconnectionResumed)
+	} else {
+		sysAp.reconnectionPaused = true
+		sysAp.reconnectionResumed = make(chan struct{})
+	}
+	return sysAp.reconnectionPaused
+}
+
+// IsReconnectionPaused reports whether the web socket connection attempts are paused.
+func (sysAp *SystemAccessPoint) IsReconnectionPaused() bool {
+	sysAp.reconnectionPauseMutex.Lock()
+	defer sysAp.reconnectionPauseMutex.Unlock()
+	return sysAp.reconnectionPaused
+}
+
 // HostName returns the host name of the system access point.
 func (sysAp *SystemAccessPoint) GetHostName() string {
+	sysAp.configMutex.RLock()
+	defer sysAp.configMutex.RUnlock()
 	return sysAp.config.Hostname
 }
 
+// SetHostName changes the host name of the system access point. It is safe to call while requests are running,
+// requests and web socket connections that are already established keep using the previous host name. The web socket
+// uses the new host name from its next connection attempt, e.g. after the system access point got a new address.
+func (sysAp *SystemAccessPoint) SetHostName(hostname string) {
+	sysAp.configMutex.Lock()
+	defer sysAp.configMutex.Unlock()
+	sysAp.config.Hostname = hostname
+}
+
+// GetUUID returns the identifier of the system access point used in requests.
+func (sysAp *SystemAccessPoint) GetUUID() string {
+	sysAp.configMutex.RLock()
+	defer sysAp.configMutex.RUnlock()
+	return sysAp.UUID
+}
+
+// SetUUID changes the identifier of the system access point used in requests. It is safe to call while requests are running.
+func (sysAp *SystemAccessPoint) SetUUID(uuid string) {
+	sysAp.configMutex.Lock()
+	defer sysAp.configMutex.Unlock()
+	sysAp.UUID = uuid
+}
+
 // TlsEnabled returns whether TLS is enabled for communication with the system access point.
 func (sysAp *SystemAccessPoint) GetTlsEnabled() bool {
 	return sysAp.config.TLSEnabled
@@ -152,7 +500,7 @@ func (sysAp *SystemAccessPoint) GetUrl(path string) string {
 		protocol = "http"
 	}
 
-	return fmt.Sprintf("%s://%s/fhapi/v1/api/rest/%s", protocol, sysAp.config.Hostname, path)
+	return fmt.Sprintf("%s://%s/fhapi/%s/api/rest/%s", protocol, sysAp.GetHostName(), apiVersion(sysAp.config), path)
 }
 
 // CreateVirtualDevice creates a new virtual device on the System Access Point (SysAP) with the specified serial number.
@@ -168,12 +516,87 @@ func (sysAp *SystemAccessPoint) GetUrl(path string) string {
 //   - *models.VirtualDeviceResponse: Pointer to the response struct with details of the created virtual device.
 //   - error: An error object if the operation fails, otherwise nil.
 func (sysAp *SystemAccessPoint) CreateVirtualDevice(serial string, virtualDevice *models.VirtualDevice) (*models.VirtualDeviceResponse, error) {
-	resp, err := sysAp.config.Client.R().
-		SetPathParams(map[string]string{"uuid": sysAp.UUID, "serial": serial}).
+	if err := sysAp.checkWritable(models.OperationVirtualDevice, "create virtual device"); err != nil {
+		return nil, err
+	}
+
+	span := sysAp.startSpan("CreateVirtualDevice")
+	resp, err := sysAp.request(nil).
+		SetPathParams(map[string]string{"uuid": sysAp.GetUUID(), "serial": serial}).
 		SetBody(virtualDevice).
 		Put(sysAp.GetUrl("virtualdevice/{uuid}/{serial}"))
 
-	return deserializeRestResponse[models.VirtualDeviceResponse](sysAp, resp, err, "failed to create virtual device")
+	result, err := deserializeRestResponse[models.VirtualDeviceResponse](sysAp, models.OperationVirtualDevice, resp, err, "failed to create virtual device")
+	endRestSpan(span, resp, err)
+	if err == nil && virtualDevice != nil {
+		sysAp.virtualDevicesMutex.Lock()
+		sysAp.virtualDevices[serial] = virtualDevice.Clone()
+		sysAp.virtualDevicesMutex.Unlock()
+	}
+	return result, err
+}
+
+// UpdateVirtualDevice partially updates a virtual device that was previously created by this client.
+// The current state is read from the configuration of the SysAP, which contains the display name of the device, e.g. if it
+// was renamed in the app. The configuration does not contain the type, flavor, capabilities and TTL of the device, so they
+// are taken from the state last sent by CreateVirtualDevice or UpdateVirtualDevice. The fields of the patch that are not
+// nil are merged into a copy of the current state, all other fields are preserved, and the merged device is sent to the SysAP.
+//
+// Parameters:
+//   - serial: The serial number of the virtual device to be updated.
+//   - patch: A pointer to the VirtualDevicePatch containing the fields to change.
+//
+// Returns:
+//   - *models.VirtualDeviceResponse: Pointer to the response struct with details of the updated virtual device.
+//   - error: An error if the virtual device is unknown, not part of the configuration or the operation fails, otherwise nil.
+func (sysAp *SystemAccessPoint) UpdateVirtualDevice(serial string, patch *models.VirtualDevicePatch) (*models.VirtualDeviceResponse, error) {
+	if err := sysAp.checkWritable(models.OperationVirtualDevice, "update virtual device"); err != nil {
+		return nil, err
+	}
+
+	sysAp.virtualDevicesMutex.Lock()
+	current, exists := sysAp.virtualDevices[serial]
+	sysAp.virtualDevicesMutex.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown virtual device: %s", serial)
+	}
+
+	// Look the device up in the configuration, it is missing there if it expired or was deleted
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	sysApConfiguration, err := configuration.SysAp(sysAp.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	device, exists := findVirtualDevice(sysApConfiguration, serial)
+	if !exists {
+		return nil, fmt.Errorf("virtual device not found in configuration: %s", serial)
+	}
+
+	// The cached state is a copy, so merging into it does not modify the cache or the device passed by the caller
+	current = current.Clone()
+	if device.DisplayName != nil {
+		displayName := *device.DisplayName
+		current.Properties.DisplayName = &displayName
+	}
+	merged := patch.ApplyTo(current)
+	return sysAp.CreateVirtualDevice(serial, &merged)
+}
+
+// findVirtualDevice returns the device of the configuration that was created with the serial, the SysAP stores virtual
+// devices under a serial of its own and keeps the serial they were created with as native ID
+func findVirtualDevice(sysApConfiguration models.SysAP, serial string) (models.Device, bool) {
+	if device, exists := sysApConfiguration.Devices[serial]; exists {
+		return device, true
+	}
+	for _, device := range sysApConfiguration.Devices {
+		if device.NativeID != nil && *device.NativeID == serial {
+			return device, true
+		}
+	}
+	return models.Device{}, false
 }
 
 // GetConfiguration retrieves the configuration from the system access point.
@@ -184,9 +607,156 @@ func (sysAp *SystemAccessPoint) CreateVirtualDevice(serial string, virtualDevice
 //
 // Possible errors include network issues, non-2xx HTTP responses, or unmarshalling errors.
 func (sysAp *SystemAccessPoint) GetConfiguration() (*models.Configuration, error) {
-	resp, err := sysAp.config.Client.R().Get(sysAp.GetUrl("configuration"))
+	return sysAp.GetConfigurationWithHeaders(nil)
+}
+
+// GetConfigurationWithHeaders retrieves the configuration like GetConfiguration and adds the given headers to the request.
+// The headers only apply to this request, the Authorization header cannot be overwritten.
+func (sysAp *SystemAccessPoint) GetConfigurationWithHeaders(headers http.Header) (*models.Configuration, error) {
+	span := sysAp.startSpan("GetConfiguration")
+	resp, err := sysAp.request(headers).Get(sysAp.GetUrl("configuration"))
+
+	configuration, err := deserializeRestResponse[models.Configuration](sysAp, models.OperationConfiguration, resp, err, "failed to get configuration")
+	endRestSpan(span, resp, err)
+	if err != nil {
+		return nil, err
+	}
+
+	sysAp.cachedConfigurationMutex.Lock()
+	sysAp.cachedConfiguration = configuration
+	sysAp.cachedConfigurationMutex.Unlock()
+	return configuration, nil
+}
+
+// GetRawConfiguration retrieves the configuration like GetConfiguration, but returns the response body without reading it
+// into memory, so that large configurations can be decoded as a stream. The caller must close the returned body.
+// The cached configuration is not updated.
+func (sysAp *SystemAccessPoint) GetRawConfiguration() (_ io.ReadCloser, resultErr error) {
+	// Count the call as successful or failed
+	defer func() {
+		sysAp.stats.record(models.OperationConfiguration, resultErr)
+	}()
+
+	span := sysAp.startSpan("GetRawConfiguration")
+	resp, err := sysAp.request(nil).SetDoNotParseResponse(true).Get(sysAp.GetUrl("configuration"))
+	if err != nil {
+		sysAp.config.Logger.Error("failed to get configuration", "error", err)
+		sysAp.emitError(err)
+		endRestSpan(span, resp, err)
+		return nil, err
+	}
+
+	if resp.IsError() {
+		body, _ := io.ReadAll(resp.RawBody())
+		_ = resp.RawBody().Close()
+		sysAp.config.Logger.Error("failed to get configuration", "status", resp.Status(), "body", string(body))
+		err := &APIError{
+			Message:    "failed to get configuration",
+			StatusCode: resp.StatusCode(),
+			Status:     resp.Status(),
+			Body:       string(body),
+		}
+		endRestSpan(span, resp, err)
+		return nil, err
+	}
+
+	endRestSpan(span, resp, nil)
+	return resp.RawBody(), nil
+}
+
+// GetConfigurationValue retrieves the raw configuration from the system access point and returns the value at the given
+// path, starting with the UUID of the system access point followed by JSON keys and array indices, e.g.
+// GetConfigurationValue(models.EmptyUUID, "sysap", "version"). Unlike Configuration.ValueAt, fields that are not modeled
+// are available as well. The cached configuration is not updated.
+//
+// Returns:
+//   - any: The value as decoded by encoding/json, i.e. objects are maps, arrays are slices and numbers are float64.
+//   - bool: Whether the path exists.
+//   - error: An error if the configuration cannot be retrieved or decoded.
+func (sysAp *SystemAccessPoint) GetConfigurationValue(path ...string) (any, bool, error) {
+	body, err := sysAp.GetRawConfiguration()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var document any
+	if err := json.NewDecoder(body).Decode(&document); err != nil {
+		return nil, false, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+	value, ok := models.ValueAtPath(document, path...)
+	return value, ok, nil
+}
+
+// GetCachedConfiguration returns the configuration most recently retrieved from the system access point,
+// or nil if the configuration has not been retrieved yet.
+func (sysAp *SystemAccessPoint) GetCachedConfiguration() *models.Configuration {
+	sysAp.cachedConfigurationMutex.RLock()
+	defer sysAp.cachedConfigurationMutex.RUnlock()
+	return sysAp.cachedConfiguration
+}
+
+// RefreshConfiguration retrieves the configuration from the system access point again and replaces the cached configuration,
+// e.g. after devices were added. The system access point itself is not modified.
+//
+// Returns:
+//   - *models.Configuration: A pointer to the refreshed configuration.
+//   - error: An error if the retrieval of the configuration fails, the cached configuration is kept in this case.
+func (sysAp *SystemAccessPoint) RefreshConfiguration() (*models.Configuration, error) {
+	return sysAp.GetConfiguration()
+}
+
+// GetScenes retrieves the scenes defined on the system access point.
+// The local API does not provide a dedicated scene endpoint, so the scenes are extracted from the configuration:
+// every channel with a scene function identifier is a scene actuator. The scenes are sorted by name and serial.
+//
+// Returns:
+//   - *models.Scenes: A pointer to the Scenes model containing the scene actuators per system access point.
+//   - error: An error if the configuration cannot be retrieved.
+func (sysAp *SystemAccessPoint) GetScenes() (*models.Scenes, error) {
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return nil, err
+	}
 
-	return deserializeRestResponse[models.Configuration](sysAp, resp, err, "failed to get configuration")
+	scenes := models.Scenes{}
+	for sysApID, sysApConfig := range *configuration {
+		actuators := []models.SceneActuator{}
+		for serial, device := range sysApConfig.Devices {
+			if device.Channels == nil {
+				continue
+			}
+			for channelID, channel := range *device.Channels {
+				if channel == nil || channel.FunctionID == nil || !models.IsSceneFunction(*channel.FunctionID) {
+					continue
+				}
+				actuators = append(actuators, models.SceneActuator{
+					Name:       sceneName(device, channel, serial),
+					Serial:     serial,
+					Channel:    channelID,
+					FunctionID: *channel.FunctionID,
+				})
+			}
+		}
+		slices.SortFunc(actuators, func(a, b models.SceneActuator) int {
+			return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Serial, b.Serial), cmp.Compare(a.Channel, b.Channel))
+		})
+		scenes[sysApID] = actuators
+	}
+	return &scenes, nil
+}
+
+// sceneName returns the display name of the scene channel, falling back to the device display name and the serial.
+func sceneName(device models.Device, channel *models.Channel, serial string) string {
+	if channel.DisplayName != nil && *channel.DisplayName != "" {
+		return *channel.DisplayName
+	}
+	if device.DisplayName != nil && *device.DisplayName != "" {
+		return *device.DisplayName
+	}
+	return serial
 }
 
 // GetDeviceList retrieves the list of devices from the system access point.
@@ -197,20 +767,89 @@ func (sysAp *SystemAccessPoint) GetConfiguration() (*models.Configuration, error
 //   - *models.DeviceList: A pointer to the DeviceList model containing the list of devices.
 //   - error: An error if the request fails or the response contains an error.
 func (sysAp *SystemAccessPoint) GetDeviceList() (*models.DeviceList, error) {
-	resp, err := sysAp.config.Client.R().Get(sysAp.GetUrl("devicelist"))
+	return sysAp.GetDeviceListWithHeaders(nil)
+}
+
+// GetDeviceListWithHeaders retrieves the list of devices like GetDeviceList and adds the given headers to the request.
+// The headers only apply to this request, the Authorization header cannot be overwritten.
+func (sysAp *SystemAccessPoint) GetDeviceListWithHeaders(headers http.Header) (*models.DeviceList, error) {
+	span := sysAp.startSpan("GetDeviceList")
+	resp, err := sysAp.request(headers).Get(sysAp.GetUrl("devicelist"))
 
-	return deserializeRestResponse[models.DeviceList](sysAp, resp, err, "failed to get device list")
+	result, err := deserializeRestResponse[models.DeviceList](sysAp, models.OperationDeviceList, resp, err, "failed to get device list")
+	endRestSpan(span, resp, err)
+	return result, err
 }
 
 // GetDevice retrieves a device with the specified serial number from the system access point.
 // It sends a GET request to the appropriate endpoint and parses the response into a DeviceResponse model.
 // Returns a pointer to the DeviceResponse and an error if the request fails or the response cannot be parsed.
+// Use DeviceResponse.Device to look up the device in the response.
 func (sysAp *SystemAccessPoint) GetDevice(serial string) (*models.DeviceResponse, error) {
-	resp, err := sysAp.config.Client.R().
-		SetPathParams(map[string]string{"uuid": sysAp.UUID, "serial": serial}).
+	return sysAp.GetDeviceWithHeaders(serial, nil)
+}
+
+// GetDeviceWithHeaders retrieves a device like GetDevice and adds the given headers to the request.
+// The headers only apply to this request, the Authorization header cannot be overwritten.
+func (sysAp *SystemAccessPoint) GetDeviceWithHeaders(serial string, headers http.Header) (*models.DeviceResponse, error) {
+	span := sysAp.startSpan("GetDevice")
+	resp, err := sysAp.request(headers).
+		SetPathParams(map[string]string{"uuid": sysAp.GetUUID(), "serial": serial}).
 		Get(sysAp.GetUrl("device/{uuid}/{serial}"))
 
-	return deserializeRestResponse[models.DeviceResponse](sysAp, resp, err, "failed to get device")
+	result, err := deserializeRestResponse[models.DeviceResponse](sysAp, models.OperationDevice, resp, err, "failed to get device")
+	endRestSpan(span, resp, err)
+	return result, err
+}
+
+// GetDeviceFromConfiguration retrieves the device with the specified serial number as it appears in the configuration of the system access point.
+// This is useful if the shape of the device returned by the device endpoint differs from the configuration, e.g. to resolve
+// the floor and room of the device within the floorplan.
+//
+// Returns an error if the configuration cannot be retrieved or the device is not part of the configuration. If the configuration
+// contains several system access points, the UUID of the system access point has to be set with SetUUID.
+func (sysAp *SystemAccessPoint) GetDeviceFromConfiguration(serial string) (*models.Device, error) {
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	sysApConfiguration, err := configuration.SysAp(sysAp.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+
+	device, exists := sysApConfiguration.Devices[serial]
+	if !exists {
+		return nil, fmt.Errorf("device not found: %s", serial)
+	}
+
+	return &device, nil
+}
+
+// IsDeviceReachable reports whether the device with the specified serial number is reachable.
+// It retrieves the configuration from the system access point and evaluates the reachability indicators of the device.
+// A device is considered reachable unless the system access point flags it as unresponsive or defect.
+//
+// Returns an error if the configuration cannot be retrieved or the device is not part of the configuration. If the configuration
+// contains several system access points, the UUID of the system access point has to be set with SetUUID.
+func (sysAp *SystemAccessPoint) IsDeviceReachable(serial string) (bool, error) {
+	configuration, err := sysAp.GetConfiguration()
+	if err != nil {
+		return false, err
+	}
+
+	sysApConfiguration, err := configuration.SysAp(sysAp.GetUUID())
+	if err != nil {
+		return false, err
+	}
+
+	device, exists := sysApConfiguration.Devices[serial]
+	if !exists {
+		return false, fmt.Errorf("device not found: %s", serial)
+	}
+
+	return device.IsReachable(), nil
 }
 
 // GetDatapoint retrieves a datapoint from the System Access Point using the provided serial number, channel, and datapoint identifiers.
@@ -228,11 +867,20 @@ func (sysAp *SystemAccessPoint) GetDevice(serial string) (*models.DeviceResponse
 //	*models.Datapoint - The retrieved datapoint object.
 //	error             - An error if the request or parsing fails.
 func (sysAp *SystemAccessPoint) GetDatapoint(serial string, channel string, datapoint string) (*models.GetDataPointResponse, error) {
-	resp, err := sysAp.config.Client.R().
-		SetPathParams(map[string]string{"uuid": sysAp.UUID, "serial": serial, "channel": channel, "datapoint": datapoint}).
+	return sysAp.GetDatapointWithHeaders(serial, channel, datapoint, nil)
+}
+
+// GetDatapointWithHeaders retrieves a datapoint like GetDatapoint and adds the given headers to the request.
+// The headers only apply to this request, the Authorization header cannot be overwritten.
+func (sysAp *SystemAccessPoint) GetDatapointWithHeaders(serial string, channel string, datapoint string, headers http.Header) (*models.GetDataPointResponse, error) {
+	span := sysAp.startSpan("GetDatapoint")
+	resp, err := sysAp.request(headers).
+		SetPathParams(map[string]string{"uuid": sysAp.GetUUID(), "serial": serial, "channel": channel, "datapoint": datapoint}).
 		Get(sysAp.GetUrl("datapoint/{uuid}/{serial}.{channel}.{datapoint}"))
 
-	return deserializeRestResponse[models.GetDataPointResponse](sysAp, resp, err, "failed to get datapoint")
+	result, err := deserializeRestResponse[models.GetDataPointResponse](sysAp, models.OperationDatapointGet, resp, err, "failed to get datapoint")
+	endRestSpan(span, resp, err)
+	return result, err
 }
 
 // SetDatapoint sets the value of a specified datapoint for a given device channel.
@@ -250,17 +898,32 @@ func (sysAp *SystemAccessPoint) GetDatapoint(serial string, channel string, data
 //	*models.SetDataPointResponse - The response from the SysAP after setting the datapoint.
 //	error                        - An error if the request fails or the response cannot be parsed.
 func (sysAp *SystemAccessPoint) SetDatapoint(serial string, channel string, datapoint string, value string) (*models.SetDataPointResponse, error) {
-	resp, err := sysAp.config.Client.R().
-		SetPathParams(map[string]string{"uuid": sysAp.UUID, "serial": serial, "channel": channel, "datapoint": datapoint}).
+	return sysAp.SetDatapointWithHeaders(serial, channel, datapoint, value, nil)
+}
+
+// SetDatapointWithHeaders sets the value of a datapoint like SetDatapoint and adds the given headers to the request.
+// The headers only apply to this request, the Authorization header cannot be overwritten.
+func (sysAp *SystemAccessPoint) SetDatapointWithHeaders(serial string, channel string, datapoint string, value string, headers http.Header) (*models.SetDataPointResponse, error) {
+	if err := sysAp.checkWritable(models.OperationDatapointSet, "set datapoint"); err != nil {
+		return nil, err
+	}
+
+	span := sysAp.startSpan("SetDatapoint")
+	resp, err := sysAp.request(headers).
+		SetPathParams(map[string]string{"uuid": sysAp.GetUUID(), "serial": serial, "channel": channel, "datapoint": datapoint}).
 		SetBody(value).
 		Put(sysAp.GetUrl("datapoint/{uuid}/{serial}.{channel}.{datapoint}"))
 
-	return deserializeRestResponse[models.SetDataPointResponse](sysAp, resp, err, "failed to set datapoint")
+	result, err := deserializeRestResponse[models.SetDataPointResponse](sysAp, models.OperationDatapointSet, resp, err, "failed to set datapoint")
+	endRestSpan(span, resp, err)
+	sysAp.writeAuditLog("SetDatapoint", fmt.Sprintf("%s.%s.%s", serial, channel, datapoint), value, result, err)
+	return result, err
 }
 
 // TriggerProxyDevice sends a request to trigger an action on a proxy device identified by its class and serial number.
 // It constructs the request URL using the SystemAccessPoint's UUID, the device class, serial, and the specified action.
 // The method returns the parsed DeviceResponse on success, or an error if the request fails or the response cannot be parsed.
+// Use DeviceResponse.Device to look up the proxy device in the response.
 //
 // Parameters:
 //   - class:  The class of the proxy device.
@@ -271,15 +934,24 @@ func (sysAp *SystemAccessPoint) SetDatapoint(serial string, channel string, data
 //   - *models.DeviceResponse: The response from the device if the action is successful.
 //   - error: An error if the request fails or the response cannot be parsed.
 func (sysAp *SystemAccessPoint) TriggerProxyDevice(class string, serial string, action string) (*models.DeviceResponse, error) {
-	resp, err := sysAp.config.Client.R().
-		SetPathParams(map[string]string{"uuid": sysAp.UUID, "class": class, "serial": serial, "action": action}).
+	if err := sysAp.checkWritable(models.OperationProxyDeviceTrigger, "trigger proxy device"); err != nil {
+		return nil, err
+	}
+
+	span := sysAp.startSpan("TriggerProxyDevice")
+	resp, err := sysAp.request(nil).
+		SetPathParams(map[string]string{"uuid": sysAp.GetUUID(), "class": class, "serial": serial, "action": action}).
 		Get(sysAp.GetUrl("proxydevice/{uuid}/{class}/{serial}/action/{action}"))
 
-	return deserializeRestResponse[models.DeviceResponse](sysAp, resp, err, "failed to trigger proxy device")
+	result, err := deserializeRestResponse[models.DeviceResponse](sysAp, models.OperationProxyDeviceTrigger, resp, err, "failed to trigger proxy device")
+	endRestSpan(span, resp, err)
+	sysAp.writeAuditLog("TriggerProxyDevice", fmt.Sprintf("%s/%s", class, serial), action, result, err)
+	return result, err
 }
 
 // SetProxyDeviceValue sets the value of a proxy device identified by its class and serial number.
 // It sends a PUT request to the system access point's API and returns the device response.
+// Use DeviceResponse.Device to look up the proxy device in the response.
 //
 // Parameters:
 //   - class:  The device class identifier.
@@ -290,24 +962,139 @@ func (sysAp *SystemAccessPoint) TriggerProxyDevice(class string, serial string,
 //   - *models.DeviceResponse: The response from the device if the operation is successful.
 //   - error: An error if the request fails or the response cannot be parsed.
 func (sysAp *SystemAccessPoint) SetProxyDeviceValue(class string, serial string, value string) (*models.DeviceResponse, error) {
-	resp, err := sysAp.config.Client.R().
-		SetPathParams(map[string]string{"uuid": sysAp.UUID, "class": class, "serial": serial, "value": value}).
+	if err := sysAp.checkWritable(models.OperationProxyDeviceSet, "set proxy device value"); err != nil {
+		return nil, err
+	}
+
+	span := sysAp.startSpan("SetProxyDeviceValue")
+	resp, err := sysAp.request(nil).
+		SetPathParams(map[string]string{"uuid": sysAp.GetUUID(), "class": class, "serial": serial, "value": value}).
 		Put(sysAp.GetUrl("proxydevice/{uuid}/{class}/{serial}/value/{value}"))
 
-	return deserializeRestResponse[models.DeviceResponse](sysAp, resp, err, "failed to set proxy device value")
+	result, err := deserializeRestResponse[models.DeviceResponse](sysAp, models.OperationProxyDeviceSet, resp, err, "failed to set proxy device value")
+	endRestSpan(span, resp, err)
+	sysAp.writeAuditLog("SetProxyDeviceValue", fmt.Sprintf("%s/%s", class, serial), value, result, err)
+	return result, err
 }
 
-func deserializeRestResponse[T any](sysAp *SystemAccessPoint, resp *resty.Response, err error, errorMessage string) (*T, error) {
-	// Check for errors
+// checkWritable returns ErrReadOnly for the operation if the client is in read-only mode. A blocked call is counted as failed.
+func (sysAp *SystemAccessPoint) checkWritable(operation models.Operation, description string) error {
+	if !sysAp.config.ReadOnly {
+		return nil
+	}
+
+	sysAp.config.Logger.Warn("write operation blocked in read-only mode", "operation", description)
+	err := fmt.Errorf("failed to %s: %w", description, ErrReadOnly)
+	sysAp.stats.record(operation, err)
+	return err
+}
+
+// request creates a new REST request with the given headers added.
+// The Authorization header and the configured authentication header are skipped, so that the basic authentication of the client cannot be overwritten.
+func (sysAp *SystemAccessPoint) request(headers http.Header) *resty.Request {
+	// Mark the request, so that the credential hook of this system access point applies to it
+	request := sysAp.config.Client.R()
+	request.SetContext(context.WithValue(request.Context(), requestOwnerKey{}, sysAp))
+	for name, values := range headers {
+		if name = http.CanonicalHeaderKey(name); name == defaultAuthHeaderName || name == authHeaderName(sysAp.config) {
+			sysAp.config.Logger.Warn("ignoring custom authentication header", "header", name)
+			continue
+		}
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+	return request
+}
+
+// credentials returns the credentials for the next request, obtained from the credential provider if one is configured.
+func (sysAp *SystemAccessPoint) credentials(ctx context.Context) (string, string, error) {
+	if sysAp.config.CredentialProvider == nil {
+		return sysAp.config.Username, sysAp.config.Password, nil
+	}
+
+	username, password, err := sysAp.config.CredentialProvider(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to obtain credentials: %w", err)
+	}
+	return username, password, nil
+}
+
+// requestOwnerKey is the context key of the system access point that created a REST request
+type requestOwnerKey struct{}
+
+// refreshCredentials sets the credentials returned by the credential provider on the REST request. An error aborts the request.
+// Requests created by other system access points sharing the REST client are left unchanged.
+func (sysAp *SystemAccessPoint) refreshCredentials(_ *resty.Client, request *resty.Request) error {
+	if owner, _ := request.Context().Value(requestOwnerKey{}).(*SystemAccessPoint); owner != sysAp {
+		return nil
+	}
+
+	username, password, err := sysAp.credentials(request.Context())
+	if err != nil {
+		return err
+	}
+
+	if authHeaderName(sysAp.config) == defaultAuthHeaderName {
+		request.SetBasicAuth(username, password)
+	} else {
+		request.SetHeader(authHeaderName(sysAp.config), basicAuthorization(username, password))
+	}
+	return nil
+}
+
+// authHeaderName returns the canonical name of the header carrying the basic authentication credentials.
+func authHeaderName(config *Config) string {
+	if config.AuthHeaderName == "" {
+		return defaultAuthHeaderName
+	}
+	return http.CanonicalHeaderKey(config.AuthHeaderName)
+}
+
+// apiVersion returns the version segment of the API paths, falling back to the default version if none is configured.
+func apiVersion(config *Config) string {
+	if config.APIVersion == "" {
+		return defaultAPIVersion
+	}
+	return config.APIVersion
+}
+
+// basicAuthorization returns the basic authentication header value for the given credentials.
+func basicAuthorization(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString(fmt.Appendf(nil, "%s:%s", username, password))
+}
+
+// checkRestResponse logs and returns the transport error of a request or an APIError if the response has an HTTP error status.
+func checkRestResponse(sysAp *SystemAccessPoint, resp *resty.Response, err error, errorMessage string) error {
 	if err != nil {
 		sysAp.config.Logger.Error(errorMessage, "error", err)
 		sysAp.emitError(err)
-		return nil, err
+		return err
 	}
 
 	if resp.IsError() {
 		sysAp.config.Logger.Error(errorMessage, "status", resp.Status(), "body", resp.String())
-		return nil, fmt.Errorf("%s: %s", errorMessage, resp.String())
+		return &APIError{
+			Message:    errorMessage,
+			StatusCode: resp.StatusCode(),
+			Status:     resp.Status(),
+			Body:       resp.String(),
+		}
+	}
+
+	return nil
+}
+
+// deserializeRestResponse checks the response of the operation for errors, unmarshals its body and counts the call in the statistics.
+func deserializeRestResponse[T any](sysAp *SystemAccessPoint, operation models.Operation, resp *resty.Response, err error, errorMessage string) (_ *T, resultErr error) {
+	// Count the call as successful or failed
+	defer func() {
+		sysAp.stats.record(operation, resultErr)
+	}()
+
+	// Check for errors
+	if err := checkRestResponse(sysAp, resp, err, errorMessage); err != nil {
+		return nil, err
 	}
 
 	var object T
@@ -317,5 +1104,15 @@ func deserializeRestResponse[T any](sysAp *SystemAccessPoint, resp *resty.Respon
 		return nil, err
 	}
 
+	// Warn about fields that are not captured by the model if strict response validation is enabled
+	if sysAp.config.StrictResponseValidation {
+		fields, err := findUncapturedFields(resp.Body(), &object)
+		if err != nil {
+			sysAp.config.Logger.Warn("failed to validate response body", "error", err)
+		} else if len(fields) > 0 {
+			sysAp.config.Logger.Warn("response contains fields not captured by the model", "fields", strings.Join(fields, ","))
+		}
+	}
+
 	return &object, nil
 }
@@ -0,0 +1,138 @@
+package freeathome
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// auditTestEntry mirrors the fields of an audit log line for decoding in tests
+type auditTestEntry struct {
+	Timestamp string         `json:"timestamp"`
+	Operation string         `json:"operation"`
+	Target    string         `json:"target"`
+	Value     string         `json:"value"`
+	Result    map[string]any `json:"result"`
+	Error     string         `json:"error"`
+}
+
+// decodeAuditLog decodes all lines written to the audit log buffer
+func decodeAuditLog(t *testing.T, buf *bytes.Buffer) []auditTestEntry {
+	t.Helper()
+
+	var entries []auditTestEntry
+	for line := range strings.SplitSeq(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		var entry auditTestEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode audit log line '%s': %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSystemAccessPointSetDatapointAuditLog(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	var auditLog bytes.Buffer
+	sysAp.config.WriteAuditLog = &auditLog
+	sysAp.clock = &fakeClock{now: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "set_datapoint.json"),
+			Header:     make(http.Header),
+		},
+	})
+
+	if _, err := sysAp.SetDatapoint("abcd1234", "ch0000", "idp0001", "123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries := decodeAuditLog(t, &auditLog)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 audit log entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Timestamp != "2025-01-02T03:04:05Z" {
+		t.Errorf("Expected timestamp '2025-01-02T03:04:05Z', got '%s'", entry.Timestamp)
+	}
+	if entry.Operation != "SetDatapoint" {
+		t.Errorf("Expected operation 'SetDatapoint', got '%s'", entry.Operation)
+	}
+	if entry.Target != "abcd1234.ch0000.idp0001" {
+		t.Errorf("Expected target 'abcd1234.ch0000.idp0001', got '%s'", entry.Target)
+	}
+	if entry.Value != "123" {
+		t.Errorf("Expected value '123', got '%s'", entry.Value)
+	}
+	if entry.Result == nil {
+		t.Error("Expected result to be recorded")
+	}
+	if entry.Error != "" {
+		t.Errorf("Expected no error, got '%s'", entry.Error)
+	}
+}
+
+func TestSystemAccessPointSetDatapointAuditLogError(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	var auditLog bytes.Buffer
+	sysAp.config.WriteAuditLog = &auditLog
+	sysAp.config.Client.SetTransport(&MockRoundTripper{Err: errors.New("Test Error")})
+
+	if _, err := sysAp.SetDatapoint("abcd1234", "ch0000", "idp0001", "123"); err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+
+	entries := decodeAuditLog(t, &auditLog)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 audit log entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Operation != "SetDatapoint" {
+		t.Errorf("Expected operation 'SetDatapoint', got '%s'", entry.Operation)
+	}
+	if entry.Value != "123" {
+		t.Errorf("Expected value '123', got '%s'", entry.Value)
+	}
+	if entry.Result != nil {
+		t.Errorf("Expected no result, got %v", entry.Result)
+	}
+	if !strings.Contains(entry.Error, "Test Error") {
+		t.Errorf("Expected error to contain 'Test Error', got '%s'", entry.Error)
+	}
+}
+
+func TestSystemAccessPointProxyDeviceAuditLog(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	var auditLog bytes.Buffer
+	sysAp.config.WriteAuditLog = &auditLog
+	sysAp.config.Client.SetTransport(&MockRoundTripper{Err: errors.New("Test Error")})
+
+	_, _ = sysAp.TriggerProxyDevice("class", "abcd1234", "on")
+	_, _ = sysAp.SetProxyDeviceValue("class", "abcd1234", "42")
+
+	entries := decodeAuditLog(t, &auditLog)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 audit log entries, got %d", len(entries))
+	}
+	if entries[0].Operation != "TriggerProxyDevice" || entries[0].Target != "class/abcd1234" || entries[0].Value != "on" {
+		t.Errorf("Unexpected audit log entry for trigger: %+v", entries[0])
+	}
+	if entries[1].Operation != "SetProxyDeviceValue" || entries[1].Target != "class/abcd1234" || entries[1].Value != "42" {
+		t.Errorf("Unexpected audit log entry for set value: %+v", entries[1])
+	}
+}
+
+func TestSystemAccessPointAuditLogDisabled(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{Err: errors.New("Test Error")})
+
+	// Writing without an audit log writer must not fail
+	if _, err := sysAp.SetDatapoint("abcd1234", "ch0000", "idp0001", "123"); err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+}
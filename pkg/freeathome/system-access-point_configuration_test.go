@@ -179,3 +179,128 @@ func TestSystemAccessPointGetConfigurationUnmarshalError(t *testing.T) {
 		t.Errorf(expectedErrorGotValue, expected, err)
 	}
 }
+
+// TestSystemAccessPointGetRawConfiguration tests that GetRawConfiguration returns the unparsed response body.
+func TestSystemAccessPointGetRawConfiguration(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, true, false)
+	expected, err := io.ReadAll(loadTestResponseBody(t, "configuration.json"))
+	if err != nil {
+		t.Fatalf("Failed to read test response: %v", err)
+	}
+	roundtripper := &MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "configuration.json"),
+			Header:     make(http.Header),
+		},
+	}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	body, err := sysAp.GetRawConfiguration()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() { _ = body.Close() }()
+
+	actual, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	if string(actual) != string(expected) {
+		t.Error("Expected the raw configuration to match the response body")
+	}
+	if logOutput := buf.String(); logOutput != "" {
+		t.Errorf("Expected no log output, got: %s", logOutput)
+	}
+	expectedUrl := "https://localhost/fhapi/v1/api/rest/configuration"
+	if roundtripper.Request.URL.String() != expectedUrl {
+		t.Errorf("Expected URL '%s', got '%s'", expectedUrl, roundtripper.Request.URL.String())
+	}
+	if stats := sysAp.Stats()[models.OperationConfiguration]; stats.Successes != 1 || stats.Failures != 0 {
+		t.Errorf("Expected one successful call in the statistics, got %+v", stats)
+	}
+}
+
+// TestSystemAccessPointGetRawConfigurationErrorResponse tests that GetRawConfiguration returns an API error for error responses.
+func TestSystemAccessPointGetRawConfigurationErrorResponse(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Status:     "Internal Server Error",
+			Body:       io.NopCloser(strings.NewReader("Internal Server Error")),
+			Header:     make(http.Header),
+		},
+	}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	body, err := sysAp.GetRawConfiguration()
+	if body != nil {
+		t.Error(expectedNil)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Body != "Internal Server Error" {
+		t.Fatalf("Expected an API error with the response body, got %v", err)
+	}
+	if logOutput := buf.String(); !strings.Contains(logOutput, "msg=\"failed to get configuration\"") {
+		t.Errorf(unexpectedLogOutput, logOutput)
+	}
+}
+
+// TestSystemAccessPointGetConfigurationValue tests that GetConfigurationValue reads modeled and unmodeled fields from the raw configuration.
+func TestSystemAccessPointGetConfigurationValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []string
+		expected any
+		ok       bool
+	}{
+		{name: "Modeled field", path: []string{models.EmptyUUID, "devices", "ABB7F595EC47", "displayName"}, expected: "Sensoreinheit 2-fach", ok: true},
+		{name: "Unmodeled field", path: []string{models.EmptyUUID, "sysap", "version"}, expected: "3.4.3-13550", ok: true},
+		{name: "Array index", path: []string{models.EmptyUUID, "sysap", "sunRiseTimes", "1"}, expected: float64(364), ok: true},
+		{name: "Missing key", path: []string{models.EmptyUUID, "devices", "ABB700000001", "displayName"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sysAp, _, _ := setupSysAp(t, true, false)
+			sysAp.config.Client.SetTransport(&MockRoundTripper{
+				Response: &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       loadTestResponseBody(t, "configuration.json"),
+					Header:     make(http.Header),
+				},
+			})
+
+			value, ok, err := sysAp.GetConfigurationValue(tt.path...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %t, got %t", tt.ok, ok)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected value %v, got %v", tt.expected, value)
+			}
+			if sysAp.GetCachedConfiguration() != nil {
+				t.Error("Expected the cached configuration not to be updated")
+			}
+		})
+	}
+}
+
+// TestSystemAccessPointGetConfigurationValueDecodeError tests that an invalid configuration is reported as an error.
+func TestSystemAccessPointGetConfigurationValueDecodeError(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("{")),
+			Header:     make(http.Header),
+		},
+	})
+
+	if _, _, err := sysAp.GetConfigurationValue(models.EmptyUUID); err == nil || !strings.Contains(err.Error(), "failed to decode configuration") {
+		t.Errorf("Expected decode error, got %v", err)
+	}
+}
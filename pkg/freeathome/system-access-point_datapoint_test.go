@@ -148,7 +148,7 @@ func TestSystemAccessPointGetDatapointUnmarshalError(t *testing.T) {
 	sysAp, buf, _ := setupSysAp(t, true, false)
 	response := &http.Response{
 		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(strings.NewReader(`{"00000000-0000-0000-0000-000000000000":{"values":[123]}}`)),
+		Body:       io.NopCloser(strings.NewReader(`{"00000000-0000-0000-0000-000000000000":{"values":[true]}}`)),
 		Header:     make(http.Header),
 	}
 	roundtripper := &MockRoundTripper{
@@ -175,12 +175,39 @@ func TestSystemAccessPointGetDatapointUnmarshalError(t *testing.T) {
 	}
 
 	// Check if the error message is correct
-	expected := "json: cannot unmarshal number into Go struct field GetDataPoint.values of type string"
+	expected := "data point value is neither a string nor a number: true"
 	if err.Error() != expected {
 		t.Errorf(expectedErrorGotValue, expected, err)
 	}
 }
 
+// TestSystemAccessPointGetDatapointNumericValue tests that numeric values are accepted whether the firmware quotes them or not.
+func TestSystemAccessPointGetDatapointNumericValue(t *testing.T) {
+	for _, body := range []string{
+		`{"00000000-0000-0000-0000-000000000000":{"values":["123"]}}`,
+		`{"00000000-0000-0000-0000-000000000000":{"values":[123]}}`,
+	} {
+		t.Run(body, func(t *testing.T) {
+			sysAp, _, _ := setupSysAp(t, true, false)
+			sysAp.config.Client.SetTransport(&MockRoundTripper{
+				Response: &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				},
+			})
+
+			result, err := sysAp.GetDatapoint("abcd1234", "ch0000", "odp0001")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if values := (*result)[models.EmptyUUID].Values; len(values) != 1 || values[0] != "123" {
+				t.Errorf("Expected value '123', got %v", values)
+			}
+		})
+	}
+}
+
 func TestSystemAccessPointSetDatapoint(t *testing.T) {
 	sysAp, buf, _ := setupSysAp(t, true, false)
 	response := &http.Response{
@@ -56,8 +56,12 @@ func TestSystemAccessPointGetDevice(t *testing.T) {
 	if len((*result)[models.EmptyUUID].Devices) != 1 {
 		t.Errorf("Expected 1 device, got %d", len((*result)[models.EmptyUUID].Devices))
 	}
-	if *(*result)[models.EmptyUUID].Devices["600028E1ED13"].NativeID != "47110815AA" {
-		t.Errorf("Expected created virtual device serial to be '47110815AA', got '%s'", *(*result)[models.EmptyUUID].Devices["600028E1ED13"].NativeID)
+	device, exists := result.Device("600028E1ED13")
+	if !exists {
+		t.Fatal("Expected the device to be part of the response")
+	}
+	if *device.NativeID != "47110815AA" {
+		t.Errorf("Expected created virtual device serial to be '47110815AA', got '%s'", *device.NativeID)
 	}
 }
 
@@ -180,3 +184,110 @@ func TestSystemAccessPointGetDeviceUnmarshalError(t *testing.T) {
 		t.Errorf(expectedErrorGotValue, expected, err)
 	}
 }
+
+func TestSystemAccessPointGetDeviceFromConfiguration(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "configuration.json"),
+			Header:     make(http.Header),
+		},
+	}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	device, err := sysAp.GetDeviceFromConfiguration("ABB7F595EC47")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Check if the log output is empty
+	if logOutput := buf.String(); logOutput != "" {
+		t.Errorf("Expected no log output, got: %s", logOutput)
+	}
+
+	// The device is extracted from the configuration
+	expectedUrl := "https://localhost/fhapi/v1/api/rest/configuration"
+	if roundtripper.Request.URL.String() != expectedUrl {
+		t.Errorf("Expected URL '%s', got '%s'", expectedUrl, roundtripper.Request.URL.String())
+	}
+	if device.DisplayName == nil || *device.DisplayName != "Sensoreinheit 2-fach" {
+		t.Errorf("Expected display name 'Sensoreinheit 2-fach', got %v", device.DisplayName)
+	}
+
+	// The floorplan context is kept
+	if device.Floor == nil || *device.Floor != "02" || device.Room == nil || *device.Room != "0D" {
+		t.Errorf("Expected floor 02 and room 0D, got %v and %v", device.Floor, device.Room)
+	}
+	if device.Channels == nil || len(*device.Channels) != 2 {
+		t.Errorf("Expected 2 channels, got %v", device.Channels)
+	}
+}
+
+func TestSystemAccessPointGetDeviceFromConfigurationNotFound(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "configuration.json"),
+			Header:     make(http.Header),
+		},
+	})
+
+	device, err := sysAp.GetDeviceFromConfiguration("ABB7FFFFFFFF")
+	if err == nil || err.Error() != "device not found: ABB7FFFFFFFF" {
+		t.Errorf(expectedErrorGotValue, "device not found: ABB7FFFFFFFF", err)
+	}
+	if device != nil {
+		t.Error(expectedNil)
+	}
+}
+
+func TestSystemAccessPointGetDeviceFromConfigurationError(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{Err: errors.New("network error")})
+
+	device, err := sysAp.GetDeviceFromConfiguration("ABB7F595EC47")
+	if err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+	if device != nil {
+		t.Error(expectedNil)
+	}
+}
+
+func TestSystemAccessPointGetDeviceFromConfigurationMultipleSysAps(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	body := `{"sysap-a":{"sysapName":"House","devices":{"ABB7F595EC47":{"displayName":"Switch"}}},"sysap-b":{"sysapName":"Garage","devices":{"ABB7013B85DE":{"displayName":"Door"}}}}`
+	setConfigurationResponse := func() {
+		sysAp.config.Client.SetTransport(&MockRoundTripper{
+			Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			},
+		})
+	}
+
+	// Without a selected system access point the device cannot be looked up
+	setConfigurationResponse()
+	device, err := sysAp.GetDeviceFromConfiguration("ABB7013B85DE")
+	var multipleErr *models.MultipleSysApsError
+	if !errors.As(err, &multipleErr) {
+		t.Fatalf("Expected a MultipleSysApsError, got %v", err)
+	}
+	if device != nil {
+		t.Error(expectedNil)
+	}
+
+	// The selected system access point is used
+	sysAp.SetUUID("sysap-b")
+	setConfigurationResponse()
+	device, err = sysAp.GetDeviceFromConfiguration("ABB7013B85DE")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if device.DisplayName == nil || *device.DisplayName != "Door" {
+		t.Errorf("Expected display name 'Door', got %v", device.DisplayName)
+	}
+}
@@ -0,0 +1,291 @@
+package freeathome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// setHeaderTestResponse sets a fresh empty response on the mock round tripper
+func setHeaderTestResponse(roundtripper *MockRoundTripper) {
+	roundtripper.Response = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Header:     make(http.Header),
+	}
+}
+
+// TestSystemAccessPointRequestHeaders tests that custom headers are only added to the request they are passed to.
+func TestSystemAccessPointRequestHeaders(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	headers := http.Header{}
+	headers.Set("X-Correlation-Id", "abc123")
+	headers.Add("x-multi", "one")
+	headers.Add("x-multi", "two")
+
+	setHeaderTestResponse(roundtripper)
+	if _, err := sysAp.GetDeviceListWithHeaders(headers); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value := roundtripper.Request.Header.Get("X-Correlation-Id"); value != "abc123" {
+		t.Errorf("Expected header 'X-Correlation-Id' to be 'abc123', got '%s'", value)
+	}
+	if values := roundtripper.Request.Header.Values("X-Multi"); len(values) != 2 || values[0] != "one" || values[1] != "two" {
+		t.Errorf("Expected header 'X-Multi' to be [one two], got %v", values)
+	}
+
+	// Subsequent default calls do not contain the custom headers
+	setHeaderTestResponse(roundtripper)
+	if _, err := sysAp.GetDeviceList(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value := roundtripper.Request.Header.Get("X-Correlation-Id"); value != "" {
+		t.Errorf("Expected no 'X-Correlation-Id' header on a default call, got '%s'", value)
+	}
+	if value := roundtripper.Request.Header.Get("X-Multi"); value != "" {
+		t.Errorf("Expected no 'X-Multi' header on a default call, got '%s'", value)
+	}
+}
+
+// TestSystemAccessPointRequestHeadersAuthorization tests that a custom Authorization header does not overwrite the basic authentication.
+func TestSystemAccessPointRequestHeadersAuthorization(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+	setHeaderTestResponse(roundtripper)
+
+	headers := http.Header{}
+	headers.Set("authorization", "Bearer token")
+
+	if _, err := sysAp.GetConfigurationWithHeaders(headers); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	username, password, ok := roundtripper.Request.BasicAuth()
+	if !ok || username != "user" || password != "password" {
+		t.Errorf("Expected basic authentication for 'user', got '%s' (ok: %t)", roundtripper.Request.Header.Get("Authorization"), ok)
+	}
+	if !strings.Contains(buf.String(), `msg="ignoring custom authentication header" header=Authorization`) {
+		t.Errorf("Expected a warning about the ignored Authorization header, got: %s", buf.String())
+	}
+}
+
+// TestSystemAccessPointRequestHeadersVariants tests that the header variants of the datapoint and device methods add the headers.
+func TestSystemAccessPointRequestHeadersVariants(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	headers := http.Header{}
+	headers.Set("X-Correlation-Id", "abc123")
+
+	calls := map[string]func(){
+		"GetDeviceWithHeaders":     func() { _, _ = sysAp.GetDeviceWithHeaders("ABB7F595EC47", headers) },
+		"GetDatapointWithHeaders":  func() { _, _ = sysAp.GetDatapointWithHeaders("ABB7F595EC47", "ch0000", "odp0000", headers) },
+		"SetDatapointWithHeaders":  func() { _, _ = sysAp.SetDatapointWithHeaders("ABB7F595EC47", "ch0000", "idp0000", "1", headers) },
+		"GetDeviceListWithHeaders": func() { _, _ = sysAp.GetDeviceListWithHeaders(headers) },
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			setHeaderTestResponse(roundtripper)
+			call()
+			if value := roundtripper.Request.Header.Get("X-Correlation-Id"); value != "abc123" {
+				t.Errorf("Expected header 'X-Correlation-Id' to be 'abc123', got '%s'", value)
+			}
+		})
+	}
+}
+
+// setupSysApWithAuthHeader creates a system access point without TLS that sends the credentials in the given header.
+func setupSysApWithAuthHeader(t *testing.T, headerName string) (*SystemAccessPoint, *ThreadSafeBuffer) {
+	t.Helper()
+
+	var buf ThreadSafeBuffer
+	config := NewConfig("localhost", "user", "password")
+	config.TLSEnabled = false
+	config.AuthHeaderName = headerName
+	config.Logger = NewDefaultLogger(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return MustNewSystemAccessPoint(config), &buf
+}
+
+// TestSystemAccessPointCustomAuthHeader tests that REST requests carry the credentials in the configured header.
+func TestSystemAccessPointCustomAuthHeader(t *testing.T) {
+	sysAp, buf := setupSysApWithAuthHeader(t, "x-proxy-authorization")
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+	setHeaderTestResponse(roundtripper)
+
+	// The configured header cannot be overwritten either
+	headers := http.Header{}
+	headers.Set("X-Proxy-Authorization", "Bearer token")
+	if _, err := sysAp.GetDeviceListWithHeaders(headers); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "Basic dXNlcjpwYXNzd29yZA=="
+	if values := roundtripper.Request.Header.Values("X-Proxy-Authorization"); len(values) != 1 || values[0] != expected {
+		t.Errorf("Expected header 'X-Proxy-Authorization' to be '%s', got %v", expected, values)
+	}
+	if value := roundtripper.Request.Header.Get("Authorization"); value != "" {
+		t.Errorf("Expected no 'Authorization' header, got '%s'", value)
+	}
+	if !strings.Contains(buf.String(), `msg="ignoring custom authentication header" header=X-Proxy-Authorization`) {
+		t.Errorf("Expected a warning about the ignored authentication header, got: %s", buf.String())
+	}
+}
+
+// TestSystemAccessPointDefaultAuthHeader tests that an empty header name uses the Authorization header.
+func TestSystemAccessPointDefaultAuthHeader(t *testing.T) {
+	sysAp, _ := setupSysApWithAuthHeader(t, "")
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+	setHeaderTestResponse(roundtripper)
+
+	if _, err := sysAp.GetDeviceList(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	username, password, ok := roundtripper.Request.BasicAuth()
+	if !ok || username != "user" || password != "password" {
+		t.Errorf("Expected basic authentication for 'user', got '%s' (ok: %t)", roundtripper.Request.Header.Get("Authorization"), ok)
+	}
+}
+
+// setupSysApWithCredentialProvider initializes a SystemAccessPoint whose credentials are obtained from the given provider.
+func setupSysApWithCredentialProvider(t *testing.T, headerName string, provider func(ctx context.Context) (string, string, error)) *SystemAccessPoint {
+	t.Helper()
+
+	config := NewConfig("localhost", "user", "password")
+	config.TLSEnabled = false
+	config.AuthHeaderName = headerName
+	config.CredentialProvider = provider
+	config.Logger = NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+	return MustNewSystemAccessPoint(config)
+}
+
+// TestSystemAccessPointCredentialProvider tests that every REST request carries the credentials currently returned by the provider.
+func TestSystemAccessPointCredentialProvider(t *testing.T) {
+	tests := []struct {
+		headerName string
+		expected   []string
+	}{
+		{headerName: "", expected: []string{"Basic dXNlcjE6cGFzc3dvcmQx", "Basic dXNlcjI6cGFzc3dvcmQy"}},
+		{headerName: "X-Proxy-Authorization", expected: []string{"Basic dXNlcjE6cGFzc3dvcmQx", "Basic dXNlcjI6cGFzc3dvcmQy"}},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+		sysAp := setupSysApWithCredentialProvider(t, tt.headerName, func(ctx context.Context) (string, string, error) {
+			calls++
+			return fmt.Sprintf("user%d", calls), fmt.Sprintf("password%d", calls), nil
+		})
+		roundtripper := &MockRoundTripper{}
+		sysAp.config.Client.SetTransport(roundtripper)
+
+		for i, expected := range tt.expected {
+			setHeaderTestResponse(roundtripper)
+			if _, err := sysAp.GetDeviceList(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if value := roundtripper.Request.Header.Get(authHeaderName(sysAp.config)); value != expected {
+				t.Errorf("Request %d with header '%s': expected credentials '%s', got '%s'", i, tt.headerName, expected, value)
+			}
+		}
+		if calls != len(tt.expected) {
+			t.Errorf("Expected the credential provider to be called %d times, got %d", len(tt.expected), calls)
+		}
+	}
+}
+
+// TestSystemAccessPointCredentialProviderSharedClient tests that system access points sharing a REST client send their own credentials.
+func TestSystemAccessPointCredentialProviderSharedClient(t *testing.T) {
+	client := resty.New()
+	roundtripper := &MockRoundTripper{}
+	client.SetTransport(roundtripper)
+
+	newSysAp := func(username string) *SystemAccessPoint {
+		config := NewConfig("localhost", username, "password")
+		config.Client = client
+		config.CredentialProvider = func(ctx context.Context) (string, string, error) {
+			return username, "password", nil
+		}
+		config.Logger = NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+		return MustNewSystemAccessPoint(config)
+	}
+	first := newSysAp("user1")
+	second := newSysAp("user2")
+
+	for _, tt := range []struct {
+		sysAp    *SystemAccessPoint
+		expected string
+	}{
+		{sysAp: first, expected: "Basic dXNlcjE6cGFzc3dvcmQ="},
+		{sysAp: second, expected: "Basic dXNlcjI6cGFzc3dvcmQ="},
+	} {
+		setHeaderTestResponse(roundtripper)
+		if _, err := tt.sysAp.GetDeviceList(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if value := roundtripper.Request.Header.Get("Authorization"); value != tt.expected {
+			t.Errorf("Expected credentials '%s', got '%s'", tt.expected, value)
+		}
+	}
+}
+
+// TestSystemAccessPointCredentialProviderError tests that a REST request is aborted if the credentials cannot be obtained.
+func TestSystemAccessPointCredentialProviderError(t *testing.T) {
+	providerErr := errors.New("vault unavailable")
+	sysAp := setupSysApWithCredentialProvider(t, "", func(ctx context.Context) (string, string, error) {
+		return "", "", providerErr
+	})
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+	setHeaderTestResponse(roundtripper)
+
+	if _, err := sysAp.GetDeviceList(); !errors.Is(err, providerErr) {
+		t.Errorf("Expected the credential provider error, got %v", err)
+	}
+	if roundtripper.Request != nil {
+		t.Error("Expected no request to be sent")
+	}
+}
+
+// TestSystemAccessPointDisableAuth tests that REST requests carry no credentials if authentication is disabled.
+func TestSystemAccessPointDisableAuth(t *testing.T) {
+	for _, headerName := range []string{"", "X-Proxy-Authorization"} {
+		config := NewConfig("localhost", "user", "password")
+		config.TLSEnabled = false
+		config.AuthHeaderName = headerName
+		config.DisableAuth = true
+		config.CredentialProvider = func(ctx context.Context) (string, string, error) {
+			t.Error("Expected the credential provider not to be called")
+			return "user", "password", nil
+		}
+		config.Logger = NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+		sysAp := MustNewSystemAccessPoint(config)
+		roundtripper := &MockRoundTripper{}
+		sysAp.config.Client.SetTransport(roundtripper)
+		setHeaderTestResponse(roundtripper)
+
+		if _, err := sysAp.GetDeviceList(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, name := range []string{"Authorization", "X-Proxy-Authorization"} {
+			if value := roundtripper.Request.Header.Get(name); value != "" {
+				t.Errorf("Expected no '%s' header with auth header name '%s', got '%s'", name, headerName, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package freeathome
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const unreachableDeviceConfiguration = `{
+  "00000000-0000-0000-0000-000000000000": {
+    "devices": {
+      "ABB7F595EC47": {"displayName": "Unresponsive", "unresponsive": true, "defect": false},
+      "ABB7013B85DE": {"displayName": "Defect", "unresponsive": false, "defect": true}
+    },
+    "floorplan": {"floors": {}},
+    "sysapName": "Test",
+    "users": {}
+  }
+}`
+
+func TestSystemAccessPointIsDeviceReachable(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "configuration.json"),
+			Header:     make(http.Header),
+		},
+	})
+
+	reachable, err := sysAp.IsDeviceReachable("ABB7F595EC47")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reachable {
+		t.Error("Expected device to be reachable")
+	}
+}
+
+func TestSystemAccessPointIsDeviceReachableUnreachable(t *testing.T) {
+	for _, serial := range []string{"ABB7F595EC47", "ABB7013B85DE"} {
+		t.Run(serial, func(t *testing.T) {
+			sysAp, _, _ := setupSysAp(t, true, false)
+			sysAp.config.Client.SetTransport(&MockRoundTripper{
+				Response: &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(unreachableDeviceConfiguration)),
+					Header:     make(http.Header),
+				},
+			})
+
+			reachable, err := sysAp.IsDeviceReachable(serial)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if reachable {
+				t.Error("Expected device to be unreachable")
+			}
+		})
+	}
+}
+
+func TestSystemAccessPointIsDeviceReachableNotFound(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(unreachableDeviceConfiguration)),
+			Header:     make(http.Header),
+		},
+	})
+
+	reachable, err := sysAp.IsDeviceReachable("ABB700000000")
+	if err == nil || err.Error() != "device not found: ABB700000000" {
+		t.Errorf(expectedErrorGotValue, "device not found: ABB700000000", err)
+	}
+	if reachable {
+		t.Error("Expected device to be unreachable")
+	}
+}
+
+func TestSystemAccessPointIsDeviceReachableCallError(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Err: errors.New("Test Error"),
+	})
+
+	reachable, err := sysAp.IsDeviceReachable("ABB7F595EC47")
+	if err == nil {
+		t.Error(expectedErrorGotNil)
+	}
+	if reachable {
+		t.Error("Expected device to be unreachable")
+	}
+}
@@ -0,0 +1,78 @@
+package freeathome
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+func TestSystemAccessPointReadOnlyBlocksWrites(t *testing.T) {
+	writes := map[string]func(sysAp *SystemAccessPoint) error{
+		"SetDatapoint": func(sysAp *SystemAccessPoint) error {
+			_, err := sysAp.SetDatapoint("ABB7F595EC47", "ch0000", "idp0000", "1")
+			return err
+		},
+		"SetProxyDeviceValue": func(sysAp *SystemAccessPoint) error {
+			_, err := sysAp.SetProxyDeviceValue("shutter", "ABB7F595EC47", "50")
+			return err
+		},
+		"TriggerProxyDevice": func(sysAp *SystemAccessPoint) error {
+			_, err := sysAp.TriggerProxyDevice("shutter", "ABB7F595EC47", "up")
+			return err
+		},
+		"CreateVirtualDevice": func(sysAp *SystemAccessPoint) error {
+			_, err := sysAp.CreateVirtualDevice("6000D2CB27B2", &models.VirtualDevice{})
+			return err
+		},
+		"UpdateVirtualDevice": func(sysAp *SystemAccessPoint) error {
+			_, err := sysAp.UpdateVirtualDevice("6000D2CB27B2", &models.VirtualDevicePatch{})
+			return err
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			sysAp, buf, _ := setupSysAp(t, true, false)
+			sysAp.config.ReadOnly = true
+			roundtripper := &MockRoundTripper{}
+			sysAp.config.Client.SetTransport(roundtripper)
+
+			err := write(sysAp)
+			if !errors.Is(err, ErrReadOnly) {
+				t.Fatalf("Expected ErrReadOnly, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "client is in read-only mode") {
+				t.Errorf("Expected a read-only error message, got '%s'", err.Error())
+			}
+			if roundtripper.Request != nil {
+				t.Errorf("Expected no request, got %s %s", roundtripper.Request.Method, roundtripper.Request.URL)
+			}
+			if !strings.Contains(buf.String(), "write operation blocked in read-only mode") {
+				t.Errorf("Expected the blocked write to be logged, got: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestSystemAccessPointReadOnlyAllowsReads(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.ReadOnly = true
+	roundtripper := &MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "get_datapoint.json"),
+			Header:     make(http.Header),
+		},
+	}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	if _, err := sysAp.GetDatapoint("ABB7F595EC47", "ch0000", "odp0000"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if roundtripper.Request == nil || roundtripper.Request.Method != http.MethodGet {
+		t.Errorf("Expected a GET request, got %v", roundtripper.Request)
+	}
+}
@@ -0,0 +1,70 @@
+package freeathome
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestSystemAccessPointRefreshConfiguration tests that the configuration is retrieved again and cached.
+func TestSystemAccessPointRefreshConfiguration(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "configuration.json"),
+			Header:     make(http.Header),
+		},
+	}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	if sysAp.GetCachedConfiguration() != nil {
+		t.Fatal("Expected no cached configuration before the refresh")
+	}
+
+	configuration, err := sysAp.RefreshConfiguration()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Check if the log output is empty
+	if logOutput := buf.String(); logOutput != "" {
+		t.Errorf("Expected no log output, got: %s", logOutput)
+	}
+
+	cached := sysAp.GetCachedConfiguration()
+	if cached == nil || cached != configuration {
+		t.Fatal("Expected the refreshed configuration to be cached")
+	}
+	if len((*cached)[models.EmptyUUID].Devices) != 76 {
+		t.Errorf("Expected 76 devices, got %d", len((*cached)[models.EmptyUUID].Devices))
+	}
+}
+
+// TestSystemAccessPointRefreshConfigurationKeepsCache tests that the cached configuration is kept if the refresh fails.
+func TestSystemAccessPointRefreshConfigurationKeepsCache(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "configuration.json"),
+			Header:     make(http.Header),
+		},
+	})
+	cached, err := sysAp.GetConfiguration()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedErr := errors.New("connection refused")
+	sysAp.config.Client.SetTransport(&MockRoundTripper{Err: expectedErr})
+
+	if _, err := sysAp.RefreshConfiguration(); !errors.Is(err, expectedErr) {
+		t.Errorf("Expected error %v, got %v", expectedErr, err)
+	}
+	if sysAp.GetCachedConfiguration() != cached {
+		t.Error("Expected the cached configuration to be kept")
+	}
+}
@@ -0,0 +1,105 @@
+package freeathome
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// TestSystemAccessPointGetScenes tests that the scenes are extracted from the configuration.
+func TestSystemAccessPointGetScenes(t *testing.T) {
+	sysAp, buf, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "configuration.json"),
+			Header:     make(http.Header),
+		},
+	}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	result, err := sysAp.GetScenes()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Check if the log output is empty
+	if logOutput := buf.String(); logOutput != "" {
+		t.Errorf("Expected no log output, got: %s", logOutput)
+	}
+
+	// The scenes are derived from the configuration
+	expectedUrl := "https://localhost/fhapi/v1/api/rest/configuration"
+	if roundtripper.Request.URL.String() != expectedUrl {
+		t.Errorf("Expected URL '%s', got '%s'", expectedUrl, roundtripper.Request.URL.String())
+	}
+
+	expected := []models.SceneActuator{
+		{Name: "Alles aus", Serial: "FFFF48020001", Channel: "ch0000", FunctionID: "4802"},
+		{Name: "Alles aus", Serial: "FFFF48020004", Channel: "ch0000", FunctionID: "4802"},
+		{Name: "Essen", Serial: "FFFF48000004", Channel: "ch0000", FunctionID: "4800"},
+		{Name: "Kochen", Serial: "FFFF48000009", Channel: "ch0000", FunctionID: "4800"},
+		{Name: "Wohnzimmer TV", Serial: "FFFF48000001", Channel: "ch0000", FunctionID: "4800"},
+		{Name: "Zu Bett Gehen", Serial: "FFFF48000006", Channel: "ch0000", FunctionID: "4800"},
+	}
+	scenes := (*result)[models.EmptyUUID]
+	if len(scenes) != len(expected) {
+		t.Fatalf("Expected %d scenes, got %d: %+v", len(expected), len(scenes), scenes)
+	}
+	for i, scene := range scenes {
+		if scene != expected[i] {
+			t.Errorf("Expected scene %+v, got %+v", expected[i], scene)
+		}
+	}
+
+	// The activation target of a scene is the channel of its actuator in the configuration
+	device := (*sysAp.GetCachedConfiguration())[models.EmptyUUID].Devices[scenes[3].Serial]
+	if _, exists := (*device.Channels)[scenes[3].Channel]; !exists {
+		t.Errorf("Expected channel %s of scene actuator %s to exist", scenes[3].Channel, scenes[3].Serial)
+	}
+}
+
+// TestSystemAccessPointGetScenesNameFallback tests that unnamed scene channels are named after their device or serial.
+func TestSystemAccessPointGetScenesNameFallback(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`{"00000000-0000-0000-0000-000000000000":{"devices":{
+			"FFFF48000002":{"displayName":"Party","channels":{"ch0000":{"functionID":"4800"}}},
+			"FFFF48010001":{"channels":{"ch0000":{"functionID":"4801"}}},
+			"ABB7F595EC47":{"displayName":"Sensor","channels":{"ch0000":{"functionID":"1"},"ch0001":null}},
+			"ABB700000001":{}
+		}}}`)),
+			Header: make(http.Header),
+		},
+	})
+
+	result, err := sysAp.GetScenes()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	scenes := (*result)[models.EmptyUUID]
+	if len(scenes) != 2 || scenes[0].Name != "FFFF48010001" || scenes[1].Name != "Party" {
+		t.Errorf("Expected scenes named after serial and device, got %+v", scenes)
+	}
+}
+
+// TestSystemAccessPointGetScenesError tests that an error is returned if the configuration cannot be retrieved.
+func TestSystemAccessPointGetScenesError(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{Err: errors.New("network error")})
+
+	result, err := sysAp.GetScenes()
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if result != nil {
+		t.Errorf("Expected nil result, got %+v", result)
+	}
+}
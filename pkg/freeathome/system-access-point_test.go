@@ -2,8 +2,11 @@ package freeathome
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"log/slog"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -176,3 +179,78 @@ func TestSystemAccessPointGetUrlWithTls(t *testing.T) {
 		t.Errorf("Expected URL '%s', got '%s'", expected, actual)
 	}
 }
+
+// TestSystemAccessPointGetUrlWithApiVersion tests that the GetUrl method uses the configured API version and defaults to v1.
+func TestSystemAccessPointGetUrlWithApiVersion(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+
+	sysAp.config.APIVersion = "v2"
+	if actual, expected := sysAp.GetUrl("test123"), "https://localhost/fhapi/v2/api/rest/test123"; actual != expected {
+		t.Errorf("Expected URL '%s', got '%s'", expected, actual)
+	}
+
+	sysAp.config.APIVersion = ""
+	if actual, expected := sysAp.GetUrl("test123"), "https://localhost/fhapi/v1/api/rest/test123"; actual != expected {
+		t.Errorf("Expected URL '%s', got '%s'", expected, actual)
+	}
+
+	if version := NewConfig("localhost", "user", "password").APIVersion; version != "v1" {
+		t.Errorf("Expected default API version 'v1', got '%s'", version)
+	}
+}
+
+// TestNewSystemAccessPointInvalidApiVersion tests that API versions that would build broken paths are rejected.
+func TestNewSystemAccessPointInvalidApiVersion(t *testing.T) {
+	for _, version := range []string{"v2/", "v1/../x", "2", "v", "V1", "v1 "} {
+		config := NewConfig("localhost", "user", "password")
+		config.APIVersion = version
+		config.Logger = NewDefaultLogger(slog.NewTextHandler(io.Discard, nil))
+		if _, err := NewSystemAccessPoint(config); err == nil || !strings.Contains(err.Error(), "invalid API version") {
+			t.Errorf("Expected invalid API version error for %q, got %v", version, err)
+		}
+	}
+
+	for _, version := range []string{"", "v1", "v12"} {
+		if err := ValidateAPIVersion(version); err != nil {
+			t.Errorf("Expected API version %q to be valid, got %v", version, err)
+		}
+	}
+}
+
+// TestSystemAccessPointConcurrentConfigAccess tests that the host name and UUID can be changed while they are read concurrently.
+// Run with -race to detect unsynchronized access.
+func TestSystemAccessPointConcurrentConfigAccess(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, false, false)
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				url := sysAp.GetUrl("test123")
+				if !strings.HasSuffix(url, "/fhapi/v1/api/rest/test123") {
+					t.Errorf("Unexpected URL '%s'", url)
+				}
+				_ = sysAp.GetUUID()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				sysAp.SetHostName(fmt.Sprintf("host-%d-%d", i, j))
+				sysAp.SetUUID(fmt.Sprintf("uuid-%d-%d", i, j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	sysAp.SetHostName("example.com")
+	sysAp.SetUUID("00000000-0000-0000-0000-000000000001")
+	if actual := sysAp.GetUrl("test123"); actual != "http://example.com/fhapi/v1/api/rest/test123" {
+		t.Errorf("Expected URL 'http://example.com/fhapi/v1/api/rest/test123', got '%s'", actual)
+	}
+	if actual := sysAp.GetUUID(); actual != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("Expected UUID '00000000-0000-0000-0000-000000000001', got '%s'", actual)
+	}
+}
@@ -1,6 +1,7 @@
 package freeathome
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -198,3 +199,173 @@ func TestSystemAccessPointCreateVirtualDeviceUnmarshalError(t *testing.T) {
 		t.Errorf(expectedErrorGotValue, expected, err)
 	}
 }
+
+// virtualDeviceConfiguration is a configuration containing the virtual device 6000D2CB27B2 that was renamed in the app
+const virtualDeviceConfiguration = `{"00000000-0000-0000-0000-000000000000":{"devices":{"60002A7A8F36":{"nativeId":"6000D2CB27B2","displayName":"Renamed"}}}}`
+
+// virtualDeviceRoundTripper responds to GET requests with the configuration and to PUT requests with the virtual device
+// response, the last PUT request is recorded
+type virtualDeviceRoundTripper struct {
+	t             *testing.T
+	configuration string
+	Request       *http.Request
+}
+
+// RoundTrip responds to the request depending on its method
+func (m *virtualDeviceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(m.configuration)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	m.Request = req
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       loadTestResponseBody(m.t, "virtualdevice.json"),
+		Header:     make(http.Header),
+	}, nil
+}
+
+// sentVirtualDevice returns the virtual device sent by the last PUT request
+func (m *virtualDeviceRoundTripper) sentVirtualDevice() models.VirtualDevice {
+	m.t.Helper()
+	if m.Request == nil {
+		m.t.Fatal("Expected a PUT request")
+	}
+	body, err := io.ReadAll(m.Request.Body)
+	if err != nil {
+		m.t.Fatalf("Failed to read request body: %v", err)
+	}
+	var sent models.VirtualDevice
+	if err := json.Unmarshal(body, &sent); err != nil {
+		m.t.Fatalf("Failed to parse request body: %v", err)
+	}
+	return sent
+}
+
+func TestSystemAccessPointUpdateVirtualDevice(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &virtualDeviceRoundTripper{t: t, configuration: virtualDeviceConfiguration}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	// Create the virtual device
+	displayName := "Old Name"
+	ttl := "300"
+	device := newVirtualDevice(t)
+	device.Properties.DisplayName = &displayName
+	device.Properties.TTL = &ttl
+	if _, err := sysAp.CreateVirtualDevice("6000D2CB27B2", device); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Update the display name only
+	newName := "New Name"
+	result, err := sysAp.UpdateVirtualDevice("6000D2CB27B2", &models.VirtualDevicePatch{DisplayName: &newName})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("Expected non-nil result")
+	}
+
+	// Check the request
+	if roundtripper.Request.Method != http.MethodPut {
+		t.Errorf("Expected PUT request, got %s", roundtripper.Request.Method)
+	}
+	sent := roundtripper.sentVirtualDevice()
+
+	// The display name is changed, all other fields are preserved
+	if sent.Properties.DisplayName == nil || *sent.Properties.DisplayName != "New Name" {
+		t.Errorf("Expected display name 'New Name', got %v", sent.Properties.DisplayName)
+	}
+	if sent.Properties.TTL == nil || *sent.Properties.TTL != "300" {
+		t.Errorf("Expected TTL '300', got %v", sent.Properties.TTL)
+	}
+	if sent.Type != models.BinarySensor {
+		t.Errorf("Expected type %d, got %d", models.BinarySensor, sent.Type)
+	}
+	if sent.Properties.Capabilities == nil || len(*sent.Properties.Capabilities) != 3 {
+		t.Errorf("Expected capabilities to be preserved, got %v", sent.Properties.Capabilities)
+	}
+}
+
+func TestSystemAccessPointUpdateVirtualDeviceCurrentState(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &virtualDeviceRoundTripper{t: t, configuration: virtualDeviceConfiguration}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	displayName := "Old Name"
+	device := newVirtualDevice(t)
+	device.Properties.DisplayName = &displayName
+	if _, err := sysAp.CreateVirtualDevice("6000D2CB27B2", device); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Changing the device passed to CreateVirtualDevice does not change the cached state
+	(*device.Properties.Capabilities)[0] = 42
+	displayName = "Changed"
+
+	ttl := "600"
+	if _, err := sysAp.UpdateVirtualDevice("6000D2CB27B2", &models.VirtualDevicePatch{TTL: &ttl}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sent := roundtripper.sentVirtualDevice()
+
+	// The display name is taken from the configuration
+	if sent.Properties.DisplayName == nil || *sent.Properties.DisplayName != "Renamed" {
+		t.Errorf("Expected display name 'Renamed', got %v", sent.Properties.DisplayName)
+	}
+	if sent.Properties.TTL == nil || *sent.Properties.TTL != "600" {
+		t.Errorf("Expected TTL '600', got %v", sent.Properties.TTL)
+	}
+	if sent.Properties.Capabilities == nil || (*sent.Properties.Capabilities)[0] != 1 {
+		t.Errorf("Expected the cached capabilities, got %v", sent.Properties.Capabilities)
+	}
+}
+
+func TestSystemAccessPointUpdateVirtualDeviceNotInConfiguration(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &virtualDeviceRoundTripper{t: t, configuration: `{"00000000-0000-0000-0000-000000000000":{"devices":{}}}`}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	if _, err := sysAp.CreateVirtualDevice("6000D2CB27B2", newVirtualDevice(t)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	roundtripper.Request = nil
+
+	newName := "New Name"
+	result, err := sysAp.UpdateVirtualDevice("6000D2CB27B2", &models.VirtualDevicePatch{DisplayName: &newName})
+
+	if result != nil {
+		t.Error(expectedNil)
+	}
+	expected := "virtual device not found in configuration: 6000D2CB27B2"
+	if err == nil || err.Error() != expected {
+		t.Errorf(expectedErrorGotValue, expected, err)
+	}
+	if roundtripper.Request != nil {
+		t.Error("Expected no PUT request to be sent")
+	}
+}
+
+func TestSystemAccessPointUpdateVirtualDeviceUnknown(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	roundtripper := &MockRoundTripper{}
+	sysAp.config.Client.SetTransport(roundtripper)
+
+	newName := "New Name"
+	result, err := sysAp.UpdateVirtualDevice("6000D2CB27B2", &models.VirtualDevicePatch{DisplayName: &newName})
+
+	if result != nil {
+		t.Error(expectedNil)
+	}
+	expected := "unknown virtual device: 6000D2CB27B2"
+	if err == nil || err.Error() != expected {
+		t.Errorf(expectedErrorGotValue, expected, err)
+	}
+	if roundtripper.Request != nil {
+		t.Error("Expected no request to be sent")
+	}
+}
@@ -0,0 +1,39 @@
+package freeathome
+
+import (
+	"github.com/go-resty/resty/v2"
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// noopSpan is used if no tracer is configured
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) AddEvent(string, ...any)  {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
+
+// startSpan starts a span with the given name using the configured tracer. A span that records nothing is returned if no tracer is configured.
+func (sysAp *SystemAccessPoint) startSpan(name string) models.Span {
+	if sysAp.config.Tracer == nil {
+		return noopSpan{}
+	}
+
+	span := sysAp.config.Tracer.Start(name)
+	span.SetAttribute("server.address", sysAp.GetHostName())
+	return span
+}
+
+// endRestSpan records the request method, the response status and the error of a REST operation and ends the span.
+func endRestSpan(span models.Span, resp *resty.Response, err error) {
+	if resp != nil && resp.Request != nil {
+		span.SetAttribute("http.request.method", resp.Request.Method)
+	}
+	if resp != nil && resp.StatusCode() != 0 {
+		span.SetAttribute("http.response.status_code", resp.StatusCode())
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
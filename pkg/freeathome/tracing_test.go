@@ -0,0 +1,174 @@
+package freeathome
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pgerke/freeathome/v2/pkg/models"
+)
+
+// recordedSpan is a span recorded in memory by the recordingTracer
+type recordedSpan struct {
+	name       string
+	attributes map[string]any
+	events     []string
+	errors     []error
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+func (s *recordedSpan) AddEvent(name string, _ ...any)     { s.events = append(s.events, name) }
+func (s *recordedSpan) RecordError(err error)              { s.errors = append(s.errors, err) }
+func (s *recordedSpan) End()                               { s.ended = true }
+
+// recordingTracer records the started spans in memory
+type recordingTracer struct {
+	mutex sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(name string) models.Span {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	span := &recordedSpan{name: name, attributes: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+// setupSysApWithTracer initializes a SystemAccessPoint with a recording tracer.
+func setupSysApWithTracer(t *testing.T) (*SystemAccessPoint, *recordingTracer) {
+	t.Helper()
+
+	sysAp, _, _ := setupSysAp(t, true, false)
+	tracer := &recordingTracer{}
+	sysAp.config.Tracer = tracer
+	return sysAp, tracer
+}
+
+func TestSystemAccessPointTracingRestCall(t *testing.T) {
+	sysAp, tracer := setupSysApWithTracer(t)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       loadTestResponseBody(t, "device.json"),
+			Header:     make(http.Header),
+		},
+	})
+
+	if _, err := sysAp.GetDevice("600028E1ED13"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "GetDevice" {
+		t.Errorf("Expected span name 'GetDevice', got '%s'", span.name)
+	}
+	if !span.ended {
+		t.Error("Expected span to be ended")
+	}
+	if len(span.errors) != 0 {
+		t.Errorf("Expected no recorded errors, got %v", span.errors)
+	}
+
+	expected := map[string]any{
+		"server.address":            "localhost",
+		"http.request.method":       http.MethodGet,
+		"http.response.status_code": http.StatusOK,
+	}
+	for key, value := range expected {
+		if span.attributes[key] != value {
+			t.Errorf("Expected attribute '%s' to be %v, got %v", key, value, span.attributes[key])
+		}
+	}
+}
+
+func TestSystemAccessPointTracingRestCallStatusError(t *testing.T) {
+	sysAp, tracer := setupSysApWithTracer(t)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{
+		Response: &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       http.NoBody,
+			Header:     make(http.Header),
+		},
+	})
+
+	if _, err := sysAp.SetDatapoint("ABB7F595EC47", "ch0000", "idp0000", "1"); err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+
+	span := tracer.spans[0]
+	if span.name != "SetDatapoint" {
+		t.Errorf("Expected span name 'SetDatapoint', got '%s'", span.name)
+	}
+	if span.attributes["http.request.method"] != http.MethodPut {
+		t.Errorf("Expected request method PUT, got %v", span.attributes["http.request.method"])
+	}
+	if span.attributes["http.response.status_code"] != http.StatusInternalServerError {
+		t.Errorf("Expected status code 500, got %v", span.attributes["http.response.status_code"])
+	}
+	var apiErr *APIError
+	if len(span.errors) != 1 || !errors.As(span.errors[0], &apiErr) {
+		t.Errorf("Expected the API error to be recorded, got %v", span.errors)
+	}
+	if !span.ended {
+		t.Error("Expected span to be ended")
+	}
+}
+
+func TestSystemAccessPointTracingRestCallTransportError(t *testing.T) {
+	sysAp, tracer := setupSysApWithTracer(t)
+	sysAp.config.Client.SetTransport(&MockRoundTripper{Err: errors.New("connection refused")})
+
+	if _, err := sysAp.GetDeviceList(); err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+
+	span := tracer.spans[0]
+	if span.name != "GetDeviceList" {
+		t.Errorf("Expected span name 'GetDeviceList', got '%s'", span.name)
+	}
+	if _, exists := span.attributes["http.response.status_code"]; exists {
+		t.Errorf("Expected no status code, got %v", span.attributes["http.response.status_code"])
+	}
+	if len(span.errors) != 1 || !strings.Contains(span.errors[0].Error(), "connection refused") {
+		t.Errorf("Expected the transport error to be recorded, got %v", span.errors)
+	}
+}
+
+func TestSystemAccessPointTracingWebSocket(t *testing.T) {
+	sysAp, tracer := setupSysApWithTracer(t)
+	sysAp.SetHostName("127.0.0.1:1")
+	sysAp.config.TLSEnabled = false
+
+	if err := sysAp.ConnectWebSocket(t.Context(), 1, false, 0); err == nil {
+		t.Fatal(expectedErrorGotNil)
+	}
+
+	span := tracer.spans[0]
+	if span.name != "ConnectWebSocket" {
+		t.Errorf("Expected span name 'ConnectWebSocket', got '%s'", span.name)
+	}
+	if len(span.events) != 1 || span.events[0] != "connection failed" {
+		t.Errorf("Expected a 'connection failed' event, got %v", span.events)
+	}
+	if len(span.errors) != 1 || span.errors[0].Error() != "maximum reconnection attempts exceeded" {
+		t.Errorf("Expected the maximum reconnection attempts error to be recorded, got %v", span.errors)
+	}
+	if !span.ended {
+		t.Error("Expected span to be ended")
+	}
+}
+
+func TestSystemAccessPointTracingDisabled(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+
+	if _, ok := sysAp.startSpan("GetDevice").(noopSpan); !ok {
+		t.Error("Expected a no-op span without a tracer")
+	}
+}
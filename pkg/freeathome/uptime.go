@@ -0,0 +1,90 @@
+package freeathome
+
+import (
+	"sync"
+	"time"
+)
+
+// ConnectionUptime describes how long the web socket was connected and disconnected since the first connection attempt.
+type ConnectionUptime struct {
+	// Connected is the total time the web socket was connected
+	Connected time.Duration
+	// Disconnected is the total time the web socket was not connected, including the time spent reconnecting
+	Disconnected time.Duration
+}
+
+// Percentage returns the share of the connected time in the total time in percent, or zero if no time has passed.
+func (u ConnectionUptime) Percentage() float64 {
+	total := u.Connected + u.Disconnected
+	if total <= 0 {
+		return 0
+	}
+	return float64(u.Connected) / float64(total) * 100
+}
+
+// uptimeTracker accumulates the connected time of the web socket from the connection lifecycle events.
+type uptimeTracker struct {
+	// start is the time of the first connection attempt, zero if there was none
+	start time.Time
+	// connectedSince is the time the current connection was established, zero if the web socket is not connected
+	connectedSince time.Time
+	// connected is the total time of the previous connections
+	connected time.Duration
+	// mutex protects access to the tracked times
+	mutex sync.Mutex
+}
+
+// begin starts tracking at the given time unless tracking already started.
+func (u *uptimeTracker) begin(now time.Time) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.start.IsZero() {
+		u.start = now
+	}
+}
+
+// markConnected records that a connection was established at the given time.
+func (u *uptimeTracker) markConnected(now time.Time) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.start.IsZero() {
+		u.start = now
+	}
+	if u.connectedSince.IsZero() {
+		u.connectedSince = now
+	}
+}
+
+// markDisconnected records that the current connection was closed at the given time.
+func (u *uptimeTracker) markDisconnected(now time.Time) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.connectedSince.IsZero() {
+		return
+	}
+	u.connected += now.Sub(u.connectedSince)
+	u.connectedSince = time.Time{}
+}
+
+// uptime returns the connected and disconnected time up to the given time, including the current connection.
+func (u *uptimeTracker) uptime(now time.Time) ConnectionUptime {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.start.IsZero() {
+		return ConnectionUptime{}
+	}
+
+	connected := u.connected
+	if !u.connectedSince.IsZero() {
+		connected += now.Sub(u.connectedSince)
+	}
+	return ConnectionUptime{
+		Connected:    connected,
+		Disconnected: now.Sub(u.start) - connected,
+	}
+}
+
+// GetConnectionUptime returns how long the web socket was connected and disconnected since the first connection attempt.
+func (sysAp *SystemAccessPoint) GetConnectionUptime() ConnectionUptime {
+	return sysAp.uptime.uptime(sysAp.clock.Now())
+}
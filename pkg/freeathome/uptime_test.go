@@ -0,0 +1,76 @@
+package freeathome
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestUptimeTracker tests the uptime computed from a sequence of connection lifecycle events.
+func TestUptimeTracker(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	var tracker uptimeTracker
+
+	// 10s reconnecting, 60s connected, 30s disconnected, 20s connected until now
+	tracker.begin(clock.Now())
+	clock.Sleep(10 * time.Second)
+	tracker.markConnected(clock.Now())
+	clock.Sleep(60 * time.Second)
+	tracker.markDisconnected(clock.Now())
+	clock.Sleep(30 * time.Second)
+	tracker.markConnected(clock.Now())
+	clock.Sleep(20 * time.Second)
+
+	uptime := tracker.uptime(clock.Now())
+	expected := ConnectionUptime{Connected: 80 * time.Second, Disconnected: 40 * time.Second}
+	if uptime != expected {
+		t.Errorf("Expected uptime %+v, got %+v", expected, uptime)
+	}
+	if percentage := uptime.Percentage(); math.Abs(percentage-66.666) > 0.001 {
+		t.Errorf("Expected uptime percentage 66.667, got %f", percentage)
+	}
+
+	// Repeated events do not count twice
+	tracker.markConnected(clock.Now())
+	clock.Sleep(20 * time.Second)
+	tracker.markDisconnected(clock.Now())
+	tracker.markDisconnected(clock.Now())
+	clock.Sleep(60 * time.Second)
+
+	uptime = tracker.uptime(clock.Now())
+	expected = ConnectionUptime{Connected: 100 * time.Second, Disconnected: 100 * time.Second}
+	if uptime != expected {
+		t.Errorf("Expected uptime %+v, got %+v", expected, uptime)
+	}
+	if percentage := uptime.Percentage(); percentage != 50 {
+		t.Errorf("Expected uptime percentage 50, got %f", percentage)
+	}
+}
+
+// TestUptimeTrackerNotStarted tests that the uptime is empty before the first connection attempt.
+func TestUptimeTrackerNotStarted(t *testing.T) {
+	var tracker uptimeTracker
+
+	uptime := tracker.uptime(time.Now())
+	if uptime != (ConnectionUptime{}) {
+		t.Errorf("Expected empty uptime, got %+v", uptime)
+	}
+	if percentage := uptime.Percentage(); percentage != 0 {
+		t.Errorf("Expected uptime percentage 0, got %f", percentage)
+	}
+}
+
+// TestSystemAccessPointGetConnectionUptime tests that the uptime is computed with the clock of the system access point.
+func TestSystemAccessPointGetConnectionUptime(t *testing.T) {
+	sysAp, _, _ := setupSysAp(t, true, false)
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	sysAp.clock = clock
+
+	sysAp.uptime.markConnected(clock.Now())
+	clock.Sleep(90 * time.Second)
+
+	uptime := sysAp.GetConnectionUptime()
+	if uptime.Connected != 90*time.Second || uptime.Disconnected != 0 || uptime.Percentage() != 100 {
+		t.Errorf("Expected 90s connected and no disconnected time, got %+v", uptime)
+	}
+}
@@ -5,9 +5,15 @@ import "time"
 // clock provides an interface for time operations that can be mocked in tests
 type clock interface {
 	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) timer
 	Now() time.Time
 }
 
+// timer is a timer created by a clock that can be stopped
+type timer interface {
+	Stop() bool
+}
+
 // realClock implements clock using the real time package
 type realClock struct{}
 
@@ -15,6 +21,10 @@ func (rt *realClock) After(d time.Duration) <-chan time.Time {
 	return time.After(d)
 }
 
+func (rt *realClock) AfterFunc(d time.Duration, f func()) timer {
+	return time.AfterFunc(d, f)
+}
+
 func (rt *realClock) Now() time.Time {
 	return time.Now()
 }
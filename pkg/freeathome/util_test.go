@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -41,9 +42,26 @@ func (tb *ThreadSafeBuffer) Reset() {
 type fakeClock struct {
 	now        time.Time
 	afterCalls []time.Duration
+	timers     []*fakeTimer
 	mu         sync.Mutex
 }
 
+// fakeTimer is a timer of the fake clock that fires when the clock is advanced past its deadline.
+type fakeTimer struct {
+	at      time.Time
+	f       func()
+	stopped bool
+	clock   *fakeClock
+}
+
+func (ft *fakeTimer) Stop() bool {
+	ft.clock.mu.Lock()
+	defer ft.clock.mu.Unlock()
+	wasActive := !ft.stopped
+	ft.stopped = true
+	return wasActive
+}
+
 func (mt *fakeClock) Now() time.Time {
 	mt.mu.Lock()
 	defer mt.mu.Unlock()
@@ -60,10 +78,39 @@ func (mt *fakeClock) After(d time.Duration) <-chan time.Time {
 	return ch
 }
 
-func (mt *fakeClock) Sleep(d time.Duration) {
+func (mt *fakeClock) AfterFunc(d time.Duration, f func()) timer {
 	mt.mu.Lock()
 	defer mt.mu.Unlock()
+	t := &fakeTimer{at: mt.now.Add(d), f: f, clock: mt}
+	mt.timers = append(mt.timers, t)
+	return t
+}
+
+// Sleep advances the clock and runs the functions of the timers that are due in the order of their deadlines.
+func (mt *fakeClock) Sleep(d time.Duration) {
+	mt.mu.Lock()
 	mt.now = mt.now.Add(d)
+	var due []*fakeTimer
+	remaining := mt.timers[:0]
+	for _, t := range mt.timers {
+		switch {
+		case t.stopped:
+		case !t.at.After(mt.now):
+			t.stopped = true
+			due = append(due, t)
+		default:
+			remaining = append(remaining, t)
+		}
+	}
+	mt.timers = remaining
+	mt.mu.Unlock()
+
+	slices.SortStableFunc(due, func(a, b *fakeTimer) int {
+		return a.at.Compare(b.at)
+	})
+	for _, t := range due {
+		t.f()
+	}
 }
 
 const expectedErrorGotNil = "Expected error, got nil"
@@ -124,6 +171,7 @@ func setupSysApWebSocket(t *testing.T, tlsEnabled bool, skipTLSVerify bool) (*Sy
 	return &SystemAccessPointWebSocket{
 		sysAp:     sysAp,
 		waitGroup: sync.WaitGroup{},
+		backoff:   NewBackoff(reconnectionBackoffBase, reconnectionBackoffMax, 0),
 	}, &buf, channelHandler.records
 }
 
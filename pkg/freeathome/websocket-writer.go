@@ -0,0 +1,90 @@
+package freeathome
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errWebSocketWriterClosed is returned for writes issued after the web socket writer was closed
+var errWebSocketWriterClosed = errors.New("web socket writer closed")
+
+// controlWriter writes control messages, e.g. keepalive pings, to a web socket connection
+type controlWriter interface {
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+}
+
+// webSocketWrite is an outbound write queued for the writer goroutine
+type webSocketWrite struct {
+	// write performs the write on the connection
+	write func(conn connection) error
+	// result receives the error returned by the write
+	result chan error
+}
+
+// webSocketWriter serializes the outbound writes to a web socket connection through a single goroutine, since the
+// connection does not support concurrent writers. It is safe for concurrent use.
+type webSocketWriter struct {
+	// conn is the connection written to
+	conn connection
+	// writes queues the outbound writes for the writer goroutine
+	writes chan webSocketWrite
+	// stop is closed to stop the writer goroutine
+	stop chan struct{}
+	// stopped is closed when the writer goroutine has stopped
+	stopped chan struct{}
+	// closeOnce guards closing stop
+	closeOnce sync.Once
+}
+
+// newWebSocketWriter creates a writer for the connection and starts its writer goroutine.
+func newWebSocketWriter(conn connection) *webSocketWriter {
+	w := &webSocketWriter{
+		conn:    conn,
+		writes:  make(chan webSocketWrite),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// run performs the queued writes one at a time until the writer is closed.
+func (w *webSocketWriter) run() {
+	defer close(w.stopped)
+	for {
+		select {
+		case <-w.stop:
+			return
+		case write := <-w.writes:
+			write.result <- write.write(w.conn)
+		}
+	}
+}
+
+// do queues the write and waits for its result. It returns errWebSocketWriterClosed if the writer was closed.
+func (w *webSocketWriter) do(write func(conn connection) error) error {
+	queued := webSocketWrite{write: write, result: make(chan error, 1)}
+	select {
+	case w.writes <- queued:
+		return <-queued.result
+	case <-w.stop:
+		return errWebSocketWriterClosed
+	}
+}
+
+// WriteControl writes a control message through the writer goroutine.
+func (w *webSocketWriter) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	return w.do(func(conn connection) error {
+		return conn.WriteControl(messageType, data, deadline)
+	})
+}
+
+// close stops the writer goroutine after the write in progress, if any, and waits until it has stopped.
+// Writes issued afterwards fail with errWebSocketWriterClosed.
+func (w *webSocketWriter) close() {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.stopped
+}
@@ -0,0 +1,88 @@
+package freeathome
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// serialConn is a mock connection that records whether writes overlapped, which a gorilla connection does not support.
+type serialConn struct {
+	MockConn
+	writing    atomic.Bool
+	concurrent atomic.Bool
+	writes     atomic.Int32
+}
+
+func (c *serialConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	if !c.writing.CompareAndSwap(false, true) {
+		c.concurrent.Store(true)
+	}
+	defer c.writing.Store(false)
+
+	// Widen the window for overlapping writes
+	time.Sleep(10 * time.Microsecond)
+	c.writes.Add(1)
+	return nil
+}
+
+// TestWebSocketWriterSerializesConcurrentWrites tests that concurrent writes are performed one at a time without error.
+func TestWebSocketWriterSerializesConcurrentWrites(t *testing.T) {
+	conn := &serialConn{}
+	writer := newWebSocketWriter(conn)
+	defer writer.close()
+
+	const writers, writesPerWriter = 20, 25
+	var waitGroup sync.WaitGroup
+	errs := make(chan error, writers*writesPerWriter)
+	for range writers {
+		waitGroup.Go(func() {
+			for range writesPerWriter {
+				errs <- writer.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(time.Second))
+			}
+		})
+	}
+	waitGroup.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if conn.concurrent.Load() {
+		t.Error("Expected writes to be serialized, got overlapping writes")
+	}
+	if count := conn.writes.Load(); count != writers*writesPerWriter {
+		t.Errorf("Expected %d writes, got %d", writers*writesPerWriter, count)
+	}
+}
+
+// TestWebSocketWriterReturnsWriteError tests that the error of a write is returned to the caller.
+func TestWebSocketWriterReturnsWriteError(t *testing.T) {
+	writer := newWebSocketWriter(&MockConn{err: errors.New("test error"), mu: &sync.Mutex{}})
+	defer writer.close()
+
+	if err := writer.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(time.Second)); err == nil || err.Error() != "test error" {
+		t.Errorf(expectedErrorGotValue, "test error", err)
+	}
+}
+
+// TestWebSocketWriterClosed tests that writes fail once the writer was closed and closing twice is safe.
+func TestWebSocketWriterClosed(t *testing.T) {
+	conn := &serialConn{}
+	writer := newWebSocketWriter(conn)
+	writer.close()
+	writer.close()
+
+	if err := writer.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(time.Second)); !errors.Is(err, errWebSocketWriterClosed) {
+		t.Errorf(expectedErrorGotValue, errWebSocketWriterClosed, err)
+	}
+	if count := conn.writes.Load(); count != 0 {
+		t.Errorf("Expected no writes, got %d", count)
+	}
+}
@@ -1,7 +1,12 @@
 package models
 
+import "strings"
+
 // Channel describes a device channel.
 type Channel struct {
+	// Name represents the name of the channel.
+	Name *string `json:"name,omitempty"`
+
 	// DisplayName represents the display name of the channel.
 	DisplayName *string `json:"displayName,omitempty"`
 
@@ -26,3 +31,26 @@ type Channel struct {
 	// Type represents the channel type.
 	Type *string `json:"type,omitempty"`
 }
+
+// IsActuator reports whether the channel is writable, i.e. it has at least one input datapoint (idp).
+func (c *Channel) IsActuator() bool {
+	return hasDatapointWithPrefix(c.Inputs, "idp")
+}
+
+// IsSensor reports whether the channel is read-only, i.e. it has at least one output datapoint (odp) and no input datapoints.
+func (c *Channel) IsSensor() bool {
+	return !c.IsActuator() && hasDatapointWithPrefix(c.Outputs, "odp")
+}
+
+// hasDatapointWithPrefix reports whether the datapoints contain an identifier with the given prefix.
+func hasDatapointWithPrefix(datapoints *map[string]InOutPut, prefix string) bool {
+	if datapoints == nil {
+		return false
+	}
+	for id := range *datapoints {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+	return false
+}
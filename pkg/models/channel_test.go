@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChannelIsActuatorIsSensor(t *testing.T) {
+	tests := []struct {
+		name     string
+		channel  Channel
+		actuator bool
+		sensor   bool
+	}{
+		{name: "No datapoints", channel: Channel{}, actuator: false, sensor: false},
+		{name: "Empty datapoints", channel: Channel{Inputs: &map[string]InOutPut{}, Outputs: &map[string]InOutPut{}}, actuator: false, sensor: false},
+		{name: "Only inputs", channel: Channel{Inputs: &map[string]InOutPut{"idp0000": {}}}, actuator: true, sensor: false},
+		{name: "Only outputs", channel: Channel{Outputs: &map[string]InOutPut{"odp0000": {}, "odp0001": {}}}, actuator: false, sensor: true},
+		{name: "Inputs and outputs", channel: Channel{Inputs: &map[string]InOutPut{"idp0000": {}}, Outputs: &map[string]InOutPut{"odp0000": {}}}, actuator: true, sensor: false},
+		{name: "Unknown prefixes", channel: Channel{Inputs: &map[string]InOutPut{"xyz0000": {}}, Outputs: &map[string]InOutPut{"xyz0001": {}}}, actuator: false, sensor: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.channel.IsActuator(); actual != tt.actuator {
+				t.Errorf("Expected actuator to be %t, got %t", tt.actuator, actual)
+			}
+			if actual := tt.channel.IsSensor(); actual != tt.sensor {
+				t.Errorf("Expected sensor to be %t, got %t", tt.sensor, actual)
+			}
+		})
+	}
+}
+
+func TestChannelNameRoundTrip(t *testing.T) {
+	input := `{"name":"Light Control","displayName":"Living Room Light","functionId":"7","type":"SwitchingActuator"}`
+
+	var channel Channel
+	if err := json.Unmarshal([]byte(input), &channel); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if channel.Name == nil || *channel.Name != "Light Control" {
+		t.Errorf("Expected name 'Light Control', got %v", channel.Name)
+	}
+	if channel.DisplayName == nil || *channel.DisplayName != "Living Room Light" {
+		t.Errorf("Expected display name 'Living Room Light', got %v", channel.DisplayName)
+	}
+
+	output, err := json.Marshal(channel)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(output) != input {
+		t.Errorf("Expected %s, got %s", input, output)
+	}
+}
+
+func TestChannelNameOmittedWhenEmpty(t *testing.T) {
+	output, err := json.Marshal(Channel{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(output) != "{}" {
+		t.Errorf("Expected {}, got %s", output)
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+// Operation identifies a type of REST operation of the client for the client statistics.
+type Operation string
+
+const (
+	// OperationConfiguration retrieves the configuration
+	OperationConfiguration Operation = "configuration"
+	// OperationDeviceList retrieves the device list
+	OperationDeviceList Operation = "devicelist"
+	// OperationDevice retrieves a single device
+	OperationDevice Operation = "device"
+	// OperationDatapointGet retrieves the value of a datapoint
+	OperationDatapointGet Operation = "datapoint-get"
+	// OperationDatapointSet sets the value of a datapoint
+	OperationDatapointSet Operation = "datapoint-set"
+	// OperationVirtualDevice creates or updates a virtual device
+	OperationVirtualDevice Operation = "virtualdevice"
+	// OperationProxyDeviceTrigger triggers an action of a proxy device
+	OperationProxyDeviceTrigger Operation = "proxydevice-trigger"
+	// OperationProxyDeviceSet sets the value of a proxy device
+	OperationProxyDeviceSet Operation = "proxydevice-set"
+)
+
+// OperationStats counts the successful and failed calls of an operation.
+type OperationStats struct {
+	// Successes is the number of calls that succeeded.
+	Successes uint64 `json:"successes"`
+
+	// Failures is the number of calls that failed, including calls rejected before a request was sent.
+	Failures uint64 `json:"failures"`
+}
+
+// ErrorRate returns the share of failed calls in all calls, or zero if there were no calls.
+func (s OperationStats) ErrorRate() float64 {
+	total := s.Successes + s.Failures
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(total)
+}
+
+// ClientStats contains the call counts of the operations performed by the client since it was created.
+// Operations that were never called are not included.
+type ClientStats map[Operation]OperationStats
@@ -0,0 +1,21 @@
+package models
+
+import "testing"
+
+func TestOperationStatsErrorRate(t *testing.T) {
+	tests := []struct {
+		stats    OperationStats
+		expected float64
+	}{
+		{stats: OperationStats{}, expected: 0},
+		{stats: OperationStats{Successes: 3}, expected: 0},
+		{stats: OperationStats{Successes: 3, Failures: 1}, expected: 0.25},
+		{stats: OperationStats{Failures: 2}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		if rate := tt.stats.ErrorRate(); rate != tt.expected {
+			t.Errorf("Expected error rate %f for %+v, got %f", tt.expected, tt.stats, rate)
+		}
+	}
+}
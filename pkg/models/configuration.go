@@ -1,4 +1,198 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
 // Configuration describes system access point configurations.
 type Configuration map[string]SysAP
+
+// MultipleSysApsError is returned if a single system access point is required, but the configuration contains several and
+// none of them was specified.
+type MultipleSysApsError struct {
+	// IDs are the sorted UUIDs of the system access points in the configuration
+	IDs []string
+}
+
+func (e *MultipleSysApsError) Error() string {
+	return fmt.Sprintf("configuration contains %d system access points (%s), specify the UUID of the system access point to use", len(e.IDs), strings.Join(e.IDs, ", "))
+}
+
+// SysApIDs returns the sorted UUIDs of the system access points in the configuration.
+func (c Configuration) SysApIDs() []string {
+	return slices.Sorted(maps.Keys(c))
+}
+
+// SysAp returns the configuration of the system access point with the given UUID. If the UUID is empty or the empty UUID,
+// the entry for the empty UUID is used, or the only entry if there is none. A *MultipleSysApsError is returned if the
+// configuration contains several system access points and none of them is keyed by the empty UUID.
+func (c Configuration) SysAp(uuid string) (SysAP, error) {
+	if uuid != "" && uuid != EmptyUUID {
+		if sysAp, exists := c[uuid]; exists {
+			return sysAp, nil
+		}
+		return SysAP{}, fmt.Errorf("system access point not found: %s", uuid)
+	}
+
+	if sysAp, exists := c[EmptyUUID]; exists {
+		return sysAp, nil
+	}
+	switch len(c) {
+	case 0:
+		return SysAP{}, fmt.Errorf("configuration contains no system access point")
+	case 1:
+		return c[c.SysApIDs()[0]], nil
+	default:
+		return SysAP{}, &MultipleSysApsError{IDs: c.SysApIDs()}
+	}
+}
+
+// ValueAt returns the value of a modeled field at the given path, starting with the UUID of the system access point followed by
+// JSON keys and array indices, e.g. ValueAt(EmptyUUID, "devices", "ABB7F595EC47", "displayName"). Values are returned as
+// decoded by encoding/json into an any, so objects are maps, arrays are slices and numbers are float64. Fields that are not
+// modeled are not available, read them from the raw configuration with ValueAtPath instead. It reports false if the path
+// does not exist.
+func (c Configuration) ValueAt(path ...string) (any, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	sysAp, exists := c[path[0]]
+	if !exists {
+		return nil, false
+	}
+
+	data, err := json.Marshal(sysAp)
+	if err != nil {
+		return nil, false
+	}
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, false
+	}
+	return ValueAtPath(document, path[1:]...)
+}
+
+// ValueAtPath returns the value at the given path of JSON keys and array indices in a document decoded by encoding/json
+// into an any. It reports false if the path does not exist.
+func ValueAtPath(document any, path ...string) (any, bool) {
+	value := document
+	for _, key := range path {
+		switch node := value.(type) {
+		case map[string]any:
+			var exists bool
+			if value, exists = node[key]; !exists {
+				return nil, false
+			}
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// DatapointMetadata describes the unit and the description of a datapoint, empty if the configuration does not provide them.
+type DatapointMetadata struct {
+	// Unit is the unit of the datapoint value, e.g. "°C"
+	Unit string
+	// Description is the human readable description of the datapoint
+	Description string
+}
+
+// DatapointMetadata looks up the unit and the description of the datapoint with the given key in the inputs and outputs
+// of the device channels. It reports false if the datapoint is not part of the configuration.
+func (c Configuration) DatapointMetadata(key DatapointKey) (DatapointMetadata, bool) {
+	for _, sysAp := range c {
+		device, exists := sysAp.Devices[key.Serial]
+		if !exists || device.Channels == nil {
+			continue
+		}
+		channel := (*device.Channels)[key.Channel]
+		if channel == nil {
+			continue
+		}
+
+		for _, datapoints := range []*map[string]InOutPut{channel.Inputs, channel.Outputs} {
+			if datapoints == nil {
+				continue
+			}
+			if datapoint, exists := (*datapoints)[key.Datapoint]; exists {
+				metadata := DatapointMetadata{}
+				if datapoint.Unit != nil {
+					metadata.Unit = *datapoint.Unit
+				}
+				if datapoint.Description != nil {
+					metadata.Description = *datapoint.Description
+				}
+				return metadata, true
+			}
+		}
+	}
+
+	return DatapointMetadata{}, false
+}
+
+// DevicesByRoom returns the serials of the devices placed in each room of the floorplans, identified by the room id.
+// Every room of the floorplans is included, rooms without devices map to an empty list. Devices are only assigned to a room
+// if the room exists on the floor the device is placed on, devices without a valid placement are omitted. The serials are sorted.
+func (c Configuration) DevicesByRoom() map[string][]string {
+	rooms := map[string][]string{}
+	for _, sysAp := range c {
+		for _, floor := range sysAp.Floorplan.Floors {
+			for roomID := range floor.Rooms {
+				if _, exists := rooms[roomID]; !exists {
+					rooms[roomID] = []string{}
+				}
+			}
+		}
+
+		for _, serial := range slices.Sorted(maps.Keys(sysAp.Devices)) {
+			device := sysAp.Devices[serial]
+			if device.Floor == nil || device.Room == nil {
+				continue
+			}
+			// Room identifiers are only valid on the floor they belong to
+			floor, exists := sysAp.Floorplan.Floors[*device.Floor]
+			if !exists {
+				continue
+			}
+			if _, exists := floor.Rooms[*device.Room]; exists {
+				rooms[*device.Room] = append(rooms[*device.Room], serial)
+			}
+		}
+	}
+
+	for roomID := range rooms {
+		slices.Sort(rooms[roomID])
+	}
+	return rooms
+}
+
+// Interfaces returns the sorted serials of the devices on each interface, identified by the interface name, e.g. "TP" for the
+// wired bus. Devices without an interface are omitted.
+func (c Configuration) Interfaces() map[string][]string {
+	interfaces := map[string][]string{}
+	for _, sysAp := range c {
+		for serial, device := range sysAp.Devices {
+			if device.Interface == nil || *device.Interface == "" {
+				continue
+			}
+			interfaces[*device.Interface] = append(interfaces[*device.Interface], serial)
+		}
+	}
+
+	for name := range interfaces {
+		slices.Sort(interfaces[name])
+	}
+	return interfaces
+}
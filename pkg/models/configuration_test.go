@@ -2,8 +2,11 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -48,3 +51,268 @@ func TestDeserializeConfigurationFromFile(t *testing.T) {
 	// 	t.Fatal("expected parameters to be set, got nil")
 	// }
 }
+
+func TestConfigurationDevicesByRoom(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration.json"))
+	if err != nil {
+		t.Fatalf("failed to read JSON test file: %v", err)
+	}
+	var config Configuration
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+
+	rooms := config.DevicesByRoom()
+
+	// Every room of the floorplan is included
+	if len(rooms) != 31 {
+		t.Errorf("Expected 31 rooms, got %d", len(rooms))
+	}
+	expected := map[string][]string{
+		"0D": {"ABB700D9AD14", "ABB7F4FADCCC", "ABB7F5947E60", "ABB7F595EC47", "FFFF48000001"},
+		"09": {"ABB7F594581D"},
+		"1F": {"FFFF40000003"},
+		"11": {},
+	}
+	for roomID, serials := range expected {
+		if actual, exists := rooms[roomID]; !exists || !slices.Equal(actual, serials) {
+			t.Errorf("Expected room %s to contain %v, got %v (exists: %t)", roomID, serials, actual, exists)
+		}
+	}
+
+	// Devices without placement are omitted
+	for roomID, serials := range rooms {
+		if slices.Contains(serials, "ABB7013B85DE") {
+			t.Errorf("Expected device without placement to be omitted, found in room %s", roomID)
+		}
+	}
+}
+
+func TestConfigurationDevicesByRoomInvalidPlacement(t *testing.T) {
+	floor, room, otherFloor, unknownRoom := "01", "02", "03", "FF"
+	config := Configuration{
+		EmptyUUID: SysAP{
+			Devices: map[string]Device{
+				"ABB7F595EC47": {Floor: &floor, Room: &room},
+				"ABB7013B85DE": {Floor: &otherFloor, Room: &room},
+				"ABB7013B85DA": {Floor: &floor, Room: &unknownRoom},
+				"ABB7013B85D5": {Room: &room},
+			},
+			Floorplan: Floorplan{Floors: Floors{
+				floor:      {Name: "Ground Floor", Rooms: Rooms{room: {Name: "Living Room"}}},
+				otherFloor: {Name: "Attic", Rooms: Rooms{}},
+			}},
+		},
+	}
+
+	rooms := config.DevicesByRoom()
+	if len(rooms) != 1 || !slices.Equal(rooms[room], []string{"ABB7F595EC47"}) {
+		t.Errorf("Expected only the correctly placed device, got %v", rooms)
+	}
+}
+
+func TestConfigurationInterfaces(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration.json"))
+	if err != nil {
+		t.Fatalf("failed to read JSON test file: %v", err)
+	}
+	var config Configuration
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+
+	interfaces := config.Interfaces()
+
+	if len(interfaces) != 2 {
+		t.Errorf("Expected 2 interfaces, got %d", len(interfaces))
+	}
+	if len(interfaces["TP"]) != 64 {
+		t.Errorf("Expected 64 devices on interface TP, got %d", len(interfaces["TP"]))
+	}
+	if !slices.IsSorted(interfaces["TP"]) || !slices.Contains(interfaces["TP"], "ABB7F595EC47") {
+		t.Errorf("Expected the sorted TP devices to contain ABB7F595EC47, got %v", interfaces["TP"])
+	}
+	if !slices.Equal(interfaces["sysap"], []string{"ABB700000000"}) {
+		t.Errorf("Expected only the system access point on interface sysap, got %v", interfaces["sysap"])
+	}
+
+	// Devices without an interface are omitted
+	for name, serials := range interfaces {
+		if slices.Contains(serials, "FFFF48000001") {
+			t.Errorf("Expected device without interface to be omitted, found on interface %s", name)
+		}
+	}
+}
+
+func TestConfigurationDatapointMetadata(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration_units.json"))
+	if err != nil {
+		t.Fatalf("failed to read JSON test file: %v", err)
+	}
+	var config Configuration
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		key      DatapointKey
+		expected DatapointMetadata
+		found    bool
+	}{
+		{name: "Output with unit", key: DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0010"}, expected: DatapointMetadata{Unit: "°C", Description: "Measured temperature"}, found: true},
+		{name: "Input with unit", key: DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "idp0016"}, expected: DatapointMetadata{Unit: "°C", Description: "Set temperature"}, found: true},
+		{name: "Datapoint without unit", key: DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}, expected: DatapointMetadata{}, found: true},
+		{name: "Unknown datapoint", key: DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0001"}, found: false},
+		{name: "Unknown channel", key: DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0001", Datapoint: "odp0010"}, found: false},
+		{name: "Unknown device", key: DatapointKey{Serial: "ABB7FFFFFFFF", Channel: "ch0000", Datapoint: "odp0010"}, found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, found := config.DatapointMetadata(tt.key)
+			if found != tt.found {
+				t.Fatalf("Expected found to be %t, got %t", tt.found, found)
+			}
+			if metadata != tt.expected {
+				t.Errorf("Expected metadata %+v, got %+v", tt.expected, metadata)
+			}
+		})
+	}
+}
+
+// twoSysApConfiguration returns a configuration of a gateway with two system access points
+func twoSysApConfiguration() Configuration {
+	return Configuration{
+		"sysap-b": SysAP{SysApName: "Garage", Devices: map[string]Device{"ABB7013B85DE": {}}},
+		"sysap-a": SysAP{SysApName: "House", Devices: map[string]Device{"ABB7F595EC47": {}}},
+	}
+}
+
+func TestConfigurationSysApIDs(t *testing.T) {
+	if ids := twoSysApConfiguration().SysApIDs(); !slices.Equal(ids, []string{"sysap-a", "sysap-b"}) {
+		t.Errorf("Expected sorted UUIDs [sysap-a sysap-b], got %v", ids)
+	}
+	if ids := (Configuration{}).SysApIDs(); len(ids) != 0 {
+		t.Errorf("Expected no UUIDs, got %v", ids)
+	}
+}
+
+func TestConfigurationSysAp(t *testing.T) {
+	withEmptyUUID := twoSysApConfiguration()
+	withEmptyUUID[EmptyUUID] = SysAP{SysApName: "Local"}
+
+	tests := []struct {
+		name          string
+		configuration Configuration
+		uuid          string
+		expected      string
+		errorContains string
+	}{
+		{name: "Selected UUID", configuration: twoSysApConfiguration(), uuid: "sysap-b", expected: "Garage"},
+		{name: "Unknown UUID", configuration: twoSysApConfiguration(), uuid: "sysap-c", errorContains: "system access point not found: sysap-c"},
+		{name: "Empty UUID entry", configuration: withEmptyUUID, uuid: EmptyUUID, expected: "Local"},
+		{name: "Single entry", configuration: Configuration{"sysap-a": SysAP{SysApName: "House"}}, uuid: EmptyUUID, expected: "House"},
+		{name: "No entry", configuration: Configuration{}, errorContains: "contains no system access point"},
+		{name: "Several entries", configuration: twoSysApConfiguration(), uuid: EmptyUUID, errorContains: "configuration contains 2 system access points (sysap-a, sysap-b)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sysAp, err := tt.configuration.SysAp(tt.uuid)
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error containing '%s', got %v", tt.errorContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if sysAp.SysApName != tt.expected {
+				t.Errorf("Expected system access point '%s', got '%s'", tt.expected, sysAp.SysApName)
+			}
+		})
+	}
+
+	var multipleErr *MultipleSysApsError
+	if _, err := twoSysApConfiguration().SysAp(""); !errors.As(err, &multipleErr) || !slices.Equal(multipleErr.IDs, []string{"sysap-a", "sysap-b"}) {
+		t.Errorf("Expected a MultipleSysApsError with both UUIDs, got %v", err)
+	}
+}
+
+func TestConfigurationValueAt(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "configuration.json"))
+	if err != nil {
+		t.Fatalf("failed to read JSON test file: %v", err)
+	}
+	var config Configuration
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     []string
+		expected any
+		ok       bool
+	}{
+		{name: "Modeled field", path: []string{EmptyUUID, "devices", "ABB7F595EC47", "displayName"}, expected: "Sensoreinheit 2-fach", ok: true},
+		{name: "Unmodeled field", path: []string{EmptyUUID, "sysap", "version"}},
+		{name: "Missing key", path: []string{EmptyUUID, "devices", "ABB700000001", "displayName"}},
+		{name: "Path beyond a scalar", path: []string{EmptyUUID, "sysapName", "first"}},
+		{name: "Unknown UUID", path: []string{"sysap-a", "sysapName"}},
+		{name: "Empty path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := config.ValueAt(tt.path...)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %t, got %t", tt.ok, ok)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected value %v, got %v", tt.expected, value)
+			}
+		})
+	}
+
+	// A configuration that was not decoded from JSON exposes its modeled fields
+	if value, ok := twoSysApConfiguration().ValueAt("sysap-b", "sysapName"); !ok || value != "Garage" {
+		t.Errorf("Expected value Garage, got %v (ok %t)", value, ok)
+	}
+}
+
+func TestValueAtPath(t *testing.T) {
+	var document any
+	if err := json.Unmarshal([]byte(`{"sysap":{"version":"3.4.3","sunRiseTimes":[363,364]}}`), &document); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     []string
+		expected any
+		ok       bool
+	}{
+		{name: "Object key", path: []string{"sysap", "version"}, expected: "3.4.3", ok: true},
+		{name: "Array index", path: []string{"sysap", "sunRiseTimes", "1"}, expected: float64(364), ok: true},
+		{name: "Array index out of range", path: []string{"sysap", "sunRiseTimes", "7"}},
+		{name: "Negative array index", path: []string{"sysap", "sunRiseTimes", "-1"}},
+		{name: "Non-numeric array index", path: []string{"sysap", "sunRiseTimes", "first"}},
+		{name: "Missing key", path: []string{"sysap", "name"}},
+		{name: "Path beyond a scalar", path: []string{"sysap", "version", "major"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := ValueAtPath(document, tt.path...)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %t, got %t", tt.ok, ok)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected value %v, got %v", tt.expected, value)
+			}
+		})
+	}
+}
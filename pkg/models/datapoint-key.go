@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DatapointKey identifies a datapoint of a device channel, e.g. "ABB7F595EC47/ch0000/odp0000".
+type DatapointKey struct {
+	// Serial is the serial number of the device.
+	Serial string
+
+	// Channel is the channel identifier of the device.
+	Channel string
+
+	// Datapoint is the datapoint identifier.
+	Datapoint string
+}
+
+// String returns the datapoint key in the format used by the system access point.
+func (k DatapointKey) String() string {
+	return k.Serial + "/" + k.Channel + "/" + k.Datapoint
+}
+
+// Normalize returns the key in the case used by the system access point, i.e. an uppercase serial and a lowercase channel
+// and datapoint, so that keys parsed case-insensitively match the keys of received updates.
+func (k DatapointKey) Normalize() DatapointKey {
+	return DatapointKey{Serial: strings.ToUpper(k.Serial), Channel: strings.ToLower(k.Channel), Datapoint: strings.ToLower(k.Datapoint)}
+}
+
+// ParseDatapointKey parses a datapoint key in the format "serial/channel/datapoint".
+// The serial consists of 12 alphanumeric characters, the channel of "ch" followed by 4 hexadecimal digits and
+// the datapoint of "idp" or "odp" followed by 4 decimal digits. Letters are matched case-insensitively,
+// but only ASCII characters are accepted, as the keys are received from an untrusted source.
+func ParseDatapointKey(key string) (DatapointKey, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return DatapointKey{}, fmt.Errorf("invalid datapoint key %q: expected serial/channel/datapoint", key)
+	}
+
+	serial, channel, datapoint := parts[0], parts[1], parts[2]
+	if !IsValidSerial(serial) {
+		return DatapointKey{}, fmt.Errorf("invalid datapoint key %q: invalid serial", key)
+	}
+	if len(channel) != 6 || !equalFoldASCII(channel[:2], "ch") || !allBytes(channel[2:], isHexDigit) {
+		return DatapointKey{}, fmt.Errorf("invalid datapoint key %q: invalid channel", key)
+	}
+	if len(datapoint) != 7 || !isDatapointPrefix(datapoint[:3]) || !allBytes(datapoint[3:], isDigit) {
+		return DatapointKey{}, fmt.Errorf("invalid datapoint key %q: invalid datapoint", key)
+	}
+
+	return DatapointKey{Serial: serial, Channel: channel, Datapoint: datapoint}, nil
+}
+
+// IsValidSerial reports whether the string is a well-formed device serial, i.e. 12 ASCII alphanumeric characters.
+// This covers the serials of physical devices, e.g. "ABB7F595EC47", as well as the serials the system access point
+// assigns to virtual devices, e.g. "6000D2CB27B2". It does not check whether a device with the serial exists.
+func IsValidSerial(s string) bool {
+	return len(s) == 12 && allBytes(s, isAlphanumeric)
+}
+
+// isDatapointPrefix reports whether the prefix identifies an input or output datapoint.
+func isDatapointPrefix(prefix string) bool {
+	return equalFoldASCII(prefix, "idp") || equalFoldASCII(prefix, "odp")
+}
+
+// equalFoldASCII reports whether the strings are equal under ASCII case-folding.
+// Unlike strings.EqualFold it does not match Unicode characters that fold to ASCII letters.
+func equalFoldASCII(s, t string) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if toLowerASCII(s[i]) != toLowerASCII(t[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func toLowerASCII(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// allBytes reports whether all bytes of the string satisfy the predicate.
+func allBytes(s string, predicate func(byte) bool) bool {
+	for i := 0; i < len(s); i++ {
+		if !predicate(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isHexDigit(b byte) bool {
+	return isDigit(b) || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F')
+}
+
+func isAlphanumeric(b byte) bool {
+	return isDigit(b) || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseDatapointKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected DatapointKey
+		valid    bool
+	}{
+		{name: "Output datapoint", key: "ABB7F595EC47/ch0000/odp0000", expected: DatapointKey{Serial: "ABB7F595EC47", Channel: "ch0000", Datapoint: "odp0000"}, valid: true},
+		{name: "Input datapoint", key: "abb7f595ec47/ch00ff/idp0012", expected: DatapointKey{Serial: "abb7f595ec47", Channel: "ch00ff", Datapoint: "idp0012"}, valid: true},
+		{name: "Upper case identifiers", key: "ABB7F595EC47/CH000A/ODP0001", expected: DatapointKey{Serial: "ABB7F595EC47", Channel: "CH000A", Datapoint: "ODP0001"}, valid: true},
+		{name: "Invalid format", key: "Test123", valid: false},
+		{name: "Empty", key: "", valid: false},
+		{name: "Separators only", key: "//", valid: false},
+		{name: "Too many parts", key: "ABB7F595EC47/ch0000/odp0000/x", valid: false},
+		{name: "Short serial", key: "ABB7F595EC4/ch0000/odp0000", valid: false},
+		{name: "Serial with symbol", key: "ABB7F595EC4-/ch0000/odp0000", valid: false},
+		{name: "Channel without prefix", key: "ABB7F595EC47/xx0000/odp0000", valid: false},
+		{name: "Channel with non-hex digit", key: "ABB7F595EC47/ch000g/odp0000", valid: false},
+		{name: "Datapoint with invalid prefix", key: "ABB7F595EC47/ch0000/xdp0000", valid: false},
+		{name: "Datapoint with hex digit", key: "ABB7F595EC47/ch0000/odp000a", valid: false},
+		{name: "Trailing newline", key: "ABB7F595EC47/ch0000/odp0000\n", valid: false},
+		{name: "Unicode folding to ASCII", key: "ABB7F595EC4K/ch0000/odp0000", valid: false},
+		{name: "Unicode long s", key: "ABB7F595EC4ſ/ch0000/odp0000", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseDatapointKey(tt.key)
+			if !tt.valid {
+				if err == nil {
+					t.Errorf("Expected error for key %q, got %+v", tt.key, actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, actual)
+			}
+			if actual.String() != tt.key {
+				t.Errorf("Expected string '%s', got '%s'", tt.key, actual.String())
+			}
+		})
+	}
+}
+
+func TestDatapointKeyNormalize(t *testing.T) {
+	key := DatapointKey{Serial: "abb7f595ec47", Channel: "CH000A", Datapoint: "ODP0000"}
+	expected := DatapointKey{Serial: "ABB7F595EC47", Channel: "ch000a", Datapoint: "odp0000"}
+	if normalized := key.Normalize(); normalized != expected {
+		t.Errorf("Expected %s, got %s", expected, normalized)
+	}
+}
+
+func TestIsValidSerial(t *testing.T) {
+	tests := []struct {
+		name   string
+		serial string
+		valid  bool
+	}{
+		{name: "Physical device", serial: "ABB7F595EC47", valid: true},
+		{name: "Lower case physical device", serial: "abb7f595ec47", valid: true},
+		{name: "Scene device", serial: "FFFF48000001", valid: true},
+		{name: "Virtual device", serial: "6000D2CB27B2", valid: true},
+		{name: "Empty", serial: "", valid: false},
+		{name: "Too short", serial: "ABB7F595EC4", valid: false},
+		{name: "Too long", serial: "ABB7F595EC470", valid: false},
+		{name: "Symbol", serial: "ABB7F595EC4-", valid: false},
+		{name: "Whitespace", serial: " ABB7F595EC4", valid: false},
+		{name: "Datapoint key", serial: "ABB7F595EC47/ch0000", valid: false},
+		{name: "Unicode folding to ASCII", serial: "ABB7F595EC4\u212a", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsValidSerial(tt.serial); actual != tt.valid {
+				t.Errorf("Expected IsValidSerial(%q) to be %t, got %t", tt.serial, tt.valid, actual)
+			}
+		})
+	}
+}
+
+func FuzzParseDatapointKey(f *testing.F) {
+	for _, seed := range []string{
+		"ABB7F595EC47/ch0000/odp0000",
+		"abb7f595ec47/CH00FF/IDP0012",
+		"Test123",
+		"",
+		"//",
+		"ABB7F595EC4K/ch0000/odp0000",
+		"ABB7F595EC47/ch0000/odp0000\n",
+	} {
+		f.Add(seed)
+	}
+
+	pattern := regexp.MustCompile(DatapointPattern)
+	f.Fuzz(func(t *testing.T, key string) {
+		parsed, err := ParseDatapointKey(key)
+		if err != nil {
+			return
+		}
+
+		// A parsed key reproduces the input and only consists of ASCII characters
+		if parsed.String() != key {
+			t.Errorf("Expected parsed key to reproduce %q, got %q", key, parsed.String())
+		}
+		for i := 0; i < len(key); i++ {
+			if key[i] >= 0x80 {
+				t.Errorf("Expected only ASCII characters in accepted key %q", key)
+				break
+			}
+		}
+
+		// Every accepted key matches the documented pattern
+		matches := pattern.FindStringSubmatch(key)
+		if matches == nil {
+			t.Fatalf("Expected accepted key %q to match the datapoint pattern", key)
+		}
+		if matches[1] != parsed.Serial || matches[2] != parsed.Channel || matches[3] != parsed.Datapoint {
+			t.Errorf("Expected %v, got %+v", matches[1:], parsed)
+		}
+		if strings.Count(key, "/") != 2 {
+			t.Errorf("Expected exactly two separators in %q", key)
+		}
+	})
+}
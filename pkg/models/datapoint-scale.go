@@ -0,0 +1,10 @@
+package models
+
+// DatapointScale describes how the raw value of a datapoint is converted, e.g. a temperature sent in tenths of a degree.
+type DatapointScale struct {
+	// Factor is multiplied with the raw value, e.g. 0.1 for tenths of a degree. Zero leaves the value unscaled.
+	Factor float64
+
+	// Unit is the unit of the scaled value, e.g. "°C". Empty uses the unit from the configuration, if any.
+	Unit string
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DatapointUpdate represents a datapoint value update received from the system access point.
+type DatapointUpdate struct {
+	// Timestamp is the time the update was received.
+	Timestamp time.Time `json:"timestamp"`
+
+	// SysApID is the identifier of the system access point that sent the update.
+	SysApID string `json:"sysApId"`
+
+	// Serial is the serial number of the device.
+	Serial string `json:"serial"`
+
+	// Channel is the channel identifier of the device.
+	Channel string `json:"channel"`
+
+	// Datapoint is the datapoint identifier.
+	Datapoint string `json:"datapoint"`
+
+	// Value is the new value of the datapoint.
+	Value string `json:"value"`
+}
@@ -1,5 +1,13 @@
 package models
 
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+)
+
 // Device represents a device in the system.
 type Device struct {
 	// DisplayName is the display name of the device.
@@ -22,6 +30,141 @@ type Device struct {
 
 	// Parameters is a map of parameter names to their values for the device.
 	Parameters *map[string]string `json:"parameters,omitempty"`
+
+	// Unresponsive indicates whether the device is currently not responding.
+	Unresponsive *bool `json:"unresponsive,omitempty"`
+
+	// UnresponsiveCounter is the number of times the device did not respond.
+	UnresponsiveCounter *uint `json:"unresponsiveCounter,omitempty"`
+
+	// Defect indicates whether the device is reported as defect.
+	Defect *bool `json:"defect,omitempty"`
+}
+
+// IsReachable reports whether the device is reachable based on its reachability indicators.
+// A device is considered reachable unless it is flagged as unresponsive or defect.
+func (d *Device) IsReachable() bool {
+	if d.Unresponsive != nil && *d.Unresponsive {
+		return false
+	}
+	if d.Defect != nil && *d.Defect {
+		return false
+	}
+	return true
+}
+
+// Hash returns a stable hex encoded SHA-256 hash over the structure and metadata of the device stored under the serial,
+// so that it only changes if the device is reconfigured. The hash covers:
+//   - the serial, display name, room, floor, interface, native ID and parameters of the device
+//   - the name, display name, function ID, room, floor, type and parameters of each channel
+//   - the pairing ID, unit and description of each input and output datapoint of the channels
+//
+// Runtime state is not covered, i.e. the values of the datapoints and the unresponsive, unresponsive counter and defect
+// indicators. Maps are hashed independently of their iteration order, so identical devices always have the same hash.
+func (d *Device) Hash(serial string) string {
+	type hashedDatapoint struct {
+		PairingID   *uint   `json:"pairingId"`
+		Unit        *string `json:"unit"`
+		Description *string `json:"description"`
+	}
+	type hashedChannel struct {
+		Name        *string                    `json:"name"`
+		DisplayName *string                    `json:"displayName"`
+		FunctionID  *string                    `json:"functionId"`
+		Room        *string                    `json:"roomId"`
+		Floor       *string                    `json:"floorId"`
+		Type        *string                    `json:"type"`
+		Parameters  *map[string]string         `json:"parameters"`
+		Inputs      map[string]hashedDatapoint `json:"inputs"`
+		Outputs     map[string]hashedDatapoint `json:"outputs"`
+	}
+	hashedDatapoints := func(datapoints *map[string]InOutPut) map[string]hashedDatapoint {
+		if datapoints == nil {
+			return nil
+		}
+		hashed := make(map[string]hashedDatapoint, len(*datapoints))
+		for id, datapoint := range *datapoints {
+			hashed[id] = hashedDatapoint{datapoint.PairingID, datapoint.Unit, datapoint.Description}
+		}
+		return hashed
+	}
+
+	var channels map[string]*hashedChannel
+	if d.Channels != nil {
+		channels = make(map[string]*hashedChannel, len(*d.Channels))
+		for id, channel := range *d.Channels {
+			if channel == nil {
+				channels[id] = nil
+				continue
+			}
+			channels[id] = &hashedChannel{
+				Name:        channel.Name,
+				DisplayName: channel.DisplayName,
+				FunctionID:  channel.FunctionID,
+				Room:        channel.Room,
+				Floor:       channel.Floor,
+				Type:        channel.Type,
+				Parameters:  channel.Parameters,
+				Inputs:      hashedDatapoints(channel.Inputs),
+				Outputs:     hashedDatapoints(channel.Outputs),
+			}
+		}
+	}
+
+	// The JSON encoding sorts map keys, which makes it a canonical representation of the fields
+	data, _ := json.Marshal(struct {
+		Serial      string                    `json:"serial"`
+		DisplayName *string                   `json:"displayName"`
+		Room        *string                   `json:"room"`
+		Floor       *string                   `json:"floor"`
+		Interface   *string                   `json:"interface"`
+		NativeID    *string                   `json:"nativeId"`
+		Parameters  *map[string]string        `json:"parameters"`
+		Channels    map[string]*hashedChannel `json:"channels"`
+	}{serial, d.DisplayName, d.Room, d.Floor, d.Interface, d.NativeID, d.Parameters, channels})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// DatapointAddress identifies a datapoint within a device by its channel and datapoint identifier.
+type DatapointAddress struct {
+	// Channel is the channel identifier of the device.
+	Channel string
+
+	// Datapoint is the datapoint identifier.
+	Datapoint string
+}
+
+// Key returns the datapoint key of the address on the device with the given serial.
+func (a DatapointAddress) Key(serial string) DatapointKey {
+	return DatapointKey{Serial: serial, Channel: a.Channel, Datapoint: a.Datapoint}
+}
+
+// DatapointAddresses returns the addresses of all input and output datapoints across all channels of the device,
+// sorted by channel and datapoint.
+func (d *Device) DatapointAddresses() []DatapointAddress {
+	var addresses []DatapointAddress
+	if d.Channels == nil {
+		return addresses
+	}
+	for channelID, channel := range *d.Channels {
+		if channel == nil {
+			continue
+		}
+		for _, datapoints := range []*map[string]InOutPut{channel.Inputs, channel.Outputs} {
+			if datapoints == nil {
+				continue
+			}
+			for datapointID := range *datapoints {
+				addresses = append(addresses, DatapointAddress{Channel: channelID, Datapoint: datapointID})
+			}
+		}
+	}
+	slices.SortFunc(addresses, func(a, b DatapointAddress) int {
+		return cmp.Or(cmp.Compare(a.Channel, b.Channel), cmp.Compare(a.Datapoint, b.Datapoint))
+	})
+	return addresses
 }
 
 // Devices represents a map of devices identified by their serial.
@@ -32,5 +175,27 @@ type Devices struct {
 // DeviceResponse represents a map of devices per system access point.
 type DeviceResponse map[string]Devices
 
+// Device returns the device with the given serial from the response of the system access point, see SysApEntry.
+// It reports false if the response contains no devices for the system access point or no device with the serial.
+func (r DeviceResponse) Device(serial string) (*Device, bool) {
+	devices, exists := SysApEntry(r)
+	if !exists {
+		return nil, false
+	}
+
+	device, exists := devices.Devices[serial]
+	if !exists {
+		return nil, false
+	}
+
+	return &device, true
+}
+
 // DeviceList represents list of devices per system access point.
 type DeviceList map[string][]string
+
+// Serials returns the serials of the devices of the system access point, see SysApEntry.
+// It reports false if the list contains no entry for the system access point.
+func (l DeviceList) Serials() ([]string, bool) {
+	return SysApEntry(l)
+}
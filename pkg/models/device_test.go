@@ -0,0 +1,227 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeviceIsReachable(t *testing.T) {
+	yes := true
+	no := false
+
+	tests := []struct {
+		name     string
+		device   Device
+		expected bool
+	}{
+		{name: "No indicators", device: Device{}, expected: true},
+		{name: "Responsive", device: Device{Unresponsive: &no, Defect: &no}, expected: true},
+		{name: "Unresponsive", device: Device{Unresponsive: &yes, Defect: &no}, expected: false},
+		{name: "Defect", device: Device{Unresponsive: &no, Defect: &yes}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.device.IsReachable(); actual != tt.expected {
+				t.Errorf("Expected reachable to be %t, got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDeviceHash(t *testing.T) {
+	newDevice := func() Device {
+		name := "Kitchen light"
+		room := "0001"
+		floor := "01"
+		pairingID := uint(1)
+		return Device{
+			DisplayName: &name,
+			Room:        &room,
+			Floor:       &floor,
+			Channels: &map[string]*Channel{
+				"ch0000": {Inputs: &map[string]InOutPut{"idp0000": {PairingID: &pairingID}, "idp0001": {}}},
+				"ch0001": {},
+			},
+		}
+	}
+
+	device := newDevice()
+	identical := newDevice()
+	hash := device.Hash("ABB7F595EC47")
+	if len(hash) != 64 {
+		t.Errorf("Expected a hex encoded SHA-256 hash, got %q", hash)
+	}
+	// Repeat to cover different map iteration orders
+	for range 10 {
+		if actual := identical.Hash("ABB7F595EC47"); actual != hash {
+			t.Fatalf("Expected identical devices to hash equally, got %s and %s", hash, actual)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(d *Device)
+	}{
+		{name: "Display name", change: func(d *Device) { name := "Garage light"; d.DisplayName = &name }},
+		{name: "Room", change: func(d *Device) { d.Room = nil }},
+		{name: "Floor", change: func(d *Device) { floor := "02"; d.Floor = &floor }},
+		{name: "Channel added", change: func(d *Device) { (*d.Channels)["ch0002"] = &Channel{} }},
+		{name: "Channel input", change: func(d *Device) { delete(*(*d.Channels)["ch0000"].Inputs, "idp0001") }},
+		{name: "Channel name", change: func(d *Device) { name := "Light"; (*d.Channels)["ch0001"].DisplayName = &name }},
+		{name: "Pairing ID", change: func(d *Device) {
+			pairingID := uint(2)
+			(*(*d.Channels)["ch0000"].Inputs)["idp0001"] = InOutPut{PairingID: &pairingID}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := newDevice()
+			tt.change(&changed)
+			if changed.Hash("ABB7F595EC47") == hash {
+				t.Error("Expected the changed device to have a different hash")
+			}
+		})
+	}
+
+	if device.Hash("ABB7F595EC48") == hash {
+		t.Error("Expected the device stored under another serial to have a different hash")
+	}
+}
+
+func TestDeviceHashIgnoresRuntimeState(t *testing.T) {
+	newDevice := func(value string, unresponsive bool) Device {
+		unit := "°C"
+		return Device{
+			Unresponsive: &unresponsive,
+			Channels: &map[string]*Channel{
+				"ch0000": {Outputs: &map[string]InOutPut{"odp0010": {Value: &value, Unit: &unit}}},
+			},
+		}
+	}
+
+	device := newDevice("21.5", false)
+	changed := newDevice("22.0", true)
+	if device.Hash("ABB7F595EC47") != changed.Hash("ABB7F595EC47") {
+		t.Error("Expected the datapoint values and reachability not to change the hash")
+	}
+}
+
+func TestDeviceResponseDevice(t *testing.T) {
+	nativeID := "native"
+	response := DeviceResponse{
+		EmptyUUID: Devices{Devices: map[string]Device{"ABB7F595EC47": {NativeID: &nativeID}}},
+	}
+
+	device, exists := response.Device("ABB7F595EC47")
+	if !exists {
+		t.Fatal("Expected device to exist")
+	}
+	if device.NativeID == nil || *device.NativeID != nativeID {
+		t.Errorf("Expected native ID %q, got %v", nativeID, device.NativeID)
+	}
+}
+
+func TestDeviceResponseDeviceSingleUUID(t *testing.T) {
+	response := DeviceResponse{
+		"11111111-1111-1111-1111-111111111111": Devices{Devices: map[string]Device{"ABB7F595EC47": {}}},
+	}
+
+	device, exists := response.Device("ABB7F595EC47")
+	if !exists || device == nil {
+		t.Error("Expected the device of the only system access point")
+	}
+}
+
+func TestDeviceResponseDeviceMissingUUID(t *testing.T) {
+	response := DeviceResponse{
+		"11111111-1111-1111-1111-111111111111": Devices{Devices: map[string]Device{"ABB7F595EC47": {}}},
+		"22222222-2222-2222-2222-222222222222": Devices{Devices: map[string]Device{"ABB7013B85DE": {}}},
+	}
+
+	device, exists := response.Device("ABB7F595EC47")
+	if exists || device != nil {
+		t.Errorf("Expected no device, got %v", device)
+	}
+}
+
+func TestDeviceResponseDeviceMissingSerial(t *testing.T) {
+	response := DeviceResponse{
+		EmptyUUID: Devices{Devices: map[string]Device{"ABB7F595EC47": {}}},
+	}
+
+	device, exists := response.Device("ABB7FFFFFFFF")
+	if exists || device != nil {
+		t.Errorf("Expected no device, got %v", device)
+	}
+}
+
+func TestDeviceDatapointAddresses(t *testing.T) {
+	device := Device{
+		Channels: &map[string]*Channel{
+			"ch0012": {
+				Inputs:  &map[string]InOutPut{"idp0000": {}},
+				Outputs: &map[string]InOutPut{"odp0001": {}, "odp0000": {}},
+			},
+			"ch0000": {
+				Inputs:  &map[string]InOutPut{"idp0001": {}, "idp0000": {}},
+				Outputs: &map[string]InOutPut{"odp0000": {}},
+			},
+			"ch0001": {Outputs: &map[string]InOutPut{"odp0003": {}}},
+			"ch0002": {},
+			"ch0003": nil,
+		},
+	}
+
+	expected := []DatapointAddress{
+		{Channel: "ch0000", Datapoint: "idp0000"},
+		{Channel: "ch0000", Datapoint: "idp0001"},
+		{Channel: "ch0000", Datapoint: "odp0000"},
+		{Channel: "ch0001", Datapoint: "odp0003"},
+		{Channel: "ch0012", Datapoint: "idp0000"},
+		{Channel: "ch0012", Datapoint: "odp0000"},
+		{Channel: "ch0012", Datapoint: "odp0001"},
+	}
+	actual := device.DatapointAddresses()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d addresses, got %d: %v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Expected address %d to be %v, got %v", i, expected[i], actual[i])
+		}
+	}
+
+	if key := expected[0].Key("ABB7F595EC47"); key.String() != "ABB7F595EC47/ch0000/idp0000" {
+		t.Errorf("Expected key ABB7F595EC47/ch0000/idp0000, got %s", key)
+	}
+	if addresses := (&Device{}).DatapointAddresses(); len(addresses) != 0 {
+		t.Errorf("Expected no addresses for a device without channels, got %v", addresses)
+	}
+}
+
+func TestDeviceListSerials(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     DeviceList
+		expected []string
+		found    bool
+	}{
+		{name: "Empty UUID", list: DeviceList{EmptyUUID: {"ABB7F595EC47"}, "other-uuid": {"ABB7013B85DE"}}, expected: []string{"ABB7F595EC47"}, found: true},
+		{name: "Single non-empty UUID", list: DeviceList{"other-uuid": {"ABB7013B85DE"}}, expected: []string{"ABB7013B85DE"}, found: true},
+		{name: "Multiple non-empty UUIDs", list: DeviceList{"other-uuid": {"ABB7013B85DE"}, "another-uuid": {"ABB7F5947E20"}}, found: false},
+		{name: "Empty list", list: DeviceList{}, found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serials, found := tt.list.Serials()
+			if found != tt.found {
+				t.Fatalf("Expected found to be %t, got %t", tt.found, found)
+			}
+			if !slices.Equal(serials, tt.expected) {
+				t.Errorf("Expected serials %v, got %v", tt.expected, serials)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	// switchOnOffPairingID is the pairing identifier of the on/off switch datapoint as defined in the Busch+Jaeger documentation.
+	switchOnOffPairingID = 0x0001
+	// infoOnOffPairingID is the pairing identifier of the on/off state datapoint as defined in the Busch+Jaeger documentation.
+	infoOnOffPairingID = 0x0100
+)
+
+// functionTypes contains the function identifiers of the supported function types as defined in the Busch+Jaeger
+// documentation, keyed by the name of the function type.
+var functionTypes = map[string][]string{
+	"switch": {"7"},
+	"dimmer": {"12"},
+}
+
+// FunctionTypes returns the sorted names of the supported function types.
+func FunctionTypes() []string {
+	return slices.Sorted(maps.Keys(functionTypes))
+}
+
+// IsFunctionType reports whether the function identifier belongs to the named function type. The comparison ignores case
+// and leading zeros of the function identifier. Unknown function types never match.
+func IsFunctionType(functionType string, functionID string) bool {
+	normalized := strings.TrimLeft(strings.ToLower(functionID), "0")
+	return slices.Contains(functionTypes[functionType], normalized)
+}
+
+// SwitchDatapoint returns the identifier of the input datapoint switching the channel on and off, i.e. the input with the
+// on/off pairing identifier. The second return value is false if the channel has no such input.
+func (c *Channel) SwitchDatapoint() (string, bool) {
+	return datapointWithPairingID(c.Inputs, switchOnOffPairingID)
+}
+
+// SwitchStateDatapoint returns the identifier of the output datapoint reporting whether the channel is switched on, i.e. the
+// output with the on/off state pairing identifier. The second return value is false if the channel has no such output.
+func (c *Channel) SwitchStateDatapoint() (string, bool) {
+	return datapointWithPairingID(c.Outputs, infoOnOffPairingID)
+}
+
+// datapointWithPairingID returns the first identifier in sorted order of the datapoints with the given pairing identifier.
+func datapointWithPairingID(datapoints *map[string]InOutPut, pairingID uint) (string, bool) {
+	if datapoints == nil {
+		return "", false
+	}
+	for _, id := range slices.Sorted(maps.Keys(*datapoints)) {
+		datapoint := (*datapoints)[id]
+		if datapoint.PairingID != nil && *datapoint.PairingID == pairingID {
+			return id, true
+		}
+	}
+	return "", false
+}
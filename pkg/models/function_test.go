@@ -0,0 +1,73 @@
+package models
+
+import "testing"
+
+func TestIsFunctionType(t *testing.T) {
+	tests := []struct {
+		functionType string
+		functionID   string
+		expected     bool
+	}{
+		{functionType: "switch", functionID: "7", expected: true},
+		{functionType: "switch", functionID: "0007", expected: true},
+		{functionType: "switch", functionID: "12", expected: false},
+		{functionType: "dimmer", functionID: "12", expected: true},
+		{functionType: "dimmer", functionID: "7", expected: false},
+		{functionType: "blind", functionID: "9", expected: false},
+		{functionType: "switch", functionID: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.functionType+"/"+tt.functionID, func(t *testing.T) {
+			if actual := IsFunctionType(tt.functionType, tt.functionID); actual != tt.expected {
+				t.Errorf("Expected %t for function type %s and function %s, got %t", tt.expected, tt.functionType, tt.functionID, actual)
+			}
+		})
+	}
+}
+
+func TestFunctionTypes(t *testing.T) {
+	types := FunctionTypes()
+	if len(types) != 2 || types[0] != "dimmer" || types[1] != "switch" {
+		t.Errorf("Expected [dimmer switch], got %v", types)
+	}
+}
+
+func TestChannelSwitchDatapoint(t *testing.T) {
+	onOff := uint(0x0001)
+	other := uint(0x0011)
+
+	channel := Channel{Inputs: &map[string]InOutPut{
+		"idp0001": {PairingID: &other},
+		"idp0000": {PairingID: &onOff},
+	}}
+	if id, ok := channel.SwitchDatapoint(); !ok || id != "idp0000" {
+		t.Errorf("Expected switch datapoint idp0000, got %q (%t)", id, ok)
+	}
+
+	withoutSwitch := Channel{Inputs: &map[string]InOutPut{"idp0001": {PairingID: &other}}}
+	if id, ok := withoutSwitch.SwitchDatapoint(); ok {
+		t.Errorf("Expected no switch datapoint, got %q", id)
+	}
+
+	if _, ok := (&Channel{}).SwitchDatapoint(); ok {
+		t.Error("Expected no switch datapoint for a channel without inputs")
+	}
+}
+
+func TestChannelSwitchStateDatapoint(t *testing.T) {
+	onOff := uint(0x0001)
+	infoOnOff := uint(0x0100)
+
+	channel := Channel{
+		Inputs:  &map[string]InOutPut{"idp0000": {PairingID: &onOff}},
+		Outputs: &map[string]InOutPut{"odp0001": {}, "odp0000": {PairingID: &infoOnOff}},
+	}
+	if id, ok := channel.SwitchStateDatapoint(); !ok || id != "odp0000" {
+		t.Errorf("Expected switch state datapoint odp0000, got %q (%t)", id, ok)
+	}
+
+	if id, ok := (&Channel{Inputs: channel.Inputs}).SwitchStateDatapoint(); ok {
+		t.Errorf("Expected no switch state datapoint for a channel without outputs, got %q", id)
+	}
+}
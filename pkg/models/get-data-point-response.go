@@ -1,9 +1,75 @@
 package models
 
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 // GetDataPointResponse describes the response to a query requesting a data point. It is a map of data point names to their values using the System Access Point's UUID as a key.
 type GetDataPointResponse map[string]GetDataPoint
 
 // GetDataPoint represents a data point in the system.
 type GetDataPoint struct {
-	Values []string `json:"values"`
+	Values DataPointValues `json:"values"`
+}
+
+// DataPointValues represents the values of a data point. Some firmware versions send numeric values as JSON numbers
+// instead of strings, so both are accepted and numbers are converted to their string form.
+type DataPointValues []string
+
+// UnmarshalJSON parses a JSON array of strings and numbers into data point values.
+func (v *DataPointValues) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*v = nil
+		return nil
+	}
+
+	values := make(DataPointValues, len(raw))
+	for i, element := range raw {
+		element = bytes.TrimSpace(element)
+		if len(element) > 0 && element[0] == '"' {
+			if err := json.Unmarshal(element, &values[i]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var number json.Number
+		if err := json.Unmarshal(element, &number); err != nil {
+			return fmt.Errorf("data point value is neither a string nor a number: %s", element)
+		}
+		values[i] = number.String()
+	}
+
+	*v = values
+	return nil
+}
+
+// DataPoint returns the data point of the system access point, see SysApEntry.
+// It reports false if the response contains no entry for the system access point.
+func (r GetDataPointResponse) DataPoint() (GetDataPoint, bool) {
+	return SysApEntry(r)
+}
+
+// BytesValue decodes the first value of the data point as a base64 encoded binary value.
+// It returns an error if the response contains no value or the value is not valid base64.
+func (r GetDataPointResponse) BytesValue() ([]byte, error) {
+	dataPoint, exists := r.DataPoint()
+	if !exists || len(dataPoint.Values) == 0 {
+		return nil, errors.New("data point response contains no value")
+	}
+
+	value, err := base64.StdEncoding.DecodeString(dataPoint.Values[0])
+	if err != nil {
+		return nil, fmt.Errorf("data point value is not valid base64: %w", err)
+	}
+
+	return value, nil
 }
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGetDataPointResponseBytesValue(t *testing.T) {
+	response := GetDataPointResponse{
+		EmptyUUID: GetDataPoint{Values: []string{"AAEC/w=="}},
+	}
+
+	value, err := response.BytesValue()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []byte{0x00, 0x01, 0x02, 0xff}
+	if !bytes.Equal(value, expected) {
+		t.Errorf("Expected value %v, got %v", expected, value)
+	}
+}
+
+func TestGetDataPointResponseBytesValueSingleUUID(t *testing.T) {
+	response := GetDataPointResponse{
+		"11111111-1111-1111-1111-111111111111": GetDataPoint{Values: []string{"AAEC/w=="}},
+	}
+
+	value, err := response.BytesValue()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []byte{0x00, 0x01, 0x02, 0xff}
+	if !bytes.Equal(value, expected) {
+		t.Errorf("Expected value %v, got %v", expected, value)
+	}
+}
+
+func TestGetDataPointResponseBytesValueInvalidBase64(t *testing.T) {
+	response := GetDataPointResponse{
+		EmptyUUID: GetDataPoint{Values: []string{"not base64!"}},
+	}
+
+	value, err := response.BytesValue()
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "data point value is not valid base64") {
+		t.Errorf("Expected error to contain 'data point value is not valid base64', got '%s'", err.Error())
+	}
+	if value != nil {
+		t.Errorf("Expected nil value, got %v", value)
+	}
+}
+
+func TestGetDataPointResponseBytesValueEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		response GetDataPointResponse
+	}{
+		{name: "Empty response", response: GetDataPointResponse{}},
+		{name: "Empty values", response: GetDataPointResponse{EmptyUUID: GetDataPoint{Values: []string{}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.response.BytesValue()
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if err.Error() != "data point response contains no value" {
+				t.Errorf("Expected error 'data point response contains no value', got '%s'", err.Error())
+			}
+			if value != nil {
+				t.Errorf("Expected nil value, got %v", value)
+			}
+		})
+	}
+}
+
+func TestGetDataPointResponseBytesValueEmptyString(t *testing.T) {
+	response := GetDataPointResponse{
+		EmptyUUID: GetDataPoint{Values: []string{""}},
+	}
+
+	value, err := response.BytesValue()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(value) != 0 {
+		t.Errorf("Expected empty value, got %v", value)
+	}
+}
+
+func TestGetDataPointResponseUnmarshalValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected []string
+	}{
+		{name: "Quoted values", json: `{"values":["123","1.5"]}`, expected: []string{"123", "1.5"}},
+		{name: "Unquoted values", json: `{"values":[123, 1.5, -2e3]}`, expected: []string{"123", "1.5", "-2e3"}},
+		{name: "Mixed values", json: `{"values":["on", 0]}`, expected: []string{"on", "0"}},
+		{name: "Empty values", json: `{"values":[]}`, expected: []string{}},
+		{name: "Missing values", json: `{}`, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response GetDataPointResponse
+			if err := json.Unmarshal([]byte(`{"`+EmptyUUID+`":`+tt.json+`}`), &response); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			values := response[EmptyUUID].Values
+			if !slices.Equal(values, tt.expected) || (values == nil) != (tt.expected == nil) {
+				t.Errorf("Expected values %#v, got %#v", tt.expected, values)
+			}
+		})
+	}
+}
+
+func TestGetDataPointResponseUnmarshalInvalidValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected string
+	}{
+		{name: "Boolean value", json: `{"values":[true]}`, expected: "data point value is neither a string nor a number: true"},
+		{name: "Object value", json: `{"values":[{}]}`, expected: "data point value is neither a string nor a number: {}"},
+		{name: "No array", json: `{"values":"1"}`, expected: "json: cannot unmarshal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response GetDataPointResponse
+			err := json.Unmarshal([]byte(`{"`+EmptyUUID+`":`+tt.json+`}`), &response)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing '%s', got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
@@ -9,4 +9,12 @@ type InOutPut struct {
 	// PairingID represents the unique identifier for pairing. It is an optional field
 	// The field is omitted from the JSON output if it is nil.
 	PairingID *uint `json:"pairingId,omitempty"`
+
+	// Unit represents the unit of the value, e.g. "°C". It is an optional field
+	// The field is omitted from the JSON output if it is nil.
+	Unit *string `json:"unit,omitempty"`
+
+	// Description represents a human readable description of the datapoint. It is an optional field
+	// The field is omitted from the JSON output if it is nil.
+	Description *string `json:"description,omitempty"`
 }
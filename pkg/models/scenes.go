@@ -0,0 +1,34 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// sceneFunctionIDs contains the function identifiers of scene channels as defined in the Busch+Jaeger documentation:
+// scene trigger, panic, all off, all blinds up and all blinds down.
+var sceneFunctionIDs = []string{"4800", "4801", "4802", "4803", "4804"}
+
+// Scenes represents the scenes of the system access points. It is a map of scene actuators using the System Access Point's UUID as a key.
+type Scenes map[string][]SceneActuator
+
+// SceneActuator represents the virtual actuator channel of a scene. A scene is activated using its serial and channel.
+type SceneActuator struct {
+	// Name is the display name of the scene.
+	Name string `json:"name"`
+
+	// Serial is the serial of the scene actuator device.
+	Serial string `json:"serial"`
+
+	// Channel is the identifier of the scene channel.
+	Channel string `json:"channel"`
+
+	// FunctionID is the function identifier of the scene channel.
+	FunctionID string `json:"functionId"`
+}
+
+// IsSceneFunction reports whether the function identifier belongs to a scene channel. The comparison ignores case and leading zeros.
+func IsSceneFunction(functionID string) bool {
+	normalized := strings.TrimLeft(strings.ToLower(functionID), "0")
+	return slices.Contains(sceneFunctionIDs, normalized)
+}
@@ -0,0 +1,27 @@
+package models
+
+import "testing"
+
+func TestIsSceneFunction(t *testing.T) {
+	tests := []struct {
+		functionID string
+		expected   bool
+	}{
+		{functionID: "4800", expected: true},
+		{functionID: "4802", expected: true},
+		{functionID: "4804", expected: true},
+		{functionID: "04801", expected: true},
+		{functionID: "4A00", expected: false},
+		{functionID: "4000", expected: false},
+		{functionID: "1", expected: false},
+		{functionID: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.functionID, func(t *testing.T) {
+			if actual := IsSceneFunction(tt.functionID); actual != tt.expected {
+				t.Errorf("Expected %t for function %s, got %t", tt.expected, tt.functionID, actual)
+			}
+		})
+	}
+}
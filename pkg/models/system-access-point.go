@@ -3,6 +3,23 @@ package models
 // EmptyUUID is a constant representing an empty UUID. In the local (non-cloud) free@home API, the system access points ID is always the empty UUID.
 const EmptyUUID = "00000000-0000-0000-0000-000000000000"
 
+// SysApEntry returns the entry of a response for the system access point. Responses are keyed by the empty UUID, but some
+// gateways key the response of a single system access point by its real UUID, so the only entry is used if there is no
+// entry for the empty UUID. It reports false if there is neither an entry for the empty UUID nor exactly one entry.
+func SysApEntry[M ~map[string]V, V any](response M) (V, bool) {
+	if entry, exists := response[EmptyUUID]; exists {
+		return entry, true
+	}
+	if len(response) == 1 {
+		for _, entry := range response {
+			return entry, true
+		}
+	}
+
+	var none V
+	return none, false
+}
+
 // SysAP represents a system access point with a name, a list of devices, a floorplan, a list of users, and an optional error.
 type SysAP struct {
 	// Devices represents a map of devices identified by their key.
@@ -0,0 +1,23 @@
+package models
+
+// Tracer is an interface that defines a method for starting spans, e.g. to export traces via OpenTelemetry.
+// The client does not depend on a tracing library, implementations of this interface adapt the tracer of the library in use.
+type Tracer interface {
+	// Start starts a new span with the given name.
+	Start(name string) Span
+}
+
+// Span is an interface that defines methods for recording a single traced operation.
+type Span interface {
+	// SetAttribute sets an attribute of the span.
+	SetAttribute(key string, value any)
+
+	// AddEvent adds an event with optional key value pairs as attributes to the span.
+	AddEvent(name string, optionalParams ...any)
+
+	// RecordError records an error and marks the span as failed.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
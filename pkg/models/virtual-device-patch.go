@@ -0,0 +1,30 @@
+package models
+
+// VirtualDevicePatch describes a partial update of a virtual device. Only the fields that are not nil are changed.
+type VirtualDevicePatch struct {
+	// DisplayName is the new display name of the virtual device.
+	DisplayName *string `json:"displayName,omitempty"`
+
+	// TTL is the new time-to-live of the virtual device.
+	TTL *string `json:"ttl,omitempty"`
+}
+
+// ApplyTo returns a deep copy of the virtual device with the fields of the patch merged into it.
+// Fields of the patch that are nil preserve the value of the device, the device itself is not modified.
+func (p *VirtualDevicePatch) ApplyTo(device VirtualDevice) VirtualDevice {
+	device = device.Clone()
+	if p == nil {
+		return device
+	}
+
+	if p.DisplayName != nil {
+		displayName := *p.DisplayName
+		device.Properties.DisplayName = &displayName
+	}
+	if p.TTL != nil {
+		ttl := *p.TTL
+		device.Properties.TTL = &ttl
+	}
+
+	return device
+}
@@ -0,0 +1,89 @@
+package models
+
+import "testing"
+
+func TestVirtualDevicePatchApplyTo(t *testing.T) {
+	displayName := "Old Name"
+	ttl := "300"
+	flavor := "flavor"
+	device := VirtualDevice{
+		Type: SwitchingActuator,
+		Properties: VirtualDeviceProperties{
+			DisplayName: &displayName,
+			TTL:         &ttl,
+			Flavor:      &flavor,
+		},
+	}
+
+	newName := "New Name"
+	result := (&VirtualDevicePatch{DisplayName: &newName}).ApplyTo(device)
+
+	// Specified fields are changed
+	if result.Properties.DisplayName == nil || *result.Properties.DisplayName != "New Name" {
+		t.Errorf("Expected display name 'New Name', got %v", result.Properties.DisplayName)
+	}
+
+	// Unspecified fields are preserved
+	if result.Type != SwitchingActuator {
+		t.Errorf("Expected type %d, got %d", SwitchingActuator, result.Type)
+	}
+	if result.Properties.TTL == nil || *result.Properties.TTL != "300" {
+		t.Errorf("Expected TTL '300', got %v", result.Properties.TTL)
+	}
+	if result.Properties.Flavor == nil || *result.Properties.Flavor != "flavor" {
+		t.Errorf("Expected flavor 'flavor', got %v", result.Properties.Flavor)
+	}
+
+	// The original device is not modified
+	if *device.Properties.DisplayName != "Old Name" {
+		t.Errorf("Expected original display name to be preserved, got '%s'", *device.Properties.DisplayName)
+	}
+}
+
+func TestVirtualDevicePatchApplyToTTL(t *testing.T) {
+	displayName := "Name"
+	device := VirtualDevice{Type: BinarySensor, Properties: VirtualDeviceProperties{DisplayName: &displayName}}
+
+	ttl := "600"
+	result := (&VirtualDevicePatch{TTL: &ttl}).ApplyTo(device)
+
+	if result.Properties.TTL == nil || *result.Properties.TTL != "600" {
+		t.Errorf("Expected TTL '600', got %v", result.Properties.TTL)
+	}
+	if result.Properties.DisplayName == nil || *result.Properties.DisplayName != "Name" {
+		t.Errorf("Expected display name 'Name', got %v", result.Properties.DisplayName)
+	}
+}
+
+func TestVirtualDevicePatchApplyToNil(t *testing.T) {
+	device := VirtualDevice{Type: BinarySensor}
+
+	var patch *VirtualDevicePatch
+	result := patch.ApplyTo(device)
+
+	if result.Type != BinarySensor || result.Properties.DisplayName != nil || result.Properties.TTL != nil {
+		t.Errorf("Expected unchanged device, got %+v", result)
+	}
+}
+
+func TestVirtualDeviceClone(t *testing.T) {
+	displayName := "Name"
+	device := VirtualDevice{
+		Type:       BinarySensor,
+		Properties: VirtualDeviceProperties{DisplayName: &displayName, Capabilities: &[]uint{1, 2}},
+	}
+
+	clone := device.Clone()
+	*clone.Properties.DisplayName = "Changed"
+	(*clone.Properties.Capabilities)[0] = 42
+
+	if displayName != "Name" {
+		t.Errorf("Expected original display name to be preserved, got '%s'", displayName)
+	}
+	if (*device.Properties.Capabilities)[0] != 1 {
+		t.Errorf("Expected original capabilities to be preserved, got %v", *device.Properties.Capabilities)
+	}
+	if clone.Type != BinarySensor || clone.Properties.TTL != nil {
+		t.Errorf("Unexpected clone: %+v", clone)
+	}
+}
@@ -1,5 +1,7 @@
 package models
 
+import "slices"
+
 // VirtualDevice represents a virtual device with a type and properties.
 type VirtualDevice struct {
 	// Type of the virtual device.
@@ -9,6 +11,28 @@ type VirtualDevice struct {
 	Properties VirtualDeviceProperties `json:"properties"`
 }
 
+// Clone returns a deep copy of the virtual device that shares no pointers with it.
+func (d VirtualDevice) Clone() VirtualDevice {
+	clone := VirtualDevice{Type: d.Type}
+	clone.Properties.TTL = clonePointer(d.Properties.TTL)
+	clone.Properties.DisplayName = clonePointer(d.Properties.DisplayName)
+	clone.Properties.Flavor = clonePointer(d.Properties.Flavor)
+	if d.Properties.Capabilities != nil {
+		capabilities := slices.Clone(*d.Properties.Capabilities)
+		clone.Properties.Capabilities = &capabilities
+	}
+	return clone
+}
+
+// clonePointer returns a pointer to a copy of the value, or nil if the pointer is nil
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
 // VirtualDeviceProperties represents the properties of a virtual device.
 type VirtualDeviceProperties struct {
 	// TTL represents the time-to-live of the virtual device.